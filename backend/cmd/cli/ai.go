@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourorg/md-spec-tool/internal/ai"
+	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	"github.com/yourorg/md-spec-tool/internal/projectconfig"
+	"github.com/yourorg/md-spec-tool/internal/suggest"
+)
+
+// aiFlags are the AI options shared by convert and suggest. The key
+// comes from --api-key or the environment (OPENAI_API_KEY, or the variable
+// named by ai.api_key_env in .mdflow.yaml) and is never written anywhere.
+type aiFlags struct {
+	enabled bool
+	apiKey  string
+	model   string
+	budget  float64
+	noCache bool
+}
+
+func (f *aiFlags) register(fs *flag.FlagSet, withToggle bool) {
+	if withToggle {
+		fs.BoolVar(&f.enabled, "ai", false, "Map columns with AI (needs OPENAI_API_KEY or --api-key)")
+	}
+	fs.StringVar(&f.apiKey, "api-key", "", "OpenAI API key (default: $OPENAI_API_KEY)")
+	fs.StringVar(&f.model, "model", "", "OpenAI model (default: $OPENAI_MODEL or "+config.DefaultOpenAIModel+")")
+	fs.Float64Var(&f.budget, "budget", 0, "Stop making AI calls once this many USD are spent in this run (0 = no limit)")
+	fs.BoolVar(&f.noCache, "no-ai-cache", false, "Do not read or write the on-disk AI response cache")
+}
+
+// aiUsage is the help text for the flags registered by aiFlags.
+const aiUsage = `  --api-key   OpenAI API key (default: $OPENAI_API_KEY); prefer the variable,
+              flags are visible to other processes
+  --model     OpenAI model (default: $OPENAI_MODEL or gpt-4o-mini)
+  --budget    Stop making AI calls once this many USD are spent in this run
+              (estimated from token usage; 0 = no limit)
+  --no-ai-cache  Skip the response cache (default: <user cache dir>/mdflow/ai_cache.db,
+              so unchanged headers are not paid for twice)`
+
+// service builds the AI service, or returns nil when AI is off. Settings from
+// the project's ai: block apply unless the matching flag was given.
+func (f *aiFlags) service(proj *project) (*ai.ServiceImpl, error) {
+	var settings projectconfig.AISettings
+	set := map[string]bool{}
+	if proj != nil {
+		settings, set = proj.cfg.AI, proj.set
+	}
+	if !f.enabled && !(settings.Enabled && !set["ai"]) {
+		return nil, nil
+	}
+
+	key := f.apiKey
+	if key == "" {
+		env := settings.APIKeyEnv
+		if env == "" {
+			env = "OPENAI_API_KEY"
+		}
+		key = os.Getenv(env)
+		if key == "" {
+			return nil, fmt.Errorf("AI mapping needs an API key: set %s or pass --api-key", env)
+		}
+	}
+	if check := ai.ValidateBYOKKey(key); !check.Valid {
+		return nil, fmt.Errorf("invalid API key: %s", check.Reason)
+	}
+
+	cfg := ai.DefaultConfig()
+	cfg.APIKey = key
+	switch {
+	case f.model != "":
+		cfg.Model = f.model
+	case settings.Model != "":
+		cfg.Model = settings.Model
+	case os.Getenv("OPENAI_MODEL") != "":
+		cfg.Model = os.Getenv("OPENAI_MODEL")
+	}
+	budget := f.budget
+	if !set["budget"] && settings.BudgetUSD > 0 {
+		budget = settings.BudgetUSD
+	}
+	// The budget covers this run only: no persisted state, no daily reset.
+	cfg.Budget = &ai.BudgetConfig{DailyBudget: budget, WarningThreshold: 0.8, HardStopThreshold: 1}
+	if !f.noCache {
+		if path, err := aiCachePath(); err == nil {
+			cfg.PersistentCachePath = path
+		}
+	}
+
+	// The service logs cache and budget events at info level, which is noise
+	// on a terminal; keep warnings and errors.
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	return ai.NewService(cfg)
+}
+
+// aiCachePath is the persistent AI cache in the user cache directory
+// (~/.cache/mdflow on Linux, ~/Library/Caches/mdflow on macOS).
+func aiCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mdflow", "ai_cache.db"), nil
+}
+
+// aiSummary describes how columns were mapped for one converted file, or ""
+// when AI was not asked for.
+func aiSummary(meta converter.SpecDocMeta) string {
+	if meta.AIMode == "" || meta.AIMode == "off" {
+		return ""
+	}
+	if !meta.AIUsed || meta.AIDegraded {
+		reason := meta.AIFallbackReason
+		if reason == "" {
+			reason = "AI unavailable"
+		}
+		return fmt.Sprintf("AI mapping fell back to rules (%s)", reason)
+	}
+	return fmt.Sprintf("AI mapping (%s): %d mapped, %d unmapped, confidence %.0f%%, est. cost $%.4f",
+		meta.AIModel, meta.AIMappedColumns, meta.AIUnmappedColumns, meta.AIAvgConfidence*100, meta.AIEstimatedCostUSD)
+}
+
+// printAISpend reports what the run spent, from the service's token counts.
+func printAISpend(w io.Writer, svc *ai.ServiceImpl) {
+	if svc == nil {
+		return
+	}
+	cost := svc.GetCostSummary()
+	budget := svc.GetBudgetStatus()
+	line := fmt.Sprintf("AI: %d call(s), %d tokens, $%.4f", cost.TotalRequests, cost.TotalInput+cost.TotalOutput, cost.TotalCost)
+	if budget.DailyBudget > 0 {
+		line += fmt.Sprintf(" of $%.2f budget", budget.DailyBudget)
+		if budget.IsOverBudget {
+			line += " (budget reached; later files used rule-based mapping)"
+		}
+	}
+	fmt.Fprintln(w, line)
+}
+
+func runSuggest(args []string) {
+	fs := flag.NewFlagSet("suggest", flag.ExitOnError)
+	sheet := fs.String("sheet", "", "Sheet name (for XLSX files)")
+	sheetRange := fs.String("range", "", "Cell range to read, e.g. A3:H200")
+	template := fs.String("template", "spec", "Template the suggestions are for")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	configPath := fs.String("config", "", "Project config (default: nearest "+projectconfig.FileNames[0]+")")
+	var aiOpts aiFlags
+	aiOpts.register(fs, false)
+
+	fs.Usage = func() {
+		fmt.Println(`Ask AI for improvements to a spec: missing fields, vague descriptions,
+incomplete steps and similar issues, per row
+
+Usage:
+  mdflow suggest [options] <file>
+
+Options:
+  --sheet     Sheet name for XLSX files
+  --range     Cell range to read, e.g. A3:H200
+  --template  Template the suggestions are for (default: "spec")
+  --json      Output as JSON
+  --config    Project config file (default: the nearest .mdflow.yaml)
+` + aiUsage + `
+
+Examples:
+  OPENAI_API_KEY=sk-... mdflow suggest spec.xlsx
+  mdflow suggest --sheet Requirements --budget 0.05 --json book.xlsx`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Error: one input file is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	proj, err := loadProject(fs, *configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	path := fs.Arg(0)
+
+	aiOpts.enabled = true
+	svc, err := aiOpts.service(proj)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer svc.Close()
+
+	opts, err := proj.options(path, convertOptions{template: *template, sheet: *sheet, sheetRange: *sheetRange, needDoc: true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	opts.rules = nil
+	converted, err := convertFile(path, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	resp, err := suggest.NewSuggester(svc).GetSuggestions(context.Background(), &suggest.SuggestionRequest{
+		SpecDoc:  converted.specDoc,
+		Template: opts.template,
+	})
+	if err == nil && resp.Error != "" {
+		err = fmt.Errorf("%s", resp.Error)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		jsonBytes, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(jsonBytes))
+	} else {
+		fmt.Print(formatSuggestions(resp.Suggestions))
+	}
+	printAISpend(os.Stderr, svc)
+}
+
+// formatSuggestions prints one suggestion per block, most severe first as
+// returned by the model.
+func formatSuggestions(suggestions []suggest.AISuggestion) string {
+	if len(suggestions) == 0 {
+		return "No suggestions.\n"
+	}
+	var b strings.Builder
+	for _, s := range suggestions {
+		where := ""
+		if s.RowRef != nil {
+			where = fmt.Sprintf(" row %d", *s.RowRef)
+		}
+		if s.Field != "" {
+			where += " " + s.Field
+		}
+		fmt.Fprintf(&b, "[%s] %s%s: %s\n", s.Severity, s.Type, where, s.Message)
+		if s.Suggestion != "" {
+			fmt.Fprintf(&b, "    -> %s\n", s.Suggestion)
+		}
+	}
+	return b.String()
+}
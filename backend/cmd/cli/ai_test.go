@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	"github.com/yourorg/md-spec-tool/internal/projectconfig"
+	"github.com/yourorg/md-spec-tool/internal/suggest"
+)
+
+func TestAIFlagsService(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("TEAM_KEY", "")
+
+	var off aiFlags
+	if svc, err := off.service(nil); svc != nil || err != nil {
+		t.Fatalf("AI off: svc=%v err=%v", svc, err)
+	}
+
+	on := aiFlags{enabled: true}
+	if _, err := on.service(nil); err == nil || !strings.Contains(err.Error(), "OPENAI_API_KEY") {
+		t.Fatalf("missing key error = %v", err)
+	}
+
+	// The project can turn AI on and name the key variable; --ai=false wins.
+	proj := &project{cfg: &projectconfig.Config{AI: projectconfig.AISettings{Enabled: true, APIKeyEnv: "TEAM_KEY"}}, set: map[string]bool{}}
+	if _, err := off.service(proj); err == nil || !strings.Contains(err.Error(), "TEAM_KEY") {
+		t.Fatalf("project key error = %v", err)
+	}
+	proj.set["ai"] = true
+	if svc, err := off.service(proj); svc != nil || err != nil {
+		t.Fatalf("--ai=false: svc=%v err=%v", svc, err)
+	}
+}
+
+func TestAISummary(t *testing.T) {
+	if got := aiSummary(converter.SpecDocMeta{AIMode: "off"}); got != "" {
+		t.Errorf("off = %q", got)
+	}
+	got := aiSummary(converter.SpecDocMeta{
+		AIMode: "on", AIUsed: true, AIModel: "gpt-4o-mini",
+		AIMappedColumns: 5, AIUnmappedColumns: 1, AIAvgConfidence: 0.87, AIEstimatedCostUSD: 0.0012,
+	})
+	if want := "AI mapping (gpt-4o-mini): 5 mapped, 1 unmapped, confidence 87%, est. cost $0.0012"; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+	got = aiSummary(converter.SpecDocMeta{AIMode: "on", AIDegraded: true, AIFallbackReason: "budget exceeded"})
+	if !strings.Contains(got, "fell back") || !strings.Contains(got, "budget exceeded") {
+		t.Errorf("fallback = %q", got)
+	}
+}
+
+func TestFormatSuggestions(t *testing.T) {
+	row := 3
+	got := formatSuggestions([]suggest.AISuggestion{
+		{Type: "missing_field", Severity: "warn", Message: "No expected result", RowRef: &row, Field: "expected", Suggestion: "Add the expected outcome"},
+	})
+	want := "[warn] missing_field row 3 expected: No expected result\n    -> Add the expected outcome\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := formatSuggestions(nil); got != "No suggestions.\n" {
+		t.Errorf("empty = %q", got)
+	}
+}
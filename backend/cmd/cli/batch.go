@@ -0,0 +1,414 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	"github.com/yourorg/md-spec-tool/internal/projectconfig"
+)
+
+// manifestName is the file in --out-dir recording what each output was built from.
+const manifestName = ".mdflow-manifest.json"
+
+// batchExtensions are the files picked up from a directory or glob passed to
+// convert, so outputs written beside their inputs are never converted again.
+var batchExtensions = map[string]bool{
+	".xlsx": true, ".xls": true, ".ods": true, ".tsv": true, ".csv": true,
+}
+
+func hasBatchExtension(path string) bool {
+	return batchExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+type batchOptions struct {
+	convertOptions
+	outDir  string
+	jobs    int
+	force   bool
+	project *project // per-file settings from .mdflow.yaml; nil without one
+}
+
+// fileOptions returns the convert options for one input.
+func (o batchOptions) fileOptions(input string) (convertOptions, error) {
+	return o.project.options(input, o.convertOptions)
+}
+
+// expand resolves inputs to files, laid out as the project config says when
+// there is one and no --out-dir was given.
+func (o batchOptions) expand(args []string) ([]batchFile, error) {
+	if o.project != nil && o.project.layout {
+		return o.project.expand(args, o.json)
+	}
+	return expandInputs(args, o.json)
+}
+
+// batchFile is one input and where its output goes.
+type batchFile struct {
+	input  string
+	output string // relative to the output directory
+}
+
+// batchResult is the outcome for one batchFile.
+type batchResult struct {
+	file   batchFile
+	status string // converted, skipped or failed
+	entry  manifestEntry
+	err    error
+}
+
+// batchManifest maps output paths (relative to --out-dir) to what they were built from.
+type batchManifest struct {
+	Version int                      `json:"version"`
+	Files   map[string]manifestEntry `json:"files"`
+}
+
+type manifestEntry struct {
+	Input    string `json:"input"`
+	Hash     string `json:"hash"`
+	Rows     int    `json:"rows"`
+	Warnings int    `json:"warnings"`
+	Quality  string `json:"quality"`
+}
+
+// isBatchInput reports whether a convert argument names a directory or glob.
+func isBatchInput(arg string) bool {
+	if projectconfig.IsPattern(arg) {
+		return true
+	}
+	info, err := os.Stat(arg)
+	return err == nil && info.IsDir()
+}
+
+func runBatchConvert(args []string, opts batchOptions) {
+	if opts.jobs < 1 {
+		opts.jobs = 1
+	}
+
+	files, err := opts.expand(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no input files matched")
+		os.Exit(1)
+	}
+
+	manifestPath := filepath.Join(opts.outDir, manifestName)
+	manifest := loadManifest(manifestPath)
+
+	results := make([]batchResult, len(files))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < opts.jobs && w < len(files); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				results[i] = convertBatchFile(files[i], opts, manifest.Files[files[i].output])
+			}
+		}()
+	}
+	for i := range files {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	failed := 0
+	next := map[string]manifestEntry{}
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			continue
+		}
+		next[r.file.output] = r.entry
+	}
+	// Keep entries for outputs this run did not touch (e.g. a narrower glob).
+	for output, entry := range manifest.Files {
+		if _, ok := next[output]; !ok && !batchCovers(files, output) {
+			next[output] = entry
+		}
+	}
+	manifest.Files = next
+	if err := saveManifest(manifestPath, manifest); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing manifest: %v\n", err)
+		os.Exit(1)
+	}
+
+	printBatchSummary(os.Stdout, results)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func batchCovers(files []batchFile, output string) bool {
+	for _, f := range files {
+		if f.output == output {
+			return true
+		}
+	}
+	return false
+}
+
+// convertBatchFile converts one file unless the manifest shows its output is
+// current.
+func convertBatchFile(f batchFile, opts batchOptions, prev manifestEntry) batchResult {
+	res := batchResult{file: f}
+	content, err := os.ReadFile(f.input)
+	if err != nil {
+		res.status, res.err = "failed", err
+		return res
+	}
+	fileOpts, err := opts.fileOptions(f.input)
+	if err != nil {
+		res.status, res.err = "failed", err
+		return res
+	}
+	hash := batchHash(content, fileOpts)
+	outPath := filepath.Join(opts.outDir, f.output)
+	if !opts.force && prev.Hash == hash {
+		if _, err := os.Stat(outPath); err == nil {
+			res.status, res.entry = "skipped", prev
+			return res
+		}
+	}
+
+	converted, err := convertFile(f.input, fileOpts)
+	if err == nil {
+		err = writeFileAtomic(outPath, []byte(converted.output))
+	}
+	if err != nil {
+		res.status, res.err = "failed", err
+		return res
+	}
+
+	res.status = "converted"
+	res.entry = manifestEntry{
+		Input:    f.input,
+		Hash:     hash,
+		Rows:     converted.result.Meta.TotalRows,
+		Warnings: len(converted.result.Warnings),
+		Quality:  qualitySummary(converted.result.Meta),
+	}
+	return res
+}
+
+// qualitySummary describes the quality gates and rule pack outcome in a few
+// words: "pass", "fail: row_loss", or "-" when the gates did not run.
+func qualitySummary(meta converter.SpecDocMeta) string {
+	q := meta.QualityReport
+	if q == nil {
+		return "-"
+	}
+	if meta.RulePack != nil && !meta.RulePack.Passed() {
+		return fmt.Sprintf("fail: %s rule pack", meta.RulePack.Template)
+	}
+	if !q.ValidationPassed {
+		return "fail: " + q.ValidationReason
+	}
+	return "pass"
+}
+
+// batchHash covers the input content and every option that changes the
+// output, so editing the template, sheet, range, overrides or rules
+// reconverts the file.
+func batchHash(content []byte, opts convertOptions) string {
+	h := sha256.New()
+	t := opts.thresholds
+	fmt.Fprintf(h, "mdflow %s\x00%s\x00%s\x00%s\x00%s\x00%s\x00%s\x00%t\x00%s\x00%t/%d/%g\x00",
+		version, opts.template, opts.format, opts.inputType, opts.sheet, opts.sheetRange, opts.mergeFill, opts.json, opts.rulesHash,
+		t.StrictMode, t.MinHeaderConfidence, t.MaxRowLossRatio)
+	if opts.ai != nil {
+		fmt.Fprintf(h, "ai %s\x00", opts.ai.GetModel())
+	}
+	// json.Marshal sorts map keys, so equal mappings hash equally.
+	mappings, _ := json.Marshal([]any{opts.overrides, opts.synonyms})
+	h.Write(mappings)
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeFileAtomic writes data to a temp file beside path and renames it into
+// place, so editors and previewers never see a half-written spec.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// expandInputs resolves files, directories and globs to input files and their
+// output paths. Directories and globs are mirrored from their static root.
+func expandInputs(args []string, jsonOutput bool) ([]batchFile, error) {
+	ext := ".mdflow.md"
+	if jsonOutput {
+		ext = ".json"
+	}
+
+	var files []batchFile
+	seen := map[string]string{} // output -> input
+	for _, arg := range args {
+		matches, base, err := expandPattern(arg)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matches {
+			rel, err := filepath.Rel(base, path)
+			if err != nil {
+				return nil, err
+			}
+			output := strings.TrimSuffix(rel, filepath.Ext(rel)) + ext
+			if prev, ok := seen[output]; ok {
+				if prev == path {
+					continue
+				}
+				return nil, fmt.Errorf("%s and %s both convert to %s", prev, path, output)
+			}
+			seen[output] = path
+			files = append(files, batchFile{input: path, output: output})
+		}
+	}
+	return files, nil
+}
+
+// expandPattern returns the files an argument names and the directory their
+// output paths are relative to. "**" matches any number of directories.
+// Directories and globs only yield spreadsheet and delimited-text inputs; a
+// file named directly is returned whatever its extension.
+func expandPattern(arg string) ([]string, string, error) {
+	if !projectconfig.IsPattern(arg) {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, "", err
+		}
+		if !info.IsDir() {
+			return []string{arg}, filepath.Dir(arg), nil
+		}
+		files, err := walkFiles(arg, hasBatchExtension)
+		return files, arg, err
+	}
+
+	base := projectconfig.Base(arg)
+	if !strings.Contains(arg, "**") {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, "", err
+		}
+		var files []string
+		for _, m := range matches {
+			if !hasBatchExtension(m) {
+				continue
+			}
+			if info, err := os.Stat(m); err == nil && !info.IsDir() {
+				files = append(files, m)
+			}
+		}
+		return files, base, nil
+	}
+
+	files, err := walkFiles(base, func(path string) bool {
+		return hasBatchExtension(path) && projectconfig.MatchGlob(arg, path)
+	})
+	return files, base, err
+}
+
+// walkFiles lists the files under root accepted by keep, skipping hidden
+// directories such as .git.
+func walkFiles(root string, keep func(path string) bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if keep(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// loadManifest reads the manifest, starting fresh when it is missing or unreadable.
+func loadManifest(path string) *batchManifest {
+	m := &batchManifest{Version: 1, Files: map[string]manifestEntry{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring manifest: %v\n", err)
+		}
+		return m
+	}
+	if err := json.Unmarshal(data, m); err != nil || m.Files == nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring unreadable manifest %s\n", path)
+		return &batchManifest{Version: 1, Files: map[string]manifestEntry{}}
+	}
+	return m
+}
+
+func saveManifest(path string, m *batchManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func printBatchSummary(w *os.File, results []batchResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tSTATUS\tROWS\tWARNINGS\tQUALITY")
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.status]++
+		if r.err != nil {
+			fmt.Fprintf(tw, "%s\tfailed\t-\t-\t%v\n", r.file.input, r.err)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", r.file.input, r.status, r.entry.Rows, r.entry.Warnings, r.entry.Quality)
+	}
+	_ = tw.Flush()
+	fmt.Fprintf(w, "%d converted, %d skipped, %d failed\n", counts["converted"], counts["skipped"], counts["failed"])
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpandInputs_MirrorsTree(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "in", "one.tsv"), "ID\tTitle\nA\tx\n")
+	writeFile(t, filepath.Join(root, "in", "a", "b", "two.csv"), "ID,Title\nB,y\n")
+	writeFile(t, filepath.Join(root, "in", "notes.txt"), "skip me")
+	writeFile(t, filepath.Join(root, "in", ".git", "three.tsv"), "ID\tTitle\n")
+
+	files, err := expandInputs([]string{filepath.Join(root, "in")}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		filepath.Join("a", "b", "two.mdflow.md"): filepath.Join(root, "in", "a", "b", "two.csv"),
+		"one.mdflow.md":                          filepath.Join(root, "in", "one.tsv"),
+	}
+	if len(files) != len(want) {
+		t.Fatalf("files = %+v", files)
+	}
+	for _, f := range files {
+		if want[f.output] != f.input {
+			t.Errorf("%s -> %s", f.input, f.output)
+		}
+	}
+
+	files, err = expandInputs([]string{filepath.Join(root, "in", "**", "*.csv")}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].output != filepath.Join("a", "b", "two.json") {
+		t.Fatalf("glob files = %+v", files)
+	}
+}
+
+func TestExpandInputs_GlobsSkipOutputs(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "sheets", "a.tsv"), "ID\tTitle\nA\tx\n")
+	writeFile(t, filepath.Join(root, "sheets", "a.mdflow.md"), "# a")
+	writeFile(t, filepath.Join(root, "sheets", "a.json"), "{}")
+	writeFile(t, filepath.Join(root, "sheets", "sub", "b.mdflow.md"), "# b")
+
+	for _, pattern := range []string{filepath.Join(root, "sheets", "*"), filepath.Join(root, "sheets", "**", "*")} {
+		files, err := expandInputs([]string{pattern}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 1 || files[0].input != filepath.Join(root, "sheets", "a.tsv") {
+			t.Errorf("%s: files = %+v, want only a.tsv", pattern, files)
+		}
+	}
+}
+
+func TestExpandInputs_OutputCollision(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "spec.tsv"), "ID\tTitle\n")
+	writeFile(t, filepath.Join(root, "spec.csv"), "ID,Title\n")
+	if _, err := expandInputs([]string{root}, false); err == nil {
+		t.Fatal("expected a collision error")
+	}
+}
+
+func TestConvertBatchFile_SkipsUnchanged(t *testing.T) {
+	root := t.TempDir()
+	input := filepath.Join(root, "spec.tsv")
+	writeFile(t, input, "ID\tTitle\tPriority\nREQ-1\tLogin\tHigh\nREQ-2\tLogout\tLow\n")
+	opts := batchOptions{convertOptions: convertOptions{template: "spec"}, outDir: filepath.Join(root, "out"), jobs: 1}
+	f := batchFile{input: input, output: "spec.mdflow.md"}
+
+	first := convertBatchFile(f, opts, manifestEntry{})
+	if first.err != nil || first.status != "converted" || first.entry.Rows != 2 {
+		t.Fatalf("first = %+v", first)
+	}
+	if second := convertBatchFile(f, opts, first.entry); second.status != "skipped" {
+		t.Fatalf("second = %+v", second)
+	}
+	opts.template = "table"
+	third := convertBatchFile(f, opts, first.entry)
+	if third.status != "converted" {
+		t.Fatalf("template change should reconvert: %+v", third)
+	}
+	opts.mergeFill = "none"
+	if fourth := convertBatchFile(f, opts, third.entry); fourth.status != "converted" {
+		t.Fatalf("merge fill change should reconvert: %+v", fourth)
+	}
+}
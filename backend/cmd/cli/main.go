@@ -1,15 +1,23 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 
+	"github.com/yourorg/md-spec-tool/internal/ai"
+	"github.com/yourorg/md-spec-tool/internal/config"
 	"github.com/yourorg/md-spec-tool/internal/converter"
 	"github.com/yourorg/md-spec-tool/internal/diff"
+	"github.com/yourorg/md-spec-tool/internal/projectconfig"
+	"github.com/yourorg/md-spec-tool/internal/report"
+	"github.com/yourorg/md-spec-tool/internal/traceability"
 )
 
 const (
@@ -20,9 +28,16 @@ Usage:
   mdflow <command> [options]
 
 Commands:
-  convert     Convert a file (TSV/CSV/XLSX) to MDFlow markdown
+  convert     Convert a file (TSV/CSV/XLSX/XLS/ODS) to MDFlow markdown
+  validate    Check a file against validation rules and quality gates
+  map         Review column mapping interactively and save overrides
+  suggest     Ask AI for improvements to a spec
+  lint        Check the structure of MDFlow markdown files
+  watch       Rebuild specs whenever their input files change
   diff        Compare two MDFlow files
+  trace       Link test cases to requirements and report coverage
   templates   List available templates
+  serve       Run the API server, or serve a directory of specs read-only
   version     Print version information
 
 Run 'mdflow <command> --help' for more information on a command.
@@ -30,8 +45,17 @@ Run 'mdflow <command> --help' for more information on a command.
 Examples:
   mdflow convert --input spec.tsv --output spec.mdflow.md
   mdflow convert --input data.xlsx --sheet "Sheet1" --template table
+  mdflow convert --out-dir specs 'sheets/**/*.xlsx'
+  mdflow validate --rules rules.yaml input.xlsx
+  mdflow lint spec.mdflow.md
+  mdflow convert --input data.xlsx --ai
+  mdflow suggest spec.xlsx
+  mdflow map requirements.xlsx
+  mdflow watch spec.xlsx
   mdflow diff before.md after.md
+  mdflow trace --requirements reqs.tsv --tests tests.tsv
   mdflow templates
+  mdflow serve --read-only specs/
 `
 )
 
@@ -44,8 +68,22 @@ func main() {
 	switch os.Args[1] {
 	case "convert":
 		runConvert(os.Args[2:])
+	case "serve":
+		runServe(os.Args[2:])
+	case "map":
+		runMap(os.Args[2:])
+	case "suggest":
+		runSuggest(os.Args[2:])
+	case "validate":
+		runValidate(os.Args[2:])
+	case "lint":
+		runLint(os.Args[2:])
+	case "watch":
+		runWatch(os.Args[2:])
 	case "diff":
 		runDiff(os.Args[2:])
+	case "trace":
+		runTrace(os.Args[2:])
 	case "templates":
 		runTemplates()
 	case "version", "-v", "--version":
@@ -61,83 +99,586 @@ func main() {
 
 func runConvert(args []string) {
 	fs := flag.NewFlagSet("convert", flag.ExitOnError)
-	input := fs.String("input", "", "Input file path (required)")
-	output := fs.String("output", "", "Output file path (default: stdout)")
+	input := fs.String("input", "", "Input file path (- for stdin)")
+	inputType := fs.String("input-type", "", "Input type for stdin or an unknown extension (tsv|csv|md|xlsx|xls|ods)")
+	var outputs outputTargets
+	fs.Var(&outputs, "output", "Output file path, optionally path:format (repeatable; default: stdout)")
 	template := fs.String("template", "spec", "Template name (spec|table)")
+	format := fs.String("format", "", "Output format (spec|table; default from the template)")
 	sheet := fs.String("sheet", "", "Sheet name (for XLSX files)")
+	sheetRange := fs.String("range", "", "Cell range to convert, e.g. A3:H200")
+	mergeFill := fs.String("merge-fill", "both", "Copy merged XLSX cell values into covered cells (both|down|across|none)")
 	jsonOutput := fs.Bool("json", false, "Output as JSON with metadata")
+	rulesFile := fs.String("rules", "", "Validation rules file (YAML or JSON)")
+	reportPath := fs.String("report", "", "Write findings as a SARIF or JUnit report to this file")
+	reportFormat := fs.String("report-format", "", "Report format (sarif|junit; default from --report extension)")
+	outDir := fs.String("out-dir", "", "Output directory for batch mode (mirrors the input tree)")
+	jobs := fs.Int("jobs", runtime.NumCPU(), "Files converted in parallel in batch mode")
+	force := fs.Bool("force", false, "Batch mode: convert every file, ignoring the manifest")
+	configPath := fs.String("config", "", "Project config (default: nearest "+projectconfig.FileNames[0]+")")
+	var aiOpts aiFlags
+	aiOpts.register(fs, true)
 
 	fs.Usage = func() {
 		fmt.Println(`Convert a file to MDFlow markdown
 
 Usage:
   mdflow convert --input <file> [options]
+  mdflow convert --out-dir <dir> [options] <file|dir|glob>...
+  mdflow convert [options]            (every input in .mdflow.yaml)
 
 Options:
-  --input     Input file path (TSV, CSV, or XLSX) (required)
-  --output    Output file path (default: stdout)
+  --input     Input file path (TSV, CSV, XLSX, XLS or ODS); - reads stdin
+  --input-type  tsv, csv, md, xlsx, xls or ods, for stdin or a file whose
+              extension does not say (default: from the extension; stdin is
+              a workbook when it starts with a zip or OLE2 signature,
+              otherwise detected text)
+  --output    Output file path; - is stdout (default: stdout). Repeat it as
+              path:format to write several formats from one conversion, with
+              format spec, table, json or xlsx (the mapped table as a
+              workbook; a .xlsx path implies it)
 	  --template  Template name (default: "spec", options: spec|table)
-  --sheet     Sheet name for XLSX files
+  --format    Output format: spec or table (default: from the template)
+  --sheet     Sheet name for XLSX, XLS and ODS files
+  --range     Cell range to convert, e.g. A3:H200 (rows and columns outside
+              it are ignored)
+  --merge-fill  Where merged XLSX cells repeat their value: both (default),
+              down (rows of a merged feature), across (columns of a merged
+              header group) or none
   --json      Output as JSON with metadata
+  --rules     Validation rules file (YAML or JSON); findings are added to warnings
+  --report    Write warnings as a CI report to this file; rows point at source
+              lines or cells (e.g. Sheet1!C5)
+  --report-format  sarif or junit (default: junit for .xml, otherwise sarif)
+  --config    Project config file (default: the nearest .mdflow.yaml in this
+              or a parent directory); flags given here override it
+
+AI column mapping (bring your own OpenAI key):
+  --ai        Map columns with AI instead of rules only; prints the mapping
+              confidence and estimated cost (also: ai.enabled in .mdflow.yaml)
+` + aiUsage + `
+
+Batch mode (directories, globs or several inputs):
+  --out-dir   Output directory; input trees are mirrored as <name>.mdflow.md
+              (or <name>.json with --json). Without it, a project config
+              decides where outputs go.
+  --jobs      Files converted in parallel (default: number of CPUs)
+  --force     Convert every file; by default inputs whose content and options
+              match <out-dir>/` + manifestName + ` are skipped
+  Globs support ** for any number of directories; quote them so the shell
+  does not expand them.
 
 Examples:
   mdflow convert --input spec.tsv
   mdflow convert --input spec.tsv --output spec.mdflow.md
+  pbpaste | mdflow convert --input - --input-type tsv
+  mdflow convert --input data.xlsx --ai --output spec.md:spec \
+    --output table.md:table --output spec.json:json --output mapped.xlsx
 	  mdflow convert --input data.xlsx --sheet "Requirements" --template table
-  mdflow convert --input test.csv --json`)
+  mdflow convert --input data.xlsx --range B3:H120
+  mdflow convert --input data.xlsx --ai --budget 0.10
+  mdflow convert --input test.csv --json
+  mdflow convert --input spec.tsv --rules rules.yaml --json
+  mdflow convert --input data.xlsx --rules rules.yaml --report mdflow.sarif
+  mdflow convert --out-dir specs sheets/
+  mdflow convert --out-dir specs --jobs 4 'sheets/**/*.xlsx' extra/notes.tsv
+  mdflow convert`)
 	}
 
 	if err := fs.Parse(args); err != nil {
 		os.Exit(1)
 	}
+	proj, err := loadProject(fs, *configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	if *input == "" {
-		fmt.Fprintln(os.Stderr, "Error: --input is required")
+	inputs := fs.Args()
+	if *input != "" {
+		inputs = append([]string{*input}, inputs...)
+	}
+	if len(inputs) == 0 && proj == nil {
+		fmt.Fprintln(os.Stderr, "Error: --input (or a file, directory or glob argument) is required")
 		fs.Usage()
 		os.Exit(1)
 	}
+	if *sheetRange != "" {
+		if _, err := converter.ParseA1Range(*sheetRange); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --range: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if _, err := converter.ParseMergeFill(*mergeFill); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --merge-fill: %v\n", err)
+		os.Exit(1)
+	}
+	if _, ok := inputTypes[strings.ToLower(*inputType)]; *inputType != "" && !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown --input-type %q (tsv|csv|md|xlsx|xls|ods)\n", *inputType)
+		os.Exit(1)
+	}
+	if err := outputs.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	// Read input file
-	content, err := os.ReadFile(*input)
+	rules, rulesHash, err := loadRulesFiles([]string{*rulesFile})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading input file: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	conv := converter.NewConverter()
-	var result *converter.ConvertResponse
+	aiService, err := aiOpts.service(proj)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if aiService != nil {
+		defer aiService.Close()
+	}
 
-	ext := strings.ToLower(filepath.Ext(*input))
-	switch ext {
-	case ".xlsx", ".xls":
-		result, err = conv.ConvertXLSX(*input, *sheet, *template)
-	case ".tsv", ".csv", ".txt", ".md":
-		result, err = conv.ConvertPaste(string(content), *template)
-	default:
-		// Try as text/paste
-		result, err = conv.ConvertPaste(string(content), *template)
+	specCfg := config.LoadSpecValidationConfig()
+	opts := convertOptions{
+		template:   *template,
+		format:     *format,
+		inputType:  *inputType,
+		sheet:      *sheet,
+		sheetRange: *sheetRange,
+		mergeFill:  *mergeFill,
+		json:       *jsonOutput,
+		rules:      rules,
+		rulesHash:  rulesHash,
+		thresholds: converter.QualityThresholds{
+			StrictMode:          specCfg.SpecStrictMode,
+			MinHeaderConfidence: specCfg.SpecMinHeaderConfidence,
+			MaxRowLossRatio:     specCfg.SpecMaxRowLossRatio,
+		},
+	}
+	if aiService != nil {
+		opts.ai = aiService
+	}
+
+	// With a project config and no explicit output, outputs go where the
+	// config says: every input when none are named, otherwise the named ones.
+	projectLayout := proj != nil && *outDir == "" && len(outputs) == 0 && *reportPath == ""
+	if projectLayout && (len(inputs) == 0 || len(inputs) > 1 || isBatchInput(inputs[0]) || proj.cfg.Input(inputs[0]) != nil) {
+		proj.layout = true
+		runBatchConvert(inputs, batchOptions{
+			convertOptions: opts,
+			outDir:         proj.cfg.Dir,
+			jobs:           *jobs,
+			force:          *force,
+			project:        proj,
+		})
+		printAISpend(os.Stderr, aiService)
+		return
+	}
+
+	// Explicit outputs with no named input convert the config's inputs.
+	if inputs = proj.inputs(inputs); len(inputs) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: --input (or a file, directory or glob argument) is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *outDir != "" || len(inputs) > 1 || isBatchInput(inputs[0]) {
+		if slices.Contains(inputs, stdio) || *inputType != "" {
+			fmt.Fprintln(os.Stderr, "Error: stdin (-) and --input-type convert a single input")
+			os.Exit(1)
+		}
+		if *outDir == "" {
+			fmt.Fprintln(os.Stderr, "Error: --out-dir is required for directories, globs and multiple inputs")
+			os.Exit(1)
+		}
+		if len(outputs) > 0 || *reportPath != "" {
+			fmt.Fprintln(os.Stderr, "Error: --output and --report convert a single file; use --out-dir in batch mode")
+			os.Exit(1)
+		}
+		runBatchConvert(inputs, batchOptions{
+			convertOptions: opts,
+			outDir:         *outDir,
+			jobs:           *jobs,
+			force:          *force,
+			project:        proj,
+		})
+		printAISpend(os.Stderr, aiService)
+		return
+	}
+
+	reportFmt, err := reportFormatFor(*reportPath, *reportFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts, err = proj.options(inputs[0], opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	opts.needDoc = *reportPath != ""
+	source := inputs[0]
+	if source == stdio {
+		if source, err = stdinFile(os.Stdin, *inputType); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	converted, err := convertFile(source, opts)
+	if source != inputs[0] {
+		os.Remove(source)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	result := converted.result
+	if summary := aiSummary(result.Meta); summary != "" {
+		fmt.Fprintln(os.Stderr, summary)
+	}
+
+	// Write output
+	if len(outputs) == 0 {
+		fmt.Print(converted.output)
+	} else {
+		if err := writeOutputs(converted, outputs); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if !outputs.toStdout() {
+		// Print warnings to stderr
+		for _, w := range result.Warnings {
+			fmt.Fprintf(os.Stderr, "[%s] %s\n", w.Severity, w.Message)
+		}
+	}
+
+	if *reportPath != "" {
+		path := inputs[0]
+		specDoc := converted.specDoc
+		findings := append(report.FromWarnings(result.Warnings), report.FromQualityReport(result.Meta.QualityReport)...)
+		var locator report.Locator = sourceLocator(path, converted)
+		if mdPath := markdownTarget(converted, outputs, opts.json); specDoc != nil && len(specDoc.Rows) > 0 && specDoc.Rows[0].SourceRow == 0 && mdPath != "" {
+			// Markdown input has no sheet rows; point at the rendered output instead.
+			locator = report.NewMarkdownLocator(mdPath, result.MDFlow, specDoc)
+		}
+		report.Locate(findings, path, locator)
+		if err := writeReport(*reportPath, reportFmt, &report.Report{Tool: "mdflow", Version: version, URI: path, Findings: findings}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Report written to %s\n", *reportPath)
+	}
+}
+
+// convertOptions are the convert flags (and project settings) for one input.
+type convertOptions struct {
+	template   string
+	format     string // spec or table; "" lets the template decide
+	inputType  string // --input-type; "" goes by the file extension
+	sheet      string
+	sheetRange string            // A1 range; "" converts the whole sheet
+	mergeFill  string            // --merge-fill; "" fills both axes
+	overrides  map[string]string // header -> replacement header
+	synonyms   map[string][]string
+	json       bool
+	rules      *converter.ValidationRules
+	rulesHash  string // hash of the rule files, so rule edits reconvert
+	thresholds converter.QualityThresholds
+	ai         ai.Service // AI column mapping; nil maps with rules only
+	needDoc    bool       // build the SpecDoc even without rules (for reports)
+}
+
+// convertedFile is one converted input.
+type convertedFile struct {
+	result     *converter.ConvertResponse
+	content    []byte
+	specDoc    *converter.SpecDoc // set when rules ran or needDoc was requested
+	isSheet    bool
+	matrix     converter.CellMatrix // parsed cells within the range, before overrides
+	sourceRows []int                // sheet row or text line of each parsed row
+	output     string               // rendered markdown, or JSON with --json
+	format     string               // spec or table: the markdown in result
+	mapped     converter.CellMatrix // the table the conversion mapped, overrides applied; nil for markdown input
+
+	// rerender renders the input in another format: mapped tables reuse the
+	// conversion's header band and column map, markdown input is converted
+	// again. rendered caches the markdown by format.
+	rerender func(format string) (string, error)
+	rendered map[string]string
+}
+
+// convertFile converts one input, runs the rules against it and measures the
+// quality gates for tabular inputs.
+func convertFile(path string, opts convertOptions) (*convertedFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading input file: %w", err)
+	}
+
+	fill, err := converter.ParseMergeFill(opts.mergeFill)
+	if err != nil {
+		return nil, err
+	}
+	conv := converter.NewConverter().WithAIService(opts.ai).WithMergeFill(fill)
+	ext := inputExt(path, opts.inputType)
+	isSheet := slices.Contains(converter.SpreadsheetExtensions, ext)
+	var cellRange *converter.A1Range
+	if opts.sheetRange != "" {
+		r, err := converter.ParseA1Range(opts.sheetRange)
+		if err != nil {
+			return nil, err
+		}
+		cellRange = &r
+	}
+
+	var matrix converter.CellMatrix
+	var sheetName string
+	var sourceRows []int
+	var merges []converter.MergedRegion
+	if isSheet {
+		matrix, sheetName, merges, err = conv.ParseXLSXSheetWithMerges(path, opts.sheet)
+		if err != nil {
+			return nil, fmt.Errorf("converting file: %w", err)
+		}
+		sourceRows, _ = converter.XLSXSourceRows(path, sheetName)
+	} else if ext != ".md" {
+		matrix, _ = converter.NewPasteParser().Parse(string(content))
+		sourceRows = textSourceRows(string(content))
+	}
+	if cellRange != nil && matrix != nil {
+		matrix, sourceRows = cellRange.Slice(matrix, sourceRows)
+		merges = converter.LocateMerges(merges, sourceRows, cellRange.StartCol)
+	}
+	options := converter.DefaultConvertOptions()
+	options.Merges = merges
+
+	overrides := opts.overrides
+	if synonyms := converter.SynonymOverrides(matrix, opts.synonyms); len(synonyms) > 0 {
+		// Explicit overrides win over the synonym dictionary.
+		for header, field := range opts.overrides {
+			synonyms[header] = field
+		}
+		overrides = synonyms
+	}
+
+	// Text goes through paste detection (markdown, single columns) unless a
+	// range or synonyms need the parsed table.
+	ctx := context.Background()
+	useMatrix := isSheet || (matrix != nil && matrix.ColCount() >= 2 && (cellRange != nil || len(opts.synonyms) > 0))
+	render := func(format string) (*converter.ConvertResponse, error) {
+		if useMatrix {
+			return conv.ConvertMatrixWithOverridesAndOptions(ctx, matrix, sheetName, opts.template, format, overrides, options)
+		}
+		return conv.ConvertPasteWithOverrides(ctx, string(content), opts.template, format, overrides)
+	}
+	result, err := render(opts.format)
+	if err != nil {
+		return nil, fmt.Errorf("converting file: %w", err)
+	}
+
+	var mapped converter.CellMatrix
+	if result.Doc != nil {
+		source := matrix
+		if source == nil {
+			source, _ = converter.NewPasteParser().Parse(string(content))
+		}
+		mapped = converter.ApplyColumnOverridesWithMerges(source, overrides, merges)
+	}
+	rerender := func(format string) (string, error) {
+		if mapped != nil {
+			return conv.RenderMapped(ctx, mapped, result.Meta, opts.template, format, options)
+		}
+		again, err := render(format)
+		if err != nil {
+			return "", err
+		}
+		return again.MDFlow, nil
+	}
+
+	if matrix != nil {
+		result.Meta.QualityReport = converter.BuildQualityReport(converter.AnalyzeQualityWithMerges(matrix, merges), result.Meta, opts.thresholds)
+	}
+
+	var specDoc *converter.SpecDoc
+	if !opts.rules.IsEmpty() || opts.needDoc {
+		if result.Doc != nil {
+			specDoc = result.Doc
+		} else if useMatrix || (matrix != nil && len(overrides) > 0 && matrix.ColCount() >= 2) {
+			specDoc = converter.BuildSpecDocFromMatrixWithMerges(converter.ApplyColumnOverrides(matrix, overrides), merges)
+			specDoc.Meta.SheetName = result.Meta.SheetName
+		} else {
+			specDoc, _ = converter.BuildSpecDocFromPaste(string(content))
+		}
+	}
+	if !opts.rules.IsEmpty() {
+		validation := converter.Validate(specDoc, opts.rules)
+		result.Warnings = append(result.Warnings, validation.Warnings...)
+	}
+
+	out := result.MDFlow
+	if opts.json {
+		jsonBytes, err := jsonOutput(result)
+		if err != nil {
+			return nil, err
+		}
+		out = string(jsonBytes)
 	}
 
+	format := strings.ToLower(strings.TrimSpace(opts.format))
+	if format == "" {
+		format = "spec"
+		if opts.template == "table" {
+			format = "table"
+		}
+	}
+	return &convertedFile{
+		result: result, content: content, specDoc: specDoc, isSheet: isSheet, matrix: matrix, sourceRows: sourceRows, output: out,
+		format: format, mapped: mapped, rerender: rerender,
+	}, nil
+}
+
+// textSourceRows returns the line of each row parsed from TSV/CSV text:
+// parsing drops blank lines, so the Nth row is the Nth non-blank line.
+func textSourceRows(text string) []int {
+	var lines []int
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+// reportFormatFor resolves --report-format, defaulting from the report file
+// extension (.xml means JUnit, anything else SARIF).
+func reportFormatFor(path, format string) (string, error) {
+	if path == "" {
+		if format != "" {
+			return "", fmt.Errorf("--report-format requires --report")
+		}
+		return "", nil
+	}
+	if format == "" {
+		if strings.EqualFold(filepath.Ext(path), ".xml") {
+			return report.FormatJUnit, nil
+		}
+		return report.FormatSARIF, nil
+	}
+	normalized, err := report.NormalizeFormat(format)
+	if err != nil {
+		return "", err
+	}
+	if normalized != report.FormatSARIF && normalized != report.FormatJUnit {
+		return "", fmt.Errorf("--report-format must be sarif or junit")
+	}
+	return normalized, nil
+}
+
+func writeReport(path, format string, r *report.Report) error {
+	f, err := os.Create(path)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error converting file: %v\n", err)
+		return err
+	}
+	if err := report.Write(f, format, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	output := fs.String("output", "", "Output file path (default: stdout)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	against := fs.String("against", "", "Existing output to compare a fresh conversion with")
+	exitCode := fs.Bool("exit-code", false, "Exit with status 1 when there are differences")
+	configPath := fs.String("config", "", "Project config (default: nearest "+projectconfig.FileNames[0]+")")
+
+	fs.Usage = func() {
+		fmt.Println(`Compare two MDFlow files
+
+Usage:
+  mdflow diff <before-file> <after-file> [options]
+  mdflow diff [options] [input...]    (fresh conversions vs. their outputs)
+
+With one input, or none in a project with .mdflow.yaml, each input is
+converted with its project settings and compared with the output it was
+last built to, showing what a convert would change.
+
+Options:
+  --output     Output file path (default: stdout)
+  --json       Output as JSON
+  --against    Existing output to compare a single input's conversion with
+               (default: the output configured in .mdflow.yaml)
+  --exit-code  Exit with status 1 when there are differences
+  --config     Project config file (default: the nearest .mdflow.yaml)
+
+Examples:
+  mdflow diff old.md new.md
+  mdflow diff spec-v1.mdflow.md spec-v2.mdflow.md --json
+  mdflow diff --against spec.mdflow.md spec.xlsx
+  mdflow diff --exit-code`)
+	}
+
+	if err := fs.Parse(args); err != nil {
 		os.Exit(1)
 	}
 
-	// Prepare output
+	remainingArgs := fs.Args()
+	var results []fileDiff
+	if len(remainingArgs) == 2 && *against == "" {
+		beforePath := remainingArgs[0]
+		afterPath := remainingArgs[1]
+
+		before, err := os.ReadFile(beforePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading before file: %v\n", err)
+			os.Exit(1)
+		}
+
+		after, err := os.ReadFile(afterPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading after file: %v\n", err)
+			os.Exit(1)
+		}
+		results = []fileDiff{{Before: beforePath, After: afterPath, Diff: diff.Diff(string(before), string(after))}}
+	} else {
+		var err error
+		results, err = diffConversions(fs, remainingArgs, *against, *configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fs.Usage()
+			os.Exit(1)
+		}
+	}
+
 	var outputContent string
 	if *jsonOutput {
-		jsonBytes, jsonErr := json.MarshalIndent(map[string]interface{}{
-			"mdflow":   result.MDFlow,
-			"warnings": result.Warnings,
-			"meta":     result.Meta,
-		}, "", "  ")
+		var value any = results
+		if len(results) == 1 {
+			value = results[0].Diff
+		}
+		jsonBytes, jsonErr := json.MarshalIndent(value, "", "  ")
 		if jsonErr != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", jsonErr)
 			os.Exit(1)
 		}
 		outputContent = string(jsonBytes)
+	} else if len(results) == 1 && len(remainingArgs) == 2 {
+		outputContent = diff.FormatUnified(results[0].Diff)
 	} else {
-		outputContent = result.MDFlow
+		var buf strings.Builder
+		for _, r := range results {
+			if len(r.Diff.Hunks) == 0 {
+				continue
+			}
+			// Name the files instead of FormatUnified's original/modified.
+			body := diff.FormatUnified(r.Diff)
+			body = body[strings.Index(body, "\n+++ ")+1:]
+			body = body[strings.Index(body, "\n")+1:]
+			fmt.Fprintf(&buf, "--- %s\n+++ %s\n%s", r.Before, r.After, body)
+		}
+		outputContent = buf.String()
 	}
 
 	// Write output
@@ -149,75 +690,183 @@ Examples:
 			os.Exit(1)
 		}
 		fmt.Fprintf(os.Stderr, "Written to %s\n", *output)
+	}
 
-		// Print warnings to stderr
-		for _, w := range result.Warnings {
-			fmt.Fprintf(os.Stderr, "[%s] %s\n", w.Severity, w.Message)
+	// Print summary
+	added, removed, changed := 0, 0, 0
+	for _, r := range results {
+		added += r.Diff.Added
+		removed += r.Diff.Removed
+		if r.Diff.Added+r.Diff.Removed > 0 {
+			changed++
 		}
 	}
+	if len(results) > 1 {
+		fmt.Fprintf(os.Stderr, "Changes: +%d -%d lines in %d of %d files\n", added, removed, changed, len(results))
+	} else {
+		fmt.Fprintf(os.Stderr, "Changes: +%d -%d lines\n", added, removed)
+	}
+	if *exitCode && changed > 0 {
+		os.Exit(1)
+	}
 }
 
-func runDiff(args []string) {
-	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+// fileDiff is one compared pair for mdflow diff.
+type fileDiff struct {
+	Before string            `json:"before"`
+	After  string            `json:"after"`
+	Diff   *diff.UnifiedDiff `json:"diff"`
+}
+
+// diffConversions converts inputs (or every project input) with their
+// project settings and compares each result with its current output.
+func diffConversions(fs *flag.FlagSet, args []string, against, configPath string) ([]fileDiff, error) {
+	if against != "" && len(args) != 1 {
+		return nil, fmt.Errorf("--against needs exactly one input")
+	}
+	proj, err := loadProject(fs, configPath)
+	if err != nil {
+		return nil, err
+	}
+	if proj == nil && against == "" {
+		return nil, fmt.Errorf("two files are required (or one input with --against, or a %s project)", projectconfig.FileNames[0])
+	}
+
+	specCfg := config.LoadSpecValidationConfig()
+	base := batchOptions{
+		convertOptions: convertOptions{
+			template: "spec",
+			thresholds: converter.QualityThresholds{
+				StrictMode:          specCfg.SpecStrictMode,
+				MinHeaderConfidence: specCfg.SpecMinHeaderConfidence,
+				MaxRowLossRatio:     specCfg.SpecMaxRowLossRatio,
+			},
+		},
+		project: proj,
+	}
+	var files []batchFile
+	if against != "" {
+		files = []batchFile{{input: args[0], output: against}}
+	} else {
+		proj.layout = true
+		base.outDir = proj.cfg.Dir
+		if files, err = base.expand(args); err != nil {
+			return nil, err
+		}
+	}
+
+	var results []fileDiff
+	for _, f := range files {
+		opts, err := base.fileOptions(f.input)
+		if err != nil {
+			return nil, err
+		}
+		converted, err := convertFile(f.input, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.input, err)
+		}
+		outPath := filepath.Join(base.outDir, f.output)
+		before, err := os.ReadFile(outPath)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		results = append(results, fileDiff{Before: outPath, After: f.input, Diff: diff.Diff(string(before), converted.output)})
+	}
+	return results, nil
+}
+
+func runTrace(args []string) {
+	fs := flag.NewFlagSet("trace", flag.ExitOnError)
+	input := fs.String("input", "", "Workbook holding both sheets")
+	reqPath := fs.String("requirements", "", "Requirements file (TSV/CSV/XLSX)")
+	testPath := fs.String("tests", "", "Test cases file (TSV/CSV/XLSX)")
+	reqSheet := fs.String("requirements-sheet", "", "Requirements sheet name (XLSX)")
+	testSheet := fs.String("tests-sheet", "", "Test cases sheet name (XLSX)")
+	refFields := fs.String("reference-fields", "", "Comma-separated test columns holding requirement IDs")
+	threshold := fs.Float64("threshold", traceability.DefaultFuzzyThreshold, "Minimum title similarity for fuzzy links (0-1)")
+	noFuzzy := fs.Bool("no-fuzzy", false, "Link by reference columns only")
 	output := fs.String("output", "", "Output file path (default: stdout)")
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
 
 	fs.Usage = func() {
-		fmt.Println(`Compare two MDFlow files
+		fmt.Println(`Link test cases to requirements and report coverage
 
 Usage:
-  mdflow diff <before-file> <after-file> [options]
+  mdflow trace --requirements <file> --tests <file> [options]
+  mdflow trace --input <workbook.xlsx> --requirements-sheet <name> --tests-sheet <name> [options]
 
 Options:
-  --output    Output file path (default: stdout)
-  --json      Output as JSON
+  --input               Workbook holding both sheets
+  --requirements        Requirements file (TSV/CSV/XLSX)
+  --tests               Test cases file (TSV/CSV/XLSX)
+  --requirements-sheet  Requirements sheet name (XLSX)
+  --tests-sheet         Test cases sheet name (XLSX)
+  --reference-fields    Comma-separated test columns holding requirement IDs
+                        (default: columns named like "Requirement", "Covers", "Ref")
+  --threshold           Minimum title similarity for fuzzy links (default: 0.5)
+  --no-fuzzy            Link by reference columns only
+  --output              Output file path (default: stdout)
+  --json                Output as JSON
 
 Examples:
-  mdflow diff old.md new.md
-  mdflow diff spec-v1.mdflow.md spec-v2.mdflow.md --json`)
+  mdflow trace --requirements reqs.tsv --tests tests.tsv
+  mdflow trace --input book.xlsx --requirements-sheet Requirements --tests-sheet Tests --json
+  mdflow trace --requirements reqs.csv --tests tests.csv --reference-fields "Req ID" --no-fuzzy`)
 	}
 
 	if err := fs.Parse(args); err != nil {
 		os.Exit(1)
 	}
 
-	remainingArgs := fs.Args()
-	if len(remainingArgs) < 2 {
-		fmt.Fprintln(os.Stderr, "Error: two files are required")
+	if *input != "" {
+		if *reqPath == "" {
+			*reqPath = *input
+		}
+		if *testPath == "" {
+			*testPath = *input
+		}
+	}
+	if *reqPath == "" || *testPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: --requirements and --tests (or --input with sheet names) are required")
 		fs.Usage()
 		os.Exit(1)
 	}
+	if *threshold < 0 || *threshold > 1 {
+		fmt.Fprintln(os.Stderr, "Error: --threshold must be between 0 and 1")
+		os.Exit(1)
+	}
 
-	beforePath := remainingArgs[0]
-	afterPath := remainingArgs[1]
-
-	before, err := os.ReadFile(beforePath)
+	reqDoc, err := loadSpecDoc(*reqPath, *reqSheet)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading before file: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error reading requirements: %v\n", err)
 		os.Exit(1)
 	}
-
-	after, err := os.ReadFile(afterPath)
+	testDoc, err := loadSpecDoc(*testPath, *testSheet)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading after file: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error reading tests: %v\n", err)
 		os.Exit(1)
 	}
 
-	result := diff.Diff(string(before), string(after))
+	opts := traceability.Options{FuzzyThreshold: *threshold, DisableFuzzy: *noFuzzy}
+	for _, field := range strings.Split(*refFields, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			opts.ReferenceFields = append(opts.ReferenceFields, field)
+		}
+	}
+	matrix := traceability.Build(reqDoc, testDoc, opts)
 
 	var outputContent string
 	if *jsonOutput {
-		jsonBytes, jsonErr := json.MarshalIndent(result, "", "  ")
+		jsonBytes, jsonErr := json.MarshalIndent(matrix, "", "  ")
 		if jsonErr != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", jsonErr)
 			os.Exit(1)
 		}
 		outputContent = string(jsonBytes)
 	} else {
-		outputContent = diff.FormatUnified(result)
+		outputContent = traceability.FormatMarkdown(matrix)
 	}
 
-	// Write output
 	if *output == "" {
 		fmt.Print(outputContent)
 	} else {
@@ -228,8 +877,28 @@ Examples:
 		fmt.Fprintf(os.Stderr, "Written to %s\n", *output)
 	}
 
-	// Print summary
-	fmt.Fprintf(os.Stderr, "Changes: +%d -%d lines\n", result.Added, result.Removed)
+	s := matrix.Summary
+	fmt.Fprintf(os.Stderr, "Coverage: %d/%d requirements, %d orphan tests\n", s.Covered, s.Requirements, s.OrphanTests)
+}
+
+// loadSpecDoc parses a sheet file into a SpecDoc; sheet selects the workbook sheet.
+func loadSpecDoc(path, sheet string) (*converter.SpecDoc, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if slices.Contains(converter.SpreadsheetExtensions, ext) {
+		matrix, _, merges, err := converter.NewConverter().ParseXLSXSheetWithMerges(path, sheet)
+		if err != nil {
+			return nil, err
+		}
+		return converter.BuildSpecDocFromMatrixWithMerges(matrix, merges), nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if ext == ".md" {
+		return converter.BuildSpecDocFromPaste(string(content))
+	}
+	return converter.BuildSpecDocFromTable(string(content))
 }
 
 func runTemplates() {
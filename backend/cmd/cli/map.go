@@ -0,0 +1,429 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/yourorg/md-spec-tool/internal/ai"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	"github.com/yourorg/md-spec-tool/internal/projectconfig"
+)
+
+const (
+	mapSampleRows   = 3
+	mapCellWidth    = 24
+	mapPreviewLines = 30
+)
+
+func runMap(args []string) {
+	fs := flag.NewFlagSet("map", flag.ExitOnError)
+	sheet := fs.String("sheet", "", "Sheet name (for XLSX files)")
+	sheetRange := fs.String("range", "", "Cell range to read, e.g. A3:H200")
+	template := fs.String("template", "spec", "Template name")
+	format := fs.String("format", "", "Output format: spec or table")
+	configPath := fs.String("config", "", "Project config to save to (default: nearest "+projectconfig.FileNames[0]+")")
+	var aiOpts aiFlags
+	aiOpts.register(fs, true)
+
+	fs.Usage = func() {
+		fmt.Println(`Review and fix how a file's columns map to spec fields, then save the
+choices as column overrides in the project config
+
+Usage:
+  mdflow map [options] <file>
+
+Shows the detected header row, sample rows and each column's field (with AI
+confidence and alternatives when --ai is on). At the map> prompt:
+
+  <col> <field>   map column <col> (number) to a field, e.g. "3 priority"
+  <col> auto      drop the override for a column
+  h <row>         use source row <row> as the header row
+  p [lines]       preview the rendered output
+  f               list the fields
+  s               save to the project config and quit
+  q               quit without saving
+
+Options:
+  --sheet     Sheet name for XLSX files
+  --range     Cell range to read, e.g. A3:H200
+  --template  Template name (default: "spec")
+  --format    Output format: spec or table
+  --config    Project config to save to (default: the nearest .mdflow.yaml,
+              or a new one in the current directory)
+  --ai        Map columns with AI and show per-column confidence
+` + aiUsage + `
+
+Examples:
+  mdflow map requirements.xlsx
+  mdflow map --sheet Tests --ai book.xlsx`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Error: one input file is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	proj, err := loadProject(fs, *configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	path := fs.Arg(0)
+
+	svc, err := aiOpts.service(proj)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	opts, err := proj.options(path, convertOptions{template: *template, format: *format, sheet: *sheet, sheetRange: *sheetRange})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	opts.rules = nil
+	if svc != nil {
+		defer svc.Close()
+		opts.ai = svc
+	}
+
+	session := &mapSession{
+		path:   path,
+		opts:   opts,
+		config: projectconfig.FileNames[0],
+		in:     bufio.NewScanner(os.Stdin),
+		out:    os.Stdout,
+		edits:  map[string]string{},
+	}
+	switch {
+	case *configPath != "":
+		session.config = *configPath
+	case proj != nil:
+		session.config = proj.cfg.Path
+	}
+	if proj != nil && proj.set["sheet"] && *sheet != proj.cfg.SettingsFor(path).Sheet {
+		session.saveSheet = true
+	} else if proj == nil && *sheet != "" {
+		session.saveSheet = true
+	}
+
+	if err := session.run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	printAISpend(os.Stderr, svc)
+}
+
+// mapSession is one interactive mapping review.
+type mapSession struct {
+	path      string
+	opts      convertOptions
+	config    string // where the choices are saved
+	in        *bufio.Scanner
+	out       io.Writer
+	edits     map[string]string // header -> field chosen here; "" drops the override
+	saveSheet bool              // the sheet was picked on the command line
+	saveRange bool              // the header row was changed
+
+	file    *convertedFile
+	headers []string
+	aiMap   *ai.ColumnMappingResult // per-column AI detail, when AI mapped the file
+}
+
+func (s *mapSession) run() error {
+	if err := s.refresh(); err != nil {
+		return err
+	}
+	s.show()
+	for {
+		fmt.Fprint(s.out, "map> ")
+		if !s.in.Scan() {
+			fmt.Fprintln(s.out)
+			return s.in.Err()
+		}
+		words := strings.Fields(s.in.Text())
+		if len(words) == 0 {
+			continue
+		}
+		switch cmd := strings.ToLower(words[0]); cmd {
+		case "q", "quit", "exit":
+			if n := s.pending(); n > 0 {
+				fmt.Fprintf(s.out, "Discarded %d change(s).\n", n)
+			}
+			return nil
+		case "s", "save":
+			return s.save()
+		case "?", "help":
+			fmt.Fprintln(s.out, "Commands: <col> <field>, <col> auto, h <row>, p [lines], f, s (save), q (quit)")
+		case "f", "fields":
+			fmt.Fprintln(s.out, strings.Join(converter.CanonicalFieldNames(), " "))
+		case "p", "preview":
+			lines := mapPreviewLines
+			if len(words) > 1 {
+				if n, err := strconv.Atoi(words[1]); err == nil && n > 0 {
+					lines = n
+				}
+			}
+			s.preview(lines)
+		case "h", "header":
+			if len(words) != 2 {
+				fmt.Fprintln(s.out, "Usage: h <row>")
+				continue
+			}
+			if err := s.setHeaderRow(words[1]); err != nil {
+				fmt.Fprintf(s.out, "Error: %v\n", err)
+			}
+		default:
+			if len(words) != 2 {
+				fmt.Fprintln(s.out, `Unknown command; type "?" for help`)
+				continue
+			}
+			if err := s.assign(words[0], words[1]); err != nil {
+				fmt.Fprintf(s.out, "Error: %v\n", err)
+			}
+		}
+	}
+}
+
+// overrides merges the session's edits into the configured overrides.
+func (s *mapSession) overrides() map[string]string {
+	merged := map[string]string{}
+	for header, field := range s.opts.overrides {
+		merged[header] = field
+	}
+	for header, field := range s.edits {
+		if field == "" {
+			delete(merged, header)
+		} else {
+			merged[header] = field
+		}
+	}
+	return merged
+}
+
+// refresh reconverts the file with the current choices.
+func (s *mapSession) refresh() error {
+	opts := s.opts
+	opts.overrides = s.overrides()
+	file, err := convertFile(s.path, opts)
+	if err != nil {
+		return err
+	}
+	if len(file.matrix) == 0 {
+		return fmt.Errorf("%s has no table to map", s.path)
+	}
+	s.file = file
+	s.headers = file.result.Meta.Headers(file.matrix)
+
+	s.aiMap = nil
+	if file.result.Meta.AIUsed {
+		// The conversion sent the same request, so this is a cache hit.
+		overridden := converter.ApplyColumnOverrides(file.matrix, opts.overrides)
+		headerRow := file.result.Meta.HeaderRow
+		result, err := converter.NewConverter().WithAIService(opts.ai).MapColumnsWithAI(context.Background(),
+			file.result.Meta.Headers(overridden), file.result.Meta.Band(), overridden.SliceRows(headerRow+1, overridden.RowCount()))
+		if err == nil {
+			s.aiMap = result
+		}
+	}
+	return nil
+}
+
+// headerSourceRow is the sheet row or text line of the header.
+func (s *mapSession) headerSourceRow() int {
+	idx := s.file.result.Meta.HeaderRow
+	if idx < len(s.file.sourceRows) {
+		return s.file.sourceRows[idx]
+	}
+	return idx + 1
+}
+
+func (s *mapSession) show() {
+	meta := s.file.result.Meta
+	where := s.path
+	if meta.SheetName != "" {
+		where += " (sheet " + meta.SheetName + ")"
+	}
+	confidence := ""
+	if q := meta.QualityReport; q != nil {
+		confidence = fmt.Sprintf(", confidence %d%%", min(q.HeaderConfidence, 100))
+	}
+	fmt.Fprintf(s.out, "%s: header row %d%s, %d data rows\n", where, s.headerSourceRow(), confidence, meta.TotalRows)
+	if summary := aiSummary(meta); summary != "" {
+		fmt.Fprintln(s.out, summary)
+	}
+
+	fmt.Fprintln(s.out, "\nSample rows:")
+	headerRow := meta.HeaderRow
+	for i := headerRow + 1; i < len(s.file.matrix) && i <= headerRow+mapSampleRows; i++ {
+		row := make([]string, len(s.file.matrix[i]))
+		for j, cell := range s.file.matrix[i] {
+			row[j] = clip(cell, mapCellWidth)
+		}
+		line := i + 1
+		if i < len(s.file.sourceRows) {
+			line = s.file.sourceRows[i]
+		}
+		fmt.Fprintf(s.out, "  %4d  %s\n", line, strings.Join(row, " | "))
+	}
+
+	fields := map[int]string{}
+	for field, idx := range meta.ColumnMap {
+		fields[idx] = string(field)
+	}
+	aiByColumn := map[int]ai.CanonicalFieldMapping{}
+	if s.aiMap != nil {
+		for _, m := range s.aiMap.CanonicalFields {
+			aiByColumn[m.ColumnIndex] = m
+		}
+	}
+
+	fmt.Fprintln(s.out, "\nColumns:")
+	tw := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+	for i, header := range s.headers {
+		field, ok := fields[i]
+		if !ok {
+			field = "(unmapped)"
+		}
+		if _, edited := s.edits[strings.TrimSpace(header)]; edited {
+			field += " *"
+		}
+		detail := ""
+		if m, ok := aiByColumn[i]; ok {
+			detail = fmt.Sprintf("AI %.0f%%", m.Confidence*100)
+			var alts []string
+			for _, alt := range m.Alternatives {
+				alts = append(alts, fmt.Sprintf("%s %.0f%%", alt.SourceHeader, alt.Confidence*100))
+			}
+			if len(alts) > 0 {
+				detail += "  alt: " + strings.Join(alts, ", ")
+			}
+		}
+		if detail != "" {
+			field += "\t" + detail
+		}
+		fmt.Fprintf(tw, "  %d\t%s\t%s\n", i+1, clip(header, mapCellWidth), field)
+	}
+	tw.Flush()
+	if n := s.pending(); n > 0 {
+		fmt.Fprintf(s.out, "%d unsaved change(s) (*); \"s\" saves them to %s\n", n, s.config)
+	}
+	fmt.Fprintln(s.out)
+}
+
+func (s *mapSession) assign(col, field string) error {
+	n, err := strconv.Atoi(col)
+	if err != nil || n < 1 || n > len(s.headers) {
+		return fmt.Errorf("column must be 1-%d", len(s.headers))
+	}
+	header := strings.TrimSpace(s.headers[n-1])
+	if header == "" {
+		return fmt.Errorf("column %d has no header to override", n)
+	}
+	if field == "auto" {
+		field = ""
+	} else if !converter.IsCanonicalField(field) {
+		return fmt.Errorf("unknown field %q; \"f\" lists them", field)
+	}
+	if field == "" {
+		if _, ok := s.opts.overrides[header]; !ok {
+			delete(s.edits, header) // nothing configured to drop
+		} else {
+			s.edits[header] = ""
+		}
+	} else {
+		s.edits[header] = field
+	}
+	if err := s.refresh(); err != nil {
+		return err
+	}
+	s.show()
+	return nil
+}
+
+// setHeaderRow moves the start of the range to row, so detection starts at
+// the chosen header.
+func (s *mapSession) setHeaderRow(arg string) error {
+	row, err := strconv.Atoi(arg)
+	if err != nil || row < 1 {
+		return fmt.Errorf("row must be a positive number")
+	}
+	r := converter.A1Range{StartCol: 1}
+	if s.opts.sheetRange != "" {
+		if r, err = converter.ParseA1Range(s.opts.sheetRange); err != nil {
+			return err
+		}
+	}
+	if r.EndRow > 0 && row > r.EndRow {
+		return fmt.Errorf("row %d is past the end of the range %s", row, s.opts.sheetRange)
+	}
+	r.StartRow = row
+
+	prev := s.opts.sheetRange
+	s.opts.sheetRange = r.String()
+	if err := s.refresh(); err != nil {
+		s.opts.sheetRange = prev
+		return err
+	}
+	s.saveRange = true
+	s.show()
+	if got := s.headerSourceRow(); got != row {
+		fmt.Fprintf(s.out, "Note: header detection picked row %d within %s\n", got, s.opts.sheetRange)
+	}
+	return nil
+}
+
+func (s *mapSession) preview(lines int) {
+	all := strings.Split(strings.TrimRight(s.file.output, "\n"), "\n")
+	if len(all) > lines {
+		fmt.Fprintln(s.out, strings.Join(all[:lines], "\n"))
+		fmt.Fprintf(s.out, "... %d more lines\n", len(all)-lines)
+		return
+	}
+	fmt.Fprintln(s.out, strings.Join(all, "\n"))
+}
+
+func (s *mapSession) pending() int {
+	n := len(s.edits)
+	if s.saveRange {
+		n++
+	}
+	return n
+}
+
+func (s *mapSession) save() error {
+	if s.pending() == 0 && !s.saveSheet {
+		fmt.Fprintln(s.out, "Nothing to save.")
+		return nil
+	}
+	edit := projectconfig.InputEdit{ColumnOverrides: s.edits}
+	if s.saveSheet {
+		edit.Sheet = &s.opts.sheet
+	}
+	if s.saveRange {
+		edit.Range = &s.opts.sheetRange
+	}
+	if err := projectconfig.EditInput(s.config, s.path, edit); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Saved %d change(s) for %s to %s\n", s.pending(), s.path, s.config)
+	return nil
+}
+
+// clip shortens s to at most n runes for table display.
+func clip(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/md-spec-tool/internal/projectconfig"
+)
+
+func TestMapSession_SavesOverrides(t *testing.T) {
+	root := t.TempDir()
+	input := filepath.Join(root, "spec.tsv")
+	writeFile(t, input, "Export 2024\t\t\nReq No\tSummary\tOwner note\nREQ-1\tLogin\tbob\nREQ-2\tLogout\tann\n")
+	config := filepath.Join(root, ".mdflow.yaml")
+
+	var out bytes.Buffer
+	s := &mapSession{
+		path:   input,
+		opts:   convertOptions{template: "spec"},
+		config: config,
+		in:     bufio.NewScanner(strings.NewReader("1 id\n3 notes\n2 nonsense\nh 2\ns\n")),
+		out:    &out,
+		edits:  map[string]string{},
+	}
+	if err := s.run(); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Owner note", "(unmapped)", `unknown field "nonsense"`, "header row 2", "Saved 3 change(s)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	cfg, err := projectconfig.Load(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := cfg.SettingsFor(input)
+	if got.ColumnOverrides["Req No"] != "id" || got.ColumnOverrides["Owner note"] != "notes" || got.Range != "A2" {
+		t.Fatalf("saved settings = %+v", got)
+	}
+
+	// The saved overrides drive a plain convert.
+	converted, err := convertFile(input, convertOptions{template: "spec", overrides: got.ColumnOverrides, sheetRange: got.Range})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := converted.result.Meta.ColumnMap["id"]; !ok {
+		t.Errorf("column map = %v", converted.result.Meta.ColumnMap)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	"github.com/yourorg/md-spec-tool/internal/projectconfig"
+)
+
+// project is the .mdflow.yaml a command runs under.
+type project struct {
+	cfg    *projectconfig.Config
+	set    map[string]bool // flags given on the command line; they win over the config
+	layout bool            // write outputs where the config says rather than under --out-dir
+
+	mu    sync.Mutex
+	rules map[string]loadedRules // by joined rule file list
+}
+
+type loadedRules struct {
+	rules *converter.ValidationRules
+	hash  string
+}
+
+// loadProject loads the config named by --config, or the nearest
+// .mdflow.yaml from the working directory upwards. It returns nil when there
+// is none. Call it after fs.Parse so explicit flags can be told apart.
+func loadProject(fs *flag.FlagSet, path string) (*project, error) {
+	var cfg *projectconfig.Config
+	var err error
+	if path != "" {
+		cfg, err = projectconfig.Load(path)
+	} else {
+		cfg, err = projectconfig.Discover(".")
+	}
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "Using %s\n", cfg.Path)
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return &project{cfg: cfg, set: set, rules: map[string]loadedRules{}}, nil
+}
+
+// options applies the config's settings for file on top of opts. A nil
+// project returns opts unchanged.
+func (p *project) options(file string, opts convertOptions) (convertOptions, error) {
+	if p == nil {
+		return opts, nil
+	}
+	s := p.cfg.SettingsFor(file)
+	pick := func(name string, dst *string, value string) {
+		if value != "" && !p.set[name] {
+			*dst = value
+		}
+	}
+	pick("template", &opts.template, s.Template)
+	pick("format", &opts.format, s.Format)
+	pick("sheet", &opts.sheet, s.Sheet)
+	pick("range", &opts.sheetRange, s.Range)
+	if len(s.ColumnOverrides) > 0 {
+		opts.overrides = s.ColumnOverrides
+	}
+	opts.synonyms = p.cfg.Synonyms
+	if len(s.Rules) > 0 && !p.set["rules"] {
+		rules, err := p.loadRules(s.Rules)
+		if err != nil {
+			return opts, err
+		}
+		opts.rules, opts.rulesHash = rules.rules, rules.hash
+	}
+	return opts, nil
+}
+
+func (p *project) loadRules(paths []string) (loadedRules, error) {
+	key := strings.Join(paths, "\x00")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if loaded, ok := p.rules[key]; ok {
+		return loaded, nil
+	}
+	rules, hash, err := loadRulesFiles(paths)
+	if err != nil {
+		return loadedRules{}, err
+	}
+	p.rules[key] = loadedRules{rules: rules, hash: hash}
+	return p.rules[key], nil
+}
+
+// inputs returns args, or the config's inputs when there are none.
+func (p *project) inputs(args []string) []string {
+	if len(args) > 0 || p == nil {
+		return args
+	}
+	return p.cfg.Patterns()
+}
+
+// expand resolves inputs like expandInputs, but places each output where the
+// config says, relative to the config directory. Files the config has no
+// output for are written beside their input.
+func (p *project) expand(args []string, jsonOutput bool) ([]batchFile, error) {
+	ext := ".mdflow.md"
+	if jsonOutput {
+		ext = ".json"
+	}
+	root, err := filepath.Abs(p.cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []batchFile
+	seen := map[string]string{} // output -> input
+	for _, arg := range p.inputs(args) {
+		matches, _, err := expandPattern(arg)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matches {
+			output := p.cfg.OutputFor(path, ext)
+			if output == "" {
+				output = strings.TrimSuffix(path, filepath.Ext(path)) + ext
+			}
+			abs, err := filepath.Abs(output)
+			if err != nil {
+				return nil, err
+			}
+			rel, err := filepath.Rel(root, abs)
+			if err != nil {
+				return nil, err
+			}
+			if prev, ok := seen[rel]; ok {
+				if prev == path {
+					continue
+				}
+				return nil, fmt.Errorf("%s and %s both convert to %s", prev, path, output)
+			}
+			seen[rel] = path
+			files = append(files, batchFile{input: path, output: rel})
+		}
+	}
+	return files, nil
+}
+
+// loadRulesFiles reads and merges rule files in order, returning a hash of
+// their content so batch and watch runs notice rule edits.
+func loadRulesFiles(paths []string) (*converter.ValidationRules, string, error) {
+	var rules *converter.ValidationRules
+	var sums []byte
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("loading rules: %w", err)
+		}
+		loaded, err := converter.ParseValidationRules(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("loading rules: %s: %w", path, err)
+		}
+		rules = rules.Merge(loaded)
+		sums = append(sums, hashBytes(data)...)
+	}
+	if sums == nil {
+		return nil, "", nil
+	}
+	return rules, hashBytes(sums), nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/md-spec-tool/internal/projectconfig"
+)
+
+func TestProject_ConvertsWithConfigSettings(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "sheets", "main.tsv"), "Exported by tool\n\nTicket\tSummary\tPrio\nREQ-1\tLogin\tHigh\nREQ-2\tLogout\tLow\nREQ-9\tOut of range\tLow\n")
+	writeFile(t, filepath.Join(root, "rules.yaml"), "required_fields: [owner]\n")
+	writeFile(t, filepath.Join(root, projectconfig.FileNames[0]), `
+out_dir: specs
+synonyms:
+  id: [Ticket]
+  title: [Summary]
+column_overrides:
+  Prio: priority
+inputs:
+  - path: sheets/main.tsv
+    output: docs/main.mdflow.md
+    range: A3:C5
+    rules: [rules.yaml]
+`)
+	cfg, err := projectconfig.Load(filepath.Join(root, projectconfig.FileNames[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proj := &project{cfg: cfg, set: map[string]bool{}, layout: true, rules: map[string]loadedRules{}}
+	opts := batchOptions{convertOptions: convertOptions{template: "spec"}, outDir: cfg.Dir, jobs: 1, project: proj}
+
+	files, err := opts.expand(nil)
+	if err != nil || len(files) != 1 || files[0].output != filepath.Join("docs", "main.mdflow.md") {
+		t.Fatalf("expand = %+v, %v", files, err)
+	}
+	res := convertBatchFile(files[0], opts, manifestEntry{})
+	if res.err != nil || res.entry.Rows != 2 {
+		t.Fatalf("convert = %+v", res)
+	}
+	out, err := os.ReadFile(filepath.Join(root, "docs", "main.mdflow.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := string(out)
+	for _, want := range []string{"REQ-1: Login", "| priority | High |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("output missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "REQ-9") {
+		t.Errorf("row outside the range was converted:\n%s", md)
+	}
+	if res.entry.Warnings == 0 {
+		t.Error("project rules did not run")
+	}
+
+	// A flag given on the command line wins over the config.
+	proj.set["range"] = true
+	fileOpts, err := opts.fileOptions(files[0].input)
+	if err != nil || fileOpts.sheetRange != "" || fileOpts.rules == nil {
+		t.Fatalf("fileOptions = %+v, %v", fileOpts, err)
+	}
+}
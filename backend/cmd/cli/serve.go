@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourorg/md-spec-tool/internal/config"
+	httphandler "github.com/yourorg/md-spec-tool/internal/http"
+	"github.com/yourorg/md-spec-tool/internal/http/middleware"
+	"github.com/yourorg/md-spec-tool/internal/share"
+	"github.com/yourorg/md-spec-tool/internal/tracing"
+)
+
+// serveLocalHost is the default listen address: a review server on a laptop
+// should not be reachable from the network unless asked.
+const serveLocalHost = "127.0.0.1"
+
+// serveOptions are the serve flags that map onto config.Config. Empty values
+// keep the setting from the environment.
+type serveOptions struct {
+	host     string
+	port     string
+	cors     string
+	dataDir  string
+	apiKey   string
+	model    string
+	noAI     bool
+	readOnly string
+}
+
+// apply maps the flags onto cfg, which was loaded from the environment.
+func (o serveOptions) apply(cfg *config.Config) {
+	switch {
+	case o.host != "":
+		cfg.Host = o.host
+	case os.Getenv("HOST") == "":
+		cfg.Host = serveLocalHost
+	}
+	if o.port != "" {
+		cfg.Port = o.port
+	}
+	if o.cors != "" {
+		var origins []string
+		for _, origin := range strings.Split(o.cors, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				origins = append(origins, origin)
+			}
+		}
+		cfg.CORSOrigins = origins
+	}
+	if o.dataDir != "" {
+		cfg.ShareStorePath = filepath.Join(o.dataDir, "shares.json")
+		cfg.WatchDBPath = filepath.Join(o.dataDir, "watches.db")
+		cfg.FeedbackDBPath = filepath.Join(o.dataDir, "feedback.db")
+		cfg.WebhookDBPath = filepath.Join(o.dataDir, "webhooks.db")
+		cfg.TelemetryDBPath = filepath.Join(o.dataDir, "telemetry.db")
+	}
+	if o.apiKey != "" {
+		cfg.OpenAIAPIKey = o.apiKey
+		cfg.AIEnabled = true
+	}
+	if o.model != "" {
+		cfg.OpenAIModel = o.model
+	}
+	if o.noAI {
+		cfg.AIEnabled = false
+	}
+}
+
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	var opts serveOptions
+	fs.StringVar(&opts.host, "host", "", "Address to listen on (default: $HOST or "+serveLocalHost+")")
+	fs.StringVar(&opts.port, "port", "", "Port to listen on (default: $PORT or "+config.DefaultPort+")")
+	fs.StringVar(&opts.cors, "cors", "", "Comma-separated allowed origins (default: $CORS_ORIGINS)")
+	fs.StringVar(&opts.dataDir, "data-dir", "", "Directory for shares, watches, feedback, webhooks and telemetry")
+	fs.StringVar(&opts.apiKey, "api-key", "", "OpenAI API key for server-side AI (default: $OPENAI_API_KEY)")
+	fs.StringVar(&opts.model, "model", "", "OpenAI model (default: $OPENAI_MODEL or "+config.DefaultOpenAIModel+")")
+	fs.BoolVar(&opts.noAI, "no-ai", false, "Disable server-side AI even when a key is set")
+	fs.StringVar(&opts.readOnly, "read-only", "", "Serve the .mdflow.md files under this directory as read-only shares")
+
+	fs.Usage = func() {
+		fmt.Println(`Run the MDFlow API server, or serve a directory of specs read-only
+
+Usage:
+  mdflow serve [options]
+  mdflow serve --read-only <dir> [options]
+
+The full server is the same API as the standalone server, configured by the
+usual environment variables (see the README); flags override them.
+
+With --read-only, every *.mdflow.md file under <dir> is served as a public,
+view-only share (GET /api/share/public, GET /api/share/<slug>), where the
+slug is the file's path without the extension, e.g. specs/login.mdflow.md is
+specs-login. Files are re-read on each request. Nothing is converted or stored.
+
+Options:
+  --host       Address to listen on (default: $HOST or 127.0.0.1)
+  --port       Port to listen on (default: $PORT or 8080)
+  --cors       Comma-separated allowed origins (default: $CORS_ORIGINS)
+  --data-dir   Directory for shares.json, watches.db, feedback.db, webhooks.db
+               and telemetry.db (default: the *_PATH variables)
+  --api-key    OpenAI API key for server-side AI (default: $OPENAI_API_KEY)
+  --model      OpenAI model (default: $OPENAI_MODEL or gpt-4o-mini)
+  --no-ai      Disable server-side AI even when a key is set
+  --read-only  Serve the .mdflow.md files under this directory as shares
+
+Examples:
+  mdflow serve --port 9000 --data-dir ~/.local/share/mdflow
+  mdflow serve --read-only specs/`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n", fs.Arg(0))
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	cfg := config.LoadConfig()
+	opts.apply(cfg)
+	if err := config.ValidateConfig(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if os.Getenv(gin.EnvGinMode) == "" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	var handler http.Handler
+	cleanup := func(context.Context) {}
+	addr := fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)
+	if opts.readOnly != "" {
+		store, err := share.NewDirStore(opts.readOnly)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		handler = httphandler.SetupShareRouter(cfg, store)
+		fmt.Fprintf(os.Stderr, "Serving %d spec(s) from %s read-only at http://%s/api/share/public\n",
+			len(store.ListPublic()), opts.readOnly, addr)
+	} else {
+		if opts.dataDir != "" {
+			if err := os.MkdirAll(opts.dataDir, 0755); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		middleware.SetMaxTelemetryEvents(cfg.TelemetryMaxEvents)
+		shutdownTracing, err := tracing.Setup(tracing.Config{
+			Exporter:    cfg.TracingExporter,
+			Endpoint:    cfg.TracingEndpoint,
+			ServiceName: cfg.TracingServiceName,
+			SampleRatio: &cfg.TracingSampleRatio,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: tracing setup failed: %v\n", err)
+			os.Exit(1)
+		}
+		router, closeRouter := httphandler.SetupRouterWithCleanup(cfg)
+		handler = router
+		cleanup = func(ctx context.Context) {
+			closeRouter()
+			if err := shutdownTracing(ctx); err != nil {
+				slog.Warn("Tracing shutdown error", "err", err)
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Serving the MDFlow API at http://%s (AI %s)\n", addr, onOff(cfg.AIEnabled))
+	}
+
+	server := &http.Server{
+		Addr:           addr,
+		Handler:        handler,
+		ReadTimeout:    30 * time.Second,
+		WriteTimeout:   180 * time.Second,
+		IdleTimeout:    120 * time.Second,
+		MaxHeaderBytes: 1 << 20, // 1MB
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			cleanup(context.Background())
+			os.Exit(1)
+		}
+	case <-sigChan:
+	}
+
+	fmt.Fprintln(os.Stderr, "Shutting down...")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: shutdown: %v\n", err)
+	}
+	cleanup(ctx)
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
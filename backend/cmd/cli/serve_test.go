@@ -0,0 +1,44 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yourorg/md-spec-tool/internal/config"
+)
+
+func TestServeOptionsApply(t *testing.T) {
+	t.Setenv("HOST", "")
+	t.Setenv("OPENAI_API_KEY", "")
+
+	cfg := config.LoadConfig()
+	serveOptions{}.apply(cfg)
+	if cfg.Host != serveLocalHost || cfg.AIEnabled {
+		t.Fatalf("defaults: host=%s ai=%v", cfg.Host, cfg.AIEnabled)
+	}
+
+	cfg = config.LoadConfig()
+	serveOptions{
+		host:    "0.0.0.0",
+		port:    "9000",
+		cors:    "http://a.test, http://b.test",
+		dataDir: "data",
+		apiKey:  "sk-test",
+		model:   "gpt-4o",
+	}.apply(cfg)
+	if cfg.Host != "0.0.0.0" || cfg.Port != "9000" || !cfg.AIEnabled || cfg.OpenAIAPIKey != "sk-test" || cfg.OpenAIModel != "gpt-4o" {
+		t.Errorf("cfg = %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.CORSOrigins, []string{"http://a.test", "http://b.test"}) {
+		t.Errorf("cors = %v", cfg.CORSOrigins)
+	}
+	if cfg.ShareStorePath != filepath.Join("data", "shares.json") || cfg.FeedbackDBPath != filepath.Join("data", "feedback.db") {
+		t.Errorf("paths: %s %s", cfg.ShareStorePath, cfg.FeedbackDBPath)
+	}
+
+	serveOptions{noAI: true}.apply(cfg)
+	if cfg.AIEnabled {
+		t.Error("--no-ai left AI enabled")
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+// stdio is the --input / --output path meaning stdin or stdout.
+const stdio = "-"
+
+// inputTypes maps --input-type values to the extension the input is read as.
+var inputTypes = map[string]string{
+	"tsv":      ".tsv",
+	"csv":      ".csv",
+	"md":       ".md",
+	"markdown": ".md",
+	"xlsx":     ".xlsx",
+	"xls":      ".xls",
+	"ods":      ".ods",
+}
+
+// inputExt returns the extension path is converted as: the one --input-type
+// names, or else its own.
+func inputExt(path, inputType string) string {
+	if ext, ok := inputTypes[strings.ToLower(inputType)]; ok {
+		return ext
+	}
+	return strings.ToLower(filepath.Ext(path))
+}
+
+// stdinFile copies r to a temporary file so stdin converts like any other
+// input; the caller removes it. Without an --input-type, workbooks are
+// recognised by their signature and anything else is text whose layout (TSV,
+// CSV or markdown) is detected from the content.
+func stdinFile(r io.Reader, inputType string) (string, error) {
+	br := bufio.NewReader(r)
+	ext, ok := inputTypes[strings.ToLower(inputType)]
+	if !ok {
+		head, _ := br.Peek(len(odsHead))
+		ext = sniffExt(head)
+	}
+	f, err := os.CreateTemp("", "mdflow-stdin-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, br); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("reading stdin: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// odsHead is how an OpenDocument spreadsheet starts: a zip whose first,
+// uncompressed entry is its mimetype.
+var odsHead = []byte("PK\x03\x04" + strings.Repeat("?", 26) + "mimetype" +
+	"application/vnd.oasis.opendocument.spreadsheet")
+
+// sniffExt names the extension of a file starting with head: .xls for an OLE2
+// compound file, .ods or .xlsx for a zip, .txt otherwise.
+func sniffExt(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("\xD0\xCF\x11\xE0")):
+		return ".xls"
+	case !bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		return ".txt"
+	case len(head) == len(odsHead) && bytes.Equal(head[30:], odsHead[30:]):
+		return ".ods"
+	}
+	return ".xlsx"
+}
+
+// outputFormats are the formats --output path:format accepts.
+var outputFormats = []string{"spec", "table", "json", "xlsx"}
+
+// outputTarget is one --output: where to write ("-" for stdout) and what. An
+// empty format writes the default output (markdown, or JSON with --json).
+type outputTarget struct {
+	path   string
+	format string
+}
+
+// outputTargets collects repeated --output flags.
+type outputTargets []outputTarget
+
+func (o *outputTargets) String() string {
+	var parts []string
+	for _, t := range *o {
+		if t.format == "" {
+			parts = append(parts, t.path)
+		} else {
+			parts = append(parts, t.path+":"+t.format)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set parses "path" or "path:format". A suffix that is not a known format
+// stays part of the path, and a .xlsx path defaults to the xlsx format.
+func (o *outputTargets) Set(value string) error {
+	target := outputTarget{path: value}
+	if i := strings.LastIndex(value, ":"); i >= 0 && slices.Contains(outputFormats, strings.ToLower(value[i+1:])) {
+		target = outputTarget{path: value[:i], format: strings.ToLower(value[i+1:])}
+	} else if strings.EqualFold(filepath.Ext(value), ".xlsx") {
+		target.format = "xlsx"
+	}
+	if target.path == "" {
+		return fmt.Errorf("missing path in %q", value)
+	}
+	*o = append(*o, target)
+	return nil
+}
+
+// validate rejects two outputs that would interleave on stdout.
+func (o outputTargets) validate() error {
+	toStdout := 0
+	for _, t := range o {
+		if t.path == stdio {
+			toStdout++
+		}
+	}
+	if toStdout > 1 {
+		return fmt.Errorf("only one --output can be %s (stdout)", stdio)
+	}
+	return nil
+}
+
+// toStdout reports whether output goes to stdout, which is the default.
+func (o outputTargets) toStdout() bool {
+	return len(o) == 0 || slices.ContainsFunc(o, func(t outputTarget) bool { return t.path == stdio })
+}
+
+// formatted renders the conversion in format (see outputTarget). Every format
+// comes from the one column mapping the conversion chose, so AI runs at most
+// once and spec, table and xlsx outputs agree.
+func (c *convertedFile) formatted(format string) ([]byte, error) {
+	switch format {
+	case "":
+		return []byte(c.output), nil
+	case "json":
+		return jsonOutput(c.result)
+	case "xlsx":
+		meta := c.result.Meta
+		if c.mapped == nil || meta.HeaderRow < 0 || meta.HeaderRow >= len(c.mapped) {
+			return nil, fmt.Errorf("xlsx output needs a table input")
+		}
+		// Name unmapped columns by their full header band, as the markdown does.
+		rows := slices.Clone(c.mapped)
+		rows[meta.HeaderRow] = meta.Headers(c.mapped)
+		table := converter.MappedTable(rows, meta.HeaderRow, meta.ColumnMap)
+		var buf bytes.Buffer
+		if err := converter.WriteXLSX(&buf, table, meta.SheetName); err != nil {
+			return nil, fmt.Errorf("writing xlsx: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+	if format == c.format {
+		return []byte(c.result.MDFlow), nil
+	}
+	if c.rendered == nil {
+		c.rendered = map[string]string{}
+	}
+	if out, ok := c.rendered[format]; ok {
+		return []byte(out), nil
+	}
+	out, err := c.rerender(format)
+	if err != nil {
+		return nil, fmt.Errorf("converting to %s: %w", format, err)
+	}
+	c.rendered[format] = out
+	return []byte(out), nil
+}
+
+// writeOutputs writes each target, reporting files written on stderr.
+func writeOutputs(c *convertedFile, targets outputTargets) error {
+	for _, t := range targets {
+		data, err := c.formatted(t.format)
+		if err != nil {
+			return err
+		}
+		if t.path == stdio {
+			if _, err := os.Stdout.Write(data); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.WriteFile(t.path, data, 0644); err != nil {
+			return fmt.Errorf("writing output file: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Written to %s\n", t.path)
+	}
+	return nil
+}
+
+// markdownTarget returns the file holding the converted markdown, if any.
+func markdownTarget(c *convertedFile, targets outputTargets, jsonDefault bool) string {
+	for _, t := range targets {
+		if t.path != stdio && (t.format == c.format || (t.format == "" && !jsonDefault)) {
+			return t.path
+		}
+	}
+	return ""
+}
+
+func jsonOutput(result *converter.ConvertResponse) ([]byte, error) {
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"mdflow":   result.MDFlow,
+		"warnings": result.Warnings,
+		"meta":     result.Meta,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding JSON: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"github.com/yourorg/md-spec-tool/internal/ai"
+)
+
+func TestOutputTargetsSet(t *testing.T) {
+	var o outputTargets
+	for _, v := range []string{"spec.md", "out/table.md:table", "-:json", "mapped.xlsx", `C:\specs\a.md:spec`, "notes:v2.md"} {
+		if err := o.Set(v); err != nil {
+			t.Fatalf("Set(%q): %v", v, err)
+		}
+	}
+	want := outputTargets{
+		{path: "spec.md"},
+		{path: "out/table.md", format: "table"},
+		{path: "-", format: "json"},
+		{path: "mapped.xlsx", format: "xlsx"},
+		{path: `C:\specs\a.md`, format: "spec"},
+		{path: "notes:v2.md"},
+	}
+	if !reflect.DeepEqual(o, want) {
+		t.Fatalf("targets = %+v, want %+v", o, want)
+	}
+	if err := o.Set(":json"); err == nil {
+		t.Error("expected an error for a missing path")
+	}
+	if err := (outputTargets{{path: "-"}, {path: "-", format: "json"}}).validate(); err == nil {
+		t.Error("expected an error for two stdout outputs")
+	}
+}
+
+func TestStdinFile(t *testing.T) {
+	var book bytes.Buffer
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]any{"ID", "Feature"})
+	if err := f.Write(&book); err != nil {
+		t.Fatal(err)
+	}
+	xls, err := os.ReadFile("../../test/converter/testdata/requirements.xls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ods, err := os.ReadFile("../../test/converter/testdata/requirements.ods")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		input, inputType, ext string
+	}{
+		{"ID\tFeature\n", "", ".txt"},
+		{"ID,Feature\n", "csv", ".csv"},
+		{"# Notes\n", "markdown", ".md"},
+		{book.String(), "", ".xlsx"},
+		{string(xls), "", ".xls"},
+		{string(ods), "", ".ods"},
+		{string(ods), "xlsx", ".xlsx"},
+	} {
+		path, err := stdinFile(strings.NewReader(tc.input), tc.inputType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := os.ReadFile(path)
+		os.Remove(path)
+		if filepath.Ext(path) != tc.ext || string(data) != tc.input {
+			t.Errorf("type %q: path %s, %d bytes; want %s extension and the input", tc.inputType, path, len(data), tc.ext)
+		}
+	}
+}
+
+func TestConvertedFileFormatted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.tsv")
+	content := "ID\tFeature\tScenario\tExpected\nTC-1\tLogin\tValid login\tDashboard shown\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	converted, err := convertFile(path, convertOptions{template: "spec"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := converted.formatted("spec")
+	if err != nil || string(spec) != converted.result.MDFlow {
+		t.Fatalf("spec output should be the conversion's markdown (err %v)", err)
+	}
+	table, err := converted.formatted("table")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(table), "| ID | Feature | Scenario | Expected |") {
+		t.Errorf("table output:\n%s", table)
+	}
+	if js, err := converted.formatted("json"); err != nil || !strings.Contains(string(js), `"mdflow"`) {
+		t.Errorf("json output = %s (err %v)", js, err)
+	}
+
+	data, err := converted.formatted("xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	book, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, _ := book.GetRows(book.GetSheetList()[0])
+	if len(rows) != 2 || rows[0][0] != "id" || rows[1][3] != "Dashboard shown" {
+		t.Errorf("xlsx rows = %q", rows)
+	}
+}
+
+func TestConvertedFileFormatted_ReusesColumnMapping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.tsv")
+	content := "Key\tArea\tSteps\tOutcome\nTC-1\tLogin\tValid login\tDashboard shown\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mock := ai.NewMockAIService()
+	mock.MapColumnsFunc = func(_ context.Context, req ai.MapColumnsRequest) (*ai.ColumnMappingResult, error) {
+		fields := []ai.CanonicalFieldMapping{}
+		for i, name := range []string{"id", "feature", "scenario", "expected"} {
+			fields = append(fields, ai.CanonicalFieldMapping{CanonicalName: name, SourceHeader: req.Headers[i], ColumnIndex: i, Confidence: 0.95})
+		}
+		return &ai.ColumnMappingResult{
+			SchemaVersion:   ai.SchemaVersionColumnMapping,
+			CanonicalFields: fields,
+			Meta:            ai.MappingMeta{TotalColumns: 4, MappedColumns: 4, AvgConfidence: 0.95},
+		}, nil
+	}
+
+	// A table conversion maps with rules; the spec output must reuse that
+	// mapping rather than convert again with AI.
+	tableFirst, err := convertFile(path, convertOptions{template: "spec", format: "table", ai: mock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := mock.CallCountFor("MapColumns")
+	if _, err := tableFirst.formatted("spec"); err != nil {
+		t.Fatal(err)
+	}
+	if n := mock.CallCountFor("MapColumns"); n != calls {
+		t.Errorf("spec output mapped columns again: %d AI calls, want %d", n, calls)
+	}
+
+	converted, err := convertFile(path, convertOptions{template: "spec", ai: mock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls = mock.CallCountFor("MapColumns")
+	for _, format := range []string{"table", "spec", "table"} {
+		if _, err := converted.formatted(format); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := mock.CallCountFor("MapColumns"); n != calls {
+		t.Errorf("extra formats mapped columns again: %d AI calls, want %d", n, calls)
+	}
+
+	data, err := converted.formatted("xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	book, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, _ := book.GetRows(book.GetSheetList()[0])
+	if len(rows) != 2 || !reflect.DeepEqual(rows[0], []string{"id", "feature", "scenario", "expected"}) {
+		t.Errorf("xlsx should carry the AI mapping, got %q", rows)
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	"github.com/yourorg/md-spec-tool/internal/projectconfig"
+	"github.com/yourorg/md-spec-tool/internal/report"
+)
+
+// Exit codes for validate and lint, so CI can tell failed checks from broken runs.
+const (
+	exitOK       = 0
+	exitFindings = 1 // findings at or above --fail-on
+	exitUsage    = 2 // bad flags or unreadable input
+)
+
+// checkOutput holds the flags validate and lint share.
+type checkOutput struct {
+	format string
+	output string
+	failOn string
+}
+
+func (o *checkOutput) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.format, "format", report.FormatText, "Output format (text|json|sarif|junit)")
+	fs.StringVar(&o.output, "output", "", "Output file path (default: stdout)")
+	fs.StringVar(&o.failOn, "fail-on", string(converter.SeverityError), "Lowest severity that fails the run (error|warn|info|none)")
+}
+
+// check validates the flag values, exiting with exitUsage on bad input.
+func (o *checkOutput) check() {
+	format, err := report.NormalizeFormat(o.format)
+	if err != nil {
+		fatalUsage(err)
+	}
+	o.format = format
+	switch o.failOn {
+	case string(converter.SeverityError), string(converter.SeverityWarn), string(converter.SeverityInfo), "none":
+	default:
+		fatalUsage(fmt.Errorf("--fail-on must be error, warn, info or none"))
+	}
+}
+
+// finish writes the report and exits with exitFindings when a finding is at
+// or above the --fail-on severity.
+func (o *checkOutput) finish(r *report.Report) {
+	var buf bytes.Buffer
+	if err := report.Write(&buf, o.format, r); err != nil {
+		fatalUsage(err)
+	}
+	if o.output == "" {
+		fmt.Print(buf.String())
+	} else {
+		if err := os.WriteFile(o.output, buf.Bytes(), 0644); err != nil {
+			fatalUsage(fmt.Errorf("writing output file: %w", err))
+		}
+		fmt.Fprintf(os.Stderr, "Written to %s\n", o.output)
+	}
+	if failsAt(r.Findings, o.failOn) {
+		os.Exit(exitFindings)
+	}
+	os.Exit(exitOK)
+}
+
+// failsAt reports whether any finding is at or above the failOn severity.
+func failsAt(findings []report.Finding, failOn string) bool {
+	rank := map[converter.WarningSeverity]int{converter.SeverityInfo: 1, converter.SeverityWarn: 2, converter.SeverityError: 3}
+	threshold, ok := rank[converter.WarningSeverity(failOn)]
+	if !ok {
+		return false
+	}
+	for _, f := range findings {
+		if rank[f.Severity] >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+func fatalUsage(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	os.Exit(exitUsage)
+}
+
+func runValidate(args []string) {
+	specCfg := config.LoadSpecValidationConfig()
+
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path (or pass it as an argument)")
+	sheet := fs.String("sheet", "", "Sheet name (for XLSX files)")
+	sheetRange := fs.String("range", "", "Cell range to validate, e.g. A3:H200")
+	template := fs.String("template", "spec", "Template whose rule pack runs after conversion")
+	rulesFile := fs.String("rules", "", "Validation rules file (YAML or JSON)")
+	strict := fs.Bool("strict", specCfg.SpecStrictMode, "Report failed quality gates as errors (SPEC_STRICT_MODE)")
+	minHeader := fs.Int("min-header-confidence", specCfg.SpecMinHeaderConfidence, "Minimum header detection confidence, 0-100 (SPEC_MIN_HEADER_CONFIDENCE)")
+	maxRowLoss := fs.Float64("max-row-loss", specCfg.SpecMaxRowLossRatio, "Maximum ratio of source rows dropped, 0-1 (SPEC_MAX_ROW_LOSS_RATIO)")
+	configPath := fs.String("config", "", "Project config (default: nearest "+projectconfig.FileNames[0]+")")
+	var out checkOutput
+	out.register(fs)
+
+	fs.Usage = func() {
+		fmt.Println(`Validate a spreadsheet against rules and quality gates
+
+Usage:
+  mdflow validate [options] <file> [file...]
+  mdflow validate [options]           (every input in .mdflow.yaml)
+
+Options:
+  --input                  Input file path (TSV, CSV, or XLSX)
+  --sheet                  Sheet name for XLSX files
+  --range                  Cell range to validate, e.g. A3:H200
+  --template               Template whose rule pack runs (default: "spec")
+  --rules                  Validation rules file (YAML or JSON)
+  --strict                 Report failed quality gates as errors (default: SPEC_STRICT_MODE or true)
+  --min-header-confidence  Minimum header confidence 0-100 (default: SPEC_MIN_HEADER_CONFIDENCE or 60)
+  --max-row-loss           Maximum dropped row ratio 0-1 (default: SPEC_MAX_ROW_LOSS_RATIO or 0.4)
+  --config                 Project config file (default: the nearest .mdflow.yaml);
+                           its sheet, range, template, overrides and rules apply
+                           unless the flag is given
+  --format                 Output format: text, json, sarif or junit (default: text)
+  --output                 Output file path (default: stdout)
+  --fail-on                Lowest severity that fails the run: error, warn, info or none (default: error)
+
+Exit codes:
+  0  no findings at or above --fail-on
+  1  findings at or above --fail-on
+  2  usage or input error
+
+Examples:
+  mdflow validate --rules rules.yaml input.xlsx
+  mdflow validate --sheet Requirements --format sarif --output mdflow.sarif book.xlsx
+  mdflow validate --rules rules.yaml --fail-on warn --format junit spec.tsv
+  mdflow validate --rules rules.yaml a.tsv b.xlsx
+  mdflow validate --format junit --output mdflow.xml`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(exitUsage)
+	}
+	proj, err := loadProject(fs, *configPath)
+	if err != nil {
+		fatalUsage(err)
+	}
+	inputs := fs.Args()
+	if *input != "" {
+		inputs = append([]string{*input}, inputs...)
+	}
+	if len(inputs) == 0 && proj != nil {
+		files, err := proj.expand(nil, false)
+		if err != nil {
+			fatalUsage(err)
+		}
+		for _, f := range files {
+			inputs = append(inputs, f.input)
+		}
+	}
+	if len(inputs) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: at least one input file is required")
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+	out.check()
+	if *minHeader < 0 || *minHeader > 100 {
+		fatalUsage(fmt.Errorf("--min-header-confidence must be in range 0..100"))
+	}
+	if *maxRowLoss < 0 || *maxRowLoss > 1 {
+		fatalUsage(fmt.Errorf("--max-row-loss must be in range 0..1"))
+	}
+
+	rules, _, err := loadRulesFiles([]string{*rulesFile})
+	if err != nil {
+		fatalUsage(err)
+	}
+	base := convertOptions{
+		template:   *template,
+		sheet:      *sheet,
+		sheetRange: *sheetRange,
+		rules:      rules,
+		thresholds: converter.QualityThresholds{StrictMode: *strict, MinHeaderConfidence: *minHeader, MaxRowLossRatio: *maxRowLoss},
+		needDoc:    true,
+	}
+
+	r := &report.Report{Tool: "mdflow", Version: version}
+	for _, path := range inputs {
+		opts, err := proj.options(path, base)
+		if err != nil {
+			fatalUsage(err)
+		}
+		findings, err := validateFile(path, opts)
+		if err != nil {
+			fatalUsage(err)
+		}
+		r.Findings = append(r.Findings, findings...)
+	}
+	if len(inputs) == 1 {
+		r.URI = inputs[0]
+	}
+	out.finish(r)
+}
+
+// validateFile converts one input and returns its rule, rule pack and
+// quality gate findings, located in the source file.
+func validateFile(path string, opts convertOptions) ([]report.Finding, error) {
+	// Rules run here rather than in convertFile so their findings can be
+	// told apart from advisory conversion warnings.
+	rules := opts.rules
+	opts.rules = nil
+	converted, err := convertFile(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := converted.result
+
+	// Conversion warnings are advisory; validate reports rule and rule pack
+	// findings plus the quality gates.
+	var warnings []converter.Warning
+	for _, w := range result.Warnings {
+		if _, ok := w.Details["rule_pack"]; ok {
+			warnings = append(warnings, w)
+		}
+	}
+	if !rules.IsEmpty() {
+		warnings = append(warnings, converter.Validate(converted.specDoc, rules).Warnings...)
+	}
+	findings := report.FromWarnings(warnings)
+
+	// Markdown input has no source table to measure row loss against.
+	if result.Meta.QualityReport != nil {
+		findings = append(findings, report.FromQualityReport(result.Meta.QualityReport)...)
+	}
+
+	report.Locate(findings, path, sourceLocator(path, converted))
+	return findings, nil
+}
+
+func runLint(args []string) {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	var out checkOutput
+	out.register(fs)
+
+	fs.Usage = func() {
+		fmt.Println(`Check the structure of MDFlow markdown files
+
+Usage:
+  mdflow lint [options] <file> [file...]
+
+Checks front matter, the title heading, empty and duplicate headings,
+heading level jumps, table column counts and unclosed code fences.
+
+Options:
+  --format   Output format: text, json, sarif or junit (default: text)
+  --output   Output file path (default: stdout)
+  --fail-on  Lowest severity that fails the run: error, warn, info or none (default: error)
+
+Exit codes:
+  0  no findings at or above --fail-on
+  1  findings at or above --fail-on
+  2  usage or input error
+
+Examples:
+  mdflow lint spec.mdflow.md
+  mdflow lint --fail-on warn --format sarif --output lint.sarif specs/*.mdflow.md`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(exitUsage)
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Error: at least one file is required")
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+	out.check()
+
+	r := &report.Report{Tool: "mdflow", Version: version}
+	for _, path := range fs.Args() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			fatalUsage(fmt.Errorf("reading %s: %w", path, err))
+		}
+		findings := report.FromWarnings(converter.LintMarkdown(string(content)))
+		report.Locate(findings, path, nil)
+		r.Findings = append(r.Findings, findings...)
+	}
+	if fs.NArg() == 1 {
+		r.URI = fs.Arg(0)
+	}
+	out.finish(r)
+}
+
+// sourceLocator points spec rows at the input they were parsed from: sheet
+// cells for workbooks, lines for text.
+func sourceLocator(path string, c *convertedFile) *report.SourceLocator {
+	return report.NewSourceLocator(path, c.specDoc, c.isSheet).WithRowNumbers(c.sourceRows)
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	"github.com/yourorg/md-spec-tool/internal/diff"
+	"github.com/yourorg/md-spec-tool/internal/projectconfig"
+)
+
+// watcher rescans its inputs when the file system reports a change (or on
+// every tick with --poll) and rebuilds a file once it has been quiet for the
+// debounce period. Scans compare size and modification time, so the events
+// only say when to look, not what changed.
+type watcher struct {
+	args     []string
+	opts     batchOptions // outDir "" writes outputs beside their inputs
+	debounce time.Duration
+	out      io.Writer
+	files    map[string]*watchedFile
+}
+
+// watchedFile is one input and what its last build produced.
+type watchedFile struct {
+	batchFile
+	modTime  time.Time
+	size     int64
+	pending  time.Time // when the latest unbuilt change was seen; zero when current
+	doc      *converter.SpecDoc
+	built    bool
+	warnings int
+	quality  string
+}
+
+func runWatch(args []string) {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	outDir := fs.String("out-dir", "", "Output directory (default: beside each input)")
+	template := fs.String("template", "spec", "Template name (spec|table)")
+	format := fs.String("format", "", "Output format (spec|table; default from the template)")
+	sheet := fs.String("sheet", "", "Sheet name (for XLSX files)")
+	sheetRange := fs.String("range", "", "Cell range to convert, e.g. A3:H200")
+	jsonOutput := fs.Bool("json", false, "Write JSON with metadata instead of markdown")
+	rulesFile := fs.String("rules", "", "Validation rules file (YAML or JSON)")
+	debounce := fs.Duration("debounce", 300*time.Millisecond, "Quiet period after a change before rebuilding")
+	interval := fs.Duration("interval", 500*time.Millisecond, "How often inputs are checked for changes with --poll")
+	pollInputs := fs.Bool("poll", false, "Poll inputs every --interval instead of using file system notifications")
+	configPath := fs.String("config", "", "Project config (default: nearest "+projectconfig.FileNames[0]+")")
+
+	fs.Usage = func() {
+		fmt.Println(`Rebuild specs whenever their input files change
+
+Usage:
+  mdflow watch [options] <file|dir|glob>...
+  mdflow watch [options]              (every input in .mdflow.yaml)
+
+Options:
+  --out-dir   Output directory mirroring the input tree (default: where the
+              project config says, else <name>.mdflow.md beside each input)
+  --template  Template name (default: "spec", options: spec|table)
+  --format    Output format: spec or table (default: from the template)
+  --sheet     Sheet name for XLSX files
+  --range     Cell range to convert, e.g. A3:H200
+  --json      Write <name>.json with metadata instead of markdown
+  --rules     Validation rules file (YAML or JSON)
+  --debounce  Quiet period after a change before rebuilding (default: 300ms)
+  --poll      Check inputs every --interval instead of relying on file system
+              notifications (for network drives and other file systems
+              that do not report changes)
+  --interval  How often inputs are checked with --poll (default: 500ms)
+  --config    Project config file (default: the nearest .mdflow.yaml)
+
+Changes are picked up through file system notifications on the inputs'
+directories; watch falls back to polling when they are unavailable.
+Every build prints the rows added (+), removed (-) and modified (~) since
+the previous build. Outputs are replaced atomically. Stop with Ctrl-C.
+
+Examples:
+  mdflow watch spec.xlsx
+  mdflow watch --out-dir specs --template table sheets/
+  mdflow watch --rules rules.yaml 'sheets/**/*.xlsx'
+  mdflow watch`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	proj, err := loadProject(fs, *configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	inputs := proj.inputs(fs.Args())
+	if len(inputs) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: at least one file, directory or glob is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *interval <= 0 || *debounce < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --interval must be positive and --debounce must not be negative")
+		os.Exit(1)
+	}
+
+	rules, rulesHash, err := loadRulesFiles([]string{*rulesFile})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	specCfg := config.LoadSpecValidationConfig()
+	opts := batchOptions{
+		convertOptions: convertOptions{
+			template:   *template,
+			format:     *format,
+			sheet:      *sheet,
+			sheetRange: *sheetRange,
+			json:       *jsonOutput,
+			rules:      rules,
+			rulesHash:  rulesHash,
+			thresholds: converter.QualityThresholds{
+				StrictMode:          specCfg.SpecStrictMode,
+				MinHeaderConfidence: specCfg.SpecMinHeaderConfidence,
+				MaxRowLossRatio:     specCfg.SpecMaxRowLossRatio,
+			},
+			needDoc: true,
+		},
+		outDir:  *outDir,
+		project: proj,
+	}
+	if proj != nil && *outDir == "" {
+		proj.layout = true
+		opts.outDir = proj.cfg.Dir
+	}
+	if _, err := opts.expand(inputs); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	w := newWatcher(inputs, opts, *debounce, os.Stdout)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	w.poll(time.Now())
+	fmt.Fprintf(os.Stderr, "Watching %d file(s); press Ctrl-C to stop\n", len(w.files))
+	if !*pollInputs {
+		err := w.notify(ctx)
+		if err == nil {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "File system notifications unavailable (%v); polling every %s\n", err, *interval)
+	}
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			w.poll(now)
+		}
+	}
+}
+
+func newWatcher(args []string, opts batchOptions, debounce time.Duration, out io.Writer) *watcher {
+	return &watcher{args: args, opts: opts, debounce: debounce, out: out, files: map[string]*watchedFile{}}
+}
+
+// poll picks up changed, new and removed inputs, then rebuilds the files
+// that have been quiet for the debounce period.
+func (w *watcher) poll(now time.Time) {
+	w.scan(now)
+	for _, f := range w.due(now) {
+		w.build(f, now)
+	}
+}
+
+// notify polls on every file system event in the inputs' directories and
+// once more when the debounce period after the latest event has passed. It
+// returns nil when ctx is done, or an error when notifications cannot be set up.
+func (w *watcher) notify(ctx context.Context) error {
+	nw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer nw.Close()
+
+	dirs, recursive := watchDirs(w.args)
+	for _, dir := range dirs {
+		if err := nw.Add(dir); err != nil {
+			return fmt.Errorf("watch %s: %w", dir, err)
+		}
+	}
+
+	settle := time.NewTimer(w.debounce)
+	settle.Stop()
+	defer settle.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-nw.Events:
+			if !ok {
+				return nil
+			}
+			// Directories created under a directory or glob input are
+			// watched too, so files added to them are seen.
+			if event.Has(fsnotify.Create) && recursive[filepath.Dir(event.Name)] {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					for _, dir := range walkDirs(event.Name) {
+						if nw.Add(dir) == nil {
+							recursive[dir] = true
+						}
+					}
+				}
+			}
+			w.poll(time.Now())
+			settle.Reset(w.debounce)
+		case err, ok := <-nw.Errors:
+			if !ok {
+				return nil
+			}
+			w.logf(time.Now(), "watch error: %v", err)
+		case now := <-settle.C:
+			w.poll(now)
+		}
+	}
+}
+
+// watchDirs returns the directories to subscribe to for args: the parent of
+// a file (editors often replace a file on save, which drops a watch on the
+// file itself) and every directory under a directory or a glob's base. The
+// map marks the latter, whose new subdirectories must be watched as well.
+func watchDirs(args []string) ([]string, map[string]bool) {
+	var dirs []string
+	recursive := map[string]bool{}
+	seen := map[string]bool{}
+	add := func(dir string) {
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, arg := range args {
+		root := projectconfig.Base(arg)
+		info, err := os.Stat(root)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			add(filepath.Dir(root))
+			continue
+		}
+		for _, dir := range walkDirs(root) {
+			add(dir)
+			recursive[dir] = true
+		}
+	}
+	return dirs, recursive
+}
+
+// walkDirs returns root and every directory below it.
+func walkDirs(root string) []string {
+	var dirs []string
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	return dirs
+}
+
+func (w *watcher) scan(now time.Time) {
+	seen := map[string]bool{}
+	for _, arg := range w.args {
+		files, err := w.opts.expand([]string{arg})
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				w.logf(now, "error %s: %v", arg, err)
+			}
+			continue
+		}
+		for _, bf := range files {
+			seen[bf.input] = true
+			info, err := os.Stat(bf.input)
+			if err != nil {
+				continue
+			}
+			f, ok := w.files[bf.input]
+			if !ok {
+				if w.opts.outDir == "" {
+					bf.output = filepath.Join(filepath.Dir(bf.input), filepath.Base(bf.output))
+				}
+				// New files build on this poll, without waiting out the debounce.
+				f = &watchedFile{batchFile: bf, pending: now.Add(-w.debounce)}
+				w.files[bf.input] = f
+			} else if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+				continue
+			} else {
+				f.pending = now
+			}
+			f.modTime, f.size = info.ModTime(), info.Size()
+		}
+	}
+	for input, f := range w.files {
+		if !seen[input] {
+			w.logf(now, "removed %s (kept %s)", input, w.outputPath(f))
+			delete(w.files, input)
+		}
+	}
+}
+
+// due returns the files with a change older than the debounce period, by path.
+func (w *watcher) due(now time.Time) []*watchedFile {
+	var due []*watchedFile
+	for _, f := range w.files {
+		if !f.pending.IsZero() && now.Sub(f.pending) >= w.debounce {
+			due = append(due, f)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].input < due[j].input })
+	return due
+}
+
+func (w *watcher) outputPath(f *watchedFile) string {
+	return filepath.Join(w.opts.outDir, f.output)
+}
+
+// build reconverts one file, replaces its output and prints what changed.
+// Failures keep the previous output so a half-saved sheet does not wipe the spec.
+func (w *watcher) build(f *watchedFile, now time.Time) {
+	f.pending = time.Time{}
+	opts, err := w.opts.fileOptions(f.input)
+	var converted *convertedFile
+	if err == nil {
+		converted, err = convertFile(f.input, opts)
+	}
+	if err == nil {
+		err = writeFileAtomic(w.outputPath(f), []byte(converted.output))
+	}
+	if err != nil {
+		w.logf(now, "error %s: %v", f.input, err)
+		return
+	}
+
+	meta := converted.result.Meta
+	warnings := len(converted.result.Warnings)
+	quality := qualitySummary(meta)
+	w.logf(now, "built %s -> %s (%d rows, %d warnings, quality %s)", f.input, w.outputPath(f), meta.TotalRows, warnings, quality)
+	if f.built {
+		for _, line := range strings.Split(strings.TrimSuffix(diff.FormatSpecChanges(diff.CompareSpecs(f.doc, converted.specDoc)), "\n"), "\n") {
+			fmt.Fprintf(w.out, "  %s\n", line)
+		}
+		if warnings != f.warnings {
+			fmt.Fprintf(w.out, "  warnings %d -> %d\n", f.warnings, warnings)
+		}
+		if quality != f.quality {
+			fmt.Fprintf(w.out, "  quality %s -> %s\n", f.quality, quality)
+		}
+	}
+	f.doc, f.built, f.warnings, f.quality = converted.specDoc, true, warnings, quality
+}
+
+func (w *watcher) logf(now time.Time, format string, args ...any) {
+	fmt.Fprintf(w.out, "[%s] %s\n", now.Format("15:04:05"), fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatcher_DebouncesAndSummarisesChanges(t *testing.T) {
+	root := t.TempDir()
+	input := filepath.Join(root, "spec.tsv")
+	writeFile(t, input, "ID\tTitle\tPriority\nREQ-1\tLogin\tHigh\nREQ-2\tLogout\tLow\n")
+
+	var out bytes.Buffer
+	w := newWatcher([]string{root}, batchOptions{convertOptions: convertOptions{template: "spec", needDoc: true}}, 300*time.Millisecond, &out)
+	t0 := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	w.poll(t0)
+	output := filepath.Join(root, "spec.mdflow.md")
+	if _, err := os.Stat(output); err != nil {
+		t.Fatalf("initial build did not write %s: %v\n%s", output, err, out.String())
+	}
+	if !strings.Contains(out.String(), "built "+input) {
+		t.Fatalf("output:\n%s", out.String())
+	}
+
+	writeFile(t, input, "ID\tTitle\tPriority\nREQ-1\tSign in\tHigh\nREQ-3\tReset\tLow\n\n")
+	mtime := time.Now().Add(time.Second)
+	if err := os.Chtimes(input, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	w.poll(t0.Add(time.Second))
+	w.poll(t0.Add(time.Second + 100*time.Millisecond))
+	if out.Len() != 0 {
+		t.Fatalf("rebuilt inside the debounce window:\n%s", out.String())
+	}
+
+	w.poll(t0.Add(time.Second + 400*time.Millisecond))
+	got := out.String()
+	for _, want := range []string{"+ REQ-3", "- REQ-2", "~ REQ-1: ", "title"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "built ") != 1 {
+		t.Errorf("expected one rebuild:\n%s", got)
+	}
+
+	if err := os.Remove(input); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	w.poll(t0.Add(2 * time.Second))
+	if !strings.Contains(out.String(), "removed "+input) || len(w.files) != 0 {
+		t.Fatalf("removal not reported:\n%s", out.String())
+	}
+}
+
+func TestWriteFileAtomic_ReplacesContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "spec.mdflow.md")
+	for _, content := range []string{"first", "second"} {
+		if err := writeFileAtomic(path, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "second" {
+		t.Fatalf("content = %q, %v", data, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Fatalf("temp files left behind: %v", entries)
+	}
+}
+
+func TestWatcher_NotifyRebuildsOnSave(t *testing.T) {
+	root := t.TempDir()
+	input := filepath.Join(root, "spec.tsv")
+	writeFile(t, input, "ID\tTitle\tPriority\nREQ-1\tLogin\tHigh\n")
+
+	var out bytes.Buffer
+	w := newWatcher([]string{input}, batchOptions{convertOptions: convertOptions{template: "spec", needDoc: true}}, 50*time.Millisecond, &out)
+	w.poll(time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.notify(ctx) }()
+
+	// Replace the file the way editors save it; repeat until the watch is up.
+	output := filepath.Join(root, "spec.mdflow.md")
+	deadline := time.Now().Add(5 * time.Second)
+	for i := 0; ; i++ {
+		tmp := filepath.Join(root, ".spec.tsv.tmp")
+		writeFile(t, tmp, fmt.Sprintf("ID\tTitle\tPriority\nREQ-1\tLogin\tHigh\nREQ-2\tLogout %d\tLow\n", i))
+		if err := os.Rename(tmp, input); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(200 * time.Millisecond)
+		if data, _ := os.ReadFile(output); strings.Contains(string(data), "REQ-2") {
+			break
+		}
+		if time.Now().After(deadline) {
+			cancel()
+			<-done
+			t.Fatalf("output not rebuilt after save:\n%s", out.String())
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+}
+
+func TestWatchDirs_WatchesParentsAndTrees(t *testing.T) {
+	root := t.TempDir()
+	input := filepath.Join(root, "spec.tsv")
+	writeFile(t, input, "ID\n")
+	writeFile(t, filepath.Join(root, "sheets", "a", "b.tsv"), "ID\n")
+
+	dirs, recursive := watchDirs([]string{input, filepath.Join(root, "sheets", "**", "*.tsv")})
+	want := []string{root, filepath.Join(root, "sheets"), filepath.Join(root, "sheets", "a")}
+	if strings.Join(dirs, ",") != strings.Join(want, ",") {
+		t.Fatalf("dirs = %v, want %v", dirs, want)
+	}
+	if recursive[root] || !recursive[filepath.Join(root, "sheets", "a")] {
+		t.Fatalf("recursive = %v", recursive)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/yourorg/md-spec-tool/internal/config"
 	httphandler "github.com/yourorg/md-spec-tool/internal/http"
 	"github.com/yourorg/md-spec-tool/internal/http/middleware"
+	"github.com/yourorg/md-spec-tool/internal/tracing"
 )
 
 func main() {
@@ -32,6 +33,17 @@ func main() {
 
 	middleware.SetMaxTelemetryEvents(cfg.TelemetryMaxEvents)
 
+	shutdownTracing, err := tracing.Setup(tracing.Config{
+		Exporter:    cfg.TracingExporter,
+		Endpoint:    cfg.TracingEndpoint,
+		ServiceName: cfg.TracingServiceName,
+		SampleRatio: &cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		slog.Error("Tracing setup failed", "error", err)
+		os.Exit(1)
+	}
+
 	// Setup router (returns router and cleanup function)
 	router, cleanup := httphandler.SetupRouterWithCleanup(cfg)
 
@@ -75,6 +87,9 @@ func main() {
 	// Cleanup handlers and providers
 	slog.Info("Cleaning up resources...")
 	cleanup()
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Warn("Tracing shutdown error", "err", err)
+	}
 
 	slog.Info("Server shutdown complete")
 }
@@ -1,9 +1,6 @@
 package ai
 
 import (
-	"fmt"
-	"net/http"
-	"strings"
 	"sync"
 	"time"
 )
@@ -160,75 +157,6 @@ func (m *AIMetrics) GetSnapshot() AIMetricsSnapshot {
 	}
 }
 
-// PrometheusFormat outputs metrics in Prometheus text exposition format
-func (m *AIMetrics) PrometheusFormat() string {
-	snap := m.GetSnapshot()
-	var b strings.Builder
-
-	// Total calls
-	b.WriteString("# HELP ai_calls_total Total number of AI API calls\n")
-	b.WriteString("# TYPE ai_calls_total counter\n")
-	b.WriteString(fmt.Sprintf("ai_calls_total %d\n\n", snap.TotalCalls))
-
-	// Input tokens
-	b.WriteString("# HELP ai_tokens_input_total Total input tokens consumed\n")
-	b.WriteString("# TYPE ai_tokens_input_total counter\n")
-	b.WriteString(fmt.Sprintf("ai_tokens_input_total %d\n\n", snap.TotalInputTokens))
-
-	// Output tokens
-	b.WriteString("# HELP ai_tokens_output_total Total output tokens generated\n")
-	b.WriteString("# TYPE ai_tokens_output_total counter\n")
-	b.WriteString(fmt.Sprintf("ai_tokens_output_total %d\n\n", snap.TotalOutputTokens))
-
-	// Cost
-	b.WriteString("# HELP ai_cost_usd_total Total cost in USD\n")
-	b.WriteString("# TYPE ai_cost_usd_total counter\n")
-	b.WriteString(fmt.Sprintf("ai_cost_usd_total %.6f\n\n", snap.TotalCost))
-
-	// Errors
-	b.WriteString("# HELP ai_errors_total Total number of AI errors\n")
-	b.WriteString("# TYPE ai_errors_total counter\n")
-	b.WriteString(fmt.Sprintf("ai_errors_total %d\n", snap.TotalErrors))
-	for errType, count := range snap.ErrorsByType {
-		b.WriteString(fmt.Sprintf("ai_errors_total{type=%q} %d\n", errType, count))
-	}
-	b.WriteString("\n")
-
-	// Latency per operation
-	b.WriteString("# HELP ai_latency_seconds Average AI call latency in seconds\n")
-	b.WriteString("# TYPE ai_latency_seconds gauge\n")
-	for op, metrics := range snap.ByOperation {
-		b.WriteString(fmt.Sprintf("ai_latency_seconds{operation=%q} %.3f\n", op, metrics.AvgLatencyMs/1000))
-	}
-	b.WriteString("\n")
-
-	// Cache hits
-	b.WriteString("# HELP ai_cache_hits_total Total cache hits\n")
-	b.WriteString("# TYPE ai_cache_hits_total counter\n")
-	b.WriteString(fmt.Sprintf("ai_cache_hits_total %d\n\n", snap.CacheHits))
-
-	// Average confidence
-	b.WriteString("# HELP ai_confidence_avg Average result confidence\n")
-	b.WriteString("# TYPE ai_confidence_avg gauge\n")
-	b.WriteString(fmt.Sprintf("ai_confidence_avg %.4f\n\n", snap.AvgConfidence))
-
-	// Per-operation call counts
-	b.WriteString("# HELP ai_operation_calls_total Calls per operation\n")
-	b.WriteString("# TYPE ai_operation_calls_total counter\n")
-	for op, metrics := range snap.ByOperation {
-		b.WriteString(fmt.Sprintf("ai_operation_calls_total{operation=%q} %d\n", op, metrics.CallCount))
-	}
-
-	return b.String()
-}
-
-// ServeMetrics writes Prometheus-format metrics to an http.ResponseWriter
-func (m *AIMetrics) ServeMetrics(w http.ResponseWriter) {
-	output := m.PrometheusFormat()
-	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
-	_, _ = w.Write([]byte(output))
-}
-
 // Reset clears all metrics
 func (m *AIMetrics) Reset() {
 	m.mu.Lock()
@@ -1,7 +1,6 @@
 package ai
 
 import (
-	"strings"
 	"testing"
 	"time"
 )
@@ -92,48 +91,6 @@ func TestAIMetrics_PerOperationStats(t *testing.T) {
 	}
 }
 
-func TestAIMetrics_PrometheusFormat(t *testing.T) {
-	m := NewAIMetrics()
-	m.RecordCall(AICallMetric{
-		Operation:    "map_columns",
-		Model:        "gpt-4o-mini",
-		Latency:      200 * time.Millisecond,
-		InputTokens:  500,
-		OutputTokens: 300,
-		Cost:         0.00035,
-		Confidence:   0.85,
-	})
-
-	output := m.PrometheusFormat()
-	if output == "" {
-		t.Error("expected non-empty Prometheus output")
-	}
-
-	// Check for expected metric names
-	expectedMetrics := []string{
-		"ai_calls_total",
-		"ai_tokens_input_total",
-		"ai_tokens_output_total",
-		"ai_cost_usd_total",
-		"ai_errors_total",
-		"ai_latency_seconds",
-		"ai_cache_hits_total",
-	}
-	for _, metric := range expectedMetrics {
-		if !strings.Contains(output, metric) {
-			t.Errorf("expected metric %q in output", metric)
-		}
-	}
-
-	// Check for HELP and TYPE annotations
-	if !strings.Contains(output, "# HELP") {
-		t.Error("expected HELP annotations in Prometheus output")
-	}
-	if !strings.Contains(output, "# TYPE") {
-		t.Error("expected TYPE annotations in Prometheus output")
-	}
-}
-
 func TestAIMetrics_Reset(t *testing.T) {
 	m := NewAIMetrics()
 	m.RecordCall(AICallMetric{Operation: "test", Cost: 0.001})
@@ -172,7 +129,6 @@ func TestAIMetrics_ConcurrentSafety(t *testing.T) {
 				Cost:         0.0001,
 			})
 			m.GetSnapshot()
-			m.PrometheusFormat()
 			done <- struct{}{}
 		}()
 	}
@@ -106,8 +106,10 @@ func CacheConfigFromServiceConfig(svcCfg Config) CacheConfig {
 	if svcCfg.CacheTTL > 0 {
 		cfg.L1TTL = svcCfg.CacheTTL
 	}
-	// L2 is enabled via AI_CACHE_ENABLE_L2 env var or explicit config
-	// For now, derived from service config — caller can override
+	if svcCfg.PersistentCachePath != "" {
+		cfg.EnableL2 = true
+		cfg.L2DBPath = svcCfg.PersistentCachePath
+	}
 	return cfg
 }
 
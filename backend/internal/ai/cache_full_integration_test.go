@@ -1,10 +1,17 @@
 package ai
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/yourorg/md-spec-tool/internal/tracing"
 )
 
 func TestFullCacheStack_L1L2L3(t *testing.T) {
@@ -277,6 +284,54 @@ func TestServiceImpl_WithCacheMetrics(t *testing.T) {
 	}
 }
 
+func TestServiceImpl_MapColumnsCacheHitRecordsLevel(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(tracing.NewProvider(tracing.Config{}, sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	l2 := NewMemoryCache(100, time.Hour)
+	cache := NewMultiLevelCache(NewMemoryCache(100, time.Hour), l2)
+	svc := &ServiceImpl{
+		model:          "gpt-4o-mini",
+		promptProfile:  PromptProfileStaticV3,
+		cache:          cache,
+		promptRegistry: DefaultPromptRegistry(),
+		cacheMetrics:   AttachMetrics(cache),
+	}
+
+	req := MapColumnsRequest{Headers: []string{"ID", "Feature"}, Format: "spec"}
+	key, err := MakeCacheKey(CacheKeyScopeMapColumns, svc.model, svc.promptCacheVersion(PromptIDColumnMapping, ColumnMappingPromptVersion(svc.promptProfile)), SchemaVersionColumnMapping, req)
+	if err != nil {
+		t.Fatalf("MakeCacheKey: %v", err)
+	}
+	l2.Set(key, &ColumnMappingResult{})
+
+	if _, err := svc.MapColumns(context.Background(), req); err != nil {
+		t.Fatalf("MapColumns: %v", err)
+	}
+
+	stats := svc.GetCacheMetrics()
+	if stats.ByLevel["L2"].Hits != 1 || stats.ByOperation[CacheKeyScopeMapColumns].Hits != 1 {
+		t.Fatalf("cache hit not recorded per level/operation: %+v", stats)
+	}
+	if state := svc.MetricsState(); state.CacheHitsByLevel["L2"] != 1 || state.CircuitState != "closed" {
+		t.Fatalf("unexpected metrics state: %+v", state)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "ai.map_columns" {
+		t.Fatalf("expected one ai.map_columns span, got %+v", spans)
+	}
+	attrs := map[string]string{}
+	for _, kv := range spans[0].Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["cache.hit"] != "true" || attrs["cache.level"] != "L2" {
+		t.Fatalf("span attributes = %v, want cache.hit=true cache.level=L2", attrs)
+	}
+}
+
 func TestServiceImpl_PersistentCacheDBPath(t *testing.T) {
 	// Verify env var controls persistent cache path
 	tmpDir := t.TempDir()
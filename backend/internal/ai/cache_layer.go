@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"fmt"
 	"sync"
 	"time"
 )
@@ -164,6 +165,13 @@ func NewMultiLevelCache(layers ...CacheLayer) *MultiLevelCache {
 
 // Get checks each layer in order and backfills upper layers on a lower-level hit.
 func (c *MultiLevelCache) Get(key string) (interface{}, bool) {
+	val, _, ok := c.GetWithLevel(key)
+	return val, ok
+}
+
+// GetWithLevel is Get that also reports which layer served the hit ("L1", "L2", ...).
+// Level names match those registered by AttachMetrics.
+func (c *MultiLevelCache) GetWithLevel(key string) (interface{}, string, bool) {
 	for i, layer := range c.layers {
 		if val, ok := layer.Get(key); ok {
 			// Backfill upper (closer-to-L1) layers
@@ -173,13 +181,13 @@ func (c *MultiLevelCache) Get(key string) (interface{}, bool) {
 			c.mu.Lock()
 			c.hits++
 			c.mu.Unlock()
-			return val, true
+			return val, fmt.Sprintf("L%d", i+1), true
 		}
 	}
 	c.mu.Lock()
 	c.misses++
 	c.mu.Unlock()
-	return nil, false
+	return nil, "", false
 }
 
 // Set writes the value to every layer.
@@ -175,3 +175,21 @@ func TestMultiLevelCache_EmptyLayers(t *testing.T) {
 	mlc.Set("key", "val")
 	mlc.Clear()
 }
+
+func TestMultiLevelCache_GetWithLevel(t *testing.T) {
+	l1 := NewMemoryCache(10, time.Hour)
+	l2 := NewMemoryCache(10, time.Hour)
+	mc := NewMultiLevelCache(l1, l2)
+
+	l2.Set("k", "v")
+	if val, level, ok := mc.GetWithLevel("k"); !ok || val != "v" || level != "L2" {
+		t.Fatalf("first lookup = (%v, %q, %v), want (v, L2, true)", val, level, ok)
+	}
+	// Backfilled into L1, so the next lookup is served there.
+	if _, level, _ := mc.GetWithLevel("k"); level != "L1" {
+		t.Fatalf("second lookup level = %q, want L1", level)
+	}
+	if _, level, ok := mc.GetWithLevel("missing"); ok || level != "" {
+		t.Fatalf("miss returned (%q, %v)", level, ok)
+	}
+}
@@ -100,6 +100,18 @@ func initSchema(db *sql.DB) error {
 	return nil
 }
 
+// Ping verifies the cache database is open and queryable.
+func (pc *PersistentCache) Ping() error {
+	if pc.closed.Load() {
+		return fmt.Errorf("cache_persistent: closed")
+	}
+	var n int
+	if err := pc.db.QueryRow(`SELECT COUNT(*) FROM cache_entries LIMIT 1`).Scan(&n); err != nil {
+		return fmt.Errorf("cache_persistent: ping: %w", err)
+	}
+	return nil
+}
+
 // Get retrieves a value by key. Returns (json.RawMessage, true) on hit,
 // or (nil, false) on miss/expiry. Access count is incremented asynchronously.
 func (pc *PersistentCache) Get(key string) (interface{}, bool) {
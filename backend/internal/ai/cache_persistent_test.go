@@ -239,3 +239,40 @@ func extractRawJSON(t *testing.T, val interface{}) json.RawMessage {
 		return nil
 	}
 }
+
+func TestCachedAs_DecodesPersistentHits(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PersistentCachePath = filepath.Join(t.TempDir(), "ai_cache.db")
+	cacheCfg := CacheConfigFromServiceConfig(cfg)
+	if !cacheCfg.EnableL2 || cacheCfg.L2DBPath != cfg.PersistentCachePath {
+		t.Fatalf("persistent cache not enabled: %+v", cacheCfg)
+	}
+	stack, cleanup, err := BuildCacheStack(cacheCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	stored := &ColumnMappingResult{SchemaVersion: "v1", CanonicalFields: []CanonicalFieldMapping{{CanonicalName: "id", SourceHeader: "Ticket", Confidence: 0.9}}}
+	stack.Set("k", stored)
+	if val, _ := stack.Get("k"); val != stored {
+		t.Fatalf("memory hit = %v", val)
+	}
+	if got, ok := cachedAs[ColumnMappingResult](stored); !ok || got != stored {
+		t.Fatalf("cachedAs(pointer) = %v, %v", got, ok)
+	}
+
+	// A fresh process only has the persistent layer.
+	l2 := stack.(*MultiLevelCache).layers[1]
+	val, ok := l2.Get("k")
+	if !ok {
+		t.Fatal("expected persistent hit")
+	}
+	got, ok := cachedAs[ColumnMappingResult](val)
+	if !ok || len(got.CanonicalFields) != 1 || got.CanonicalFields[0].SourceHeader != "Ticket" {
+		t.Fatalf("persistent hit = %+v, %v", got, ok)
+	}
+	if _, ok := cachedAs[ColumnMappingResult]("unexpected"); ok {
+		t.Fatal("decoded an unknown value")
+	}
+}
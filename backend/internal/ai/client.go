@@ -22,6 +22,7 @@ const (
 	MaxDiffBeforeBytes         = 4000
 	MaxDiffAfterBytes          = 4000
 	MaxDiffTextBytes           = 2000
+	MaxTranscriptBytes         = 24000
 
 	// Default retry after for rate limiting
 	DefaultRetryAfterSeconds = 60
@@ -619,6 +620,10 @@ Headers: %v
 
 `, req.Headers)
 
+	if req.LayeredHeaders {
+		prompt += fmt.Sprintf("Headers written \"group%scolumn\" come from a multi-row header: map them by the column name, using the group as context.\n\n", HeaderLevelSeparator)
+	}
+
 	if len(req.SampleRows) > 0 {
 		prompt += "Sample data rows (showing data types and patterns):\n"
 		for i, row := range req.SampleRows {
@@ -725,6 +730,9 @@ func formatMapColumnsPromptStatic(req MapColumnsRequest) string {
 	b.WriteString("- Prefer extra_columns over risky mappings when ambiguous.\n")
 	b.WriteString("\nINPUT CONTEXT:\n")
 	b.WriteString(fmt.Sprintf("headers=%v\n", req.Headers))
+	if req.LayeredHeaders {
+		b.WriteString(fmt.Sprintf("header_levels=\"group%scolumn\" headers come from a multi-row header; map by the column name, using the group as context\n", HeaderLevelSeparator))
+	}
 	if req.FileType != "" {
 		b.WriteString(fmt.Sprintf("file_type=%s\n", req.FileType))
 	}
@@ -1110,3 +1118,126 @@ func (c *Client) buildSemanticValidationSchema() interface{} {
 		"additionalProperties": false,
 	}
 }
+
+// ExtractRequirements extracts candidate requirements from transcript paragraphs
+func (c *Client) ExtractRequirements(ctx context.Context, req ExtractRequirementsRequest) (*RequirementsExtraction, *UsageInfo, error) {
+	userContent := formatRequirementsExtractionPrompt(req)
+	result := &RequirementsExtraction{}
+
+	schema := c.buildRequirementsExtractionSchema()
+
+	var usage UsageInfo
+	err := c.callWithBreaker(ctx, "ExtractRequirements", func() error {
+		return c.callStructured(ctx, SystemPromptRequirementsExtraction, userContent, schema, result, &usage)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, &usage, nil
+}
+
+// formatRequirementsExtractionPrompt formats the user prompt for requirements extraction.
+// Paragraphs are tagged "[ID start-end]" so the model can cite them; whole
+// paragraphs past MaxTranscriptBytes are dropped rather than cut mid-sentence.
+func formatRequirementsExtractionPrompt(req ExtractRequirementsRequest) string {
+	var b strings.Builder
+	kept := TranscriptPromptParagraphs(req.Paragraphs)
+	for _, p := range req.Paragraphs[:kept] {
+		b.WriteString(transcriptPromptLine(p))
+	}
+	if kept < len(req.Paragraphs) {
+		b.WriteString("... (truncated)\n")
+	}
+
+	language := req.Language
+	if language == "" {
+		language = "auto"
+	}
+
+	return fmt.Sprintf(`Extract candidate requirements and user stories from the following meeting transcript.
+
+Language: %s
+
+Transcript:
+%s
+Cite the paragraph IDs each item is based on.`, language, b.String())
+}
+
+// TranscriptPromptParagraphs returns how many leading paragraphs fit in the
+// requirements extraction prompt under MaxTranscriptBytes. Callers use it to
+// report the paragraphs the model never saw.
+func TranscriptPromptParagraphs(paragraphs []TranscriptParagraph) int {
+	size := 0
+	for i, p := range paragraphs {
+		size += len(transcriptPromptLine(p))
+		if size > MaxTranscriptBytes {
+			return i
+		}
+	}
+	return len(paragraphs)
+}
+
+func transcriptPromptLine(p TranscriptParagraph) string {
+	return fmt.Sprintf("[%s %s-%s] %s\n", p.ID, FormatTimestamp(p.Start), FormatTimestamp(p.End), strings.TrimSpace(p.Text))
+}
+
+// FormatTimestamp formats seconds as hh:mm:ss
+func FormatTimestamp(seconds float64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	total := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, (total%3600)/60, total%60)
+}
+
+// buildRequirementsExtractionSchema builds the JSON schema for requirements extraction results
+func (c *Client) buildRequirementsExtractionSchema() interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"requirements": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"kind": map[string]interface{}{
+							"type": "string",
+							"enum": []string{"user_story", "requirement"},
+						},
+						"title":       map[string]interface{}{"type": "string"},
+						"description": map[string]interface{}{"type": "string"},
+						"acceptance_criteria": map[string]interface{}{
+							"type":  "array",
+							"items": map[string]interface{}{"type": "string"},
+						},
+						"priority": map[string]interface{}{
+							"type": "string",
+							"enum": []string{"high", "medium", "low", ""},
+						},
+						"feature": map[string]interface{}{"type": "string"},
+						"source_paragraphs": map[string]interface{}{
+							"type":     "array",
+							"items":    map[string]interface{}{"type": "string"},
+							"minItems": 1,
+						},
+						"confidence": map[string]interface{}{
+							"type":    "number",
+							"minimum": 0,
+							"maximum": 1,
+						},
+					},
+					"required":             []string{"kind", "title", "description", "acceptance_criteria", "source_paragraphs", "confidence"},
+					"additionalProperties": false,
+				},
+			},
+			"confidence": map[string]interface{}{
+				"type":    "number",
+				"minimum": 0,
+				"maximum": 1,
+			},
+		},
+		"required":             []string{"requirements", "confidence"},
+		"additionalProperties": false,
+	}
+}
@@ -16,6 +16,10 @@ func NewColumnMapperService(aiService Service) *ColumnMapperService {
 	}
 }
 
+// HeaderLevelSeparator joins the levels of a multi-row header into one
+// composite column name, e.g. "Input / Type".
+const HeaderLevelSeparator = " / "
+
 // MapColumnsRequest represents the input for column mapping
 type MapColumnsRequest struct {
 	Headers              []string   `json:"headers"`               // Column headers from spreadsheet
@@ -26,6 +30,7 @@ type MapColumnsRequest struct {
 	SchemaHint           string     `json:"schema_hint"`           // Optional hint: "test_case", "product_backlog", "issue_tracker", "api_spec", "ui_spec", "auto"
 	Language             string     `json:"language"`              // Alternative to SourceLang for consistency
 	RefinementContext    string     `json:"refinement_context"`    // Context for refinement prompts (internal use)
+	LayeredHeaders       bool       `json:"layered_headers,omitempty"` // Headers join the levels of a multi-row header with HeaderLevelSeparator
 }
 
 // MapColumns maps source headers to canonical fields using LLM
@@ -41,12 +46,13 @@ func (s *ColumnMapperService) MapColumns(ctx context.Context, req MapColumnsRequ
 
 	// For "spec" format, use LLM to map to canonical fields
 	return s.aiService.MapColumns(ctx, MapColumnsRequest{
-		Headers:    req.Headers,
-		SampleRows: req.SampleRows,
-		Format:     req.Format,
-		FileType:   req.FileType,
-		SourceLang: req.SourceLang,
-		SchemaHint: req.SchemaHint,
+		Headers:        req.Headers,
+		SampleRows:     req.SampleRows,
+		Format:         req.Format,
+		FileType:       req.FileType,
+		SourceLang:     req.SourceLang,
+		SchemaHint:     req.SchemaHint,
+		LayeredHeaders: req.LayeredHeaders,
 	})
 }
 
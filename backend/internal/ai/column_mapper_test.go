@@ -3,6 +3,7 @@ package ai
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -240,3 +241,22 @@ func TestGetExtraColumnName_ReturnsEmptyForMissing(t *testing.T) {
 		t.Errorf("expected empty string for missing index, got %q", got)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// TestFormatMapColumnsPrompt_LayeredHeaders
+// Only headers flagged as coming from a multi-row header get the level note;
+// a single-row "Expected / Actual" header does not.
+// ---------------------------------------------------------------------------
+
+func TestFormatMapColumnsPrompt_LayeredHeaders(t *testing.T) {
+	for _, profile := range []string{"", PromptProfileLegacyV2} {
+		req := MapColumnsRequest{Headers: []string{"ID", "Expected / Actual"}}
+		if prompt := formatMapColumnsPrompt(req, profile); strings.Contains(prompt, "multi-row header") {
+			t.Errorf("profile %q: single-row headers got the level note:\n%s", profile, prompt)
+		}
+		req.LayeredHeaders = true
+		if prompt := formatMapColumnsPrompt(req, profile); !strings.Contains(prompt, "group"+HeaderLevelSeparator+"column") {
+			t.Errorf("profile %q: layered headers missing the level note:\n%s", profile, prompt)
+		}
+	}
+}
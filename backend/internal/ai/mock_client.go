@@ -19,11 +19,12 @@ type MockAIService struct {
 	mu sync.RWMutex
 
 	// Override functions for each operation
-	MapColumnsFunc       func(ctx context.Context, req MapColumnsRequest) (*ColumnMappingResult, error)
-	AnalyzePasteFunc     func(ctx context.Context, req AnalyzePasteRequest) (*PasteAnalysis, error)
-	GetSuggestionsFunc   func(ctx context.Context, req SuggestionsRequest) (*SuggestionsResult, error)
-	SummarizeDiffFunc    func(ctx context.Context, req SummarizeDiffRequest) (*DiffSummary, error)
-	ValidateSemanticFunc func(ctx context.Context, req SemanticValidationRequest) (*SemanticValidationResult, error)
+	MapColumnsFunc          func(ctx context.Context, req MapColumnsRequest) (*ColumnMappingResult, error)
+	AnalyzePasteFunc        func(ctx context.Context, req AnalyzePasteRequest) (*PasteAnalysis, error)
+	GetSuggestionsFunc      func(ctx context.Context, req SuggestionsRequest) (*SuggestionsResult, error)
+	SummarizeDiffFunc       func(ctx context.Context, req SummarizeDiffRequest) (*DiffSummary, error)
+	ValidateSemanticFunc    func(ctx context.Context, req SemanticValidationRequest) (*SemanticValidationResult, error)
+	ExtractRequirementsFunc func(ctx context.Context, req ExtractRequirementsRequest) (*RequirementsExtraction, error)
 
 	// Call tracking
 	Calls []MockCall
@@ -162,6 +163,14 @@ func (m *MockAIService) ValidateSemantic(ctx context.Context, req SemanticValida
 	return &SemanticValidationResult{Overall: "good", Score: 1.0, Confidence: 1.0}, nil
 }
 
+func (m *MockAIService) ExtractRequirements(ctx context.Context, req ExtractRequirementsRequest) (*RequirementsExtraction, error) {
+	m.recordCall("ExtractRequirements", req)
+	if m.ExtractRequirementsFunc != nil {
+		return m.ExtractRequirementsFunc(ctx, req)
+	}
+	return &RequirementsExtraction{}, nil
+}
+
 func (m *MockAIService) GetMode() string {
 	if m.Mode != "" {
 		return m.Mode
@@ -110,30 +110,6 @@ func TestServiceImpl_GetBudgetStatus(t *testing.T) {
 	}
 }
 
-func TestServiceImpl_GetAIMetricsPrometheus(t *testing.T) {
-	metrics := NewAIMetrics()
-	metrics.RecordCall(AICallMetric{
-		Operation:    "map_columns",
-		Model:        "gpt-4o-mini",
-		InputTokens:  500,
-		OutputTokens: 300,
-		Cost:         0.00035,
-	})
-
-	svc := &ServiceImpl{
-		model:          "gpt-4o-mini",
-		promptProfile:  PromptProfileStaticV3,
-		cache:          NewMultiLevelCache(NewMemoryCache(100, time.Hour)),
-		promptRegistry: DefaultPromptRegistry(),
-		aiMetrics:      metrics,
-	}
-
-	output := svc.GetAIMetricsPrometheus()
-	if output == "" {
-		t.Error("expected non-empty Prometheus output")
-	}
-}
-
 func TestServiceImpl_CheckBudget_RejectsOverBudget(t *testing.T) {
 	bm := NewBudgetManager(BudgetConfig{
 		DailyBudget:       0.01, // very small budget
@@ -201,11 +177,6 @@ func TestServiceImpl_NilObservability(t *testing.T) {
 		t.Error("expected 0 budget for nil manager")
 	}
 
-	output := svc.GetAIMetricsPrometheus()
-	if output != "" {
-		t.Error("expected empty prometheus output for nil metrics")
-	}
-
 	// checkBudget should pass with nil manager
 	err := svc.checkBudget("test")
 	if err != nil {
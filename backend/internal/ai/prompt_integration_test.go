@@ -446,6 +446,7 @@ func TestPromptPipeline_AllOperations(t *testing.T) {
 		PromptIDSuggestions,
 		PromptIDDiffSummary,
 		PromptIDSemanticValidation,
+		PromptIDRequirementsExtraction,
 	}
 
 	for _, opID := range operations {
@@ -149,12 +149,13 @@ func toEnvKey(id string) string {
 // ---- Operation ID constants ----
 
 const (
-	PromptIDColumnMapping      = "column_mapping"
-	PromptIDRefineMapping      = "refine_mapping"
-	PromptIDPasteAnalysis      = "paste_analysis"
-	PromptIDSuggestions        = "suggestions"
-	PromptIDDiffSummary        = "diff_summary"
-	PromptIDSemanticValidation = "semantic_validation"
+	PromptIDColumnMapping          = "column_mapping"
+	PromptIDRefineMapping          = "refine_mapping"
+	PromptIDPasteAnalysis          = "paste_analysis"
+	PromptIDSuggestions            = "suggestions"
+	PromptIDDiffSummary            = "diff_summary"
+	PromptIDSemanticValidation     = "semantic_validation"
+	PromptIDRequirementsExtraction = "requirements_extraction"
 )
 
 // DefaultPromptRegistry creates a registry pre-loaded with all current prompts.
@@ -191,6 +192,11 @@ func DefaultPromptRegistry() *PromptRegistry {
 		Version: PromptVersionSemanticValidation,
 		Content: SystemPromptSemanticValidation,
 	})
+	reg.Register(PromptEntry{
+		ID:      PromptIDRequirementsExtraction,
+		Version: PromptVersionRequirementsExtraction,
+		Content: SystemPromptRequirementsExtraction,
+	})
 
 	return reg
 }
@@ -16,6 +16,7 @@ var (
 	SystemPromptSuggestions   = ""
 	SystemPromptDiffSummary   = ""
 	SystemPromptSemanticValidation = ""
+	SystemPromptRequirementsExtraction = ""
 )
 
 func init() {
@@ -25,4 +26,5 @@ func init() {
 	SystemPromptSuggestions = BuildSystemPromptSuggestions()
 	SystemPromptDiffSummary = BuildSystemPromptDiffSummary()
 	SystemPromptSemanticValidation = BuildSystemPromptSemanticValidation()
+	SystemPromptRequirementsExtraction = BuildSystemPromptRequirementsExtraction()
 }
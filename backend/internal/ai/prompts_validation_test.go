@@ -122,6 +122,7 @@ func TestPromptVersionConsistency(t *testing.T) {
 		{"suggestions", PromptVersionSuggestions},
 		{"diff_summary", PromptVersionDiffSummary},
 		{"semantic_validation", PromptVersionSemanticValidation},
+		{"requirements_extraction", PromptVersionRequirementsExtraction},
 	}
 
 	for _, tc := range testCases {
@@ -9,6 +9,7 @@ const (
 	PromptVersionSuggestionsLegacy   = "v1"
 	PromptVersionDiffSummary         = "v1"
 	PromptVersionSemanticValidation  = "v1"
+	PromptVersionRequirementsExtraction = "v1"
 )
 
 // PromptDef ties a prompt name/version to its system message
@@ -28,6 +29,7 @@ func GetPromptDef(promptName string) *PromptDef {
 		"suggestions":          {Name: "suggestions", Version: PromptVersionSuggestions, SystemPrompt: BuildSystemPromptSuggestions()},
 		"diff_summary":         {Name: "diff_summary", Version: PromptVersionDiffSummary, SystemPrompt: BuildSystemPromptDiffSummary()},
 		"semantic_validation":  {Name: "semantic_validation", Version: PromptVersionSemanticValidation, SystemPrompt: BuildSystemPromptSemanticValidation()},
+		"requirements_extraction": {Name: "requirements_extraction", Version: PromptVersionRequirementsExtraction, SystemPrompt: BuildSystemPromptRequirementsExtraction()},
 	}
 	return defs[promptName]
 }
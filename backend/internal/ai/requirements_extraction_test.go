@@ -0,0 +1,113 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/yourorg/md-spec-tool/internal/tracing"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	cases := map[float64]string{
+		0:       "00:00:00",
+		65.7:    "00:01:05",
+		3725:    "01:02:05",
+		-3:      "00:00:00",
+		36000.2: "10:00:00",
+	}
+	for in, want := range cases {
+		if got := FormatTimestamp(in); got != want {
+			t.Errorf("FormatTimestamp(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFormatRequirementsExtractionPrompt_TagsParagraphs(t *testing.T) {
+	prompt := formatRequirementsExtractionPrompt(ExtractRequirementsRequest{
+		Language: "en",
+		Paragraphs: []TranscriptParagraph{
+			{ID: "P1", Start: 0, End: 42, Text: "  Let's start with login. "},
+			{ID: "P2", Start: 42, End: 95.5, Text: "Users must reset passwords by email."},
+		},
+	})
+
+	for _, want := range []string{
+		"Language: en",
+		"[P1 00:00:00-00:00:42] Let's start with login.",
+		"[P2 00:00:42-00:01:35] Users must reset passwords by email.",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "truncated") {
+		t.Error("short transcript should not be truncated")
+	}
+}
+
+func TestFormatRequirementsExtractionPrompt_TruncatesWholeParagraphs(t *testing.T) {
+	long := strings.Repeat("word ", MaxTranscriptBytes/10)
+	prompt := formatRequirementsExtractionPrompt(ExtractRequirementsRequest{
+		Paragraphs: []TranscriptParagraph{
+			{ID: "P1", Text: long},
+			{ID: "P2", Text: long},
+			{ID: "P3", Text: long},
+		},
+	})
+
+	if !strings.Contains(prompt, "[P1 ") || strings.Contains(prompt, "[P3 ") {
+		t.Fatalf("expected P1 kept and P3 dropped")
+	}
+	if !strings.Contains(prompt, "... (truncated)") {
+		t.Fatal("expected truncation marker")
+	}
+	if kept := TranscriptPromptParagraphs([]TranscriptParagraph{{ID: "P1", Text: long}, {ID: "P2", Text: long}, {ID: "P3", Text: long}}); kept != 1 {
+		t.Fatalf("TranscriptPromptParagraphs = %d, want 1", kept)
+	}
+	if !strings.Contains(prompt, "Language: auto") {
+		t.Fatal("expected auto language when none given")
+	}
+}
+
+func TestServiceImpl_ExtractRequirementsSpan(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(tracing.NewProvider(tracing.Config{}, sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	cache := NewMultiLevelCache(NewMemoryCache(100, time.Hour))
+	svc := &ServiceImpl{
+		model:          "gpt-4o-mini",
+		cache:          cache,
+		promptRegistry: DefaultPromptRegistry(),
+		cacheMetrics:   AttachMetrics(cache),
+	}
+	req := ExtractRequirementsRequest{Paragraphs: []TranscriptParagraph{{ID: "P1", Text: "Users sign in with SSO."}}}
+	key, err := MakeCacheKey(CacheKeyScopeExtractReqs, svc.model, svc.promptCacheVersion(PromptIDRequirementsExtraction, PromptVersionRequirementsExtraction), SchemaVersionRequirementsExtraction, req)
+	if err != nil {
+		t.Fatalf("MakeCacheKey: %v", err)
+	}
+	cache.Set(key, &RequirementsExtraction{})
+
+	if _, err := svc.ExtractRequirements(context.Background(), req); err != nil {
+		t.Fatalf("ExtractRequirements: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "ai.extract_requirements" {
+		t.Fatalf("expected one ai.extract_requirements span, got %+v", spans)
+	}
+	attrs := map[string]string{}
+	for _, kv := range spans[0].Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["cache.hit"] != "true" || attrs["ai.paragraphs"] != "1" {
+		t.Fatalf("span attributes = %v, want cache.hit=true ai.paragraphs=1", attrs)
+	}
+}
@@ -2,10 +2,16 @@ package ai
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/yourorg/md-spec-tool/internal/metrics"
+	"github.com/yourorg/md-spec-tool/internal/tracing"
 )
 
 const (
@@ -15,10 +21,12 @@ const (
 	CacheKeyScopeSuggestions      = "suggestions"
 	CacheKeyScopeSummarizeDiff    = "summarize_diff"
 	CacheKeyScopeValidateSemantic = "validate_semantic"
+	CacheKeyScopeExtractReqs      = "extract_requirements"
 
-	// Schema versions for diff and semantic
-	SchemaVersionDiffSummary        = "v1"
-	SchemaVersionSemanticValidation = "v1"
+	// Schema versions for diff, semantic and requirements extraction
+	SchemaVersionDiffSummary            = "v1"
+	SchemaVersionSemanticValidation     = "v1"
+	SchemaVersionRequirementsExtraction = "v1"
 )
 
 // Service defines high-level AI operations for the converter domain
@@ -28,6 +36,7 @@ type Service interface {
 	GetSuggestions(ctx context.Context, req SuggestionsRequest) (*SuggestionsResult, error)
 	SummarizeDiff(ctx context.Context, req SummarizeDiffRequest) (*DiffSummary, error)
 	ValidateSemantic(ctx context.Context, req SemanticValidationRequest) (*SemanticValidationResult, error)
+	ExtractRequirements(ctx context.Context, req ExtractRequirementsRequest) (*RequirementsExtraction, error)
 	GetMode() string // Returns "on" when service is active
 	GetModel() string
 }
@@ -71,6 +80,38 @@ type SemanticIssue struct {
 	Suggestion string `json:"suggestion"`
 }
 
+// TranscriptParagraph is one timestamped paragraph of a meeting transcript
+type TranscriptParagraph struct {
+	ID    string  `json:"id"`    // e.g. "P3"; cited by extracted requirements
+	Start float64 `json:"start"` // seconds into the audio
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// ExtractRequirementsRequest is the input for transcript requirements extraction
+type ExtractRequirementsRequest struct {
+	Paragraphs []TranscriptParagraph `json:"paragraphs"`
+	Language   string                `json:"language,omitempty"`
+}
+
+// RequirementsExtraction contains candidate requirements found in a transcript
+type RequirementsExtraction struct {
+	Requirements []ExtractedRequirement `json:"requirements"`
+	Confidence   float64                `json:"confidence"`
+}
+
+// ExtractedRequirement is one candidate requirement or user story
+type ExtractedRequirement struct {
+	Kind               string   `json:"kind"` // "user_story" or "requirement"
+	Title              string   `json:"title"`
+	Description        string   `json:"description"` // user story sentence or requirement statement
+	AcceptanceCriteria []string `json:"acceptance_criteria"`
+	Priority           string   `json:"priority,omitempty"` // "high", "medium", "low"
+	Feature            string   `json:"feature,omitempty"`
+	SourceParagraphs   []string `json:"source_paragraphs"` // TranscriptParagraph IDs
+	Confidence         float64  `json:"confidence"`
+}
+
 // Config holds service configuration
 type Config struct {
 	Model               string        // OpenAI model name (e.g., "gpt-4o-mini")
@@ -83,6 +124,8 @@ type Config struct {
 	RetryBaseDelay      time.Duration // Base delay between retries
 	DisableCache        bool          // When true (BYOK), skip cache to avoid cross-user pollution
 	MaxCompletionTokens int           // Guardrail: maximum completion tokens per request
+	PersistentCachePath string        // SQLite file for the L2 cache; empty keeps results in memory only
+	Budget              *BudgetConfig // Spend limit; nil uses DefaultBudgetConfig
 }
 
 // DefaultConfig returns default configuration
@@ -116,6 +159,7 @@ type ServiceImpl struct {
 	promptRegistry *PromptRegistry
 	cacheMetrics   *CacheMetrics
 	cacheCleanup   func() // called on shutdown to close persistent cache
+	cacheL2Enabled bool   // persistent cache was requested (it may have failed to open)
 
 	// Phase 4: Observability
 	tracer        *AITracer
@@ -149,7 +193,11 @@ func NewService(config Config) (*ServiceImpl, error) {
 	costCalc := NewCostCalculator()
 	costTracker := NewCostTracker()
 	tracer := NewAITracer(aiMetrics, costCalc, costTracker)
-	budgetMgr := NewBudgetManager(DefaultBudgetConfig())
+	budgetCfg := DefaultBudgetConfig()
+	if config.Budget != nil {
+		budgetCfg = *config.Budget
+	}
+	budgetMgr := NewBudgetManager(budgetCfg)
 
 	return &ServiceImpl{
 		client:         client,
@@ -161,6 +209,7 @@ func NewService(config Config) (*ServiceImpl, error) {
 		promptRegistry: DefaultPromptRegistry(),
 		cacheMetrics:   cacheMetrics,
 		cacheCleanup:   cleanup,
+		cacheL2Enabled: cacheCfg.EnableL2,
 		tracer:         tracer,
 		costTracker:    costTracker,
 		budgetManager:  budgetMgr,
@@ -179,21 +228,34 @@ func (s *ServiceImpl) GetModel() string {
 
 // MapColumns maps source headers to canonical fields
 func (s *ServiceImpl) MapColumns(ctx context.Context, req MapColumnsRequest) (*ColumnMappingResult, error) {
+	ctx, span := tracing.Start(ctx, "ai.map_columns",
+		attribute.String("ai.operation", CacheKeyScopeMapColumns),
+		attribute.String("ai.model", s.model),
+		attribute.Int("ai.headers", len(req.Headers)),
+	)
+	var spanErr error
+	defer func() { tracing.End(span, spanErr) }()
+
 	// Check cache first
 	var cacheKey string
 	if !s.disableCache {
 		var err error
 		cacheKey, err = MakeCacheKey(CacheKeyScopeMapColumns, s.model, s.promptCacheVersion(PromptIDColumnMapping, ColumnMappingPromptVersion(s.promptProfile)), SchemaVersionColumnMapping, req)
 		if err == nil {
-			if cached, ok := s.cache.Get(cacheKey); ok {
+			if cached, level, ok := s.cacheGet(CacheKeyScopeMapColumns, cacheKey); ok {
 				s.recordCacheHit(CacheKeyScopeMapColumns)
-				return cached.(*ColumnMappingResult), nil
+				span.SetAttributes(attribute.Bool("cache.hit", true), attribute.String("cache.level", level))
+				if result, ok := cachedAs[ColumnMappingResult](cached); ok {
+					return result, nil
+				}
 			}
 		}
 	}
+	span.SetAttributes(attribute.Bool("cache.hit", false))
 
 	// Check budget
 	if err := s.checkBudget(CacheKeyScopeMapColumns); err != nil {
+		spanErr = err
 		return nil, err
 	}
 
@@ -220,15 +282,23 @@ func (s *ServiceImpl) MapColumns(ctx context.Context, req MapColumnsRequest) (*C
 	s.logAICall(trace, err)
 
 	if err != nil {
+		spanErr = err
 		return nil, err
 	}
 
 	// Record cost in budget
 	s.recordSpend(trace.Cost.TotalCost)
+	span.SetAttributes(
+		attribute.Int64("ai.input_tokens", trace.InputTokens),
+		attribute.Int64("ai.output_tokens", trace.OutputTokens),
+		attribute.Float64("ai.cost_usd", trace.Cost.TotalCost),
+		attribute.Float64("ai.confidence", trace.Confidence),
+	)
 
 	// Validate result with header count for column_index range check
 	if err := s.validator.ValidateColumnMappingWithHeaders(result, len(req.Headers)); err != nil {
 		slog.Warn("ai.MapColumns validation failed", "error", err, "headers_count", len(req.Headers), "mapped_count", len(result.CanonicalFields))
+		spanErr = err
 		return nil, err
 	}
 
@@ -246,9 +316,11 @@ func (s *ServiceImpl) AnalyzePaste(ctx context.Context, req AnalyzePasteRequest)
 		var err error
 		cacheKey, err = MakeCacheKey(CacheKeyScopeAnalyzePaste, s.model, s.promptCacheVersion(PromptIDPasteAnalysis, PromptVersionPasteAnalysis), SchemaVersionPasteAnalysis, req)
 		if err == nil {
-			if cached, ok := s.cache.Get(cacheKey); ok {
+			if cached, _, ok := s.cacheGet(CacheKeyScopeAnalyzePaste, cacheKey); ok {
 				s.recordCacheHit(CacheKeyScopeAnalyzePaste)
-				return cached.(*PasteAnalysis), nil
+				if result, ok := cachedAs[PasteAnalysis](cached); ok {
+					return result, nil
+				}
 			}
 		}
 	}
@@ -299,9 +371,11 @@ func (s *ServiceImpl) GetSuggestions(ctx context.Context, req SuggestionsRequest
 		var err error
 		cacheKey, err = MakeCacheKey(CacheKeyScopeSuggestions, s.model, s.promptCacheVersion(PromptIDSuggestions, SuggestionsPromptVersion(s.promptProfile)), SchemaVersionSuggestions, req)
 		if err == nil {
-			if cached, ok := s.cache.Get(cacheKey); ok {
+			if cached, _, ok := s.cacheGet(CacheKeyScopeSuggestions, cacheKey); ok {
 				s.recordCacheHit(CacheKeyScopeSuggestions)
-				return cached.(*SuggestionsResult), nil
+				if result, ok := cachedAs[SuggestionsResult](cached); ok {
+					return result, nil
+				}
 			}
 		}
 	}
@@ -347,9 +421,11 @@ func (s *ServiceImpl) SummarizeDiff(ctx context.Context, req SummarizeDiffReques
 		var err error
 		cacheKey, err = MakeCacheKey(CacheKeyScopeSummarizeDiff, s.model, s.promptCacheVersion(PromptIDDiffSummary, PromptVersionDiffSummary), SchemaVersionDiffSummary, req)
 		if err == nil {
-			if cached, ok := s.cache.Get(cacheKey); ok {
+			if cached, _, ok := s.cacheGet(CacheKeyScopeSummarizeDiff, cacheKey); ok {
 				s.recordCacheHit(CacheKeyScopeSummarizeDiff)
-				return cached.(*DiffSummary), nil
+				if result, ok := cachedAs[DiffSummary](cached); ok {
+					return result, nil
+				}
 			}
 		}
 	}
@@ -395,9 +471,11 @@ func (s *ServiceImpl) ValidateSemantic(ctx context.Context, req SemanticValidati
 		var err error
 		cacheKey, err = MakeCacheKey(CacheKeyScopeValidateSemantic, s.model, s.promptCacheVersion(PromptIDSemanticValidation, PromptVersionSemanticValidation), SchemaVersionSemanticValidation, req)
 		if err == nil {
-			if cached, ok := s.cache.Get(cacheKey); ok {
+			if cached, _, ok := s.cacheGet(CacheKeyScopeValidateSemantic, cacheKey); ok {
 				s.recordCacheHit(CacheKeyScopeValidateSemantic)
-				return cached.(*SemanticValidationResult), nil
+				if result, ok := cachedAs[SemanticValidationResult](cached); ok {
+					return result, nil
+				}
 			}
 		}
 	}
@@ -436,6 +514,75 @@ func (s *ServiceImpl) ValidateSemantic(ctx context.Context, req SemanticValidati
 	return result, nil
 }
 
+// ExtractRequirements extracts candidate requirements and user stories from a meeting transcript
+func (s *ServiceImpl) ExtractRequirements(ctx context.Context, req ExtractRequirementsRequest) (*RequirementsExtraction, error) {
+	ctx, span := tracing.Start(ctx, "ai.extract_requirements",
+		attribute.String("ai.operation", CacheKeyScopeExtractReqs),
+		attribute.String("ai.model", s.model),
+		attribute.Int("ai.paragraphs", len(req.Paragraphs)),
+	)
+	var spanErr error
+	defer func() { tracing.End(span, spanErr) }()
+
+	var cacheKey string
+	if !s.disableCache {
+		var err error
+		cacheKey, err = MakeCacheKey(CacheKeyScopeExtractReqs, s.model, s.promptCacheVersion(PromptIDRequirementsExtraction, PromptVersionRequirementsExtraction), SchemaVersionRequirementsExtraction, req)
+		if err == nil {
+			if cached, level, ok := s.cacheGet(CacheKeyScopeExtractReqs, cacheKey); ok {
+				s.recordCacheHit(CacheKeyScopeExtractReqs)
+				span.SetAttributes(attribute.Bool("cache.hit", true), attribute.String("cache.level", level))
+				if result, ok := cachedAs[RequirementsExtraction](cached); ok {
+					return result, nil
+				}
+			}
+		}
+	}
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+
+	if err := s.checkBudget(CacheKeyScopeExtractReqs); err != nil {
+		spanErr = err
+		return nil, err
+	}
+
+	var result *RequirementsExtraction
+	trace, err := s.tracer.TraceCall(ctx, TraceInput{
+		Operation: CacheKeyScopeExtractReqs,
+		Model:     s.model,
+	}, func(ctx context.Context) (*TraceOutput, error) {
+		r, usage, callErr := s.client.ExtractRequirements(ctx, req)
+		if callErr != nil {
+			return nil, callErr
+		}
+		result = r
+		out := &TraceOutput{Confidence: r.Confidence}
+		if usage != nil {
+			out.InputTokens = usage.InputTokens
+			out.OutputTokens = usage.OutputTokens
+		}
+		return out, nil
+	})
+	s.logAICall(trace, err)
+	if err != nil {
+		spanErr = err
+		return nil, err
+	}
+	s.recordSpend(trace.Cost.TotalCost)
+	span.SetAttributes(
+		attribute.Int64("ai.input_tokens", trace.InputTokens),
+		attribute.Int64("ai.output_tokens", trace.OutputTokens),
+		attribute.Float64("ai.cost_usd", trace.Cost.TotalCost),
+		attribute.Float64("ai.confidence", trace.Confidence),
+		attribute.Int("ai.requirements", len(result.Requirements)),
+	)
+
+	if !s.disableCache && cacheKey != "" {
+		s.cache.Set(cacheKey, result)
+	}
+
+	return result, nil
+}
+
 // GetMappingWithFallback returns the column mapping result with confidence-based fallback orchestration.
 // If average confidence is below 0.6, it attempts to refine the mapping.
 // Confidence < 0.4 mappings are moved to extra_columns (never lost).
@@ -486,11 +633,12 @@ func (s *ServiceImpl) RefineMapping(ctx context.Context, original *ColumnMapping
 		sourceLang = originalReq.Language
 	}
 	refinementReq := MapColumnsRequest{
-		Headers:    originalReq.Headers,
-		SampleRows: originalReq.SampleRows,
-		SchemaHint: originalReq.SchemaHint,
-		SourceLang: sourceLang,
-		Language:   originalReq.Language,
+		Headers:        originalReq.Headers,
+		SampleRows:     originalReq.SampleRows,
+		SchemaHint:     originalReq.SchemaHint,
+		SourceLang:     sourceLang,
+		Language:       originalReq.Language,
+		LayeredHeaders: originalReq.LayeredHeaders,
 		RefinementContext: fmt.Sprintf("Previous attempt mapped these headers with low confidence <%s>. Please reconsider these mappings with higher scrutiny, using sample data patterns and semantic analysis. If truly ambiguous, move to extra_columns rather than force an incorrect mapping.",
 			strings.Join(ambiguousFields, ", ")),
 	}
@@ -572,6 +720,7 @@ func (s *ServiceImpl) checkBudget(operation string) error {
 	ok, remaining := s.budgetManager.CheckBudget()
 	if !ok {
 		slog.Warn("ai_budget_exceeded", "operation", operation, "remaining", remaining)
+		metrics.IncQuotaRejection(metrics.QuotaKindAIBudget)
 		return fmt.Errorf("%w: daily AI budget exceeded", ErrAIUnavailable)
 	}
 	return nil
@@ -584,6 +733,45 @@ func (s *ServiceImpl) recordSpend(cost float64) {
 	}
 }
 
+// cacheGet looks up key and records the hit level or miss in cache metrics.
+func (s *ServiceImpl) cacheGet(operation, key string) (interface{}, string, bool) {
+	start := time.Now()
+	var (
+		val   interface{}
+		level = "L1"
+		ok    bool
+	)
+	if multi, isMulti := s.cache.(*MultiLevelCache); isMulti {
+		val, level, ok = multi.GetWithLevel(key)
+	} else {
+		val, ok = s.cache.Get(key)
+	}
+	if s.cacheMetrics != nil {
+		if ok {
+			s.cacheMetrics.RecordHit(operation, level, time.Since(start))
+		} else {
+			s.cacheMetrics.RecordMiss(operation)
+		}
+	}
+	return val, level, ok
+}
+
+// cachedAs returns a cache hit as *T. Memory layers hold the stored pointer;
+// the persistent layer returns JSON, which is decoded here.
+func cachedAs[T any](val interface{}) (*T, bool) {
+	switch v := val.(type) {
+	case *T:
+		return v, true
+	case json.RawMessage:
+		var out T
+		if err := json.Unmarshal(v, &out); err != nil {
+			return nil, false
+		}
+		return &out, true
+	}
+	return nil, false
+}
+
 // recordCacheHit records a cache hit in AI metrics
 func (s *ServiceImpl) recordCacheHit(operation string) {
 	if s.tracer != nil {
@@ -642,12 +830,43 @@ func (s *ServiceImpl) GetBudgetStatus() BudgetStatus {
 	return s.budgetManager.GetStatus()
 }
 
-// GetAIMetricsPrometheus returns metrics in Prometheus text format
-func (s *ServiceImpl) GetAIMetricsPrometheus() string {
-	if s.aiMetrics == nil {
-		return ""
+// MetricsState reports circuit breaker, budget and cache state for the /metrics/prometheus scrape.
+func (s *ServiceImpl) MetricsState() metrics.AIServiceState {
+	state := metrics.AIServiceState{Model: s.model, CircuitState: "closed"}
+	if s.client != nil && s.client.breaker != nil {
+		state.CircuitState = string(s.client.breaker.State())
+	}
+	if s.budgetManager != nil {
+		status := s.budgetManager.GetStatus()
+		state.BudgetSpentUSD = status.Spent
+		state.BudgetLimitUSD = status.DailyBudget
+	}
+	if s.cacheMetrics != nil {
+		snap := s.cacheMetrics.GetStats()
+		state.CacheMisses = snap.TotalMisses
+		state.CacheHitsByLevel = make(map[string]int64, len(snap.ByLevel))
+		for level, lv := range snap.ByLevel {
+			state.CacheHitsByLevel[level] = lv.Hits
+		}
+	}
+	return state
+}
+
+// CheckPersistentCache reports whether the persistent (L2) cache is enabled and,
+// if so, whether its database is usable. A failed open at startup is reported
+// here because BuildCacheStack silently falls back to memory-only caching.
+func (s *ServiceImpl) CheckPersistentCache() (enabled bool, err error) {
+	if !s.cacheL2Enabled {
+		return false, nil
+	}
+	if multi, ok := s.cache.(*MultiLevelCache); ok {
+		for _, layer := range multi.layers {
+			if pc, ok := layer.(*PersistentCache); ok {
+				return true, pc.Ping()
+			}
+		}
 	}
-	return s.aiMetrics.PrometheusFormat()
+	return true, fmt.Errorf("persistent cache failed to initialize; running memory-only")
 }
 
 // PromptInfo contains metadata about a registered prompt (for diagnostics)
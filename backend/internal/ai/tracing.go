@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"time"
+
+	"github.com/yourorg/md-spec-tool/internal/metrics"
 )
 
 // TraceInput is the input to TraceCall
@@ -101,6 +103,17 @@ func (t *AITracer) TraceCall(ctx context.Context, input TraceInput, fn func(ctx
 		})
 	}
 
+	metrics.ObserveAICall(metrics.AICall{
+		Operation:    trace.Operation,
+		Model:        trace.Model,
+		Latency:      trace.Latency,
+		InputTokens:  trace.InputTokens,
+		OutputTokens: trace.OutputTokens,
+		CostUSD:      trace.Cost.TotalCost,
+		CacheHit:     trace.CacheHit,
+		Failed:       err != nil,
+	})
+
 	// Record in cost tracker
 	if t.costTracker != nil && trace.Cost.TotalCost > 0 {
 		t.costTracker.Record(trace.Operation, trace.Cost)
@@ -25,6 +25,30 @@ const (
 	DefaultHTTPClientTimeout = 30 * time.Second
 	DefaultGSheetMaxRetries  = 2
 
+	// Watched sheet defaults
+	DefaultWatchPollInterval = 30 * time.Second
+	DefaultWatchMinInterval  = time.Minute
+	DefaultWatchMaxWatches   = 500
+
+	// Telemetry history kept in TELEMETRY_DB_PATH
+	DefaultTelemetryRetention = 30 * 24 * time.Hour
+	DefaultTelemetryMaxRows   = 1000000
+
+	// Outbound webhook defaults
+	DefaultWebhookMaxAttempts    = 5
+	DefaultWebhookRetryBaseDelay = 2 * time.Second
+	DefaultWebhookTimeout        = 10 * time.Second
+
+	// Speech-to-text defaults
+	DefaultTranscribeProvider = "openai"
+	DefaultTranscribeModel    = "whisper-1"
+
+	// OpenTelemetry tracing defaults (exporter "none" keeps the no-op provider)
+	DefaultTracingExporter    = "none"
+	DefaultTracingEndpoint    = "http://localhost:4318"
+	DefaultTracingServiceName = "md-spec-tool"
+	DefaultTracingSampleRatio = 1.0
+
 	// Rate limiting defaults
 	DefaultShareCreateRateLimit  = 10
 	DefaultShareUpdateRateLimit  = 20
@@ -33,6 +57,7 @@ const (
 	DefaultPreviewRateLimit      = 60
 	DefaultConvertRateLimit      = 60
 	DefaultAISuggestRateLimit    = 30
+	DefaultTelemetryRateLimit    = 120
 	DefaultTrustedProxies        = "127.0.0.1,::1"
 
 	// AI defaults
@@ -82,6 +107,7 @@ type Config struct {
 	// Google Sheets (optional; defaults to HTTPClientTimeout if not set)
 	GSheetHTTPTimeout time.Duration
 	GSheetMaxRetries  int
+	GSheetAPIEndpoint string // overrides the Sheets API base URL (e.g. a local fake); empty uses Google
 
 	// Rate limiting
 	ShareCreateRateLimit  int
@@ -90,6 +116,7 @@ type Config struct {
 	PreviewRateLimit      int
 	ConvertRateLimit      int
 	AISuggestRateLimit    int
+	TelemetryRateLimit    int
 	RateLimitWindow       time.Duration
 	TrustedProxies        []string
 
@@ -122,11 +149,40 @@ type Config struct {
 
 	// Telemetry
 	TelemetryMaxEvents int
+	TelemetryDBPath    string        // SQLite history; empty keeps events in memory only
+	TelemetryRetention time.Duration // events older than this are pruned
+	TelemetryMaxRows   int           // oldest events beyond this row count are pruned
 
 	// Storage
 	ShareStorePath string
 	FeedbackDBPath string
 
+	// Watched sheets (scheduled re-sync)
+	WatchDBPath       string        // SQLite watches and versions; empty keeps them in memory only
+	WatchPollInterval time.Duration // scheduler tick; 0 disables background syncing
+	WatchMinInterval  time.Duration // shortest per-watch interval accepted
+	WatchMaxWatches   int
+
+	// Outbound webhooks
+	WebhookDBPath         string
+	WebhookMaxAttempts    int
+	WebhookRetryBaseDelay time.Duration // doubles per retry, capped at 16x
+	WebhookTimeout        time.Duration
+	WebhookAdminToken     string // bearer token for /api/webhooks; empty leaves the endpoints unregistered
+
+	// Speech-to-text
+	TranscribeProvider       string // "openai" or "whisper_http" (self-hosted, OpenAI-compatible)
+	TranscribeBaseURL        string // API base incl. version; required for whisper_http
+	TranscribeModel          string
+	TranscribeAPIKey         string // optional; OpenAI falls back to OPENAI_API_KEY
+	TranscribeMaxUploadBytes int64  // per-request limit before chunking; 0 uses the provider default
+
+	// OpenTelemetry tracing
+	TracingExporter    string // "none" or "otlp"
+	TracingEndpoint    string // OTLP/HTTP collector base URL
+	TracingServiceName string
+	TracingSampleRatio float64 // fraction of root spans sampled
+
 	// Spec validation
 	SpecStrictMode          bool
 	SpecMinHeaderConfidence int
@@ -166,6 +222,7 @@ func LoadConfig() *Config {
 		// Google Sheets
 		GSheetHTTPTimeout: getEnvDuration("GSHEET_HTTP_TIMEOUT", DefaultHTTPClientTimeout+15*time.Second),
 		GSheetMaxRetries:  getEnvInt("GSHEET_MAX_RETRIES", DefaultGSheetMaxRetries),
+		GSheetAPIEndpoint: getEnv("GSHEET_API_ENDPOINT", ""),
 
 		// Rate limiting
 		ShareCreateRateLimit:  getEnvInt("SHARE_CREATE_RATE_LIMIT", DefaultShareCreateRateLimit),
@@ -174,6 +231,7 @@ func LoadConfig() *Config {
 		PreviewRateLimit:      getEnvInt("PREVIEW_RATE_LIMIT", DefaultPreviewRateLimit),
 		ConvertRateLimit:      getEnvInt("CONVERT_RATE_LIMIT", DefaultConvertRateLimit),
 		AISuggestRateLimit:    getEnvInt("AI_SUGGEST_RATE_LIMIT", DefaultAISuggestRateLimit),
+		TelemetryRateLimit:    getEnvInt("TELEMETRY_RATE_LIMIT", DefaultTelemetryRateLimit),
 		RateLimitWindow:       getEnvDuration("RATE_LIMIT_WINDOW", DefaultRateLimitWindow),
 		TrustedProxies:        splitCSV(getEnv("TRUSTED_PROXIES", DefaultTrustedProxies)),
 
@@ -206,11 +264,40 @@ func LoadConfig() *Config {
 
 		// Telemetry
 		TelemetryMaxEvents: getEnvInt("TELEMETRY_MAX_EVENTS", 10000),
+		TelemetryDBPath:    getEnv("TELEMETRY_DB_PATH", ".cache/telemetry.db"),
+		TelemetryRetention: getEnvDuration("TELEMETRY_RETENTION", DefaultTelemetryRetention),
+		TelemetryMaxRows:   getEnvInt("TELEMETRY_MAX_ROWS", DefaultTelemetryMaxRows),
 
 		// Storage
 		ShareStorePath: getEnv("SHARE_STORE_PATH", ""),
 		FeedbackDBPath: getEnv("FEEDBACK_DB_PATH", ".cache/feedback.db"),
 
+		// Watched sheets
+		WatchDBPath:       getEnv("WATCH_DB_PATH", ""),
+		WatchPollInterval: getEnvDuration("WATCH_POLL_INTERVAL", DefaultWatchPollInterval),
+		WatchMinInterval:  getEnvDuration("WATCH_MIN_INTERVAL", DefaultWatchMinInterval),
+		WatchMaxWatches:   getEnvInt("WATCH_MAX_WATCHES", DefaultWatchMaxWatches),
+
+		// Outbound webhooks
+		WebhookDBPath:         getEnv("WEBHOOK_DB_PATH", ".cache/webhooks.db"),
+		WebhookMaxAttempts:    getEnvInt("WEBHOOK_MAX_ATTEMPTS", DefaultWebhookMaxAttempts),
+		WebhookRetryBaseDelay: getEnvDuration("WEBHOOK_RETRY_BASE_DELAY", DefaultWebhookRetryBaseDelay),
+		WebhookTimeout:        getEnvDuration("WEBHOOK_TIMEOUT", DefaultWebhookTimeout),
+		WebhookAdminToken:     getEnv("WEBHOOK_ADMIN_TOKEN", ""),
+
+		// Speech-to-text
+		TranscribeProvider:       strings.ToLower(getEnv("TRANSCRIBE_PROVIDER", DefaultTranscribeProvider)),
+		TranscribeBaseURL:        getEnv("TRANSCRIBE_BASE_URL", ""),
+		TranscribeModel:          getEnv("TRANSCRIBE_MODEL", DefaultTranscribeModel),
+		TranscribeAPIKey:         getEnv("TRANSCRIBE_API_KEY", ""),
+		TranscribeMaxUploadBytes: getEnvInt64("TRANSCRIBE_MAX_UPLOAD_BYTES", 0),
+
+		// OpenTelemetry tracing
+		TracingExporter:    strings.ToLower(getEnv("OTEL_TRACES_EXPORTER", DefaultTracingExporter)),
+		TracingEndpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", DefaultTracingEndpoint),
+		TracingServiceName: getEnv("OTEL_SERVICE_NAME", DefaultTracingServiceName),
+		TracingSampleRatio: getEnvFloat64("OTEL_TRACES_SAMPLER_ARG", DefaultTracingSampleRatio),
+
 		// Spec validation
 		SpecStrictMode:          getEnvBool("SPEC_STRICT_MODE", DefaultSpecStrictMode),
 		SpecMinHeaderConfidence: getEnvInt("SPEC_MIN_HEADER_CONFIDENCE", DefaultSpecMinHeaderConfidence),
@@ -261,6 +348,38 @@ func ValidateConfig(cfg *Config) error {
 	if cfg.AISuggestTimeout <= 0 {
 		return fmt.Errorf("AI_SUGGEST_TIMEOUT must be positive")
 	}
+	if cfg.WatchPollInterval < 0 || cfg.WatchMinInterval < 0 {
+		return fmt.Errorf("WATCH_POLL_INTERVAL and WATCH_MIN_INTERVAL must not be negative")
+	}
+	if cfg.TelemetryRetention <= 0 || cfg.TelemetryMaxRows <= 0 {
+		return fmt.Errorf("TELEMETRY_RETENTION and TELEMETRY_MAX_ROWS must be positive")
+	}
+	if cfg.TelemetryRateLimit <= 0 {
+		return fmt.Errorf("TELEMETRY_RATE_LIMIT must be positive")
+	}
+	if cfg.WebhookMaxAttempts <= 0 || cfg.WebhookRetryBaseDelay <= 0 || cfg.WebhookTimeout <= 0 {
+		return fmt.Errorf("WEBHOOK_MAX_ATTEMPTS, WEBHOOK_RETRY_BASE_DELAY and WEBHOOK_TIMEOUT must be positive")
+	}
+	switch cfg.TranscribeProvider {
+	case "", "openai":
+	case "whisper_http":
+		if strings.TrimSpace(cfg.TranscribeBaseURL) == "" {
+			return fmt.Errorf("TRANSCRIBE_BASE_URL is required when TRANSCRIBE_PROVIDER is 'whisper_http'")
+		}
+	default:
+		return fmt.Errorf("TRANSCRIBE_PROVIDER must be 'openai' or 'whisper_http'")
+	}
+	if cfg.TranscribeMaxUploadBytes < 0 {
+		return fmt.Errorf("TRANSCRIBE_MAX_UPLOAD_BYTES must be >= 0")
+	}
+	switch cfg.TracingExporter {
+	case "", "none", "otlp":
+	default:
+		return fmt.Errorf("OTEL_TRACES_EXPORTER must be 'none' or 'otlp'")
+	}
+	if cfg.TracingSampleRatio < 0 || cfg.TracingSampleRatio > 1 {
+		return fmt.Errorf("OTEL_TRACES_SAMPLER_ARG must be between 0 and 1")
+	}
 	if len(cfg.TrustedProxies) == 0 {
 		return fmt.Errorf("TRUSTED_PROXIES must have at least one entry")
 	}
@@ -279,6 +398,16 @@ func ValidateConfig(cfg *Config) error {
 	return nil
 }
 
+// LoadSpecValidationConfig reads only the SPEC_* quality gate settings. The
+// CLI uses it instead of LoadConfig, which needs the full server environment.
+func LoadSpecValidationConfig() *Config {
+	return &Config{
+		SpecStrictMode:          getEnvBool("SPEC_STRICT_MODE", DefaultSpecStrictMode),
+		SpecMinHeaderConfidence: getEnvInt("SPEC_MIN_HEADER_CONFIDENCE", DefaultSpecMinHeaderConfidence),
+		SpecMaxRowLossRatio:     getEnvFloat64("SPEC_MAX_ROW_LOSS_RATIO", DefaultSpecMaxRowLossRatio),
+	}
+}
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
@@ -0,0 +1,119 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A1Range is a 1-based, inclusive cell range. EndCol/EndRow are 0 when the
+// range is open-ended ("B3" or "B3:D").
+type A1Range struct {
+	StartCol int
+	StartRow int
+	EndCol   int
+	EndRow   int
+}
+
+var a1CellRe = regexp.MustCompile(`^\$?([A-Za-z]{1,3})\$?([0-9]*)$`)
+
+// ParseA1Range parses "B3:F40", "B3:F", "B3" or "Sheet1!B3:F40" (the sheet
+// prefix is ignored).
+func ParseA1Range(s string) (A1Range, error) {
+	raw := strings.TrimSpace(s)
+	if idx := strings.LastIndex(raw, "!"); idx >= 0 {
+		raw = raw[idx+1:]
+	}
+	if raw == "" {
+		return A1Range{}, fmt.Errorf("empty range")
+	}
+	start, end, hasEnd := strings.Cut(raw, ":")
+	var r A1Range
+	var err error
+	if r.StartCol, r.StartRow, err = parseA1Cell(start); err != nil {
+		return A1Range{}, fmt.Errorf("invalid range %q: %w", s, err)
+	}
+	if r.StartRow == 0 {
+		r.StartRow = 1
+	}
+	if hasEnd {
+		if r.EndCol, r.EndRow, err = parseA1Cell(end); err != nil {
+			return A1Range{}, fmt.Errorf("invalid range %q: %w", s, err)
+		}
+		if r.EndCol < r.StartCol || (r.EndRow > 0 && r.EndRow < r.StartRow) {
+			return A1Range{}, fmt.Errorf("invalid range %q: end is before start", s)
+		}
+	}
+	return r, nil
+}
+
+// String formats the range in A1 notation, e.g. "B3:F40" or "B3".
+func (r A1Range) String() string {
+	s := a1Column(r.StartCol) + strconv.Itoa(r.StartRow)
+	switch {
+	case r.EndCol > 0 && r.EndRow > 0:
+		s += ":" + a1Column(r.EndCol) + strconv.Itoa(r.EndRow)
+	case r.EndCol > 0:
+		s += ":" + a1Column(r.EndCol)
+	}
+	return s
+}
+
+// a1Column converts a 1-based column number to letters (1 -> A, 27 -> AA).
+func a1Column(col int) string {
+	name := ""
+	for ; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name
+}
+
+func parseA1Cell(cell string) (col, row int, err error) {
+	m := a1CellRe.FindStringSubmatch(strings.TrimSpace(cell))
+	if m == nil {
+		return 0, 0, fmt.Errorf("bad cell %q", cell)
+	}
+	for _, ch := range strings.ToUpper(m[1]) {
+		col = col*26 + int(ch-'A'+1)
+	}
+	if m[2] != "" {
+		if row, err = strconv.Atoi(m[2]); err != nil || row < 1 {
+			return 0, 0, fmt.Errorf("bad row in %q", cell)
+		}
+	}
+	return col, row, nil
+}
+
+// Slice returns the part of matrix inside the range, with the source row of
+// each kept row. sourceRows gives the sheet row of each matrix row (parsing
+// drops blank rows, see XLSXSourceRows); nil means row i is sheet row i+1.
+// Cells past the end of a row are dropped rather than padded, and rows left
+// blank by the crop are skipped.
+func (r A1Range) Slice(matrix CellMatrix, sourceRows []int) (CellMatrix, []int) {
+	out := CellMatrix{}
+	var rows []int
+	for i, row := range matrix {
+		sheetRow := i + 1
+		if i < len(sourceRows) {
+			sheetRow = sourceRows[i]
+		}
+		if sheetRow < r.StartRow || (r.EndRow > 0 && sheetRow > r.EndRow) {
+			continue
+		}
+		var cells []string
+		if r.StartCol-1 < len(row) {
+			end := len(row)
+			if r.EndCol > 0 && r.EndCol < end {
+				end = r.EndCol
+			}
+			cells = append(cells, row[r.StartCol-1:end]...)
+		}
+		if strings.TrimSpace(strings.Join(cells, "")) == "" {
+			continue // blank once cropped, like rows Normalize drops
+		}
+		out = append(out, cells)
+		rows = append(rows, sheetRow)
+	}
+	return out, rows
+}
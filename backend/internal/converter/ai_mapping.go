@@ -6,7 +6,10 @@ import (
 	"log/slog"
 	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/yourorg/md-spec-tool/internal/ai"
+	"github.com/yourorg/md-spec-tool/internal/tracing"
 )
 
 const (
@@ -32,19 +35,80 @@ type AIMappingMeta struct {
 	EstimatedCostUSD      float64
 }
 
-func (c *Converter) resolveColumnMapping(ctx context.Context, headers []string, dataRows [][]string, format string) (ColumnMap, []string, []Warning, *AIMappingMeta) {
-	return c.resolveColumnMappingWithFallback(ctx, headers, dataRows, format, false, func(h []string) (ColumnMap, []string) {
-		return c.columnMapper.MapColumns(h)
+// MapColumnsWithAI asks the AI mapper for the mapping of headers, including
+// per-field confidence and alternatives. It sends the same request as
+// conversion, so a mapping made by a recent conversion comes from the cache.
+func (c *Converter) MapColumnsWithAI(ctx context.Context, headers []string, band HeaderBand, dataRows [][]string) (*ai.ColumnMappingResult, error) {
+	if c.aiService == nil || c.aiMapper == nil {
+		return nil, ai.ErrAIUnavailable
+	}
+	return c.aiMapper.MapColumns(ctx, aiMappingRequest(headers, band, dataRows))
+}
+
+// aiMappingRequest builds the column mapping request for the headers of band
+// and a sample of dataRows.
+func aiMappingRequest(headers []string, band HeaderBand, dataRows [][]string) ai.MapColumnsRequest {
+	cleanHeaders := SanitizeHeaders(normalizeHeaders(headers))
+	sampleRows := SanitizeSampleRows(buildSampleRows(dataRows, aiSampleRows))
+	// Prompt-injection defense: sanitize headers and sample cells before sending to LLM
+	promptSafeHeaders := ai.SanitizeHeadersForPrompt(cleanHeaders)
+	promptSafeRows := make([][]string, len(sampleRows))
+	for i, row := range sampleRows {
+		promptSafeRows[i] = make([]string, len(row))
+		for j, cell := range row {
+			promptSafeRows[i][j] = ai.SanitizeForPrompt(cell)
+		}
+	}
+	return ai.MapColumnsRequest{
+		Headers:        promptSafeHeaders,
+		SampleRows:     promptSafeRows,
+		Format:         "spec",
+		FileType:       "table",
+		SourceLang:     DetectLanguageHint(EstimateEnglishScore(headers, dataRows), headers, dataRows),
+		SchemaHint:     inferSchemaHint(headers, dataRows),
+		LayeredHeaders: band.Rows() > 1,
+	}
+}
+
+func (c *Converter) resolveColumnMapping(ctx context.Context, headers []string, band HeaderBand, dataRows [][]string, format string) (ColumnMap, []string, []Warning, *AIMappingMeta) {
+	ctx, span := tracing.Start(ctx, "converter.map_columns",
+		attribute.String("mapping.format", format),
+		attribute.Int("mapping.headers", len(headers)),
+	)
+	defer span.End()
+
+	colMap, unmapped, warnings, meta := c.resolveColumnMappingWithFallback(ctx, headers, band, dataRows, format, false, func(h []string) (ColumnMap, []string) {
+		return c.columnMapper.MapBandColumns(h, band)
 	})
+
+	strategy := "rule_based"
+	if meta.Used {
+		strategy = "ai"
+	}
+	span.SetAttributes(
+		attribute.String("mapping.strategy", strategy),
+		attribute.String("ai.mode", meta.Mode),
+		attribute.Bool("ai.used", meta.Used),
+		attribute.Bool("ai.degraded", meta.Degraded),
+		attribute.Int("mapping.mapped", len(colMap)),
+		attribute.Int("mapping.unmapped", len(unmapped)),
+	)
+	if meta.Model != "" {
+		span.SetAttributes(attribute.String("ai.model", meta.Model))
+	}
+	if meta.FallbackReason != "" {
+		span.SetAttributes(attribute.String("ai.fallback_reason", meta.FallbackReason))
+	}
+	return colMap, unmapped, warnings, meta
 }
 
 // resolveColumnMappingRuleBasedOnly resolves column mapping using only rule-based fallback, never AI.
 // Used by preview endpoints to guarantee fast response times.
-func (c *Converter) resolveColumnMappingRuleBasedOnly(ctx context.Context, headers []string, dataRows [][]string, format string, fallback func([]string) (ColumnMap, []string)) (ColumnMap, []string, []Warning, *AIMappingMeta) {
-	return c.resolveColumnMappingWithFallback(ctx, headers, dataRows, format, true, fallback)
+func (c *Converter) resolveColumnMappingRuleBasedOnly(ctx context.Context, headers []string, band HeaderBand, dataRows [][]string, format string, fallback func([]string) (ColumnMap, []string)) (ColumnMap, []string, []Warning, *AIMappingMeta) {
+	return c.resolveColumnMappingWithFallback(ctx, headers, band, dataRows, format, true, fallback)
 }
 
-func (c *Converter) resolveColumnMappingWithFallback(ctx context.Context, headers []string, dataRows [][]string, format string, skipAI bool, fallback func([]string) (ColumnMap, []string)) (ColumnMap, []string, []Warning, *AIMappingMeta) {
+func (c *Converter) resolveColumnMappingWithFallback(ctx context.Context, headers []string, band HeaderBand, dataRows [][]string, format string, skipAI bool, fallback func([]string) (ColumnMap, []string)) (ColumnMap, []string, []Warning, *AIMappingMeta) {
 	meta := &AIMappingMeta{Mode: "off"}
 
 	// For table format, always use fallback (no AI needed)
@@ -77,26 +141,7 @@ func (c *Converter) resolveColumnMappingWithFallback(ctx context.Context, header
 
 	cleanHeaders := SanitizeHeaders(normalizeHeaders(headers))
 	sampleRows := SanitizeSampleRows(buildSampleRows(dataRows, aiSampleRows))
-	// Prompt-injection defense: sanitize headers and sample cells before sending to LLM
-	promptSafeHeaders := ai.SanitizeHeadersForPrompt(cleanHeaders)
-	promptSafeRows := make([][]string, len(sampleRows))
-	for i, row := range sampleRows {
-		promptSafeRows[i] = make([]string, len(row))
-		for j, cell := range row {
-			promptSafeRows[i][j] = ai.SanitizeForPrompt(cell)
-		}
-	}
-	sourceLang := DetectLanguageHint(EstimateEnglishScore(headers, dataRows), headers, dataRows)
-	schemaHint := inferSchemaHint(headers, dataRows)
-
-	result, err := c.aiMapper.MapColumns(ctx, ai.MapColumnsRequest{
-		Headers:    promptSafeHeaders,
-		SampleRows: promptSafeRows,
-		Format:     "spec",
-		FileType:   "table",
-		SourceLang: sourceLang,
-		SchemaHint: schemaHint,
-	})
+	result, err := c.aiMapper.MapColumns(ctx, aiMappingRequest(headers, band, dataRows))
 	if err != nil {
 		meta.Degraded = true
 		colMap, unmapped := fallback(headers)
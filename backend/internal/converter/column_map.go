@@ -1,6 +1,7 @@
 package converter
 
 import (
+	"sort"
 	"strings"
 )
 
@@ -164,6 +165,7 @@ var HeaderSynonyms = map[string]CanonicalField{
 
 	// === Required/Optional (Phase 3) ===
 	"required/optional": FieldRequiredOptional,
+	"required_optional": FieldRequiredOptional,
 	"required":          FieldRequiredOptional,
 	"optional":          FieldRequiredOptional,
 	"必須":                FieldRequiredOptional,
@@ -224,6 +226,16 @@ func NewColumnMapper() *ColumnMapper {
 
 // MapColumns analyzes headers and returns column mapping
 func (m *ColumnMapper) MapColumns(headers []string) (ColumnMap, []string) {
+	return m.mapColumns(headers, false)
+}
+
+// MapBandColumns is MapColumns for the headers band.Headers returned. When the
+// band spans several rows, a composite header is also mapped by its levels.
+func (m *ColumnMapper) MapBandColumns(headers []string, band HeaderBand) (ColumnMap, []string) {
+	return m.mapColumns(headers, band.Rows() > 1)
+}
+
+func (m *ColumnMapper) mapColumns(headers []string, layered bool) (ColumnMap, []string) {
 	colMap := make(ColumnMap)
 	var unmapped []string
 
@@ -234,6 +246,8 @@ func (m *ColumnMapper) MapColumns(headers []string) (ColumnMap, []string) {
 			if _, exists := colMap[field]; !exists {
 				colMap[field] = i
 			}
+		} else if field, ok := m.mapLevels(header, colMap); layered && ok {
+			colMap[field] = i
 		} else {
 			unmapped = append(unmapped, header)
 		}
@@ -242,6 +256,27 @@ func (m *ColumnMapper) MapColumns(headers []string) (ColumnMap, []string) {
 	return colMap, unmapped
 }
 
+// mapLevels maps a composite header from a multi-row header band ("Input /
+// Type") by its levels: the column's own name first, then its groups from the
+// innermost out. A level whose field is already mapped is skipped, so columns
+// sharing a group are not all claimed by it.
+func (m *ColumnMapper) mapLevels(header string, colMap ColumnMap) (CanonicalField, bool) {
+	levels := HeaderLevels(header)
+	if len(levels) < 2 {
+		return "", false
+	}
+	for i := len(levels) - 1; i >= 0; i-- {
+		field, ok := HeaderSynonyms[m.normalizeHeader(levels[i])]
+		if !ok {
+			continue
+		}
+		if _, exists := colMap[field]; !exists {
+			return field, true
+		}
+	}
+	return "", false
+}
+
 // normalizeHeader converts a header to lowercase and trims whitespace
 func (m *ColumnMapper) normalizeHeader(header string) string {
 	// Convert to lowercase
@@ -253,6 +288,25 @@ func (m *ColumnMapper) normalizeHeader(header string) string {
 	return h
 }
 
+// IsCanonicalField reports whether name is a canonical field name such as
+// "feature" or "navigation_destination".
+func IsCanonicalField(name string) bool {
+	field, ok := HeaderSynonyms[name]
+	return ok && string(field) == name
+}
+
+// CanonicalFieldNames lists the canonical field names, sorted.
+func CanonicalFieldNames() []string {
+	var names []string
+	for name, field := range HeaderSynonyms {
+		if string(field) == name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // GetFieldValue extracts a field value from a row using the column map
 func GetFieldValue(row []string, colMap ColumnMap, field CanonicalField) string {
 	if idx, ok := colMap[field]; ok && idx < len(row) {
@@ -2,12 +2,52 @@ package converter
 
 import "strings"
 
-func applyColumnOverrides(matrix CellMatrix, overrides map[string]string) CellMatrix {
+// ApplyColumnOverrides renames header cells (header text -> replacement) so
+// they map to the intended fields. Callers that build a SpecDoc themselves
+// use it to match ConvertMatrixWithOverrides.
+func ApplyColumnOverrides(matrix CellMatrix, overrides map[string]string) CellMatrix {
+	return applyColumnOverrides(matrix, overrides, nil)
+}
+
+// ApplyColumnOverridesWithMerges is ApplyColumnOverrides for a sheet with
+// merged cells, finding the header band as ConvertMatrixWithOverridesAndOptions
+// does when given the same merges.
+func ApplyColumnOverridesWithMerges(matrix CellMatrix, overrides map[string]string, merges []MergedRegion) CellMatrix {
+	return applyColumnOverrides(matrix, overrides, merges)
+}
+
+// SynonymOverrides turns a synonym dictionary (canonical field -> extra
+// header names) into column overrides for the headers of matrix that match.
+func SynonymOverrides(matrix CellMatrix, synonyms map[string][]string) map[string]string {
+	if len(matrix) == 0 || len(synonyms) == 0 {
+		return nil
+	}
+	lookup := map[string]string{}
+	for field, names := range synonyms {
+		for _, name := range names {
+			lookup[normalizeHeaderForMatching(name)] = field
+		}
+	}
+	band, _ := NewHeaderDetector().DetectHeaderBand(matrix, nil)
+	leaf := matrix.GetRow(band.End)
+	overrides := map[string]string{}
+	for i, header := range band.Headers(matrix) {
+		if field, ok := lookup[normalizeHeaderForMatching(header)]; ok {
+			overrides[strings.TrimSpace(header)] = field
+		} else if field, ok := lookup[normalizeHeaderForMatching(cellAt(leaf, i))]; ok {
+			overrides[strings.TrimSpace(leaf[i])] = field
+		}
+	}
+	return overrides
+}
+
+func applyColumnOverrides(matrix CellMatrix, overrides map[string]string, merges []MergedRegion) CellMatrix {
 	if len(matrix) == 0 {
 		return matrix
 	}
 	detector := NewHeaderDetector()
-	headerRowIndex, _ := detector.DetectHeaderRow(matrix)
+	band, _ := detector.DetectHeaderBand(matrix, merges)
+	headerRowIndex := band.End
 	if headerRowIndex < 0 || headerRowIndex >= len(matrix) {
 		return matrix
 	}
@@ -15,6 +55,7 @@ func applyColumnOverrides(matrix CellMatrix, overrides map[string]string) CellMa
 	if len(headerRow) == 0 {
 		return matrix
 	}
+	composite := band.Headers(matrix)
 
 	updated := make(CellMatrix, len(matrix))
 	for rowIdx := range matrix {
@@ -27,6 +68,14 @@ func applyColumnOverrides(matrix CellMatrix, overrides map[string]string) CellMa
 		if trimmed == "" {
 			continue
 		}
+		// A band's composite name ("Input / Type") is what previews show.
+		if override, ok := overrides[strings.TrimSpace(cellAt(composite, i))]; ok && band.Rows() > 1 {
+			override = strings.TrimSpace(override)
+			if override != "" {
+				updated[headerRowIndex][i] = override
+				continue
+			}
+		}
 		if override, ok := overrides[trimmed]; ok {
 			override = strings.TrimSpace(override)
 			if override != "" {
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/yourorg/md-spec-tool/internal/ai"
+	"github.com/yourorg/md-spec-tool/internal/tracing"
 )
 
 // OutputFormat represents supported output formats
@@ -59,6 +62,10 @@ type Converter struct {
 type ConvertOptions struct {
 	IncludeMetadata bool
 	NumberRows      bool
+	// Merges are the merged regions of the matrix (see ParseXLSXWithMerges);
+	// they steer header detection and continuation rows and are reported in
+	// the metadata.
+	Merges []MergedRegion
 }
 
 // DefaultConvertOptions returns the default conversion options.
@@ -119,6 +126,12 @@ func (c *Converter) CloneWithAIService(service ai.Service) *Converter {
 	return clone
 }
 
+// WithMergeFill sets which cells of merged XLSX regions take the region's value
+func (c *Converter) WithMergeFill(fill MergeFill) *Converter {
+	c.xlsxParser = NewXLSXParser().WithMergeFill(fill)
+	return c
+}
+
 // WithAIService injects an AI service for column mapping
 func (c *Converter) WithAIService(service ai.Service) *Converter {
 	c.aiService = service
@@ -140,17 +153,40 @@ func BuildSpecDocFromPaste(text string) (*SpecDoc, error) {
 	if err != nil {
 		return nil, err
 	}
+	return BuildSpecDocFromMatrix(matrix), nil
+}
+
+// BuildSpecDocFromTable parses pasted TSV/CSV as a table, skipping the
+// markdown detection BuildSpecDocFromPaste applies to narrow input.
+func BuildSpecDocFromTable(text string) (*SpecDoc, error) {
+	matrix, err := NewPasteParser().Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return BuildSpecDocFromMatrix(matrix), nil
+}
+
+// BuildSpecDocFromMatrix maps a parsed sheet into a SpecDoc without rendering,
+// e.g. to run validation rules against an uploaded file.
+func BuildSpecDocFromMatrix(matrix CellMatrix) *SpecDoc {
+	return BuildSpecDocFromMatrixWithMerges(matrix, nil)
+}
+
+// BuildSpecDocFromMatrixWithMerges is BuildSpecDocFromMatrix for a sheet with
+// merged regions.
+func BuildSpecDocFromMatrixWithMerges(matrix CellMatrix, merges []MergedRegion) *SpecDoc {
 	if len(matrix) == 0 {
-		return &SpecDoc{Title: "Converted Spec"}, nil
+		return &SpecDoc{Title: "Converted Spec"}
 	}
 
 	converter := NewConverter()
-	headerRow, _ := converter.headerDetector.DetectHeaderRow(matrix)
-	headers := matrix.GetRow(headerRow)
-	colMap, unmapped := converter.columnMapper.MapColumns(headers)
+	band, _ := converter.headerDetector.DetectHeaderBand(matrix, merges)
+	headers := band.Headers(matrix)
+	colMap, unmapped := converter.columnMapper.MapBandColumns(headers, band)
 
-	specDoc := converter.buildSpecDoc(matrix, headerRow, headers, colMap, unmapped, "")
-	return specDoc, nil
+	doc := converter.buildSpecDoc(matrix, band.End, headers, colMap, unmapped, "", merges)
+	doc.Meta.HeaderBand = multiRowBand(band)
+	return doc
 }
 
 // ConvertPaste converts pasted text to MDFlow
@@ -220,11 +256,18 @@ func (c *Converter) resolvePasteParseFirst(text string) pasteParseFirstResult {
 // Uses parse-first strategy: when paste parses as a multi-column table, always takes table path.
 // Otherwise falls back to DetectInputType (e.g. markdown, single-column, or parse failure).
 func (c *Converter) ConvertPasteWithOverridesAndOptions(ctx context.Context, text string, templateName string, outputFormat string, overrides map[string]string, options ConvertOptions) (*ConvertResponse, error) {
+	_, parseSpan := tracing.Start(ctx, "converter.parse_input", attribute.String("input.type", "paste"))
 	res := c.resolvePasteParseFirst(text)
+	parseSpan.SetAttributes(
+		attribute.String("input.detected_type", string(res.analysis.Type)),
+		attribute.Int("input.rows", res.matrix.RowCount()),
+		attribute.Int("input.columns", res.matrix.ColCount()),
+	)
+	tracing.End(parseSpan, res.err)
 	if res.hasTable {
 		matrix := res.matrix
 		if len(overrides) > 0 {
-			matrix = applyColumnOverrides(matrix, overrides)
+			matrix = applyColumnOverrides(matrix, overrides, nil)
 		}
 		return c.convertMatrixWithFormatAndOptions(ctx, matrix, "", templateName, outputFormat, options)
 	}
@@ -239,7 +282,7 @@ func (c *Converter) ConvertPasteWithOverridesAndOptions(ctx context.Context, tex
 	if res.matrix != nil && res.matrix.RowCount() > 0 {
 		matrix := res.matrix
 		if len(overrides) > 0 {
-			matrix = applyColumnOverrides(matrix, overrides)
+			matrix = applyColumnOverrides(matrix, overrides, nil)
 		}
 		return c.convertMatrixWithFormatAndOptions(ctx, matrix, "", templateName, outputFormat, options)
 	}
@@ -273,7 +316,7 @@ func (c *Converter) ConvertMatrixWithOverrides(ctx context.Context, matrix CellM
 // ConvertMatrixWithOverridesAndOptions applies column overrides and rendering options before conversion.
 func (c *Converter) ConvertMatrixWithOverridesAndOptions(ctx context.Context, matrix CellMatrix, sheetName string, templateName string, outputFormat string, overrides map[string]string, options ConvertOptions) (*ConvertResponse, error) {
 	if len(overrides) > 0 {
-		matrix = applyColumnOverrides(matrix, overrides)
+		matrix = applyColumnOverrides(matrix, overrides, options.Merges)
 	}
 	return c.convertMatrixWithFormatAndOptions(ctx, matrix, sheetName, templateName, outputFormat, options)
 }
@@ -294,48 +337,92 @@ func (c *Converter) convertMarkdown(text string, template string) (*ConvertRespo
 	}, nil
 }
 
-// ConvertXLSX converts an XLSX file to MDFlow
+// ConvertXLSX converts a workbook file (.xlsx, .xls or .ods) to MDFlow
 func (c *Converter) ConvertXLSX(filePath string, sheetName string, template string) (*ConvertResponse, error) {
-	var matrix CellMatrix
-	var err error
-
-	if sheetName == "" {
-		result, err := c.xlsxParser.ParseFile(filePath)
-		if err != nil {
-			return nil, err
-		}
-		sheetName = result.ActiveSheet
-		matrix = result.GetMatrix(sheetName)
-	} else {
-		matrix, err = c.xlsxParser.ParseSheet(filePath, sheetName)
-		if err != nil {
-			return nil, err
-		}
+	matrix, sheetName, merges, err := c.parseXLSX(filePath, sheetName)
+	if err != nil {
+		return nil, err
 	}
 
-	return c.convertMatrixWithFormat(context.Background(), matrix, sheetName, template, "")
+	options := DefaultConvertOptions()
+	options.Merges = merges
+	return c.convertMatrixWithFormatAndOptions(context.Background(), matrix, sheetName, template, "", options)
 }
 
-// GetXLSXSheets returns list of sheets in an XLSX file
+// GetXLSXSheets returns list of sheets in a workbook file
 func (c *Converter) GetXLSXSheets(filePath string) ([]string, error) {
-	result, err := c.xlsxParser.ParseFile(filePath)
+	result, err := c.spreadsheetParser(filePath).ParseFile(filePath)
 	if err != nil {
 		return nil, err
 	}
 	return result.Sheets, nil
 }
 
-// ParseXLSX parses an XLSX file and returns the cell matrix
+// ParseXLSX parses a workbook file (.xlsx, .xls or .ods) and returns the cell matrix
 func (c *Converter) ParseXLSX(filePath string, sheetName string) (CellMatrix, error) {
+	return c.ParseXLSXWithContext(context.Background(), filePath, sheetName)
+}
+
+// ParseXLSXWithContext is ParseXLSX recorded as an input parsing span under ctx.
+func (c *Converter) ParseXLSXWithContext(ctx context.Context, filePath string, sheetName string) (CellMatrix, error) {
+	matrix, _, err := c.ParseXLSXWithMerges(ctx, filePath, sheetName)
+	return matrix, err
+}
+
+// ParseXLSXWithMerges is ParseXLSXWithContext that also returns the sheet's
+// merged regions, for ConvertOptions.Merges. Only .xlsx files report merges.
+func (c *Converter) ParseXLSXWithMerges(ctx context.Context, filePath string, sheetName string) (CellMatrix, []MergedRegion, error) {
+	_, span := tracing.Start(ctx, "converter.parse_input",
+		attribute.String("input.type", spreadsheetType(filePath)),
+		attribute.String("input.sheet", sheetName),
+	)
+	matrix, _, merges, err := c.parseXLSX(filePath, sheetName)
+	span.SetAttributes(
+		attribute.Int("input.rows", matrix.RowCount()),
+		attribute.Int("input.columns", matrix.ColCount()),
+		attribute.Int("input.merged_cells", len(merges)),
+	)
+	tracing.End(span, err)
+	return matrix, merges, err
+}
+
+// ParseXLSXSheet parses one sheet and returns it with its name; an empty
+// sheetName selects the workbook's active sheet.
+func (c *Converter) ParseXLSXSheet(filePath string, sheetName string) (CellMatrix, string, error) {
+	matrix, sheetName, _, err := c.parseXLSX(filePath, sheetName)
+	return matrix, sheetName, err
+}
+
+// ParseXLSXSheetWithMerges is ParseXLSXSheet that also returns the sheet's
+// merged regions.
+func (c *Converter) ParseXLSXSheetWithMerges(filePath string, sheetName string) (CellMatrix, string, []MergedRegion, error) {
+	return c.parseXLSX(filePath, sheetName)
+}
+
+func (c *Converter) parseXLSX(filePath string, sheetName string) (CellMatrix, string, []MergedRegion, error) {
+	parser := c.spreadsheetParser(filePath)
 	if sheetName == "" {
-		result, err := c.xlsxParser.ParseFile(filePath)
+		result, err := parser.ParseFile(filePath)
 		if err != nil {
-			return nil, err
+			return nil, "", nil, err
 		}
 		sheetName = result.ActiveSheet
-		return result.GetMatrix(sheetName), nil
+		return result.GetMatrix(sheetName), sheetName, result.Merges[sheetName], nil
 	}
-	return c.xlsxParser.ParseSheet(filePath, sheetName)
+	if parser == SpreadsheetParser(c.xlsxParser) {
+		matrix, merges, err := c.xlsxParser.ParseSheetWithMerges(filePath, sheetName)
+		return matrix, sheetName, merges, err
+	}
+	matrix, err := parser.ParseSheet(filePath, sheetName)
+	return matrix, sheetName, nil, err
+}
+
+// spreadsheetParser picks the parser for a workbook by its extension.
+func (c *Converter) spreadsheetParser(filePath string) SpreadsheetParser {
+	if spreadsheetType(filePath) == "xlsx" {
+		return c.xlsxParser
+	}
+	return SpreadsheetParserFor(filePath)
 }
 
 // convertMatrixWithFormat converts a CellMatrix to markdown with output format option
@@ -372,7 +459,11 @@ func (c *Converter) convertWithTemplate(ctx context.Context, matrix CellMatrix,
 	}
 
 	// Detect header row
-	headerRow, confidence := c.headerDetector.DetectHeaderRow(matrix)
+	_, headerSpan := tracing.Start(ctx, "converter.detect_header")
+	band, confidence := c.headerDetector.DetectHeaderBand(matrix, options.Merges)
+	headerRow := band.End
+	headerSpan.SetAttributes(attribute.Int("header.row", headerRow), attribute.Int("header.rows", band.Rows()), attribute.Int("header.confidence", confidence))
+	headerSpan.End()
 
 	var warnings []Warning
 	if confidence < 50 {
@@ -387,9 +478,9 @@ func (c *Converter) convertWithTemplate(ctx context.Context, matrix CellMatrix,
 	}
 
 	// Parse matrix to Table (schema-agnostic)
-	headers := matrix.GetRow(headerRow)
+	headers := band.Headers(matrix)
 	dataRows := matrix.SliceRows(headerRow+1, matrix.RowCount())
-	colMap, unmapped, mappingWarnings, aiMeta := c.resolveColumnMapping(ctx, headers, dataRows, format)
+	colMap, unmapped, mappingWarnings, aiMeta := c.resolveColumnMapping(ctx, headers, band, dataRows, format)
 	warnings = append(warnings, mappingWarnings...)
 	colMap, unmapped, inferredWarnings := enhanceColumnMapping(headers, dataRows, colMap)
 	warnings = append(warnings, inferredWarnings...)
@@ -418,27 +509,7 @@ func (c *Converter) convertWithTemplate(ctx context.Context, matrix CellMatrix,
 	table.Meta.NumberRows = options.NumberRows
 	applyAIMetaToTableMeta(&table.Meta, aiMeta)
 
-	// Load template - use format name if templateName is empty
-	templateToUse := templateName
-	if templateToUse == "" {
-		templateToUse = format // Try to use format as template name
-	}
-	template := c.templateRegistry.LoadTemplateOrDefault(templateToUse)
-
-	// Create renderer using factory (Phase 4)
-	var renderer Renderer
-	var err error
-	if format == "table" {
-		renderer, err = NewRendererSimple("table")
-	} else {
-		renderer, err = c.rendererFactory.CreateRenderer(template)
-	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to create renderer for format '%s': %w", format, err)
-	}
-
-	// Render using template
-	mdflow, renderWarnings, err := renderer.Render(table)
+	template, mdflow, renderWarnings, err := c.renderTable(ctx, table, templateName, format)
 	if err != nil {
 		return nil, err
 	}
@@ -463,16 +534,97 @@ func (c *Converter) convertWithTemplate(ctx context.Context, matrix CellMatrix,
 		UnmappedColumns: unmapped,
 		TotalRows:       table.RowCount(),
 		OutputFormat:    format,
+		MergedCells:     options.Merges,
+		HeaderBand:      multiRowBand(band),
 	}
 	applyAIMeta(&meta, aiMeta)
 
+	doc := c.buildSpecDoc(matrix, headerRow, headers, colMap, unmapped, sheetName, options.Merges)
+	if pack := template.RulePack(colMap); pack != nil {
+		packWarnings, report := runRulePack(ctx, template.Name, doc, pack)
+		warnings = append(warnings, packWarnings...)
+		meta.RulePack = report
+	}
+
 	return &ConvertResponse{
 		MDFlow:   mdflow,
 		Warnings: warnings,
 		Meta:     meta,
+		Doc:      doc,
 	}, nil
 }
 
+// renderTable renders a mapped table with the template (or the format's
+// template when templateName is empty) and returns the template used.
+func (c *Converter) renderTable(ctx context.Context, table *Table, templateName string, format string) (*TemplateConfig, string, []string, error) {
+	templateToUse := templateName
+	if templateToUse == "" {
+		templateToUse = format // Try to use format as template name
+	}
+	template := c.templateRegistry.LoadTemplateOrDefault(templateToUse)
+
+	// Create renderer using factory (Phase 4)
+	var renderer Renderer
+	var err error
+	if format == "table" {
+		renderer, err = NewRendererSimple("table")
+	} else {
+		renderer, err = c.rendererFactory.CreateRenderer(template)
+	}
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to create renderer for format '%s': %w", format, err)
+	}
+
+	_, renderSpan := tracing.Start(ctx, "converter.render",
+		attribute.String("render.format", format),
+		attribute.String("render.template", template.Name),
+	)
+	mdflow, renderWarnings, err := renderer.Render(table)
+	renderSpan.SetAttributes(attribute.Int("render.warnings", len(renderWarnings)))
+	tracing.End(renderSpan, err)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return template, mdflow, renderWarnings, nil
+}
+
+// RenderMapped renders matrix in another format with the header band and
+// column mapping an earlier conversion recorded in meta, so columns are not
+// mapped again and AI is not called. matrix is the converted input, after
+// column overrides.
+func (c *Converter) RenderMapped(ctx context.Context, matrix CellMatrix, meta SpecDocMeta, templateName string, format string, options ConvertOptions) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if OutputFormat(format) != OutputFormatSpec && OutputFormat(format) != OutputFormatTable {
+		return "", fmt.Errorf("invalid output format '%s': must be 'spec' or 'table'", format)
+	}
+	if len(matrix) == 0 {
+		return "", nil
+	}
+
+	table := c.tableParser.MatrixToTable(meta.Headers(matrix), matrix.SliceRows(meta.HeaderRow+1, matrix.RowCount()), meta.SheetName)
+	table.Meta.HeaderRowIndex = meta.HeaderRow
+	table.Meta.ColumnMap = meta.ColumnMap
+	table.Meta.IncludeMetadata = options.IncludeMetadata
+	table.Meta.NumberRows = options.NumberRows
+	applyAIMetaToTableMeta(&table.Meta, &AIMappingMeta{
+		Mode:                  meta.AIMode,
+		Used:                  meta.AIUsed,
+		Degraded:              meta.AIDegraded,
+		FallbackReason:        meta.AIFallbackReason,
+		Model:                 meta.AIModel,
+		PromptVersion:         meta.AIPromptVersion,
+		AvgConfidence:         meta.AIAvgConfidence,
+		MappedColumns:         meta.AIMappedColumns,
+		UnmappedColumns:       meta.AIUnmappedColumns,
+		EstimatedInputTokens:  meta.AIEstimatedInputTokens,
+		EstimatedOutputTokens: meta.AIEstimatedOutputTokens,
+		EstimatedCostUSD:      meta.AIEstimatedCostUSD,
+	})
+
+	_, mdflow, _, err := c.renderTable(ctx, table, templateName, format)
+	return mdflow, err
+}
+
 // convertToGenericTable converts matrix to simple Markdown table format (Phase 2)
 func (c *Converter) convertToGenericTable(matrix CellMatrix, sheetName string) (*ConvertResponse, error) {
 	if len(matrix) == 0 {
@@ -545,50 +697,19 @@ func convertStringWarningsToWarningObjects(strWarnings []string) []Warning {
 }
 
 // buildSpecDoc constructs a SpecDoc from parsed data
-func (c *Converter) buildSpecDoc(matrix CellMatrix, headerRow int, headers []string, colMap ColumnMap, unmapped []string, sheetName string) *SpecDoc {
+// Cells filled from merged regions count as the row's values but not as text
+// of its own when deciding whether it continues the previous row.
+func (c *Converter) buildSpecDoc(matrix CellMatrix, headerRow int, headers []string, colMap ColumnMap, unmapped []string, sheetName string, merges []MergedRegion) *SpecDoc {
 	// Count rows by feature
 	rowsByFeature := make(map[string]int)
 
 	var rows []SpecRow
 	dataRows := matrix.SliceRows(headerRow+1, matrix.RowCount())
 
-	for _, row := range dataRows {
-		specRow := SpecRow{
-			ID:           normalizeCell(GetFieldValue(row, colMap, FieldID)),
-			Title:        normalizeCell(GetFieldValue(row, colMap, FieldTitle)),
-			Description:  normalizeCell(GetFieldValue(row, colMap, FieldDescription)),
-			Acceptance:   normalizeCell(GetFieldValue(row, colMap, FieldAcceptance)),
-			Feature:      normalizeCell(GetFieldValue(row, colMap, FieldFeature)),
-			Scenario:     normalizeCell(GetFieldValue(row, colMap, FieldScenario)),
-			Instructions: normalizeCell(GetFieldValue(row, colMap, FieldInstructions)),
-			Inputs:       normalizeCell(GetFieldValue(row, colMap, FieldInputs)),
-			Expected:     normalizeCell(GetFieldValue(row, colMap, FieldExpected)),
-			Precondition: normalizeCell(GetFieldValue(row, colMap, FieldPrecondition)),
-			Priority:     normalizeCell(GetFieldValue(row, colMap, FieldPriority)),
-			Type:         normalizeCell(GetFieldValue(row, colMap, FieldType)),
-			Status:       normalizeCell(GetFieldValue(row, colMap, FieldStatus)),
-			Endpoint:     normalizeCell(GetFieldValue(row, colMap, FieldEndpoint)),
-			Method:       normalizeCell(GetFieldValue(row, colMap, FieldMethod)),
-			Parameters:   normalizeCell(GetFieldValue(row, colMap, FieldParameters)),
-			Response:     normalizeCell(GetFieldValue(row, colMap, FieldResponse)),
-			StatusCode:   normalizeCell(GetFieldValue(row, colMap, FieldStatusCode)),
-			Notes:        normalizeCell(GetFieldValue(row, colMap, FieldNotes)),
-			Component:    normalizeCell(GetFieldValue(row, colMap, FieldComponent)),
-			Assignee:     normalizeCell(GetFieldValue(row, colMap, FieldAssignee)),
-			Category:     normalizeCell(GetFieldValue(row, colMap, FieldCategory)),
-
-			// Phase 3 fields
-			No:                normalizeCell(GetFieldValue(row, colMap, FieldNo)),
-			ItemName:          normalizeCell(GetFieldValue(row, colMap, FieldItemName)),
-			ItemType:          normalizeCell(GetFieldValue(row, colMap, FieldItemType)),
-			RequiredOptional:  normalizeCell(GetFieldValue(row, colMap, FieldRequiredOptional)),
-			InputRestrictions: normalizeCell(GetFieldValue(row, colMap, FieldInputRestrictions)),
-			DisplayConditions: normalizeCell(GetFieldValue(row, colMap, FieldDisplayConditions)),
-			Action:            normalizeCell(GetFieldValue(row, colMap, FieldAction)),
-			NavigationDest:    normalizeCell(GetFieldValue(row, colMap, FieldNavigationDest)),
-
-			Metadata: make(map[string]string),
-		}
+	for i, row := range dataRows {
+		specRow := specRowFromCells(row, colMap)
+		specRow.Metadata = make(map[string]string)
+		specRow.SourceRow = headerRow + i + 2 // 0-based header index + 1-based data offset
 
 		// Store unmapped columns in metadata
 		for i, header := range headers {
@@ -607,7 +728,11 @@ func (c *Converter) buildSpecDoc(matrix CellMatrix, headerRow int, headers []str
 			}
 		}
 
-		if shouldAppendContinuation(rows, specRow) {
+		continuation := specRow
+		if len(merges) > 0 {
+			continuation = specRowFromCells(ownCells(row, headerRow+1+i, merges), colMap)
+		}
+		if shouldAppendContinuation(rows, continuation) {
 			continue
 		}
 
@@ -691,10 +816,49 @@ func (c *Converter) buildSpecDoc(matrix CellMatrix, headerRow int, headers []str
 			UnmappedColumns: unmapped,
 			TotalRows:       len(rows),
 			RowsByFeature:   rowsByFeature,
+			MergedCells:     merges,
 		},
 	}
 }
 
+// specRowFromCells maps one data row's cells to a SpecRow through colMap.
+func specRowFromCells(row []string, colMap ColumnMap) SpecRow {
+	return SpecRow{
+		ID:           normalizeCell(GetFieldValue(row, colMap, FieldID)),
+		Title:        normalizeCell(GetFieldValue(row, colMap, FieldTitle)),
+		Description:  normalizeCell(GetFieldValue(row, colMap, FieldDescription)),
+		Acceptance:   normalizeCell(GetFieldValue(row, colMap, FieldAcceptance)),
+		Feature:      normalizeCell(GetFieldValue(row, colMap, FieldFeature)),
+		Scenario:     normalizeCell(GetFieldValue(row, colMap, FieldScenario)),
+		Instructions: normalizeCell(GetFieldValue(row, colMap, FieldInstructions)),
+		Inputs:       normalizeCell(GetFieldValue(row, colMap, FieldInputs)),
+		Expected:     normalizeCell(GetFieldValue(row, colMap, FieldExpected)),
+		Precondition: normalizeCell(GetFieldValue(row, colMap, FieldPrecondition)),
+		Priority:     normalizeCell(GetFieldValue(row, colMap, FieldPriority)),
+		Type:         normalizeCell(GetFieldValue(row, colMap, FieldType)),
+		Status:       normalizeCell(GetFieldValue(row, colMap, FieldStatus)),
+		Endpoint:     normalizeCell(GetFieldValue(row, colMap, FieldEndpoint)),
+		Method:       normalizeCell(GetFieldValue(row, colMap, FieldMethod)),
+		Parameters:   normalizeCell(GetFieldValue(row, colMap, FieldParameters)),
+		Response:     normalizeCell(GetFieldValue(row, colMap, FieldResponse)),
+		StatusCode:   normalizeCell(GetFieldValue(row, colMap, FieldStatusCode)),
+		Notes:        normalizeCell(GetFieldValue(row, colMap, FieldNotes)),
+		Component:    normalizeCell(GetFieldValue(row, colMap, FieldComponent)),
+		Assignee:     normalizeCell(GetFieldValue(row, colMap, FieldAssignee)),
+		Category:     normalizeCell(GetFieldValue(row, colMap, FieldCategory)),
+
+		// Phase 3 fields
+		No:                normalizeCell(GetFieldValue(row, colMap, FieldNo)),
+		ItemName:          normalizeCell(GetFieldValue(row, colMap, FieldItemName)),
+		ItemType:          normalizeCell(GetFieldValue(row, colMap, FieldItemType)),
+		RequiredOptional:  normalizeCell(GetFieldValue(row, colMap, FieldRequiredOptional)),
+		InputRestrictions: normalizeCell(GetFieldValue(row, colMap, FieldInputRestrictions)),
+		DisplayConditions: normalizeCell(GetFieldValue(row, colMap, FieldDisplayConditions)),
+		Action:            normalizeCell(GetFieldValue(row, colMap, FieldAction)),
+		NavigationDest:    normalizeCell(GetFieldValue(row, colMap, FieldNavigationDest)),
+	}
+}
+
 func joinStrings(strs []string, sep string) string {
 	return strings.Join(strs, sep)
 }
@@ -840,14 +1004,15 @@ func (c *Converter) GetPreviewColumnMapping(headers []string, templateName strin
 
 // GetPreviewColumnMappingWithContext returns column mapping using AI when available.
 // Falls back to template-driven resolver when AI is off/low confidence.
-func (c *Converter) GetPreviewColumnMappingWithContext(ctx context.Context, headers []string, dataRows [][]string, templateName string, format string) (columnMapping map[string]string, unmapped []string) {
+// headers are those band.Headers returned.
+func (c *Converter) GetPreviewColumnMappingWithContext(ctx context.Context, headers []string, band HeaderBand, dataRows [][]string, templateName string, format string) (columnMapping map[string]string, unmapped []string) {
 	if templateName == "" {
 		templateName = DefaultTemplateName
 	}
 	template := c.templateRegistry.LoadTemplateOrDefault(templateName)
 	resolver := NewHeaderResolver(template)
-	colMap, unmapped, _, _ := c.resolveColumnMappingWithFallback(ctx, headers, dataRows, format, false, func(h []string) (ColumnMap, []string) {
-		resolved, unresolved, _ := resolver.ResolveHeaders(h)
+	colMap, unmapped, _, _ := c.resolveColumnMappingWithFallback(ctx, headers, band, dataRows, format, false, func(h []string) (ColumnMap, []string) {
+		resolved, unresolved, _ := resolver.ResolveBandHeaders(h, band)
 		return resolved, unresolved
 	})
 	colMap, unmapped, _ = enhanceColumnMapping(headers, dataRows, colMap)
@@ -862,13 +1027,14 @@ func (c *Converter) GetPreviewColumnMappingWithContext(ctx context.Context, head
 
 // GetPreviewColumnMappingRuleBased returns column mapping using only rule-based resolution.
 // Never calls AI service. Used by preview endpoints when skip_ai=true for guaranteed fast response.
-func (c *Converter) GetPreviewColumnMappingRuleBased(headers []string, templateName string) (columnMapping map[string]string, unmapped []string) {
+// headers are those band.Headers returned.
+func (c *Converter) GetPreviewColumnMappingRuleBased(headers []string, band HeaderBand, templateName string) (columnMapping map[string]string, unmapped []string) {
 	if templateName == "" {
 		templateName = DefaultTemplateName
 	}
 	template := c.templateRegistry.LoadTemplateOrDefault(templateName)
 	resolver := NewHeaderResolver(template)
-	colMap, unmapped, _ := resolver.ResolveHeaders(headers)
+	colMap, unmapped, _ := resolver.ResolveBandHeaders(headers, band)
 	colMap, unmapped, _ = enhanceColumnMapping(headers, nil, colMap)
 	columnMapping = make(map[string]string)
 	for field, idx := range colMap {
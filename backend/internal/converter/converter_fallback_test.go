@@ -25,6 +25,9 @@ func (m *mockUnavailableAI) SummarizeDiff(ctx context.Context, req ai.SummarizeD
 func (m *mockUnavailableAI) ValidateSemantic(ctx context.Context, req ai.SemanticValidationRequest) (*ai.SemanticValidationResult, error) {
 	return nil, &ai.AIError{Err: ai.ErrAIUnavailable, Message: "circuit breaker open"}
 }
+func (m *mockUnavailableAI) ExtractRequirements(ctx context.Context, req ai.ExtractRequirementsRequest) (*ai.RequirementsExtraction, error) {
+	return nil, &ai.AIError{Err: ai.ErrAIUnavailable, Message: "circuit breaker open"}
+}
 func (m *mockUnavailableAI) GetMode() string  { return "on" }
 func (m *mockUnavailableAI) GetModel() string { return "mock" }
 
@@ -153,6 +156,9 @@ func (m *mockPartialAI) SummarizeDiff(ctx context.Context, req ai.SummarizeDiffR
 func (m *mockPartialAI) ValidateSemantic(ctx context.Context, req ai.SemanticValidationRequest) (*ai.SemanticValidationResult, error) {
 	return nil, &ai.AIError{Err: ai.ErrAIUnavailable, Message: "not implemented"}
 }
+func (m *mockPartialAI) ExtractRequirements(ctx context.Context, req ai.ExtractRequirementsRequest) (*ai.RequirementsExtraction, error) {
+	return nil, &ai.AIError{Err: ai.ErrAIUnavailable, Message: "not implemented"}
+}
 func (m *mockPartialAI) GetMode() string  { return "on" }
 func (m *mockPartialAI) GetModel() string { return "mock-partial" }
 
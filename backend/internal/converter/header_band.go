@@ -0,0 +1,234 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/yourorg/md-spec-tool/internal/ai"
+)
+
+// maxHeaderBandRows caps how many rows a header may span.
+const maxHeaderBandRows = 3
+
+// HeaderLevelSeparator joins the levels of a multi-row header into one
+// composite column name, e.g. "Input / Type". The AI mapper is told the same.
+const HeaderLevelSeparator = ai.HeaderLevelSeparator
+
+// HeaderBand is the rows a table header spans (0-based, inclusive). End is the
+// row naming the columns; rows above it group those columns, like an "Input"
+// cell over "Field", "Type" and "Required". A one-row header has Start == End.
+type HeaderBand struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Rows is the number of rows in the band.
+func (b HeaderBand) Rows() int {
+	return b.End - b.Start + 1
+}
+
+// Headers returns one name per column: the group labels above the column and
+// its own name, top-down, joined by HeaderLevelSeparator. A one-row band
+// returns the header row as it is.
+func (b HeaderBand) Headers(matrix CellMatrix) []string {
+	if b.Rows() <= 1 || b.Start < 0 || b.End >= len(matrix) {
+		return matrix.GetRow(b.End)
+	}
+
+	width := 0
+	for r := b.Start; r <= b.End; r++ {
+		width = max(width, len(matrix[r]))
+	}
+	levels := make([][]string, width)
+	for r := b.Start; r < b.End; r++ {
+		for _, s := range headerSpans(matrix[r], labelledBelow(matrix, r+1, b.End, width)) {
+			for c := s.start; c <= s.end; c++ {
+				levels[c] = appendLevel(levels[c], s.label)
+			}
+		}
+	}
+
+	headers := make([]string, width)
+	for c := range headers {
+		headers[c] = strings.Join(appendLevel(levels[c], cellAt(matrix[b.End], c)), HeaderLevelSeparator)
+	}
+	return headers
+}
+
+// appendLevel adds label to a column's levels unless it is blank or repeats
+// the level above, as a header merged down its band does once filled.
+func appendLevel(levels []string, label string) []string {
+	label = strings.TrimSpace(label)
+	if label == "" || (len(levels) > 0 && levels[len(levels)-1] == label) {
+		return levels
+	}
+	return append(levels, label)
+}
+
+// HeaderLevels splits a composite header name into its levels, top-down.
+func HeaderLevels(header string) []string {
+	parts := strings.Split(header, HeaderLevelSeparator)
+	levels := parts[:0]
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			levels = append(levels, part)
+		}
+	}
+	return levels
+}
+
+// DetectHeaderBand finds the header row like DetectHeaderRowWithMerges, then
+// widens it to the group rows above it and, when the detected row is itself a
+// group row, the rows below naming the grouped columns. The confidence is
+// that of the detected row.
+func (d *HeaderDetector) DetectHeaderBand(matrix CellMatrix, merges []MergedRegion) (HeaderBand, int) {
+	row, confidence := d.DetectHeaderRowWithMerges(matrix, merges)
+	band := HeaderBand{Start: row, End: row}
+	if len(matrix) == 0 {
+		return band, confidence
+	}
+
+	for end := min(row+maxHeaderBandRows, len(matrix)) - 1; end > row; end-- {
+		if d.groupsBand(matrix, row, end, merges) {
+			band.End = end
+			break
+		}
+	}
+	for band.Rows() < maxHeaderBandRows && band.Start > 0 && d.groupsColumns(matrix, band.Start-1, band.End, merges) {
+		band.Start--
+	}
+	return band, confidence
+}
+
+// groupsColumns reports whether row upper groups the columns named by the
+// rows below it down to end: every cell in those rows reads like a header,
+// at least one upper label spans two or more labelled columns, and the other
+// upper labels sit over blanks or repeat themselves. A title over the whole
+// table is a banner, not a group.
+func (d *HeaderDetector) groupsColumns(matrix CellMatrix, upper, end int, merges []MergedRegion) bool {
+	if isBannerRow(matrix[upper], upper, merges) || d.scoreRow(matrix[end]) == 0 {
+		return false
+	}
+	width := 0
+	for r := upper; r <= end; r++ {
+		for _, cell := range matrix[r] {
+			if strings.TrimSpace(cell) != "" && !d.looksLikeHeader(cell) {
+				return false
+			}
+		}
+		width = max(width, len(matrix[r]))
+	}
+
+	below := labelledBelow(matrix, upper+1, end, width)
+	labelled := 0
+	for _, ok := range below {
+		if ok {
+			labelled++
+		}
+	}
+	spans := headerSpans(matrix[upper], below)
+	groups := 0
+	for _, s := range spans {
+		if s.children >= 2 {
+			groups++
+			continue
+		}
+		if next := strings.TrimSpace(cellAt(matrix[upper+1], s.start)); next != "" && next != s.label {
+			return false
+		}
+	}
+	if len(spans) == 1 && spans[0].children == labelled {
+		return false
+	}
+	return groups > 0
+}
+
+// groupsBand reports whether each of rows start..end-1 groups the columns
+// named below it, so the rows form one header naming columns on end.
+func (d *HeaderDetector) groupsBand(matrix CellMatrix, start, end int, merges []MergedRegion) bool {
+	for r := start; r < end; r++ {
+		if !d.groupsColumns(matrix, r, end, merges) {
+			return false
+		}
+	}
+	return true
+}
+
+// headerSpan is a label in a group row and the columns it covers.
+type headerSpan struct {
+	label      string
+	start, end int
+	children   int // labelled columns below the span
+}
+
+// headerSpans splits a header row into labels and the columns each covers: a
+// label extends right over cells repeating it, as merged cells do once
+// filled, and over blank cells with a labelled column below.
+func headerSpans(row []string, below []bool) []headerSpan {
+	var spans []headerSpan
+	for c := 0; c < len(below); c++ {
+		label := strings.TrimSpace(cellAt(row, c))
+		if label == "" {
+			continue
+		}
+		s := headerSpan{label: label, start: c, end: c}
+		for s.end+1 < len(below) {
+			next := strings.TrimSpace(cellAt(row, s.end+1))
+			if next != label && (next != "" || !below[s.end+1]) {
+				break
+			}
+			s.end++
+		}
+		for i := s.start; i <= s.end; i++ {
+			if below[i] {
+				s.children++
+			}
+		}
+		spans = append(spans, s)
+		c = s.end
+	}
+	return spans
+}
+
+// labelledBelow reports, per column, whether any of rows from..to has a label.
+func labelledBelow(matrix CellMatrix, from, to, width int) []bool {
+	below := make([]bool, width)
+	for r := from; r <= to && r < len(matrix); r++ {
+		for c, cell := range matrix[r] {
+			if c < width && strings.TrimSpace(cell) != "" {
+				below[c] = true
+			}
+		}
+	}
+	return below
+}
+
+func cellAt(row []string, col int) string {
+	if col < len(row) {
+		return row[col]
+	}
+	return ""
+}
+
+// multiRowBand returns band when it spans several rows and nil otherwise, for
+// metadata that only mentions the band when there is more than one row.
+func multiRowBand(band HeaderBand) *HeaderBand {
+	if band.Rows() <= 1 {
+		return nil
+	}
+	return &band
+}
+
+// Band returns the header band of the conversion described by m: its
+// multi-row band, or its header row.
+func (m SpecDocMeta) Band() HeaderBand {
+	if m.HeaderBand != nil {
+		return *m.HeaderBand
+	}
+	return HeaderBand{Start: m.HeaderRow, End: m.HeaderRow}
+}
+
+// Headers returns the column names of matrix the conversion described by m
+// mapped: the composite names of its header band, or its header row.
+func (m SpecDocMeta) Headers(matrix CellMatrix) []string {
+	return m.Band().Headers(matrix)
+}
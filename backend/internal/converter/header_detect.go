@@ -30,6 +30,14 @@ func NewHeaderDetector() *HeaderDetector {
 // DetectHeaderRow finds the most likely header row
 // Returns the row index (0-based) and a confidence score (0-100)
 func (d *HeaderDetector) DetectHeaderRow(matrix CellMatrix) (int, int) {
+	return d.DetectHeaderRowWithMerges(matrix, nil)
+}
+
+// DetectHeaderRowWithMerges is DetectHeaderRow for a sheet with merged cells.
+// A row that is one merged region is a title banner, not a header, and a row
+// whose merged cells group the columns of the row below is the upper half of
+// a two-row header: the lower row names the columns.
+func (d *HeaderDetector) DetectHeaderRowWithMerges(matrix CellMatrix, merges []MergedRegion) (int, int) {
 	if len(matrix) == 0 {
 		return 0, 0
 	}
@@ -40,6 +48,9 @@ func (d *HeaderDetector) DetectHeaderRow(matrix CellMatrix) (int, int) {
 	// Check first few rows (headers are usually in first maxRowsToCheck rows)
 	maxCheck := min(maxRowsToCheck, len(matrix))
 	for i := 0; i < maxCheck; i++ {
+		if isBannerRow(matrix[i], i, merges) {
+			continue
+		}
 		score := d.scoreRow(matrix[i])
 		if i+1 < len(matrix) {
 			score += d.scoreHeaderDataSeparation(matrix[i], matrix[i+1])
@@ -50,9 +61,66 @@ func (d *HeaderDetector) DetectHeaderRow(matrix CellMatrix) (int, int) {
 		}
 	}
 
+	for bestRow+1 < len(matrix) && groupsRowBelow(merges, bestRow) {
+		lower := d.scoreRow(matrix[bestRow+1])
+		if lower == 0 || (lower < d.scoreRow(matrix[bestRow]) && !mergedDown(merges, bestRow)) {
+			break
+		}
+		bestRow++
+		bestScore = max(bestScore, lower)
+	}
+
 	return bestRow, bestScore
 }
 
+// isBannerRow reports whether every value in row sits in one merged region
+// spanning several columns, like a sheet title merged across the table.
+func isBannerRow(row []string, index int, merges []MergedRegion) bool {
+	var banner *MergedRegion
+	for col, cell := range row {
+		if strings.TrimSpace(cell) == "" {
+			continue
+		}
+		if banner == nil {
+			for i := range merges {
+				if merges[i].horizontal() && merges[i].Covers(index, col) {
+					banner = &merges[i]
+					break
+				}
+			}
+			if banner == nil {
+				return false
+			}
+		}
+		if !banner.Covers(index, col) {
+			return false
+		}
+	}
+	return banner != nil
+}
+
+// groupsRowBelow reports whether a merged region spanning several columns
+// ends on row, so the row below holds the names of the columns it groups.
+func groupsRowBelow(merges []MergedRegion, row int) bool {
+	for _, m := range merges {
+		if m.horizontal() && m.EndRow == row {
+			return true
+		}
+	}
+	return false
+}
+
+// mergedDown reports whether a merged region starting on row continues into
+// the row below, as the ungrouped headers of a two-row header do.
+func mergedDown(merges []MergedRegion, row int) bool {
+	for _, m := range merges {
+		if m.StartRow == row && m.EndRow > row {
+			return true
+		}
+	}
+	return false
+}
+
 // scoreRow calculates how likely a row is to be a header
 func (d *HeaderDetector) scoreRow(row []string) int {
 	if len(row) == 0 {
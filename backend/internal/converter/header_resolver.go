@@ -38,6 +38,17 @@ func NewHeaderResolver(template *TemplateConfig) *HeaderResolver {
 // Returns (ColumnMap, unmappedHeaders, warnings)
 // Matches the signature of ColumnMapper.MapColumns
 func (r *HeaderResolver) ResolveHeaders(headers []string) (ColumnMap, []string, []string) {
+	return r.resolveHeaders(headers, false)
+}
+
+// ResolveBandHeaders is ResolveHeaders for the headers band.Headers returned.
+// When the band spans several rows, a composite header is also resolved by
+// its levels.
+func (r *HeaderResolver) ResolveBandHeaders(headers []string, band HeaderBand) (ColumnMap, []string, []string) {
+	return r.resolveHeaders(headers, band.Rows() > 1)
+}
+
+func (r *HeaderResolver) resolveHeaders(headers []string, layered bool) (ColumnMap, []string, []string) {
 	colMap := make(ColumnMap)
 	var unmapped []string
 	var warnings []string
@@ -57,6 +68,9 @@ func (r *HeaderResolver) ResolveHeaders(headers []string) (ColumnMap, []string,
 				warnings = append(warnings, "Duplicate header '"+header+"' ignored (field '"+string(canonicalField)+"' already mapped at column "+strconv.Itoa(seenFields[canonicalField])+")")
 				unmapped = append(unmapped, header)
 			}
+		} else if canonicalField, ok := r.resolveLevels(header, seenFields); layered && ok {
+			colMap[canonicalField] = i
+			seenFields[canonicalField] = i
 		} else {
 			unmapped = append(unmapped, header)
 		}
@@ -65,6 +79,26 @@ func (r *HeaderResolver) ResolveHeaders(headers []string) (ColumnMap, []string,
 	return colMap, unmapped, warnings
 }
 
+// resolveLevels resolves a composite header from a multi-row header band by
+// its own name, then its groups from the innermost out, like
+// ColumnMapper.MapBandColumns. Levels whose field is already mapped are skipped.
+func (r *HeaderResolver) resolveLevels(header string, seen map[CanonicalField]int) (CanonicalField, bool) {
+	levels := HeaderLevels(header)
+	if len(levels) < 2 {
+		return "", false
+	}
+	for i := len(levels) - 1; i >= 0; i-- {
+		field, ok := r.headerMap[normalizeHeader(levels[i])]
+		if !ok {
+			continue
+		}
+		if _, exists := seen[field]; !exists {
+			return field, true
+		}
+	}
+	return "", false
+}
+
 // GetFieldValue extracts a field value from a row using the resolved column map
 // Matches the signature and behavior of GetFieldValue in column_map.go
 func (r *HeaderResolver) GetFieldValue(row []string, colMap ColumnMap, field CanonicalField) string {
@@ -0,0 +1,158 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lint warning codes reported by LintMarkdown.
+const (
+	LintEmptyDocument       = "LINT_EMPTY_DOCUMENT"
+	LintFrontMatterMissing  = "LINT_FRONT_MATTER_MISSING"
+	LintFrontMatterOpen     = "LINT_FRONT_MATTER_UNTERMINATED"
+	LintFrontMatterInvalid  = "LINT_FRONT_MATTER_INVALID"
+	LintNoTitle             = "LINT_NO_TITLE"
+	LintEmptyHeading        = "LINT_EMPTY_HEADING"
+	LintDuplicateHeading    = "LINT_DUPLICATE_HEADING"
+	LintHeadingLevelSkipped = "LINT_HEADING_LEVEL_SKIPPED"
+	LintTableSeparator      = "LINT_TABLE_SEPARATOR"
+	LintTableColumns        = "LINT_TABLE_COLUMNS"
+	LintUnclosedFence       = "LINT_UNCLOSED_CODE_FENCE"
+)
+
+var (
+	lintHeadingRe   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	lintSeparatorRe = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+	lintFenceRe     = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+)
+
+// LintMarkdown checks the structure of an existing MDFlow markdown document:
+// front matter, headings, tables and code fences. Each warning carries its
+// 1-based line in Details["line"] (0 for document-level warnings).
+func LintMarkdown(text string) []Warning {
+	warnings := []Warning{}
+	lint := func(code string, severity WarningSeverity, line int, message, hint string) {
+		warnings = append(warnings, newWarning(code, severity, CatLint, message, hint, map[string]any{"line": line}))
+	}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if strings.TrimSpace(text) == "" {
+		lint(LintEmptyDocument, SeverityError, 0, "Document is empty", "Convert a spreadsheet with 'mdflow convert' to produce a spec")
+		return warnings
+	}
+	lines := strings.Split(text, "\n")
+
+	start := 0
+	if strings.TrimSpace(lines[0]) == "---" {
+		end := -1
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			lint(LintFrontMatterOpen, SeverityError, 1, "Front matter is not closed", "End the front matter block with a '---' line")
+			return warnings
+		}
+		var meta map[string]any
+		if err := yaml.Unmarshal([]byte(strings.Join(lines[1:end], "\n")), &meta); err != nil {
+			lint(LintFrontMatterInvalid, SeverityError, 1, fmt.Sprintf("Front matter is not valid YAML: %v", err), "")
+		}
+		start = end + 1
+	} else {
+		lint(LintFrontMatterMissing, SeverityInfo, 0, "Document has no front matter", "MDFlow specs start with a '---' YAML block naming the spec")
+	}
+
+	hasTitle := false
+	lastLevel := 0
+	seen := map[string]int{} // "level:text" -> first line
+	fence, fenceLine := "", 0
+	tableCols, tableRow := 0, 0
+
+	for i := start; i < len(lines); i++ {
+		lineNo := i + 1
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if m := lintFenceRe.FindStringSubmatch(line); m != nil {
+			marker := m[1]
+			switch {
+			case fence == "":
+				fence, fenceLine = marker, lineNo
+			case marker[0] == fence[0] && len(marker) >= len(fence) && strings.TrimSpace(line[strings.Index(line, marker)+len(marker):]) == "":
+				fence = ""
+			}
+			tableRow = 0
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "|") {
+			tableRow++
+			cols := countTableCells(trimmed)
+			switch tableRow {
+			case 1:
+				tableCols = cols
+			case 2:
+				if !lintSeparatorRe.MatchString(trimmed) {
+					lint(LintTableSeparator, SeverityWarn, lineNo, "Table header is not followed by a separator row", "Add a '|---|---|' row below the header")
+				} else if cols != tableCols {
+					lint(LintTableColumns, SeverityError, lineNo, fmt.Sprintf("Table separator has %d columns, header has %d", cols, tableCols), "")
+				}
+			default:
+				if cols != tableCols {
+					lint(LintTableColumns, SeverityError, lineNo, fmt.Sprintf("Table row has %d columns, header has %d", cols, tableCols), "Escape literal pipes in cells as '\\|'")
+				}
+			}
+			continue
+		}
+		tableRow = 0
+
+		m := lintHeadingRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		level := len(m[1])
+		heading := strings.TrimSpace(m[2])
+		if heading == "" {
+			lint(LintEmptyHeading, SeverityWarn, lineNo, fmt.Sprintf("Empty level %d heading", level), "")
+			continue
+		}
+		if level == 1 {
+			hasTitle = true
+		}
+		if lastLevel > 0 && level > lastLevel+1 {
+			lint(LintHeadingLevelSkipped, SeverityInfo, lineNo, fmt.Sprintf("Heading level jumps from %d to %d", lastLevel, level), "")
+		}
+		lastLevel = level
+
+		key := fmt.Sprintf("%d:%s", level, strings.ToLower(heading))
+		if first, ok := seen[key]; ok {
+			lint(LintDuplicateHeading, SeverityWarn, lineNo, fmt.Sprintf("Duplicate heading %q (first on line %d)", heading, first), "Spec IDs and section names should be unique")
+		} else {
+			seen[key] = lineNo
+		}
+	}
+
+	if fence != "" {
+		lint(LintUnclosedFence, SeverityError, fenceLine, "Code fence is never closed", fmt.Sprintf("Close the block with %s", fence))
+	}
+	if !hasTitle {
+		lint(LintNoTitle, SeverityWarn, 0, "Document has no '# ' title heading", "")
+	}
+	return warnings
+}
+
+// countTableCells counts the cells of a markdown table row, ignoring escaped pipes.
+func countTableCells(row string) int {
+	row = strings.ReplaceAll(row, `\|`, "")
+	row = strings.TrimPrefix(row, "|")
+	row = strings.TrimSuffix(row, "|")
+	return strings.Count(row, "|") + 1
+}
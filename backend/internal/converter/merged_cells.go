@@ -0,0 +1,174 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// MergeFill selects which cells of a merged region take its value. A sheet
+// shows the value across the whole region but stores it only in the top-left
+// cell, so without filling, rows under a vertically merged feature lose their
+// feature and columns under a merged header group lose their group.
+type MergeFill struct {
+	Down   bool // rows below the top-left cell
+	Across bool // columns right of the top-left cell
+}
+
+// DefaultMergeFill fills merged regions along both axes.
+func DefaultMergeFill() MergeFill {
+	return MergeFill{Down: true, Across: true}
+}
+
+// ParseMergeFill parses "both", "down", "across" or "none"; empty is "both".
+func ParseMergeFill(s string) (MergeFill, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "both":
+		return DefaultMergeFill(), nil
+	case "down":
+		return MergeFill{Down: true}, nil
+	case "across":
+		return MergeFill{Across: true}, nil
+	case "none":
+		return MergeFill{}, nil
+	}
+	return MergeFill{}, fmt.Errorf("invalid merge fill %q: must be both, down, across or none", s)
+}
+
+// MergedRegion is a merged cell range: Range is where it sits on the sheet,
+// StartRow..EndRow and StartCol..EndCol where it sits in the parsed matrix
+// (0-based, inclusive; blank rows dropped by parsing are not counted).
+type MergedRegion struct {
+	Range    string `json:"range"`
+	Value    string `json:"value,omitempty"`
+	StartRow int    `json:"start_row"`
+	StartCol int    `json:"start_col"`
+	EndRow   int    `json:"end_row"`
+	EndCol   int    `json:"end_col"`
+}
+
+// Covers reports whether the matrix cell at row, col lies inside the region.
+func (m MergedRegion) Covers(row, col int) bool {
+	return row >= m.StartRow && row <= m.EndRow && col >= m.StartCol && col <= m.EndCol
+}
+
+// horizontal reports whether the region spans more than one column.
+func (m MergedRegion) horizontal() bool {
+	return m.EndCol > m.StartCol
+}
+
+// LocateMerges places merged regions in a matrix whose row i came from sheet
+// row sourceRows[i] (nil means sheet row i+1) and whose first column is sheet
+// column firstCol. Regions with no row or column left in the matrix are
+// dropped; the rest are clipped to the rows that are.
+func LocateMerges(merges []MergedRegion, sourceRows []int, firstCol int) []MergedRegion {
+	var located []MergedRegion
+	for _, m := range merges {
+		r, err := ParseA1Range(m.Range)
+		if err != nil || r.EndCol < firstCol {
+			continue
+		}
+		rows := len(sourceRows)
+		if sourceRows == nil {
+			rows = r.EndRow
+		}
+		m.StartRow, m.EndRow = -1, -1
+		for i := 0; i < rows; i++ {
+			sheetRow := i + 1
+			if sourceRows != nil {
+				sheetRow = sourceRows[i]
+			}
+			if sheetRow < r.StartRow || sheetRow > r.EndRow {
+				continue
+			}
+			if m.StartRow < 0 {
+				m.StartRow = i
+			}
+			m.EndRow = i
+		}
+		if m.StartRow < 0 {
+			continue
+		}
+		m.StartCol = max(r.StartCol-firstCol, 0)
+		m.EndCol = r.EndCol - firstCol
+		located = append(located, m)
+	}
+	return located
+}
+
+// fillMerges copies each merged value into the covered cells fill selects and
+// returns the regions with their sheet ranges. Rows with no value of their own
+// are left blank, so parsing still drops them and XLSXSourceRows stays valid.
+func fillMerges(rows [][]string, merges []excelize.MergeCell, fill MergeFill) ([][]string, []MergedRegion) {
+	hasValue := make([]bool, len(rows))
+	for i, row := range rows {
+		for _, cell := range row {
+			if strings.TrimSpace(cell) != "" {
+				hasValue[i] = true
+				break
+			}
+		}
+	}
+
+	regions := make([]MergedRegion, 0, len(merges))
+	for _, merge := range merges {
+		if len(merge) == 0 {
+			continue
+		}
+		r, err := ParseA1Range(merge[0])
+		if err != nil {
+			continue
+		}
+		if r.EndCol == 0 || r.EndRow == 0 {
+			r.EndCol, r.EndRow = r.StartCol, r.StartRow
+		}
+		value := merge.GetCellValue()
+		if top := r.StartRow - 1; top < len(rows) && r.StartCol-1 < len(rows[top]) {
+			value = rows[top][r.StartCol-1]
+		}
+		regions = append(regions, MergedRegion{Range: r.String(), Value: value})
+
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		for row := r.StartRow; row <= r.EndRow && row <= len(rows); row++ {
+			if !hasValue[row-1] || (row > r.StartRow && !fill.Down) {
+				continue
+			}
+			for col := r.StartCol; col <= r.EndCol; col++ {
+				if row == r.StartRow && col == r.StartCol {
+					continue
+				}
+				if col > r.StartCol && !fill.Across {
+					break
+				}
+				rows = setCell(rows, row-1, col-1, value)
+			}
+		}
+	}
+	return rows, regions
+}
+
+// mergeCovered reports whether the matrix cell at row, col lies in a merged
+// region without being its top-left cell, i.e. its value was copied there.
+func mergeCovered(merges []MergedRegion, row, col int) bool {
+	for _, m := range merges {
+		if m.Covers(row, col) && (row != m.StartRow || col != m.StartCol) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownCells returns matrix row index with the values copied from merged
+// regions blanked, leaving only what the row itself holds.
+func ownCells(row []string, index int, merges []MergedRegion) []string {
+	own := make([]string, len(row))
+	for col, cell := range row {
+		if !mergeCovered(merges, index, col) {
+			own[col] = cell
+		}
+	}
+	return own
+}
@@ -19,6 +19,7 @@ const (
 	CatMapping WarningCategory = "mapping"
 	CatRows    WarningCategory = "rows"
 	CatRender  WarningCategory = "render"
+	CatLint    WarningCategory = "lint"
 )
 
 // Warning represents a structured warning from the conversion pipeline
@@ -111,6 +112,10 @@ type SpecRow struct {
 	NavigationDest    string `json:"navigation_destination,omitempty"`
 
 	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// SourceRow is the 1-based row in the source sheet (0 when unknown), used
+	// to point reports at the original cell.
+	SourceRow int `json:"source_row,omitempty"`
 }
 
 // SpecDoc represents the complete parsed document
@@ -125,27 +130,30 @@ type SpecDoc struct {
 
 // SpecDocMeta contains metadata about the parsed document
 type SpecDocMeta struct {
-	SheetName               string         `json:"sheet_name,omitempty"`
-	HeaderRow               int            `json:"header_row"`
-	ColumnMap               ColumnMap      `json:"column_map"`
-	UnmappedColumns         []string       `json:"unmapped_columns,omitempty"`
-	TotalRows               int            `json:"total_rows"`
-	RowsByFeature           map[string]int `json:"rows_by_feature,omitempty"`
-	SourceURL               string         `json:"source_url,omitempty"`
-	AIMode                  string         `json:"ai_mode,omitempty"`
-	AIUsed                  bool           `json:"ai_used,omitempty"`
-	AIDegraded              bool           `json:"ai_degraded,omitempty"`
-	AIFallbackReason        string         `json:"ai_fallback_reason,omitempty"`
-	AIModel                 string         `json:"ai_model,omitempty"`
-	AIPromptVersion         string         `json:"ai_prompt_version,omitempty"`
-	AIAvgConfidence         float64        `json:"ai_avg_confidence,omitempty"`
-	AIMappedColumns         int            `json:"ai_mapped_columns,omitempty"`
-	AIUnmappedColumns       int            `json:"ai_unmapped_columns,omitempty"`
-	AIEstimatedInputTokens  int            `json:"ai_estimated_input_tokens,omitempty"`
-	AIEstimatedOutputTokens int            `json:"ai_estimated_output_tokens,omitempty"`
-	AIEstimatedCostUSD      float64        `json:"ai_estimated_cost_usd,omitempty"`
-	OutputFormat            string         `json:"output_format,omitempty"`
-	QualityReport           *QualityReport `json:"quality_report,omitempty"`
+	SheetName               string          `json:"sheet_name,omitempty"`
+	HeaderRow               int             `json:"header_row"`
+	HeaderBand              *HeaderBand     `json:"header_band,omitempty"`
+	ColumnMap               ColumnMap       `json:"column_map"`
+	UnmappedColumns         []string        `json:"unmapped_columns,omitempty"`
+	TotalRows               int             `json:"total_rows"`
+	RowsByFeature           map[string]int  `json:"rows_by_feature,omitempty"`
+	SourceURL               string          `json:"source_url,omitempty"`
+	AIMode                  string          `json:"ai_mode,omitempty"`
+	AIUsed                  bool            `json:"ai_used,omitempty"`
+	AIDegraded              bool            `json:"ai_degraded,omitempty"`
+	AIFallbackReason        string          `json:"ai_fallback_reason,omitempty"`
+	AIModel                 string          `json:"ai_model,omitempty"`
+	AIPromptVersion         string          `json:"ai_prompt_version,omitempty"`
+	AIAvgConfidence         float64         `json:"ai_avg_confidence,omitempty"`
+	AIMappedColumns         int             `json:"ai_mapped_columns,omitempty"`
+	AIUnmappedColumns       int             `json:"ai_unmapped_columns,omitempty"`
+	AIEstimatedInputTokens  int             `json:"ai_estimated_input_tokens,omitempty"`
+	AIEstimatedOutputTokens int             `json:"ai_estimated_output_tokens,omitempty"`
+	AIEstimatedCostUSD      float64         `json:"ai_estimated_cost_usd,omitempty"`
+	OutputFormat            string          `json:"output_format,omitempty"`
+	QualityReport           *QualityReport  `json:"quality_report,omitempty"`
+	RulePack                *RulePackResult `json:"rule_pack,omitempty"`
+	MergedCells             []MergedRegion  `json:"merged_cells,omitempty"`
 }
 
 type QualityReport struct {
@@ -153,6 +161,7 @@ type QualityReport struct {
 	ValidationPassed    bool            `json:"validation_passed"`
 	ValidationReason    string          `json:"validation_reason,omitempty"`
 	HeaderConfidence    int             `json:"header_confidence"`
+	HeaderBand          HeaderBand      `json:"header_band"`
 	MinHeaderConfidence int             `json:"min_header_confidence"`
 	SourceRows          int             `json:"source_rows"`
 	ConvertedRows       int             `json:"converted_rows"`
@@ -162,6 +171,7 @@ type QualityReport struct {
 	MappedColumns       int             `json:"mapped_columns"`
 	MappedRatio         float64         `json:"mapped_ratio"`
 	CoreFieldCoverage   map[string]bool `json:"core_field_coverage,omitempty"`
+	RulePack            *RulePackResult `json:"rule_pack,omitempty"`
 }
 
 // ConvertRequest represents the API request for conversion
@@ -176,4 +186,7 @@ type ConvertResponse struct {
 	MDFlow   string      `json:"mdflow"`
 	Warnings []Warning   `json:"warnings"`
 	Meta     SpecDocMeta `json:"meta"`
+	// Doc is the document the output was rendered from, with the column
+	// mapping (overrides, AI) the conversion chose. Nil for markdown input.
+	Doc *SpecDoc `json:"-"`
 }
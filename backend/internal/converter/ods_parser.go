@@ -0,0 +1,263 @@
+package converter
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ODSParser reads OpenDocument spreadsheets (.ods): the tables in the
+// content.xml of the zip package. Cells are read as displayed, so dates and
+// numbers keep the formatting the author saw.
+type ODSParser struct{}
+
+// NewODSParser creates a new ODSParser
+func NewODSParser() *ODSParser {
+	return &ODSParser{}
+}
+
+// ParseFile parses an .ods file from path
+func (p *ODSParser) ParseFile(filePath string) (*XLSXResult, error) {
+	sheets, err := readODSFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return workbookResult(sheets)
+}
+
+// ParseReader parses an .ods file of the given size from r
+func (p *ODSParser) ParseReader(r io.ReaderAt, size int64) (*XLSXResult, error) {
+	sheets, err := readODS(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return workbookResult(sheets)
+}
+
+// ParseSheet parses one sheet of an .ods file; an empty name is the first sheet.
+func (p *ODSParser) ParseSheet(filePath string, sheetName string) (CellMatrix, error) {
+	sheets, err := readODSFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	sheet, err := findSheet(sheets, sheetName)
+	if err != nil {
+		return nil, err
+	}
+	return NewCellMatrix(sheet.rows).Normalize(), nil
+}
+
+func readODSFile(filePath string) ([]rawSheet, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ods file: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return readODS(f, info.Size())
+}
+
+const (
+	// odsMaxRepeat caps the cells and rows a repeat count may expand to.
+	// Editors pad tables with huge repeats of empty or styled cells; real
+	// content never comes close.
+	odsMaxRepeat = 1 << 16
+	// odsMaxCells caps the cells of one sheet, so a repeated row of repeated
+	// cells cannot expand to odsMaxRepeat squared.
+	odsMaxCells = 1 << 20
+	// odsMaxSpaces caps the run a single <text:s text:c> may stand for.
+	odsMaxSpaces = 1 << 10
+)
+
+func readODS(r io.ReaderAt, size int64) ([]rawSheet, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ods file: %w", err)
+	}
+	var content *zip.File
+	for _, f := range zr.File {
+		if f.Name == "content.xml" {
+			content = f
+			break
+		}
+	}
+	if content == nil {
+		return nil, fmt.Errorf("not an OpenDocument spreadsheet: no content.xml")
+	}
+	rc, err := content.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ods content: %w", err)
+	}
+	defer rc.Close()
+
+	sheets, err := parseODSContent(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ods content: %w", err)
+	}
+	return sheets, nil
+}
+
+// parseODSContent streams content.xml. Rows and cells are flushed lazily so
+// trailing repeats of empty rows and cells cost nothing.
+func parseODSContent(r io.Reader) ([]rawSheet, error) {
+	dec := xml.NewDecoder(r)
+	var (
+		sheets    []rawSheet
+		sheet     *rawSheet
+		row       []string
+		emptyRows int // empty rows seen but not yet added
+		emptyCols int // empty cells seen in this row but not yet added
+		rowRepeat int
+		inCell    bool
+		cellSkip  int // depth inside an annotation, whose text is not content
+		cellText  strings.Builder
+		paras     int
+		cellValue string
+		colRepeat int
+		cells     int // cells added to this sheet
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if inCell {
+				switch {
+				case cellSkip > 0 || t.Name.Local == "annotation":
+					cellSkip++
+				case t.Name.Local == "p" || t.Name.Local == "h":
+					if paras > 0 {
+						cellText.WriteByte('\n')
+					}
+					paras++
+				case t.Name.Local == "s":
+					n, _ := strconv.Atoi(odsAttr(t, "c"))
+					cellText.WriteString(strings.Repeat(" ", min(max(n, 1), odsMaxSpaces)))
+				case t.Name.Local == "tab":
+					cellText.WriteByte('\t')
+				case t.Name.Local == "line-break":
+					cellText.WriteByte('\n')
+				}
+				continue
+			}
+			switch t.Name.Local {
+			case "table":
+				sheets = append(sheets, rawSheet{name: odsAttr(t, "name")})
+				sheet = &sheets[len(sheets)-1]
+				emptyRows, cells = 0, 0
+			case "table-row":
+				if sheet == nil {
+					continue
+				}
+				row, emptyCols = nil, 0
+				rowRepeat = odsRepeat(t, "number-rows-repeated")
+			case "table-cell", "covered-table-cell":
+				if sheet == nil {
+					continue
+				}
+				inCell, cellSkip, paras = true, 0, 0
+				cellText.Reset()
+				colRepeat = odsRepeat(t, "number-columns-repeated")
+				cellValue = odsCellValue(t)
+			}
+		case xml.CharData:
+			if inCell && cellSkip == 0 && paras > 0 {
+				cellText.Write(t)
+			}
+		case xml.EndElement:
+			if inCell {
+				if cellSkip > 0 {
+					cellSkip--
+					continue
+				}
+				if t.Name.Local != "table-cell" && t.Name.Local != "covered-table-cell" {
+					continue
+				}
+				inCell = false
+				value := cellText.String()
+				if paras == 0 {
+					value = cellValue
+				}
+				if strings.TrimSpace(value) == "" {
+					emptyCols += colRepeat
+					continue
+				}
+				for ; emptyCols > 0 && len(row) < odsMaxRepeat; emptyCols-- {
+					row = append(row, "")
+				}
+				emptyCols = 0
+				for i := 0; i < colRepeat && len(row) < odsMaxRepeat; i++ {
+					row = append(row, value)
+				}
+				continue
+			}
+			switch t.Name.Local {
+			case "table-row":
+				if sheet == nil {
+					continue
+				}
+				if len(row) == 0 {
+					emptyRows += rowRepeat
+					continue
+				}
+				for ; emptyRows > 0 && len(sheet.rows) < odsMaxRepeat; emptyRows-- {
+					sheet.rows = append(sheet.rows, nil)
+				}
+				emptyRows = 0
+				for i := 0; i < rowRepeat && len(sheet.rows) < odsMaxRepeat && cells+len(row) <= odsMaxCells; i++ {
+					sheet.rows = append(sheet.rows, append([]string(nil), row...))
+					cells += len(row)
+				}
+			case "table":
+				sheet = nil
+			}
+		}
+	}
+	return sheets, nil
+}
+
+// odsCellValue is a cell's typed value, used when it has no text paragraphs.
+func odsCellValue(t xml.StartElement) string {
+	switch odsAttr(t, "value-type") {
+	case "date":
+		return odsAttr(t, "date-value")
+	case "time":
+		return odsAttr(t, "time-value")
+	case "boolean":
+		return strings.ToUpper(odsAttr(t, "boolean-value"))
+	case "string":
+		return odsAttr(t, "string-value")
+	case "":
+		return ""
+	}
+	return odsAttr(t, "value")
+}
+
+func odsRepeat(t xml.StartElement, name string) int {
+	n, err := strconv.Atoi(odsAttr(t, name))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+func odsAttr(t xml.StartElement, local string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
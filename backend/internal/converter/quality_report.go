@@ -0,0 +1,115 @@
+package converter
+
+// Quality gate failure reasons reported in QualityReport.ValidationReason.
+const (
+	QualityReasonLowHeaderConfidence = "low_header_confidence"
+	QualityReasonRowLoss             = "row_loss"
+)
+
+// QualityThresholds are the spec quality gates (SPEC_STRICT_MODE,
+// SPEC_MIN_HEADER_CONFIDENCE and SPEC_MAX_ROW_LOSS_RATIO).
+type QualityThresholds struct {
+	StrictMode          bool
+	MinHeaderConfidence int
+	MaxRowLossRatio     float64
+}
+
+// QualityStats describes the source sheet a conversion started from.
+type QualityStats struct {
+	SourceRows       int
+	HeaderRow        int
+	HeaderBand       HeaderBand
+	HeaderConfidence int
+	HeaderCount      int
+}
+
+// AnalyzeQuality detects the header row of matrix and counts the data rows below it.
+func AnalyzeQuality(matrix CellMatrix) QualityStats {
+	return AnalyzeQualityWithMerges(matrix, nil)
+}
+
+// AnalyzeQualityWithMerges is AnalyzeQuality for a sheet with merged regions.
+// A header spanning several rows counts as one header whose data starts below
+// its last row.
+func AnalyzeQualityWithMerges(matrix CellMatrix, merges []MergedRegion) QualityStats {
+	band, confidence := NewHeaderDetector().DetectHeaderBand(matrix, merges)
+	headerRow := band.End
+	sourceRows := matrix.RowCount() - headerRow - 1
+	if sourceRows < 0 {
+		sourceRows = 0
+	}
+	return QualityStats{
+		SourceRows:       sourceRows,
+		HeaderRow:        headerRow,
+		HeaderBand:       band,
+		HeaderConfidence: confidence,
+		HeaderCount:      len(matrix.GetRow(headerRow)),
+	}
+}
+
+// BuildQualityReport checks a conversion's meta against the quality gates.
+func BuildQualityReport(stats QualityStats, meta SpecDocMeta, t QualityThresholds) *QualityReport {
+	convertedRows := meta.TotalRows
+	mappedColumns := len(meta.ColumnMap)
+	mappedRatio := 0.0
+	if stats.HeaderCount > 0 {
+		mappedRatio = float64(mappedColumns) / float64(stats.HeaderCount)
+	}
+	rowLossRatio := 0.0
+	if stats.SourceRows > 0 {
+		rowLossRatio = 1 - (float64(convertedRows) / float64(stats.SourceRows))
+		if rowLossRatio < 0 {
+			rowLossRatio = 0
+		}
+	}
+
+	validationPassed := true
+	validationReason := ""
+	if stats.HeaderConfidence < t.MinHeaderConfidence {
+		validationPassed = false
+		validationReason = QualityReasonLowHeaderConfidence
+	} else if stats.SourceRows >= 2 && rowLossRatio > t.MaxRowLossRatio {
+		validationPassed = false
+		validationReason = QualityReasonRowLoss
+	}
+
+	return &QualityReport{
+		StrictMode:          t.StrictMode,
+		ValidationPassed:    validationPassed,
+		ValidationReason:    validationReason,
+		HeaderConfidence:    stats.HeaderConfidence,
+		HeaderBand:          stats.HeaderBand,
+		MinHeaderConfidence: t.MinHeaderConfidence,
+		SourceRows:          stats.SourceRows,
+		ConvertedRows:       convertedRows,
+		RowLossRatio:        rowLossRatio,
+		MaxRowLossRatio:     t.MaxRowLossRatio,
+		HeaderCount:         stats.HeaderCount,
+		MappedColumns:       mappedColumns,
+		MappedRatio:         mappedRatio,
+		CoreFieldCoverage:   CoreFieldCoverage(meta.ColumnMap),
+		RulePack:            meta.RulePack,
+	}
+}
+
+// CoreFieldCoverage reports which core spec fields colMap maps.
+func CoreFieldCoverage(colMap ColumnMap) map[string]bool {
+	coverage := map[string]bool{
+		string(FieldFeature):           false,
+		string(FieldScenario):          false,
+		string(FieldDescription):       false,
+		string(FieldInstructions):      false,
+		string(FieldExpected):          false,
+		string(FieldItemName):          false,
+		string(FieldDisplayConditions): false,
+		string(FieldAction):            false,
+		string(FieldNavigationDest):    false,
+	}
+	for field := range colMap {
+		key := string(field)
+		if _, ok := coverage[key]; ok {
+			coverage[key] = true
+		}
+	}
+	return coverage
+}
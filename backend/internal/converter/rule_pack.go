@@ -0,0 +1,85 @@
+package converter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/yourorg/md-spec-tool/internal/tracing"
+)
+
+// RulePackResult summarises a template's bundled rules run after conversion.
+type RulePackResult struct {
+	Template string `json:"template"`
+	Rules    int    `json:"rules"`
+	Errors   int    `json:"errors"`
+	Warnings int    `json:"warnings"`
+	Infos    int    `json:"infos"`
+}
+
+// Passed reports whether no error-severity rule failed.
+func (r *RulePackResult) Passed() bool {
+	return r == nil || r.Errors == 0
+}
+
+// RulePack returns the template's bundled validation rules for a sheet
+// mapped with colMap. RequiredFields become required rules only for mapped
+// columns: a sheet without the column gets a mapping warning, not one
+// warning per row.
+func (t *TemplateConfig) RulePack(colMap ColumnMap) *ValidationRules {
+	if t == nil {
+		return nil
+	}
+	pack := &ValidationRules{}
+	for _, field := range t.RequiredFields {
+		if _, mapped := colMap[CanonicalField(field)]; !mapped {
+			continue
+		}
+		pack.Rules = append(pack.Rules, FieldRule{
+			Name:     "required-" + field,
+			Field:    field,
+			Required: true,
+			Severity: SeverityWarn,
+		})
+	}
+	if t.ValidationRules != nil {
+		pack = pack.Merge(t.ValidationRules)
+	}
+	if pack.IsEmpty() {
+		return nil
+	}
+	return pack
+}
+
+// runRulePack validates doc against a template rule pack. Findings are tagged
+// with the template name so clients can tell them from ad-hoc request rules.
+func runRulePack(ctx context.Context, template string, doc *SpecDoc, pack *ValidationRules) ([]Warning, *RulePackResult) {
+	_, span := tracing.Start(ctx, "converter.rule_pack", attribute.String("rule_pack.template", template))
+	defer span.End()
+
+	result := Validate(doc, pack)
+	report := &RulePackResult{
+		Template: template,
+		Rules:    len(pack.RequiredFields) + len(pack.CrossField) + len(pack.Rules),
+	}
+	for i := range result.Warnings {
+		w := &result.Warnings[i]
+		if w.Details == nil {
+			w.Details = map[string]any{}
+		}
+		w.Details["rule_pack"] = template
+		switch w.Severity {
+		case SeverityError:
+			report.Errors++
+		case SeverityInfo:
+			report.Infos++
+		default:
+			report.Warnings++
+		}
+	}
+	span.SetAttributes(
+		attribute.Int("rule_pack.errors", report.Errors),
+		attribute.Int("rule_pack.warnings", report.Warnings),
+	)
+	return result.Warnings, report
+}
@@ -0,0 +1,49 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// XLSXSourceRows returns the 1-based sheet row of each row ParseXLSX keeps,
+// for .xlsx, .xls and .ods files.
+// Blank rows are dropped while parsing, so SpecRow.SourceRow N lives on sheet
+// row XLSXSourceRows(...)[N-1]. An empty sheet name means the first sheet.
+func XLSXSourceRows(filePath, sheetName string) ([]int, error) {
+	if kind := spreadsheetType(filePath); kind != "xlsx" {
+		read := readXLSFile
+		if kind == "ods" {
+			read = readODSFile
+		}
+		sheets, err := read(filePath)
+		if err != nil {
+			return nil, err
+		}
+		sheet, err := findSheet(sheets, sheetName)
+		if err != nil {
+			return nil, err
+		}
+		return keptRows(sheet.rows), nil
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open excel file: %w", err)
+	}
+	defer f.Close()
+
+	if sheetName == "" {
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("no sheets found in excel file")
+		}
+		sheetName = sheets[0]
+	}
+	rows, err := f.GetRows(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows from sheet %s: %w", sheetName, err)
+	}
+
+	return keptRows(rows), nil
+}
@@ -0,0 +1,124 @@
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SpreadsheetExtensions are the workbook formats the converter reads: Office
+// Open XML (.xlsx), legacy Excel 97-2003 (.xls) and OpenDocument (.ods).
+var SpreadsheetExtensions = []string{".xlsx", ".xls", ".ods"}
+
+var (
+	zipMagic = []byte{0x50, 0x4B, 0x03, 0x04}
+	oleMagic = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+)
+
+// IsSpreadsheetFile reports whether path has one of SpreadsheetExtensions.
+func IsSpreadsheetFile(path string) bool {
+	return slices.Contains(SpreadsheetExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// SpreadsheetSignature returns the leading bytes of a file of the given
+// extension: an OLE2 compound file for .xls, a zip archive for .xlsx and .ods.
+func SpreadsheetSignature(ext string) []byte {
+	if strings.EqualFold(ext, ".xls") {
+		return oleMagic[:4]
+	}
+	return zipMagic
+}
+
+// SpreadsheetParser reads the sheets of a workbook file.
+type SpreadsheetParser interface {
+	ParseFile(filePath string) (*XLSXResult, error)
+	ParseSheet(filePath string, sheetName string) (CellMatrix, error)
+}
+
+// SpreadsheetParserFor returns the parser for path's extension; anything that
+// is not .xls or .ods is read as .xlsx.
+func SpreadsheetParserFor(path string) SpreadsheetParser {
+	switch spreadsheetType(path) {
+	case "xls":
+		return NewXLSParser()
+	case "ods":
+		return NewODSParser()
+	default:
+		return NewXLSXParser()
+	}
+}
+
+// spreadsheetType is "xls", "ods" or, for anything else, "xlsx".
+func spreadsheetType(path string) string {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".xls", ".ods":
+		return ext[1:]
+	}
+	return "xlsx"
+}
+
+// rawSheet is one sheet as read, blank rows included, so source rows can be
+// recovered after Normalize drops them.
+type rawSheet struct {
+	name string
+	rows [][]string
+}
+
+func workbookResult(sheets []rawSheet) (*XLSXResult, error) {
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("no sheets found in workbook")
+	}
+	result := &XLSXResult{
+		Sheets:      make([]string, 0, len(sheets)),
+		SheetData:   make(map[string]CellMatrix, len(sheets)),
+		ActiveSheet: sheets[0].name,
+	}
+	for _, sheet := range sheets {
+		result.Sheets = append(result.Sheets, sheet.name)
+		result.SheetData[sheet.name] = NewCellMatrix(sheet.rows).Normalize()
+	}
+	return result, nil
+}
+
+// findSheet returns the named sheet, or the first one when name is empty.
+func findSheet(sheets []rawSheet, name string) (rawSheet, error) {
+	if len(sheets) == 0 {
+		return rawSheet{}, fmt.Errorf("no sheets found in workbook")
+	}
+	if name == "" {
+		return sheets[0], nil
+	}
+	for _, sheet := range sheets {
+		if sheet.name == name {
+			return sheet, nil
+		}
+	}
+	return rawSheet{}, fmt.Errorf("sheet %s not found", name)
+}
+
+// keptRows returns the 1-based row of each row Normalize keeps.
+func keptRows(rows [][]string) []int {
+	var kept []int
+	for i, row := range rows {
+		for _, cell := range row {
+			if strings.TrimSpace(cell) != "" {
+				kept = append(kept, i+1)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// setCell stores value at rows[row][col], growing the grid as needed.
+func setCell(rows [][]string, row, col int, value string) [][]string {
+	for len(rows) <= row {
+		rows = append(rows, nil)
+	}
+	for len(rows[row]) <= col {
+		rows[row] = append(rows[row], "")
+	}
+	rows[row][col] = value
+	return rows
+}
@@ -45,7 +45,7 @@ func (c *Converter) ConvertPasteStreaming(
 	callback StreamCallback,
 	options ConvertOptions,
 ) (*ConvertResponse, error) {
-	if (ConvertOptions{} == options) {
+	if !options.IncludeMetadata && !options.NumberRows && options.Merges == nil {
 		options = DefaultConvertOptions()
 	}
 	// Validate format early (before any work) so callers always get a fast,
@@ -120,8 +120,9 @@ func (c *Converter) ConvertPasteStreaming(
 	}
 
 	// Detect header row
-	headerRow, confidence := c.headerDetector.DetectHeaderRow(matrix)
-	headers := matrix.GetRow(headerRow)
+	band, confidence := c.headerDetector.DetectHeaderBand(matrix, options.Merges)
+	headerRow := band.End
+	headers := band.Headers(matrix)
 	dataRows := matrix.SliceRows(headerRow+1, matrix.RowCount())
 
 	var warnings []Warning
@@ -140,7 +141,7 @@ func (c *Converter) ConvertPasteStreaming(
 		Data:  ProgressData{Phase: "mapping", Percent: 50, Message: "Mapping columns..."},
 	})
 
-	colMap, unmapped, mappingWarnings, aiMeta := c.resolveColumnMapping(ctx, headers, dataRows, outputFormat)
+	colMap, unmapped, mappingWarnings, aiMeta := c.resolveColumnMapping(ctx, headers, band, dataRows, outputFormat)
 	warnings = append(warnings, mappingWarnings...)
 	colMap, unmapped, inferredWarnings := enhanceColumnMapping(headers, dataRows, colMap)
 	warnings = append(warnings, inferredWarnings...)
@@ -208,6 +209,7 @@ func (c *Converter) ConvertPasteStreaming(
 
 	meta := SpecDocMeta{
 		HeaderRow:       headerRow,
+		HeaderBand:      multiRowBand(band),
 		ColumnMap:       colMap,
 		UnmappedColumns: unmapped,
 		TotalRows:       table.RowCount(),
@@ -215,6 +217,13 @@ func (c *Converter) ConvertPasteStreaming(
 	}
 	applyAIMeta(&meta, aiMeta)
 
+	doc := c.buildSpecDoc(matrix, headerRow, headers, colMap, unmapped, "", options.Merges)
+	if pack := tmpl.RulePack(colMap); pack != nil {
+		packWarnings, report := runRulePack(ctx, tmpl.Name, doc, pack)
+		warnings = append(warnings, packWarnings...)
+		meta.RulePack = report
+	}
+
 	// ─── Phase 4: Complete ────────────────────────────────────────────────────
 	callback(StreamEvent{
 		Event: "complete",
@@ -225,5 +234,6 @@ func (c *Converter) ConvertPasteStreaming(
 		MDFlow:   mdflow,
 		Warnings: warnings,
 		Meta:     meta,
+		Doc:      doc,
 	}, nil
 }
@@ -240,3 +240,38 @@ func TestConvertPasteStreaming_ResultIsNonEmpty(t *testing.T) {
 		t.Error("expected non-empty MDFlow output")
 	}
 }
+
+// TestConvertPasteStreaming_RunsTemplateRulePack ensures streamed pastes get
+// the same template rule pack findings as ConvertPaste.
+func TestConvertPasteStreaming_RunsTemplateRulePack(t *testing.T) {
+	conv := NewConverter()
+	paste := "ID\tFeature\tScenario\tExpected\nTC-1\tAuth\tLogin\tDashboard\ntc-1\tAuth\t\tError shown"
+
+	_, streamed, err := collectStreamEvents(t, conv, paste, "spec", "spec")
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	direct, err := conv.ConvertPasteWithFormatContext(context.Background(), paste, "spec", "spec")
+	if err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+
+	if streamed.Meta.RulePack == nil || direct.Meta.RulePack == nil || *streamed.Meta.RulePack != *direct.Meta.RulePack {
+		t.Fatalf("streamed rule_pack = %+v, direct = %+v", streamed.Meta.RulePack, direct.Meta.RulePack)
+	}
+	if streamed.Meta.RulePack.Warnings == 0 {
+		t.Errorf("expected the duplicate ID to warn in the spec rule pack: %+v", streamed.Meta.RulePack)
+	}
+	count := func(warnings []Warning) int {
+		n := 0
+		for _, w := range warnings {
+			if w.Details["rule_pack"] == "spec" {
+				n++
+			}
+		}
+		return n
+	}
+	if got, want := count(streamed.Warnings), count(direct.Warnings); got == 0 || got != want {
+		t.Errorf("streamed rule pack warnings = %d, want %d", got, want)
+	}
+}
@@ -225,3 +225,16 @@ func (a *TableToSpecDocAdapter) applySpecTableMapping(specRow *SpecRow) {
 		specRow.Expected = "Navigation: " + specRow.NavigationDest
 	}
 }
+
+// BuildSpecRows maps every data row to a SpecRow using colMap.
+// Unlike Convert, blank and continuation rows are kept so the result stays
+// index-aligned with dataRows (row i of the result is dataRows[i]).
+func BuildSpecRows(headers []string, dataRows CellMatrix, colMap ColumnMap) []SpecRow {
+	adapter := NewTableToSpecDocAdapter()
+	rows := make([]SpecRow, 0, len(dataRows))
+	for _, cells := range dataRows {
+		rowMap := NewTableRow(cells).ToMap(headers)
+		rows = append(rows, adapter.buildSpecRow(rowMap, colMap, headers))
+	}
+	return rows
+}
@@ -2,12 +2,14 @@ package converter
 
 // TemplateConfig defines a conversion template with field mappings
 type TemplateConfig struct {
-	Name           string                 `yaml:"name"`
-	Description    string                 `yaml:"description"`
-	HeaderSynonyms map[string][]string    `yaml:"header_synonyms"`
-	RequiredFields []string               `yaml:"required_fields"`
-	Output         TemplateOutputConfig   `yaml:"output"`
-	Metadata       map[string]interface{} `yaml:"metadata,omitempty"`
+	Name           string              `yaml:"name"`
+	Description    string              `yaml:"description"`
+	HeaderSynonyms map[string][]string `yaml:"header_synonyms"`
+	RequiredFields []string            `yaml:"required_fields"`
+	// ValidationRules is the rule pack run after every conversion with this template.
+	ValidationRules *ValidationRules       `yaml:"validation_rules,omitempty"`
+	Output          TemplateOutputConfig   `yaml:"output"`
+	Metadata        map[string]interface{} `yaml:"metadata,omitempty"`
 }
 
 // TemplateOutputConfig configures output format and how unmapped columns are handled
@@ -35,6 +37,10 @@ func (t *TemplateConfig) Validate() []TemplateValidationError {
 		errors = append(errors, TemplateValidationError{"header_synonyms", "at least one field mapping is required"})
 	}
 
+	if err := t.ValidationRules.Check(); err != nil {
+		errors = append(errors, TemplateValidationError{"validation_rules", err.Error()})
+	}
+
 	// Validate output type specific requirements.
 	outputType := t.Output.Type
 	switch outputType {
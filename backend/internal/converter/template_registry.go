@@ -94,6 +94,10 @@ func (r *TemplateRegistry) createSpecTemplate() *TemplateConfig {
 		Description:    "Structured specification output",
 		HeaderSynonyms: headerSynonyms,
 		RequiredFields: []string{"scenario"},
+		ValidationRules: &ValidationRules{Rules: []FieldRule{
+			{Name: "unique-id", Field: string(FieldID), Unique: true, IgnoreCase: true, Severity: SeverityWarn},
+			{Name: "title-length", Field: string(FieldTitle), MaxLength: 200, Severity: SeverityInfo},
+		}},
 		Output: TemplateOutputConfig{
 			Type:              "spec",
 			UnmappedColumns:   "append_section",
@@ -122,6 +126,9 @@ func (r *TemplateRegistry) createTableTemplate() *TemplateConfig {
 		Description:    "Simple markdown table output",
 		HeaderSynonyms: headerSynonyms,
 		RequiredFields: []string{},
+		ValidationRules: &ValidationRules{Rules: []FieldRule{
+			{Name: "unique-id", Field: string(FieldID), Unique: true, IgnoreCase: true, Severity: SeverityWarn},
+		}},
 		Output: TemplateOutputConfig{
 			Type:              "table",
 			UnmappedColumns:   "ignore",
@@ -0,0 +1,337 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/md-spec-tool/internal/ai"
+)
+
+// Transcript spec columns not covered by canonical fields; they surface as row
+// metadata in spec output and as extra columns in table output.
+const (
+	TranscriptSourceHeader    = "Source"
+	TranscriptTimestampHeader = "Timestamp"
+)
+
+type transcriptColumn struct {
+	Header string
+	Field  CanonicalField
+}
+
+// transcriptLayouts are the column layouts transcript drafts render with, by
+// template: spec carries each requirement in full, table is a scannable
+// summary without the description. Both keep the citation columns.
+var transcriptLayouts = map[string][]transcriptColumn{
+	"spec": {
+		{"ID", FieldID},
+		{"Feature", FieldFeature},
+		{"Title", FieldTitle},
+		{"Type", FieldType},
+		{"Description", FieldDescription},
+		{"Acceptance Criteria", FieldAcceptance},
+		{"Priority", FieldPriority},
+		{TranscriptSourceHeader, ""},
+		{TranscriptTimestampHeader, ""},
+	},
+	"table": {
+		{"ID", FieldID},
+		{"Feature", FieldFeature},
+		{"Title", FieldTitle},
+		{"Type", FieldType},
+		{"Priority", FieldPriority},
+		{"Acceptance Criteria", FieldAcceptance},
+		{TranscriptSourceHeader, ""},
+		{TranscriptTimestampHeader, ""},
+	},
+}
+
+// leadingTimestamp matches "[01:02:03]", "00:12", "(1:05:00)" etc. at the start of a paragraph.
+var leadingTimestamp = regexp.MustCompile(`^[\[(]?((?:\d{1,2}:)?\d{1,2}:\d{2})(?:\.\d+)?[\])]?\s*[-–:]?\s*`)
+
+var blankLineSplit = regexp.MustCompile(`\n\s*\n`)
+
+// ParseTranscriptText splits pasted transcript text into paragraphs on blank
+// lines. A leading timestamp on a paragraph sets its start; each paragraph
+// ends where the next timestamped one starts.
+func ParseTranscriptText(text string) []ai.TranscriptParagraph {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	var paragraphs []ai.TranscriptParagraph
+	for _, block := range blankLineSplit.Split(text, -1) {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+		p := ai.TranscriptParagraph{ID: fmt.Sprintf("P%d", len(paragraphs)+1), Start: -1}
+		if m := leadingTimestamp.FindStringSubmatch(block); m != nil {
+			if seconds, ok := parseClock(m[1]); ok {
+				p.Start = seconds
+				block = strings.TrimSpace(block[len(m[0]):])
+			}
+		}
+		p.Text = strings.Join(strings.Fields(block), " ")
+		if p.Text == "" {
+			continue
+		}
+		paragraphs = append(paragraphs, p)
+	}
+
+	// Fill missing starts from the previous paragraph and close each range at the next start.
+	for i := range paragraphs {
+		if paragraphs[i].Start < 0 {
+			paragraphs[i].Start = 0
+			if i > 0 {
+				paragraphs[i].Start = paragraphs[i-1].Start
+			}
+		}
+	}
+	for i := range paragraphs {
+		paragraphs[i].End = paragraphs[i].Start
+		if i+1 < len(paragraphs) && paragraphs[i+1].Start > paragraphs[i].Start {
+			paragraphs[i].End = paragraphs[i+1].Start
+		}
+	}
+	return paragraphs
+}
+
+func parseClock(s string) (float64, bool) {
+	parts := strings.Split(s, ":")
+	total := 0
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, false
+		}
+		total = total*60 + n
+	}
+	return float64(total), true
+}
+
+// BuildTranscriptSpecDoc turns extracted requirements into a SpecDoc laid out
+// for template (spec or table; anything else uses spec). Each row cites the
+// transcript paragraphs it came from and their time ranges; items whose
+// citations do not match any paragraph are dropped with a warning, and so are
+// paragraphs that did not fit in the extraction prompt.
+func BuildTranscriptSpecDoc(title, template string, paragraphs []ai.TranscriptParagraph, extraction *ai.RequirementsExtraction) *SpecDoc {
+	if title == "" {
+		title = "Meeting Requirements"
+	}
+	layout, ok := transcriptLayouts[template]
+	if !ok {
+		layout = transcriptLayouts["spec"]
+	}
+	byID := make(map[string]ai.TranscriptParagraph, len(paragraphs))
+	for _, p := range paragraphs {
+		byID[p.ID] = p
+	}
+
+	doc := &SpecDoc{
+		Title:    title,
+		Rows:     []SpecRow{},
+		Warnings: []Warning{},
+		Headers:  make([]string, 0, len(layout)),
+		Meta: SpecDocMeta{
+			ColumnMap:     ColumnMap{},
+			RowsByFeature: map[string]int{},
+			OutputFormat:  string(OutputFormatSpec),
+		},
+	}
+	for i, col := range layout {
+		doc.Headers = append(doc.Headers, col.Header)
+		if col.Field != "" {
+			doc.Meta.ColumnMap[col.Field] = i
+		}
+	}
+	if kept := ai.TranscriptPromptParagraphs(paragraphs); kept < len(paragraphs) {
+		first, last := paragraphs[kept], paragraphs[len(paragraphs)-1]
+		doc.Warnings = append(doc.Warnings, newWarning(
+			"TRANSCRIPT_TRUNCATED",
+			SeverityWarn,
+			CatInput,
+			fmt.Sprintf("Paragraphs %s-%s (%s-%s) exceeded the transcript size limit and were not analysed.",
+				first.ID, last.ID, ai.FormatTimestamp(first.Start), ai.FormatTimestamp(last.End)),
+			"Split the transcript and draft the remaining part separately.",
+			map[string]any{
+				"first_paragraph": first.ID,
+				"last_paragraph":  last.ID,
+				"start":           first.Start,
+				"end":             last.End,
+				"dropped":         len(paragraphs) - kept,
+			},
+		))
+	}
+	if extraction == nil {
+		return doc
+	}
+
+	var dropped []string
+	for _, req := range extraction.Requirements {
+		var sources, timestamps []string
+		seen := map[string]bool{}
+		for _, id := range req.SourceParagraphs {
+			id = strings.ToUpper(strings.TrimSpace(id))
+			p, ok := byID[id]
+			if !ok || seen[id] {
+				continue
+			}
+			seen[id] = true
+			sources = append(sources, id)
+			timestamps = append(timestamps, ai.FormatTimestamp(p.Start)+"-"+ai.FormatTimestamp(p.End))
+		}
+		if len(sources) == 0 {
+			dropped = append(dropped, req.Title)
+			continue
+		}
+
+		kind := "Requirement"
+		if req.Kind == "user_story" {
+			kind = "User Story"
+		}
+		feature := strings.TrimSpace(req.Feature)
+		if feature == "" {
+			feature = strings.TrimSpace(req.Title)
+		}
+		row := SpecRow{
+			ID:          fmt.Sprintf("REQ-%03d", len(doc.Rows)+1),
+			Feature:     feature,
+			Title:       strings.TrimSpace(req.Title),
+			Type:        kind,
+			Description: strings.TrimSpace(req.Description),
+			Acceptance:  strings.Join(req.AcceptanceCriteria, "\n"),
+			Priority:    req.Priority,
+			Metadata: map[string]string{
+				TranscriptSourceHeader:    strings.Join(sources, ", "),
+				TranscriptTimestampHeader: strings.Join(timestamps, ", "),
+			},
+		}
+		doc.Rows = append(doc.Rows, row)
+		doc.Meta.RowsByFeature[feature]++
+	}
+	doc.Meta.TotalRows = len(doc.Rows)
+
+	if len(dropped) > 0 {
+		doc.Warnings = append(doc.Warnings, newWarning(
+			"TRANSCRIPT_UNCITED_ITEMS",
+			SeverityWarn,
+			CatRows,
+			fmt.Sprintf("%d extracted item(s) did not cite a transcript paragraph and were dropped.", len(dropped)),
+			"Review the transcript for these topics manually.",
+			map[string]any{"titles": dropped},
+		))
+	}
+	if len(doc.Rows) == 0 {
+		doc.Warnings = append(doc.Warnings, newWarning(
+			"TRANSCRIPT_NO_REQUIREMENTS",
+			SeverityInfo,
+			CatRows,
+			"No requirements were found in the transcript.",
+			"Check that the recording covers requirement discussion rather than small talk.",
+			nil,
+		))
+	}
+	return doc
+}
+
+// RenderSpecDocTable renders a SpecDoc built outside the matrix pipeline through
+// the standard spec or table renderer, using doc.Headers as the column layout.
+func RenderSpecDocTable(doc *SpecDoc, format string, options ConvertOptions) (string, error) {
+	if doc == nil {
+		return "", fmt.Errorf("spec doc is nil")
+	}
+	renderer, err := NewRendererSimple(format)
+	if err != nil {
+		return "", err
+	}
+
+	table := &Table{SheetName: doc.Title, Headers: doc.Headers}
+	fieldByIndex := make(map[int]CanonicalField, len(doc.Meta.ColumnMap))
+	for field, idx := range doc.Meta.ColumnMap {
+		fieldByIndex[idx] = field
+	}
+	for _, row := range doc.Rows {
+		cells := make([]string, len(doc.Headers))
+		for i, header := range doc.Headers {
+			if field, ok := fieldByIndex[i]; ok {
+				cells[i] = specRowField(row, field)
+			} else {
+				cells[i] = row.Metadata[header]
+			}
+		}
+		table.Rows = append(table.Rows, TableRow{Cells: cells})
+	}
+	table.Meta.ColumnMap = doc.Meta.ColumnMap
+	table.Meta.IncludeMetadata = options.IncludeMetadata
+	table.Meta.NumberRows = options.NumberRows
+
+	mdflow, _, err := renderer.Render(table)
+	return mdflow, err
+}
+
+// specRowField returns the raw value of a canonical field on row.
+func specRowField(row SpecRow, field CanonicalField) string {
+	switch field {
+	case FieldID:
+		return row.ID
+	case FieldTitle:
+		return row.Title
+	case FieldDescription:
+		return row.Description
+	case FieldAcceptance:
+		return row.Acceptance
+	case FieldFeature:
+		return row.Feature
+	case FieldScenario:
+		return row.Scenario
+	case FieldInstructions:
+		return row.Instructions
+	case FieldInputs:
+		return row.Inputs
+	case FieldExpected:
+		return row.Expected
+	case FieldPrecondition:
+		return row.Precondition
+	case FieldPriority:
+		return row.Priority
+	case FieldType:
+		return row.Type
+	case FieldStatus:
+		return row.Status
+	case FieldNotes:
+		return row.Notes
+	case FieldComponent:
+		return row.Component
+	case FieldAssignee:
+		return row.Assignee
+	case FieldCategory:
+		return row.Category
+	case FieldEndpoint:
+		return row.Endpoint
+	case FieldMethod:
+		return row.Method
+	case FieldParameters:
+		return row.Parameters
+	case FieldResponse:
+		return row.Response
+	case FieldStatusCode:
+		return row.StatusCode
+	case FieldNo:
+		return row.No
+	case FieldItemName:
+		return row.ItemName
+	case FieldItemType:
+		return row.ItemType
+	case FieldRequiredOptional:
+		return row.RequiredOptional
+	case FieldInputRestrictions:
+		return row.InputRestrictions
+	case FieldDisplayConditions:
+		return row.DisplayConditions
+	case FieldAction:
+		return row.Action
+	case FieldNavigationDest:
+		return row.NavigationDest
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourorg/md-spec-tool/internal/ai"
+)
+
+func TestParseTranscriptText_Timestamps(t *testing.T) {
+	text := "[00:00:05] Welcome everyone.\n\n" +
+		"00:01:10 - We need SSO for\nenterprise customers.\n\n" +
+		"No timestamp on this one.\n\n" +
+		"(1:02:03) Wrap up."
+
+	paragraphs := ParseTranscriptText(text)
+	if len(paragraphs) != 4 {
+		t.Fatalf("got %d paragraphs, want 4: %+v", len(paragraphs), paragraphs)
+	}
+	want := []ai.TranscriptParagraph{
+		{ID: "P1", Start: 5, End: 70, Text: "Welcome everyone."},
+		{ID: "P2", Start: 70, End: 70, Text: "We need SSO for enterprise customers."},
+		{ID: "P3", Start: 70, End: 3723, Text: "No timestamp on this one."},
+		{ID: "P4", Start: 3723, End: 3723, Text: "Wrap up."},
+	}
+	for i, w := range want {
+		if paragraphs[i] != w {
+			t.Errorf("paragraph %d = %+v, want %+v", i, paragraphs[i], w)
+		}
+	}
+}
+
+func transcriptFixture() ([]ai.TranscriptParagraph, *ai.RequirementsExtraction) {
+	paragraphs := []ai.TranscriptParagraph{
+		{ID: "P1", Start: 0, End: 30, Text: "Admins want single sign-on."},
+		{ID: "P2", Start: 30, End: 75, Text: "It has to work with Okta and Azure AD."},
+		{ID: "P3", Start: 75, End: 90, Text: "Lunch next week?"},
+	}
+	extraction := &ai.RequirementsExtraction{
+		Confidence: 0.8,
+		Requirements: []ai.ExtractedRequirement{
+			{
+				Kind:               "user_story",
+				Title:              "Single sign-on",
+				Feature:            "Authentication",
+				Description:        "As an admin, I want SSO, so that staff use one login.",
+				AcceptanceCriteria: []string{"Okta login works", "Azure AD login works"},
+				Priority:           "high",
+				SourceParagraphs:   []string{"p1", "P2", "P1"},
+				Confidence:         0.9,
+			},
+			{
+				Kind:             "requirement",
+				Title:            "Invented feature",
+				SourceParagraphs: []string{"P9"},
+			},
+		},
+	}
+	return paragraphs, extraction
+}
+
+func TestBuildTranscriptSpecDoc_CitesParagraphs(t *testing.T) {
+	paragraphs, extraction := transcriptFixture()
+	doc := BuildTranscriptSpecDoc("", "spec", paragraphs, extraction)
+
+	if doc.Title != "Meeting Requirements" {
+		t.Errorf("title = %q", doc.Title)
+	}
+	if len(doc.Rows) != 1 {
+		t.Fatalf("rows = %d, want uncited item dropped", len(doc.Rows))
+	}
+	row := doc.Rows[0]
+	if row.ID != "REQ-001" || row.Type != "User Story" || row.Feature != "Authentication" {
+		t.Errorf("row = %+v", row)
+	}
+	if got := row.Metadata[TranscriptSourceHeader]; got != "P1, P2" {
+		t.Errorf("source = %q, want deduplicated P1, P2", got)
+	}
+	if got := row.Metadata[TranscriptTimestampHeader]; got != "00:00:00-00:00:30, 00:00:30-00:01:15" {
+		t.Errorf("timestamps = %q", got)
+	}
+	if len(doc.Warnings) != 1 || doc.Warnings[0].Code != "TRANSCRIPT_UNCITED_ITEMS" {
+		t.Errorf("warnings = %+v", doc.Warnings)
+	}
+}
+
+func TestRenderSpecDocTable_SpecAndTable(t *testing.T) {
+	paragraphs, extraction := transcriptFixture()
+	doc := BuildTranscriptSpecDoc("Kickoff", "spec", paragraphs, extraction)
+
+	spec, err := RenderSpecDocTable(doc, "spec", DefaultConvertOptions())
+	if err != nil {
+		t.Fatalf("spec render: %v", err)
+	}
+	for _, want := range []string{"# Kickoff", "Single sign-on", "Okta login works", "P1, P2"} {
+		if !strings.Contains(spec, want) {
+			t.Errorf("spec output missing %q:\n%s", want, spec)
+		}
+	}
+
+	table, err := RenderSpecDocTable(doc, "table", DefaultConvertOptions())
+	if err != nil {
+		t.Fatalf("table render: %v", err)
+	}
+	for _, want := range []string{"| Source |", "| Timestamp |", "00:00:00-00:00:30, 00:00:30-00:01:15"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("table output missing %q:\n%s", want, table)
+		}
+	}
+
+	if _, err := RenderSpecDocTable(doc, "xml", DefaultConvertOptions()); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestBuildTranscriptSpecDoc_TemplateLayout(t *testing.T) {
+	paragraphs, extraction := transcriptFixture()
+
+	spec := BuildTranscriptSpecDoc("", "spec", paragraphs, extraction)
+	table := BuildTranscriptSpecDoc("", "table", paragraphs, extraction)
+	if _, ok := spec.Meta.ColumnMap[FieldDescription]; !ok {
+		t.Errorf("spec layout lost the description column: %v", spec.Headers)
+	}
+	if _, ok := table.Meta.ColumnMap[FieldDescription]; ok || len(table.Headers) >= len(spec.Headers) {
+		t.Errorf("table layout = %v, want a summary without the description", table.Headers)
+	}
+	if last := table.Headers[len(table.Headers)-1]; last != TranscriptTimestampHeader {
+		t.Errorf("table layout dropped citations: %v", table.Headers)
+	}
+	if other := BuildTranscriptSpecDoc("", "unknown", paragraphs, extraction); strings.Join(other.Headers, ",") != strings.Join(spec.Headers, ",") {
+		t.Errorf("unknown template layout = %v, want spec", other.Headers)
+	}
+}
+
+func TestBuildTranscriptSpecDoc_WarnsOnTruncatedParagraphs(t *testing.T) {
+	long := strings.Repeat("word ", ai.MaxTranscriptBytes/10)
+	paragraphs := []ai.TranscriptParagraph{
+		{ID: "P1", Start: 0, End: 60, Text: long},
+		{ID: "P2", Start: 60, End: 120, Text: long},
+		{ID: "P3", Start: 120, End: 185, Text: long},
+	}
+	doc := BuildTranscriptSpecDoc("", "spec", paragraphs, nil)
+
+	if len(doc.Warnings) != 1 || doc.Warnings[0].Code != "TRANSCRIPT_TRUNCATED" {
+		t.Fatalf("warnings = %+v", doc.Warnings)
+	}
+	w := doc.Warnings[0]
+	if !strings.Contains(w.Message, "P2-P3 (00:01:00-00:03:05)") || w.Details["dropped"] != 2 {
+		t.Errorf("warning = %+v", w)
+	}
+}
@@ -0,0 +1,365 @@
+package converter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldRule is one declarative check in a rules file. Field is a canonical
+// field name ("priority"), a metadata key ("metadata.Component") or a source
+// header kept in metadata ("Rollout"); canonical names win, so use the
+// "metadata." prefix when a header shares one. A rule only applies to rows matching
+// every When condition. Empty values are skipped by all checks except Required.
+//
+//	rules:
+//	  - name: priority-values
+//	    field: priority
+//	    enum: [P0, P1, P2, P3]
+//	    severity: error
+//	  - field: expected
+//	    required: true
+//	    when:
+//	      - field: type
+//	        equals: Functional
+type FieldRule struct {
+	Name       string          `json:"name,omitempty" yaml:"name,omitempty"`
+	Field      string          `json:"field" yaml:"field"`
+	Severity   WarningSeverity `json:"severity,omitempty" yaml:"severity,omitempty"` // info|warn|error, default warn
+	Message    string          `json:"message,omitempty" yaml:"message,omitempty"`
+	When       []RuleCondition `json:"when,omitempty" yaml:"when,omitempty"`
+	Required   bool            `json:"required,omitempty" yaml:"required,omitempty"`
+	Enum       []string        `json:"enum,omitempty" yaml:"enum,omitempty"`
+	IgnoreCase bool            `json:"ignore_case,omitempty" yaml:"ignore_case,omitempty"` // enum and unique comparisons
+	Unique     bool            `json:"unique,omitempty" yaml:"unique,omitempty"`
+	MinLength  int             `json:"min_length,omitempty" yaml:"min_length,omitempty"` // in characters, 0 = no minimum
+	MaxLength  int             `json:"max_length,omitempty" yaml:"max_length,omitempty"` // in characters, 0 = no maximum
+	Pattern    string          `json:"pattern,omitempty" yaml:"pattern,omitempty"`       // Go regexp the value must match
+}
+
+// RuleCondition is a predicate on one field of a row. Set exactly one of
+// Equals, NotEquals, In, Matches or Empty. Comparisons ignore case and
+// surrounding whitespace.
+type RuleCondition struct {
+	Field     string   `json:"field" yaml:"field"`
+	Equals    *string  `json:"equals,omitempty" yaml:"equals,omitempty"`
+	NotEquals *string  `json:"not_equals,omitempty" yaml:"not_equals,omitempty"`
+	In        []string `json:"in,omitempty" yaml:"in,omitempty"`
+	Matches   string   `json:"matches,omitempty" yaml:"matches,omitempty"`
+	Empty     *bool    `json:"empty,omitempty" yaml:"empty,omitempty"`
+}
+
+// ParseValidationRules decodes a YAML or JSON rules document and checks it.
+// Unknown keys are rejected so typos in rule files do not silently disable checks.
+func ParseValidationRules(data []byte) (*ValidationRules, error) {
+	var rules ValidationRules
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&rules); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid validation rules: %w", err)
+	}
+	if err := rules.Check(); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// LoadValidationRulesFile reads a .yaml, .yml or .json rules file.
+func LoadValidationRulesFile(path string) (*ValidationRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file %s: %w", path, err)
+	}
+	rules, err := ParseValidationRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Check reports the first malformed rule: unknown severity, bad regex,
+// inverted length bounds, a rule without checks or a condition without a predicate.
+func (r *ValidationRules) Check() error {
+	if r == nil {
+		return nil
+	}
+	if r.FormatRules != nil && r.FormatRules.IDPattern != "" {
+		if _, err := regexp.Compile(r.FormatRules.IDPattern); err != nil {
+			return fmt.Errorf("format_rules.id_pattern: %w", err)
+		}
+	}
+	for i, rule := range r.Rules {
+		label := rule.label(i)
+		if strings.TrimSpace(rule.Field) == "" {
+			return fmt.Errorf("%s: field is required", label)
+		}
+		switch rule.Severity {
+		case "", SeverityInfo, SeverityWarn, SeverityError:
+		default:
+			return fmt.Errorf("%s: unknown severity %q (use info, warn or error)", label, rule.Severity)
+		}
+		if rule.MinLength < 0 || rule.MaxLength < 0 {
+			return fmt.Errorf("%s: lengths must not be negative", label)
+		}
+		if rule.MaxLength > 0 && rule.MinLength > rule.MaxLength {
+			return fmt.Errorf("%s: min_length %d exceeds max_length %d", label, rule.MinLength, rule.MaxLength)
+		}
+		if rule.Pattern != "" {
+			if _, err := regexp.Compile(rule.Pattern); err != nil {
+				return fmt.Errorf("%s: pattern: %w", label, err)
+			}
+		}
+		if !rule.Required && len(rule.Enum) == 0 && !rule.Unique && rule.MinLength == 0 && rule.MaxLength == 0 && rule.Pattern == "" {
+			return fmt.Errorf("%s: no checks configured (set required, enum, unique, min_length, max_length or pattern)", label)
+		}
+		for j, cond := range rule.When {
+			if err := cond.check(); err != nil {
+				return fmt.Errorf("%s: when[%d]: %w", label, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+// IsEmpty reports whether no rule of any kind is configured.
+func (r *ValidationRules) IsEmpty() bool {
+	if r == nil {
+		return true
+	}
+	if len(r.RequiredFields) > 0 || len(r.CrossField) > 0 || len(r.Rules) > 0 {
+		return false
+	}
+	f := r.FormatRules
+	return f == nil || (f.IDPattern == "" && len(f.EmailFields) == 0 && len(f.URLFields) == 0)
+}
+
+// Merge returns a copy of r with other's rules appended. Other's format rules
+// replace r's when set.
+func (r *ValidationRules) Merge(other *ValidationRules) *ValidationRules {
+	if r == nil {
+		return other
+	}
+	if other == nil {
+		return r
+	}
+	merged := &ValidationRules{
+		RequiredFields: append(append([]string{}, r.RequiredFields...), other.RequiredFields...),
+		FormatRules:    r.FormatRules,
+		CrossField:     append(append([]CrossFieldRule{}, r.CrossField...), other.CrossField...),
+		Rules:          append(append([]FieldRule{}, r.Rules...), other.Rules...),
+	}
+	if other.FormatRules != nil {
+		merged.FormatRules = other.FormatRules
+	}
+	return merged
+}
+
+func (rule FieldRule) label(index int) string {
+	if rule.Name != "" {
+		return fmt.Sprintf("rules[%d] (%s)", index, rule.Name)
+	}
+	return fmt.Sprintf("rules[%d]", index)
+}
+
+func (cond RuleCondition) check() error {
+	if strings.TrimSpace(cond.Field) == "" {
+		return errors.New("field is required")
+	}
+	set := 0
+	if cond.Equals != nil {
+		set++
+	}
+	if cond.NotEquals != nil {
+		set++
+	}
+	if len(cond.In) > 0 {
+		set++
+	}
+	if cond.Matches != "" {
+		set++
+		if _, err := regexp.Compile(cond.Matches); err != nil {
+			return fmt.Errorf("matches: %w", err)
+		}
+	}
+	if cond.Empty != nil {
+		set++
+	}
+	if set != 1 {
+		return errors.New("set exactly one of equals, not_equals, in, matches or empty")
+	}
+	return nil
+}
+
+// ruleRegexps compiles patterns once per Validate call; invalid patterns map to nil.
+type ruleRegexps map[string]*regexp.Regexp
+
+func (c ruleRegexps) get(pattern string) *regexp.Regexp {
+	if re, ok := c[pattern]; ok {
+		return re
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = nil
+	}
+	c[pattern] = re
+	return re
+}
+
+func (cond RuleCondition) holds(row *SpecRow, res ruleRegexps) bool {
+	value := strings.TrimSpace(getFieldValue(row, cond.Field))
+	switch {
+	case cond.Equals != nil:
+		return strings.EqualFold(value, strings.TrimSpace(*cond.Equals))
+	case cond.NotEquals != nil:
+		return !strings.EqualFold(value, strings.TrimSpace(*cond.NotEquals))
+	case len(cond.In) > 0:
+		return containsValue(cond.In, value, true)
+	case cond.Matches != "":
+		re := res.get(cond.Matches)
+		return re != nil && re.MatchString(value)
+	case cond.Empty != nil:
+		return (value == "") == *cond.Empty
+	}
+	return false
+}
+
+func containsValue(values []string, value string, ignoreCase bool) bool {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == value || (ignoreCase && strings.EqualFold(v, value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFieldRules runs the declarative rules over every row.
+func validateFieldRules(rows []SpecRow, rules []FieldRule) []Warning {
+	var warnings []Warning
+	res := ruleRegexps{}
+
+	for i, rule := range rules {
+		severity := rule.Severity
+		if severity == "" {
+			severity = SeverityWarn
+		}
+		field := strings.TrimSpace(rule.Field)
+		firstSeen := map[string]int{}
+
+		report := func(code, message, hint string, details map[string]any) {
+			if rule.Message != "" {
+				message = rule.Message
+			}
+			details["field"] = field
+			if rule.Name != "" {
+				details["rule"] = rule.Name
+			} else {
+				details["rule_index"] = i
+			}
+			warnings = append(warnings, newWarning(code, severity, CatRows, message, hint, details))
+		}
+
+		for ri := range rows {
+			row := &rows[ri]
+			rowNum := ri + 1
+			if !ruleApplies(rule, row, res) {
+				continue
+			}
+			value := strings.TrimSpace(getFieldValue(row, field))
+			if value == "" {
+				if rule.Required {
+					report("VALIDATION_REQUIRED",
+						"Required field \""+field+"\" is empty",
+						"Fill in the required field or disable this rule.",
+						map[string]any{"row": rowNum})
+				}
+				continue
+			}
+
+			if len(rule.Enum) > 0 && !containsValue(rule.Enum, value, rule.IgnoreCase) {
+				report("VALIDATION_ENUM",
+					fmt.Sprintf("Field \"%s\" has value %q outside the allowed set", field, value),
+					"Use one of: "+strings.Join(rule.Enum, ", ")+".",
+					map[string]any{"row": rowNum, "value": value, "allowed": rule.Enum})
+			}
+
+			length := utf8.RuneCountInString(value)
+			if rule.MinLength > 0 && length < rule.MinLength {
+				report("VALIDATION_MIN_LENGTH",
+					fmt.Sprintf("Field \"%s\" is shorter than %d characters", field, rule.MinLength),
+					"Add more detail to this field.",
+					map[string]any{"row": rowNum, "length": length, "min_length": rule.MinLength})
+			}
+			if rule.MaxLength > 0 && length > rule.MaxLength {
+				report("VALIDATION_MAX_LENGTH",
+					fmt.Sprintf("Field \"%s\" is longer than %d characters", field, rule.MaxLength),
+					"Shorten this field or split the row.",
+					map[string]any{"row": rowNum, "length": length, "max_length": rule.MaxLength})
+			}
+
+			if rule.Pattern != "" {
+				if re := res.get(rule.Pattern); re != nil && !re.MatchString(value) {
+					report("VALIDATION_PATTERN",
+						fmt.Sprintf("Field \"%s\" value %q does not match pattern %s", field, value, rule.Pattern),
+						"Use a value matching the regex "+rule.Pattern+".",
+						map[string]any{"row": rowNum, "value": value, "pattern": rule.Pattern})
+				}
+			}
+
+			if rule.Unique {
+				key := value
+				if rule.IgnoreCase {
+					key = strings.ToLower(key)
+				}
+				if first, dup := firstSeen[key]; dup {
+					report("VALIDATION_UNIQUE",
+						fmt.Sprintf("Field \"%s\" value %q duplicates row %d", field, value, first),
+						"Give each row a distinct value.",
+						map[string]any{"row": rowNum, "value": value, "first_row": first})
+				} else {
+					firstSeen[key] = rowNum
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+func ruleApplies(rule FieldRule, row *SpecRow, res ruleRegexps) bool {
+	for _, cond := range rule.When {
+		if !cond.holds(row, res) {
+			return false
+		}
+	}
+	return true
+}
+
+// FieldValue returns a row value addressed the way rule fields are: a
+// canonical field name, "metadata.<header>", or a bare metadata header.
+func FieldValue(row *SpecRow, field string) string {
+	return getFieldValue(row, field)
+}
+
+// metadataValue looks up a metadata key exactly, then case-insensitively.
+func metadataValue(row *SpecRow, key string) string {
+	if row.Metadata == nil {
+		return ""
+	}
+	key = strings.TrimSpace(key)
+	if v, ok := row.Metadata[key]; ok {
+		return v
+	}
+	for k, v := range row.Metadata {
+		if strings.EqualFold(strings.TrimSpace(k), key) {
+			return v
+		}
+	}
+	return ""
+}
@@ -1,38 +1,61 @@
 package converter
 
 import (
+	"context"
 	"regexp"
 	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/yourorg/md-spec-tool/internal/tracing"
 )
 
-// ValidationRules holds user-configurable validation rules
+// ValidationRules holds user-configurable validation rules.
+// Loadable from YAML or JSON with ParseValidationRules.
 type ValidationRules struct {
-	RequiredFields []string          `json:"required_fields"` // canonical field names, e.g. "id", "feature", "expected"
-	FormatRules    *FormatRules      `json:"format_rules,omitempty"`
-	CrossField     []CrossFieldRule  `json:"cross_field,omitempty"`
+	RequiredFields []string          `json:"required_fields" yaml:"required_fields,omitempty"` // canonical field names, e.g. "id", "feature", "expected"
+	FormatRules    *FormatRules      `json:"format_rules,omitempty" yaml:"format_rules,omitempty"`
+	CrossField     []CrossFieldRule  `json:"cross_field,omitempty" yaml:"cross_field,omitempty"`
+	Rules          []FieldRule       `json:"rules,omitempty" yaml:"rules,omitempty"` // declarative per-field rules (enum, unique, length, pattern, conditions)
 }
 
 // FormatRules define format validation per field
 type FormatRules struct {
-	IDPattern   string `json:"id_pattern,omitempty"`   // regex for ID field
-	DateFormat  string `json:"date_format,omitempty"` // e.g. "2006-01-02"
-	EmailFields []string `json:"email_fields,omitempty"` // field names to validate as email
-	URLFields   []string `json:"url_fields,omitempty"`   // field names to validate as URL
+	IDPattern   string `json:"id_pattern,omitempty" yaml:"id_pattern,omitempty"`   // regex for ID field
+	DateFormat  string `json:"date_format,omitempty" yaml:"date_format,omitempty"` // e.g. "2006-01-02"
+	EmailFields []string `json:"email_fields,omitempty" yaml:"email_fields,omitempty"` // field names to validate as email
+	URLFields   []string `json:"url_fields,omitempty" yaml:"url_fields,omitempty"`   // field names to validate as URL
 }
 
 // CrossFieldRule defines a rule like "if field A present then field B required"
 type CrossFieldRule struct {
-	IfField   string `json:"if_field"`   // when this field is non-empty
-	ThenField string `json:"then_field"` // this field must be non-empty
-	Message   string `json:"message,omitempty"`
+	IfField   string `json:"if_field" yaml:"if_field"`   // when this field is non-empty
+	ThenField string `json:"then_field" yaml:"then_field"` // this field must be non-empty
+	Message   string `json:"message,omitempty" yaml:"message,omitempty"`
 }
 
-// ValidationResult holds validation errors/warnings
+// ValidationResult holds validation errors/warnings.
+// Valid is false when any finding has warn or error severity.
 type ValidationResult struct {
 	Valid    bool      `json:"valid"`
 	Warnings []Warning `json:"warnings"`
 }
 
+// ValidateWithContext runs Validate inside a validation span under ctx.
+func ValidateWithContext(ctx context.Context, doc *SpecDoc, rules *ValidationRules) ValidationResult {
+	_, span := tracing.Start(ctx, "converter.validate")
+	defer span.End()
+	result := Validate(doc, rules)
+	if doc != nil {
+		span.SetAttributes(attribute.Int("validation.rows", len(doc.Rows)))
+	}
+	span.SetAttributes(
+		attribute.Bool("validation.valid", result.Valid),
+		attribute.Int("validation.warnings", len(result.Warnings)),
+	)
+	return result
+}
+
 // Validate runs custom validation rules against a SpecDoc and returns validation warnings
 func Validate(doc *SpecDoc, rules *ValidationRules) ValidationResult {
 	if doc == nil || rules == nil {
@@ -132,60 +155,32 @@ func Validate(doc *SpecDoc, rules *ValidationRules) ValidationResult {
 		}
 	}
 
+	warnings = append(warnings, validateFieldRules(doc.Rows, rules.Rules)...)
+
+	valid := true
+	for _, w := range warnings {
+		if w.Severity != SeverityInfo {
+			valid = false
+			break
+		}
+	}
 	return ValidationResult{
-		Valid:    len(warnings) == 0,
+		Valid:    valid,
 		Warnings: warnings,
 	}
 }
 
+// getFieldValue resolves a canonical field name, a "metadata.<key>" reference
+// or a bare metadata key (source header) on row.
 func getFieldValue(row *SpecRow, field string) string {
-	switch strings.ToLower(strings.TrimSpace(field)) {
-	case "id":
-		return row.ID
-	case "feature":
-		return row.Feature
-	case "scenario":
-		return row.Scenario
-	case "instructions":
-		return row.Instructions
-	case "inputs":
-		return row.Inputs
-	case "expected":
-		return row.Expected
-	case "precondition":
-		return row.Precondition
-	case "priority":
-		return row.Priority
-	case "type":
-		return row.Type
-	case "status":
-		return row.Status
-	case "endpoint":
-		return row.Endpoint
-	case "notes":
-		return row.Notes
-	case "no":
-		return row.No
-	case "item_name":
-		return row.ItemName
-	case "item_type":
-		return row.ItemType
-	case "required_optional":
-		return row.RequiredOptional
-	case "input_restrictions":
-		return row.InputRestrictions
-	case "display_conditions":
-		return row.DisplayConditions
-	case "action":
-		return row.Action
-	case "navigation_destination":
-		return row.NavigationDest
-	default:
-		if row.Metadata != nil {
-			return row.Metadata[field]
-		}
-		return ""
+	key := strings.ToLower(strings.TrimSpace(field))
+	if strings.HasPrefix(key, "metadata.") {
+		return metadataValue(row, strings.TrimSpace(field)[len("metadata."):])
+	}
+	if isCanonicalField(CanonicalField(key)) {
+		return specRowField(*row, CanonicalField(key))
 	}
+	return metadataValue(row, field)
 }
 
 func matchEmail(s string) bool {
@@ -0,0 +1,647 @@
+package converter
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/richardlehane/mscfb"
+)
+
+// XLSParser reads legacy Excel 97-2003 workbooks (.xls): BIFF8 records in the
+// "Workbook" stream of an OLE2 compound file. Cell values are read as stored;
+// numbers with a date or time format become ISO 8601 dates and times, and
+// formulas give their cached result.
+type XLSParser struct{}
+
+// NewXLSParser creates a new XLSParser
+func NewXLSParser() *XLSParser {
+	return &XLSParser{}
+}
+
+// ParseFile parses an .xls file from path
+func (p *XLSParser) ParseFile(filePath string) (*XLSXResult, error) {
+	sheets, err := readXLSFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return workbookResult(sheets)
+}
+
+// ParseReader parses an .xls file from r
+func (p *XLSParser) ParseReader(r io.ReaderAt) (*XLSXResult, error) {
+	sheets, err := readXLS(r)
+	if err != nil {
+		return nil, err
+	}
+	return workbookResult(sheets)
+}
+
+// ParseSheet parses one sheet of an .xls file; an empty name is the first sheet.
+func (p *XLSParser) ParseSheet(filePath string, sheetName string) (CellMatrix, error) {
+	sheets, err := readXLSFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	sheet, err := findSheet(sheets, sheetName)
+	if err != nil {
+		return nil, err
+	}
+	return NewCellMatrix(sheet.rows).Normalize(), nil
+}
+
+func readXLSFile(filePath string) ([]rawSheet, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open xls file: %w", err)
+	}
+	defer f.Close()
+	return readXLS(f)
+}
+
+// BIFF8 record types.
+const (
+	biffFormula    = 0x0006
+	biffEOF        = 0x000A
+	biffDateMode   = 0x0022
+	biffFilePass   = 0x002F
+	biffContinue   = 0x003C
+	biffBoundSheet = 0x0085
+	biffMulRK      = 0x00BD
+	biffXF         = 0x00E0
+	biffSST        = 0x00FC
+	biffLabelSST   = 0x00FD
+	biffNumber     = 0x0203
+	biffLabel      = 0x0204
+	biffBoolErr    = 0x0205
+	biffString     = 0x0207
+	biffRK         = 0x027E
+	biffFormat     = 0x041E
+	biffBOF        = 0x0809
+
+	biff8Version = 0x0600
+
+	// BIFF8 sheet limits; cells outside them come from a corrupt or hostile file.
+	biff8MaxRows = 65536
+	biff8MaxCols = 256
+	// xlsMaxCells caps the cells of one sheet, counting the blanks padding
+	// each row out to its last cell, so a few bytes of far-right cells per
+	// row cannot expand to the full BIFF8 grid.
+	xlsMaxCells = 1 << 20
+)
+
+func readXLS(r io.ReaderAt) ([]rawSheet, error) {
+	doc, err := mscfb.New(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open xls file: %w", err)
+	}
+	var stream []byte
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		switch entry.Name {
+		case "Workbook":
+			if stream, err = io.ReadAll(entry); err != nil {
+				return nil, fmt.Errorf("failed to read xls workbook: %w", err)
+			}
+		case "Book":
+			return nil, fmt.Errorf("xls files older than Excel 97 are not supported")
+		}
+		if stream != nil {
+			break
+		}
+	}
+	if stream == nil {
+		return nil, fmt.Errorf("not an Excel workbook: no Workbook stream")
+	}
+	return parseBIFF8(stream)
+}
+
+type biffRecord struct {
+	typ  uint16
+	data []byte
+}
+
+// readRecord returns the record at pos and the position after it.
+func readRecord(stream []byte, pos int) (biffRecord, int, bool) {
+	if pos < 0 || pos+4 > len(stream) {
+		return biffRecord{}, pos, false
+	}
+	typ := binary.LittleEndian.Uint16(stream[pos:])
+	size := int(binary.LittleEndian.Uint16(stream[pos+2:]))
+	end := pos + 4 + size
+	if end > len(stream) {
+		return biffRecord{}, pos, false
+	}
+	return biffRecord{typ: typ, data: stream[pos+4 : end]}, end, true
+}
+
+type xlsSheetRef struct {
+	name   string
+	offset int
+}
+
+// xlsWorkbook holds the workbook globals cells need.
+type xlsWorkbook struct {
+	strings   []string
+	xfFormats []uint16          // number format of each XF record
+	formats   map[uint16]string // custom number formats
+	date1904  bool
+}
+
+func parseBIFF8(stream []byte) ([]rawSheet, error) {
+	wb := &xlsWorkbook{formats: map[uint16]string{}}
+	var refs []xlsSheetRef
+
+	rec, pos, ok := readRecord(stream, 0)
+	if !ok || rec.typ != biffBOF || len(rec.data) < 2 {
+		return nil, fmt.Errorf("not an Excel workbook: missing BOF record")
+	}
+	if binary.LittleEndian.Uint16(rec.data) != biff8Version {
+		return nil, fmt.Errorf("only Excel 97-2003 (BIFF8) xls files are supported")
+	}
+
+globals:
+	for {
+		rec, pos, ok = readRecord(stream, pos)
+		if !ok {
+			return nil, fmt.Errorf("truncated xls workbook")
+		}
+		switch rec.typ {
+		case biffEOF:
+			break globals
+		case biffFilePass:
+			return nil, fmt.Errorf("password-protected xls files are not supported")
+		case biffDateMode:
+			wb.date1904 = len(rec.data) >= 2 && binary.LittleEndian.Uint16(rec.data) == 1
+		case biffBoundSheet:
+			if len(rec.data) < 8 || rec.data[5] != 0 { // 0: worksheet; skip charts and macros
+				continue
+			}
+			name, _, err := readShortString(rec.data[6:])
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, xlsSheetRef{name: name, offset: int(binary.LittleEndian.Uint32(rec.data))})
+		case biffFormat:
+			if len(rec.data) < 2 {
+				continue
+			}
+			code, _, err := readUnicodeString(rec.data[2:])
+			if err != nil {
+				return nil, err
+			}
+			wb.formats[binary.LittleEndian.Uint16(rec.data)] = code
+		case biffXF:
+			if len(rec.data) >= 4 {
+				wb.xfFormats = append(wb.xfFormats, binary.LittleEndian.Uint16(rec.data[2:]))
+			}
+		case biffSST:
+			chunks := [][]byte{rec.data}
+			for {
+				next, after, ok := readRecord(stream, pos)
+				if !ok || next.typ != biffContinue {
+					break
+				}
+				chunks = append(chunks, next.data)
+				pos = after
+			}
+			sst, err := readSST(chunks)
+			if err != nil {
+				return nil, err
+			}
+			wb.strings = sst
+		}
+	}
+
+	sheets := make([]rawSheet, 0, len(refs))
+	for _, ref := range refs {
+		rows, err := wb.readSheet(stream, ref.offset)
+		if err != nil {
+			return nil, fmt.Errorf("sheet %s: %w", ref.name, err)
+		}
+		sheets = append(sheets, rawSheet{name: ref.name, rows: rows})
+	}
+	return sheets, nil
+}
+
+// readSheet reads the cells of the worksheet substream starting at offset.
+func (wb *xlsWorkbook) readSheet(stream []byte, offset int) ([][]string, error) {
+	rec, pos, ok := readRecord(stream, offset)
+	if !ok || rec.typ != biffBOF {
+		return nil, fmt.Errorf("bad sheet offset")
+	}
+
+	var rows [][]string
+	cells := 0 // cells allocated in rows
+	setAt := func(row, col int, value string) {
+		if row >= biff8MaxRows || col >= biff8MaxCols {
+			return
+		}
+		grow := col + 1
+		if row < len(rows) {
+			grow -= len(rows[row])
+		}
+		if grow > 0 {
+			if cells+grow > xlsMaxCells {
+				return
+			}
+			cells += grow
+		}
+		rows = setCell(rows, row, col, value)
+	}
+	set := func(data []byte, value string) {
+		setAt(int(binary.LittleEndian.Uint16(data)), int(binary.LittleEndian.Uint16(data[2:])), value)
+	}
+	var stringCell []byte // the FORMULA whose string result the next STRING holds
+	for {
+		rec, pos, ok = readRecord(stream, pos)
+		if !ok {
+			return nil, fmt.Errorf("truncated sheet")
+		}
+		data := rec.data
+		switch rec.typ {
+		case biffEOF:
+			return rows, nil
+		case biffLabelSST:
+			if len(data) < 10 {
+				continue
+			}
+			if i := int(binary.LittleEndian.Uint32(data[6:])); i < len(wb.strings) {
+				set(data, wb.strings[i])
+			}
+		case biffLabel:
+			if len(data) < 8 {
+				continue
+			}
+			if s, _, err := readUnicodeString(data[6:]); err == nil {
+				set(data, s)
+			}
+		case biffNumber:
+			if len(data) < 14 {
+				continue
+			}
+			set(data, wb.formatNumber(math.Float64frombits(binary.LittleEndian.Uint64(data[6:])), binary.LittleEndian.Uint16(data[4:])))
+		case biffRK:
+			if len(data) < 10 {
+				continue
+			}
+			set(data, wb.formatNumber(rkValue(binary.LittleEndian.Uint32(data[6:])), binary.LittleEndian.Uint16(data[4:])))
+		case biffMulRK:
+			if len(data) < 6 {
+				continue
+			}
+			row := int(binary.LittleEndian.Uint16(data))
+			col := int(binary.LittleEndian.Uint16(data[2:]))
+			for i := 4; i+6 <= len(data)-2; i += 6 {
+				xf := binary.LittleEndian.Uint16(data[i:])
+				setAt(row, col, wb.formatNumber(rkValue(binary.LittleEndian.Uint32(data[i+2:])), xf))
+				col++
+			}
+		case biffBoolErr:
+			if len(data) < 8 {
+				continue
+			}
+			if data[7] == 0 {
+				set(data, boolString(data[6] != 0))
+			} else {
+				set(data, xlsErrorString(data[6]))
+			}
+		case biffFormula:
+			if len(data) < 14 {
+				continue
+			}
+			stringCell = nil
+			result := data[6:14]
+			if result[6] != 0xFF || result[7] != 0xFF {
+				set(data, wb.formatNumber(math.Float64frombits(binary.LittleEndian.Uint64(result)), binary.LittleEndian.Uint16(data[4:])))
+				continue
+			}
+			switch result[0] {
+			case 0: // string, in the STRING record that follows
+				stringCell = data
+			case 1:
+				set(data, boolString(result[2] != 0))
+			case 2:
+				set(data, xlsErrorString(result[2]))
+			}
+		case biffString:
+			if stringCell != nil {
+				if s, _, err := readUnicodeString(data); err == nil {
+					set(stringCell, s)
+				}
+				stringCell = nil
+			}
+		}
+	}
+}
+
+// rkValue decodes an RK number: a 30-bit integer or the high bits of a
+// float64, optionally divided by 100.
+func rkValue(rk uint32) float64 {
+	var v float64
+	if rk&0x02 != 0 {
+		v = float64(int32(rk) >> 2)
+	} else {
+		v = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&0x01 != 0 {
+		v /= 100
+	}
+	return v
+}
+
+func boolString(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func xlsErrorString(code byte) string {
+	switch code {
+	case 0x00:
+		return "#NULL!"
+	case 0x07:
+		return "#DIV/0!"
+	case 0x0F:
+		return "#VALUE!"
+	case 0x17:
+		return "#REF!"
+	case 0x1D:
+		return "#NAME?"
+	case 0x24:
+		return "#NUM!"
+	case 0x2A:
+		return "#N/A"
+	}
+	return "#ERROR!"
+}
+
+var errShortString = errors.New("truncated xls string")
+
+// readShortString reads a ShortXLUnicodeString (8-bit length), returning the
+// bytes it used.
+func readShortString(b []byte) (string, int, error) {
+	if len(b) < 2 {
+		return "", 0, errShortString
+	}
+	s, n, err := decodeChars(b[2:], int(b[0]), b[1]&0x01 != 0)
+	return s, 2 + n, err
+}
+
+// readUnicodeString reads an XLUnicodeString (16-bit length).
+func readUnicodeString(b []byte) (string, int, error) {
+	if len(b) < 3 {
+		return "", 0, errShortString
+	}
+	s, n, err := decodeChars(b[3:], int(binary.LittleEndian.Uint16(b)), b[2]&0x01 != 0)
+	return s, 3 + n, err
+}
+
+// decodeChars decodes count characters, stored as UTF-16LE when high is set
+// and as Latin-1 bytes otherwise.
+func decodeChars(b []byte, count int, high bool) (string, int, error) {
+	if !high {
+		if len(b) < count {
+			return "", 0, errShortString
+		}
+		runes := make([]rune, count)
+		for i := 0; i < count; i++ {
+			runes[i] = rune(b[i])
+		}
+		return string(runes), count, nil
+	}
+	if len(b) < 2*count {
+		return "", 0, errShortString
+	}
+	units := make([]uint16, count)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(units)), 2 * count, nil
+}
+
+// sstReader reads the shared string table across its CONTINUE records. A
+// string's characters may be split between records, and each continuation
+// starts with a flag byte giving the width of the characters that follow.
+type sstReader struct {
+	chunks [][]byte
+	chunk  int
+	pos    int
+}
+
+func (r *sstReader) next() bool {
+	for r.chunk < len(r.chunks) && r.pos >= len(r.chunks[r.chunk]) {
+		r.chunk++
+		r.pos = 0
+	}
+	return r.chunk < len(r.chunks)
+}
+
+func (r *sstReader) read(n int) ([]byte, error) {
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		if !r.next() {
+			return nil, errShortString
+		}
+		data := r.chunks[r.chunk]
+		take := min(n-len(out), len(data)-r.pos)
+		out = append(out, data[r.pos:r.pos+take]...)
+		r.pos += take
+	}
+	return out, nil
+}
+
+// skip advances past n bytes without reading them. The formatting runs and
+// extended data it skips have file-controlled lengths, so it fails rather
+// than allocate when n exceeds what is left.
+func (r *sstReader) skip(n int) error {
+	for n > 0 {
+		if !r.next() {
+			return errShortString
+		}
+		take := min(n, len(r.chunks[r.chunk])-r.pos)
+		r.pos += take
+		n -= take
+	}
+	return nil
+}
+
+func (r *sstReader) chars(count int, high bool) (string, error) {
+	units := make([]uint16, 0, count)
+	for len(units) < count {
+		if r.pos >= len(r.chunks[r.chunk]) {
+			r.chunk++
+			if r.chunk >= len(r.chunks) || len(r.chunks[r.chunk]) == 0 {
+				return "", errShortString
+			}
+			high = r.chunks[r.chunk][0]&0x01 != 0
+			r.pos = 1
+			continue
+		}
+		data := r.chunks[r.chunk]
+		if high {
+			if r.pos+2 > len(data) {
+				return "", errShortString
+			}
+			units = append(units, binary.LittleEndian.Uint16(data[r.pos:]))
+			r.pos += 2
+		} else {
+			units = append(units, uint16(data[r.pos]))
+			r.pos++
+		}
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+func readSST(chunks [][]byte) ([]string, error) {
+	r := &sstReader{chunks: chunks}
+	head, err := r.read(8)
+	if err != nil {
+		return nil, err
+	}
+	unique := int(binary.LittleEndian.Uint32(head[4:]))
+	result := make([]string, 0, min(unique, 1<<16))
+	for len(result) < unique {
+		head, err := r.read(3)
+		if err != nil {
+			return nil, err
+		}
+		count, flags := int(binary.LittleEndian.Uint16(head)), head[2]
+		var runs, ext int
+		if flags&0x08 != 0 {
+			b, err := r.read(2)
+			if err != nil {
+				return nil, err
+			}
+			runs = int(binary.LittleEndian.Uint16(b))
+		}
+		if flags&0x04 != 0 {
+			b, err := r.read(4)
+			if err != nil {
+				return nil, err
+			}
+			ext = int(binary.LittleEndian.Uint32(b))
+		}
+		s, err := r.chars(count, flags&0x01 != 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.skip(4*runs + ext); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// formatNumber renders a number cell. Dates and times use ISO 8601 and
+// percentages keep their sign; anything else is the shortest exact decimal.
+func (wb *xlsWorkbook) formatNumber(v float64, xf uint16) string {
+	var code string
+	var id uint16
+	if int(xf) < len(wb.xfFormats) {
+		id = wb.xfFormats[xf]
+		code = wb.formats[id]
+	}
+	switch kind := numberFormatKind(id, code); kind {
+	case "date", "time", "datetime":
+		if t, ok := excelSerialTime(v, wb.date1904); ok {
+			switch kind {
+			case "date":
+				return t.Format("2006-01-02")
+			case "time":
+				return t.Format("15:04:05")
+			default:
+				return t.Format("2006-01-02 15:04:05")
+			}
+		}
+	case "percent":
+		return strconv.FormatFloat(math.Round(v*100*1e9)/1e9, 'f', -1, 64) + "%"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// numberFormatKind classifies a number format by its built-in id or, for
+// custom formats, by the tokens left once literals are removed.
+func numberFormatKind(id uint16, code string) string {
+	switch {
+	case id == 9 || id == 10:
+		return "percent"
+	case id >= 14 && id <= 17, id >= 27 && id <= 31, id >= 34 && id <= 36, id >= 50 && id <= 58:
+		return "date"
+	case id >= 18 && id <= 21, id >= 32 && id <= 33, id >= 45 && id <= 47:
+		return "time"
+	case id == 22:
+		return "datetime"
+	}
+	if code == "" {
+		return ""
+	}
+
+	var plain strings.Builder
+	inQuote, inBracket := false, false
+	for i := 0; i < len(code); i++ {
+		ch := code[i]
+		switch {
+		case inQuote:
+			inQuote = ch != '"'
+		case inBracket:
+			if ch == ']' {
+				inBracket = false
+			} else if strings.ContainsRune("hHsS", rune(ch)) {
+				plain.WriteByte('h') // elapsed time, e.g. [h]:mm
+			}
+		case ch == '"':
+			inQuote = true
+		case ch == '[':
+			inBracket = true
+		case ch == '\\' || ch == '_' || ch == '*':
+			i++
+		case ch == ';':
+			i = len(code) // the first section decides
+		default:
+			plain.WriteByte(ch)
+		}
+	}
+	tokens := strings.ToLower(plain.String())
+	date := strings.ContainsAny(tokens, "dy")
+	clock := strings.ContainsAny(tokens, "hs")
+	switch {
+	case date && clock:
+		return "datetime"
+	case date:
+		return "date"
+	case clock:
+		return "time"
+	case strings.Contains(tokens, "m") && !strings.ContainsAny(tokens, "0#?"):
+		return "date" // a month on its own, e.g. "mmm"
+	case strings.Contains(tokens, "%"):
+		return "percent"
+	}
+	return ""
+}
+
+// excelSerialTime converts a serial date. The 1900 system counts 1900-01-01
+// as day 1 and includes the nonexistent 1900-02-29 as day 60.
+func excelSerialTime(v float64, date1904 bool) (time.Time, bool) {
+	if v < 0 || v > 2958465 { // past 9999-12-31
+		return time.Time{}, false
+	}
+	base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	switch {
+	case date1904:
+		base = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	case v < 60:
+		base = base.AddDate(0, 0, 1)
+	}
+	days := math.Floor(v)
+	seconds := math.Round((v - days) * 86400)
+	return base.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second), true
+}
@@ -0,0 +1,82 @@
+package converter
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func biffRecordBytes(typ uint16, data []byte) []byte {
+	out := binary.LittleEndian.AppendUint16(nil, typ)
+	out = binary.LittleEndian.AppendUint16(out, uint16(len(data)))
+	return append(out, data...)
+}
+
+func TestReadSST_RejectsOversizedExtData(t *testing.T) {
+	// One string "ab" whose extended data claims 4 GiB.
+	chunk := []byte{1, 0, 0, 0, 1, 0, 0, 0}
+	chunk = append(chunk, 2, 0, 0x04)
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0xFFFFFFFF)
+	chunk = append(chunk, 'a', 'b')
+
+	allocs := testing.AllocsPerRun(1, func() {
+		if _, err := readSST([][]byte{chunk}); err == nil {
+			t.Fatal("expected an error for ext data past the end of the table")
+		}
+	})
+	if allocs > 10 {
+		t.Errorf("readSST made %v allocations skipping ext data", allocs)
+	}
+
+	ok := append(chunk[:11:11], binary.LittleEndian.AppendUint32(nil, 1)...)
+	ok = append(ok, 'a', 'b', 0xEE)
+	got, err := readSST([][]byte{ok})
+	if err != nil || len(got) != 1 || got[0] != "ab" {
+		t.Fatalf("readSST = %q, %v", got, err)
+	}
+}
+
+func labelSSTRecord(row, col uint16, sst uint32) []byte {
+	data := binary.LittleEndian.AppendUint16(nil, row)
+	data = binary.LittleEndian.AppendUint16(data, col)
+	data = binary.LittleEndian.AppendUint16(data, 0)
+	return biffRecordBytes(biffLabelSST, binary.LittleEndian.AppendUint32(data, sst))
+}
+
+func TestReadSheet_IgnoresCellsPastBIFF8Limits(t *testing.T) {
+	stream := biffRecordBytes(biffBOF, make([]byte, 16))
+	stream = append(stream, labelSSTRecord(0, 0, 0)...)
+	stream = append(stream, labelSSTRecord(1, 0xFFFF, 0)...)
+	stream = append(stream, labelSSTRecord(0xFFFF, 300, 0)...)
+	stream = append(stream, biffRecordBytes(biffEOF, nil)...)
+
+	wb := &xlsWorkbook{strings: []string{"x"}, formats: map[uint16]string{}}
+	rows, err := wb.readSheet(stream, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || len(rows[0]) != 1 || rows[0][0] != "x" {
+		t.Errorf("rows = %d (%v), want only the in-range cell", len(rows), rows[:min(len(rows), 2)])
+	}
+}
+
+func TestReadSheet_CapsTotalCells(t *testing.T) {
+	// One far-right cell per row would pad every row of the grid to 256 cells.
+	stream := biffRecordBytes(biffBOF, make([]byte, 16))
+	for row := 0; row < biff8MaxRows; row++ {
+		stream = append(stream, labelSSTRecord(uint16(row), biff8MaxCols-1, 0)...)
+	}
+	stream = append(stream, biffRecordBytes(biffEOF, nil)...)
+
+	wb := &xlsWorkbook{strings: []string{"x"}, formats: map[uint16]string{}}
+	rows, err := wb.readSheet(stream, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cells := 0
+	for _, row := range rows {
+		cells += len(row)
+	}
+	if cells > xlsMaxCells || len(rows[0]) != biff8MaxCols {
+		t.Errorf("sheet kept %d cells across %d rows, cap %d", cells, len(rows), xlsMaxCells)
+	}
+}
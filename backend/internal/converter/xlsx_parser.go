@@ -8,11 +8,19 @@ import (
 )
 
 // XLSXParser parses Excel files
-type XLSXParser struct{}
+type XLSXParser struct {
+	fill MergeFill
+}
 
-// NewXLSXParser creates a new XLSXParser
+// NewXLSXParser creates a new XLSXParser that fills merged cells along both axes
 func NewXLSXParser() *XLSXParser {
-	return &XLSXParser{}
+	return &XLSXParser{fill: DefaultMergeFill()}
+}
+
+// WithMergeFill sets which cells of merged regions take the region's value
+func (p *XLSXParser) WithMergeFill(fill MergeFill) *XLSXParser {
+	p.fill = fill
+	return p
 }
 
 // ParseFile parses an Excel file from path
@@ -39,9 +47,10 @@ func (p *XLSXParser) ParseReader(reader io.Reader) (*XLSXResult, error) {
 
 // XLSXResult contains parsed XLSX data
 type XLSXResult struct {
-	Sheets     []string              `json:"sheets"`
-	SheetData  map[string]CellMatrix `json:"-"`
-	ActiveSheet string               `json:"active_sheet"`
+	Sheets      []string                  `json:"sheets"`
+	SheetData   map[string]CellMatrix     `json:"-"`
+	Merges      map[string][]MergedRegion `json:"-"`
+	ActiveSheet string                    `json:"active_sheet"`
 }
 
 // GetMatrix returns the CellMatrix for a specific sheet
@@ -61,17 +70,20 @@ func (p *XLSXParser) parseExcelFile(f *excelize.File) (*XLSXResult, error) {
 	result := &XLSXResult{
 		Sheets:      sheets,
 		SheetData:   make(map[string]CellMatrix),
+		Merges:      make(map[string][]MergedRegion),
 		ActiveSheet: sheets[0],
 	}
 
 	for _, sheetName := range sheets {
-		rows, err := f.GetRows(sheetName)
+		matrix, merges, err := p.readSheet(f, sheetName)
 		if err != nil {
 			continue // Skip sheets that can't be read
 		}
 
-		matrix := NewCellMatrix(rows).Normalize()
 		result.SheetData[sheetName] = matrix
+		if len(merges) > 0 {
+			result.Merges[sheetName] = merges
+		}
 	}
 
 	return result, nil
@@ -79,18 +91,20 @@ func (p *XLSXParser) parseExcelFile(f *excelize.File) (*XLSXResult, error) {
 
 // ParseSheet parses a specific sheet from a file
 func (p *XLSXParser) ParseSheet(filePath string, sheetName string) (CellMatrix, error) {
+	matrix, _, err := p.ParseSheetWithMerges(filePath, sheetName)
+	return matrix, err
+}
+
+// ParseSheetWithMerges parses a specific sheet from a file along with its
+// merged regions, located in the returned matrix
+func (p *XLSXParser) ParseSheetWithMerges(filePath string, sheetName string) (CellMatrix, []MergedRegion, error) {
 	f, err := excelize.OpenFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open excel file: %w", err)
+		return nil, nil, fmt.Errorf("failed to open excel file: %w", err)
 	}
 	defer f.Close()
 
-	rows, err := f.GetRows(sheetName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get rows from sheet %s: %w", sheetName, err)
-	}
-
-	return NewCellMatrix(rows).Normalize(), nil
+	return p.readSheet(f, sheetName)
 }
 
 // ParseSheetFromReader parses a specific sheet from reader
@@ -110,10 +124,24 @@ func (p *XLSXParser) ParseSheetFromReader(reader io.Reader, sheetName string) (C
 		sheetName = sheets[0]
 	}
 
+	matrix, _, err := p.readSheet(f, sheetName)
+	return matrix, err
+}
+
+// readSheet reads a sheet with its merged cells filled as p.fill selects.
+func (p *XLSXParser) readSheet(f *excelize.File, sheetName string) (CellMatrix, []MergedRegion, error) {
 	rows, err := f.GetRows(sheetName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get rows from sheet %s: %w", sheetName, err)
+		return nil, nil, fmt.Errorf("failed to get rows from sheet %s: %w", sheetName, err)
+	}
+	cells, err := f.GetMergeCells(sheetName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get merged cells from sheet %s: %w", sheetName, err)
+	}
+	if len(cells) == 0 {
+		return NewCellMatrix(rows).Normalize(), nil, nil
 	}
 
-	return NewCellMatrix(rows).Normalize(), nil
+	rows, merges := fillMerges(rows, cells, p.fill)
+	return NewCellMatrix(rows).Normalize(), LocateMerges(merges, keptRows(rows), 1), nil
 }
@@ -0,0 +1,92 @@
+package converter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// MappedTable returns the header row and the rows below it, with each mapped
+// column's header replaced by its canonical field name. Unmapped columns keep
+// their header, so nothing from the source is dropped.
+func MappedTable(matrix CellMatrix, headerRow int, colMap ColumnMap) CellMatrix {
+	if headerRow < 0 || headerRow >= len(matrix) {
+		return CellMatrix{}
+	}
+	headers := make([]string, len(matrix[headerRow]))
+	for i, header := range matrix[headerRow] {
+		headers[i] = strings.TrimSpace(header)
+	}
+	for field, col := range colMap {
+		if col >= 0 && col < len(headers) {
+			headers[col] = string(field)
+		}
+	}
+	table := CellMatrix{headers}
+	return append(table, matrix.SliceRows(headerRow+1, matrix.RowCount())...)
+}
+
+// WriteXLSX writes matrix as a one-sheet workbook with a bold, frozen first
+// row. An empty sheet name is written as "Sheet1".
+func WriteXLSX(w io.Writer, matrix CellMatrix, sheetName string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := sanitizeSheetName(sheetName)
+	if sheet != "Sheet1" {
+		if err := f.SetSheetName("Sheet1", sheet); err != nil {
+			return fmt.Errorf("naming sheet: %w", err)
+		}
+	}
+	for i, row := range matrix {
+		cells := make([]any, len(row))
+		for j, value := range row {
+			cells[j] = value
+		}
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetSheetRow(sheet, cell, &cells); err != nil {
+			return fmt.Errorf("writing row %d: %w", i+1, err)
+		}
+	}
+
+	if cols := matrix.ColCount(); len(matrix) > 0 && cols > 0 {
+		bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+		if err != nil {
+			return err
+		}
+		last, err := excelize.CoordinatesToCellName(cols, 1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(sheet, "A1", last, bold); err != nil {
+			return err
+		}
+		if err := f.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+			return err
+		}
+	}
+	return f.Write(w)
+}
+
+// sanitizeSheetName makes name a valid Excel sheet name: at most 31
+// characters and none of : \ / ? * [ ].
+func sanitizeSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if name == "" {
+		return "Sheet1"
+	}
+	return name
+}
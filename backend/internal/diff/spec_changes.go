@@ -0,0 +1,152 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+// RowChange names a spec row that exists in both versions and the fields that differ.
+type RowChange struct {
+	Key    string   `json:"key"`
+	Fields []string `json:"fields"`
+}
+
+// SpecChanges is a row-level comparison of two specs. Rows are matched by ID,
+// falling back to title, feature/scenario and finally position.
+type SpecChanges struct {
+	Added     []string    `json:"added"`
+	Removed   []string    `json:"removed"`
+	Modified  []RowChange `json:"modified"`
+	Unchanged int         `json:"unchanged"`
+}
+
+// Empty reports whether no rows were added, removed or modified.
+func (c *SpecChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Modified) == 0
+}
+
+// CompareSpecs compares the rows of two specs. A nil before compares against
+// an empty spec.
+func CompareSpecs(before, after *converter.SpecDoc) *SpecChanges {
+	oldRows, oldKeys := keyedRows(before)
+	newRows, newKeys := keyedRows(after)
+
+	changes := &SpecChanges{Added: []string{}, Removed: []string{}, Modified: []RowChange{}}
+	for _, key := range newKeys {
+		oldFields, ok := oldRows[key]
+		if !ok {
+			changes.Added = append(changes.Added, key)
+			continue
+		}
+		if fields := changedFields(oldFields, newRows[key]); len(fields) > 0 {
+			changes.Modified = append(changes.Modified, RowChange{Key: key, Fields: fields})
+		} else {
+			changes.Unchanged++
+		}
+	}
+	for _, key := range oldKeys {
+		if _, ok := newRows[key]; !ok {
+			changes.Removed = append(changes.Removed, key)
+		}
+	}
+	return changes
+}
+
+// FormatSpecChanges renders changes one row per line ("+ REQ-4", "- REQ-2",
+// "~ REQ-1: priority, title"), or "no row changes".
+func FormatSpecChanges(c *SpecChanges) string {
+	if c.Empty() {
+		return "no row changes\n"
+	}
+	var buf strings.Builder
+	for _, key := range c.Added {
+		buf.WriteString(fmt.Sprintf("+ %s\n", key))
+	}
+	for _, key := range c.Removed {
+		buf.WriteString(fmt.Sprintf("- %s\n", key))
+	}
+	for _, m := range c.Modified {
+		buf.WriteString(fmt.Sprintf("~ %s: %s\n", m.Key, strings.Join(m.Fields, ", ")))
+	}
+	return buf.String()
+}
+
+// keyedRows flattens each row to field -> value under a stable key, in order.
+func keyedRows(doc *converter.SpecDoc) (map[string]map[string]string, []string) {
+	rows := map[string]map[string]string{}
+	var keys []string
+	if doc == nil {
+		return rows, keys
+	}
+	for i, row := range doc.Rows {
+		key := rowKey(row, i)
+		base := key
+		for n := 2; ; n++ {
+			if _, dup := rows[key]; !dup {
+				break
+			}
+			key = fmt.Sprintf("%s #%d", base, n)
+		}
+		rows[key] = flattenRow(row)
+		keys = append(keys, key)
+	}
+	return rows, keys
+}
+
+func rowKey(row converter.SpecRow, index int) string {
+	for _, candidate := range []string{row.ID, row.Title} {
+		if v := strings.TrimSpace(candidate); v != "" {
+			return v
+		}
+	}
+	if f, s := strings.TrimSpace(row.Feature), strings.TrimSpace(row.Scenario); f != "" || s != "" {
+		if s == "" || s == f {
+			return f
+		}
+		return f + " / " + s
+	}
+	return fmt.Sprintf("row %d", index+1)
+}
+
+// flattenRow maps a row's JSON field names (and metadata.<header>) to values.
+// SourceRow is left out: moving a row in the sheet is not a content change.
+func flattenRow(row converter.SpecRow) map[string]string {
+	row.SourceRow = 0
+	data, _ := json.Marshal(row)
+	var raw map[string]any
+	_ = json.Unmarshal(data, &raw)
+
+	fields := map[string]string{}
+	for k, v := range raw {
+		if meta, ok := v.(map[string]any); ok {
+			for mk, mv := range meta {
+				fields["metadata."+mk] = fmt.Sprint(mv)
+			}
+			continue
+		}
+		if s := fmt.Sprint(v); s != "" {
+			fields[k] = s
+		}
+	}
+	return fields
+}
+
+func changedFields(before, after map[string]string) []string {
+	var fields []string
+	for k, v := range after {
+		if before[k] != v {
+			fields = append(fields, k)
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
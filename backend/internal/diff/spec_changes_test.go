@@ -0,0 +1,48 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+func TestCompareSpecs(t *testing.T) {
+	before := &converter.SpecDoc{Rows: []converter.SpecRow{
+		{ID: "REQ-1", Title: "Login", Priority: "High", SourceRow: 2},
+		{ID: "REQ-2", Title: "Logout"},
+		{Title: "Untitled", Metadata: map[string]string{"Owner": "ana"}},
+	}}
+	after := &converter.SpecDoc{Rows: []converter.SpecRow{
+		{ID: "REQ-1", Title: "Sign in", Priority: "High", SourceRow: 7},
+		{Title: "Untitled", Metadata: map[string]string{"Owner": "bo"}},
+		{ID: "REQ-3", Title: "Reset password"},
+	}}
+
+	got := CompareSpecs(before, after)
+	want := &SpecChanges{
+		Added:   []string{"REQ-3"},
+		Removed: []string{"REQ-2"},
+		Modified: []RowChange{
+			{Key: "REQ-1", Fields: []string{"title"}},
+			{Key: "Untitled", Fields: []string{"metadata.Owner"}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("CompareSpecs = %+v, want %+v", got, want)
+	}
+	if out := FormatSpecChanges(got); out != "+ REQ-3\n- REQ-2\n~ REQ-1: title\n~ Untitled: metadata.Owner\n" {
+		t.Fatalf("FormatSpecChanges = %q", out)
+	}
+}
+
+func TestCompareSpecs_DuplicateKeysAndNil(t *testing.T) {
+	doc := &converter.SpecDoc{Rows: []converter.SpecRow{{Feature: "Cart"}, {Feature: "Cart"}}}
+	got := CompareSpecs(nil, doc)
+	if !reflect.DeepEqual(got.Added, []string{"Cart", "Cart #2"}) {
+		t.Fatalf("added = %v", got.Added)
+	}
+	if same := CompareSpecs(doc, doc); !same.Empty() || same.Unchanged != 2 {
+		t.Fatalf("same = %+v", same)
+	}
+}
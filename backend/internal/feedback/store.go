@@ -1,6 +1,7 @@
 package feedback
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -262,6 +263,12 @@ func (s *Store) GetByRequestHash(hash string) ([]Feedback, error) {
 	return results, nil
 }
 
+// Ping verifies the database is reachable and the schema is queryable.
+func (s *Store) Ping(ctx context.Context) error {
+	var n int
+	return s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM feedback LIMIT 1`).Scan(&n)
+}
+
 // Close closes the underlying database connection.
 func (s *Store) Close() error {
 	return s.db.Close()
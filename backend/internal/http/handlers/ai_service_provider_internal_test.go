@@ -34,6 +34,10 @@ func (fakeAIService) ValidateSemantic(context.Context, ai.SemanticValidationRequ
 	return nil, nil
 }
 
+func (fakeAIService) ExtractRequirements(context.Context, ai.ExtractRequirementsRequest) (*ai.RequirementsExtraction, error) {
+	return nil, nil
+}
+
 func (fakeAIService) GetMode() string  { return "on" }
 func (fakeAIService) GetModel() string { return "gpt-4o-mini" }
 
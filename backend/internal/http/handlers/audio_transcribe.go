@@ -1,13 +1,11 @@
 package handlers
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/exec"
@@ -19,12 +17,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/transcribe"
 )
 
 const (
-	openAITranscribeURL    = "https://api.openai.com/v1/audio/transcriptions"
-	openAITranscribeModel  = "whisper-1"
-	maxOpenAIUploadBytes   = 25 << 20
 	segmentDurationSeconds = 600.0
 	segmentMinSeconds      = 30.0
 	silenceThresholdDB     = "-30dB"
@@ -32,31 +28,16 @@ const (
 )
 
 type AudioTranscribeHandler struct {
-	cfg        *config.Config
-	httpClient *http.Client
+	cfg         *config.Config
+	transcriber transcribe.Transcriber
 }
 
-type OpenAIWord struct {
-	Word       string  `json:"word"`
-	Start      float64 `json:"start"`
-	End        float64 `json:"end"`
-	Confidence float64 `json:"confidence,omitempty"`
-}
-
-type OpenAISegment struct {
-	ID    int     `json:"id"`
-	Start float64 `json:"start"`
-	End   float64 `json:"end"`
-	Text  string  `json:"text"`
-}
-
-type OpenAITranscriptionResponse struct {
-	Text     string          `json:"text"`
-	Language string          `json:"language"`
-	Duration float64         `json:"duration"`
-	Segments []OpenAISegment `json:"segments"`
-	Words    []OpenAIWord    `json:"words"`
-}
+// Transcript shapes are shared by every backend; the OpenAI names are kept for existing callers.
+type (
+	OpenAIWord                  = transcribe.Word
+	OpenAISegment               = transcribe.Segment
+	OpenAITranscriptionResponse = transcribe.Transcription
+)
 
 type TranscriptSplit struct {
 	ID    string  `json:"id"`
@@ -83,21 +64,49 @@ func NewAudioTranscribeHandler(cfg *config.Config) *AudioTranscribeHandler {
 		timeout = 120 * time.Second
 	}
 
+	transcriber, err := transcribe.New(transcribe.Config{
+		Provider:       cfg.TranscribeProvider,
+		BaseURL:        cfg.TranscribeBaseURL,
+		Model:          cfg.TranscribeModel,
+		APIKey:         transcribeAPIKey(cfg),
+		MaxUploadBytes: cfg.TranscribeMaxUploadBytes,
+	}, &http.Client{Timeout: timeout + 30*time.Second})
+	if err != nil {
+		slog.Warn("transcription backend unavailable", "provider", cfg.TranscribeProvider, "error", err)
+	}
+
 	return &AudioTranscribeHandler{
-		cfg: cfg,
-		httpClient: &http.Client{
-			Timeout: timeout + 30*time.Second,
-		},
+		cfg:         cfg,
+		transcriber: transcriber,
+	}
+}
+
+// transcribeAPIKey returns the server key for the configured backend. OpenAI
+// falls back to OPENAI_API_KEY; self-hosted backends only use TRANSCRIBE_API_KEY.
+func transcribeAPIKey(cfg *config.Config) string {
+	if key := strings.TrimSpace(cfg.TranscribeAPIKey); key != "" {
+		return key
 	}
+	provider := strings.ToLower(strings.TrimSpace(cfg.TranscribeProvider))
+	if provider == "" || provider == transcribe.ProviderOpenAI {
+		return strings.TrimSpace(cfg.OpenAIAPIKey)
+	}
+	return ""
+}
+
+// SetTranscriber replaces the configured backend (used by tests).
+func (h *AudioTranscribeHandler) SetTranscriber(t transcribe.Transcriber) {
+	h.transcriber = t
 }
 
 func (h *AudioTranscribeHandler) Transcribe(c *gin.Context) {
-	apiKey := strings.TrimSpace(getUserAPIKey(c))
-	if apiKey == "" {
-		apiKey = strings.TrimSpace(h.cfg.OpenAIAPIKey)
+	if h.transcriber == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: "transcription backend not configured"})
+		return
 	}
-	if apiKey == "" {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "OpenAI API key not configured"})
+	apiKey := strings.TrimSpace(getUserAPIKey(c))
+	if err := h.transcriber.CheckAPIKey(apiKey); err != nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: "transcription backend API key not configured"})
 		return
 	}
 
@@ -128,6 +137,17 @@ func (h *AudioTranscribeHandler) Transcribe(c *gin.Context) {
 		return
 	}
 
+	format, err := transcribe.NormalizeExportFormat(formOrQuery(c, "format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	split, err := exportSplit(formOrQuery(c, "split"), format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
 	tempFile, err := os.CreateTemp("", "audio-upload-*"+filepath.Ext(header.Filename))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: fmt.Sprintf("failed to process file: %v", err)})
@@ -160,9 +180,71 @@ func (h *AudioTranscribeHandler) Transcribe(c *gin.Context) {
 	}
 	response.Warnings = warnings
 
+	if format != transcribe.ExportJSON {
+		writeTranscriptExport(c, response, format, split, header.Filename)
+		return
+	}
 	c.JSON(http.StatusOK, response)
 }
 
+// formOrQuery reads a parameter from the multipart form, falling back to the query string.
+func formOrQuery(c *gin.Context, key string) string {
+	if v := strings.TrimSpace(c.PostForm(key)); v != "" {
+		return v
+	}
+	return strings.TrimSpace(c.Query(key))
+}
+
+// exportSplit picks which splits to export: sentences suit subtitles, paragraphs suit reading.
+func exportSplit(split, format string) (string, error) {
+	switch strings.ToLower(split) {
+	case "":
+		if format == transcribe.ExportMarkdown {
+			return "paragraphs", nil
+		}
+		return "sentences", nil
+	case "sentences", "sentence":
+		return "sentences", nil
+	case "paragraphs", "paragraph":
+		return "paragraphs", nil
+	default:
+		return "", fmt.Errorf("unsupported split %q (use sentences or paragraphs)", split)
+	}
+}
+
+// writeTranscriptExport serves the transcript as a WebVTT, SRT or markdown
+// attachment named after the uploaded file. Chunking warnings go in a header.
+func writeTranscriptExport(c *gin.Context, resp *AudioTranscribeResponse, format, split, filename string) {
+	splits := resp.Sentences
+	if split == "paragraphs" {
+		splits = resp.Paragraphs
+	}
+	cues := make([]transcribe.Cue, 0, len(splits))
+	for _, s := range splits {
+		cues = append(cues, transcribe.Cue{ID: s.ID, Start: s.Start, End: s.End, Text: s.Text})
+	}
+
+	base := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	var body string
+	switch format {
+	case transcribe.ExportVTT:
+		body = transcribe.FormatWebVTT(cues)
+	case transcribe.ExportSRT:
+		body = transcribe.FormatSRT(cues)
+	default:
+		body = transcribe.FormatTimestampedMarkdown(base, cues)
+	}
+
+	if base == "" || base == "." {
+		base = "transcript"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", base+transcribe.ExportExtension(format)))
+	if len(resp.Warnings) > 0 {
+		c.Header("X-Transcribe-Warnings", strings.Join(resp.Warnings, "; "))
+	}
+	c.Data(http.StatusOK, transcribe.ExportContentType(format), []byte(body))
+}
+
 func validateAudioExtension(filename string) error {
 	ext := strings.ToLower(filepath.Ext(filename))
 	switch ext {
@@ -179,15 +261,16 @@ func (h *AudioTranscribeHandler) transcribeWithChunking(ctx context.Context, fil
 		return nil, nil, fmt.Errorf("failed to read audio file: %w", err)
 	}
 
-	if info.Size() <= maxOpenAIUploadBytes {
-		resp, err := h.requestTranscription(ctx, filePath, apiKey)
+	limit := h.transcriber.MaxUploadBytes()
+	if limit <= 0 || info.Size() <= limit {
+		resp, err := h.transcriber.Transcribe(ctx, transcribe.Request{FilePath: filePath, APIKey: apiKey})
 		if err != nil {
 			return nil, nil, err
 		}
 		return h.buildResponse(resp), nil, nil
 	}
 
-	warnings := []string{"Audio exceeds 25MB; server-side chunking enabled."}
+	warnings := []string{fmt.Sprintf("Audio exceeds %s; server-side chunking enabled.", humanSize(limit))}
 	chunkPaths, err := splitAudioWithSilence(filePath)
 	if err != nil {
 		warnings = append(warnings, "Falling back to fixed-length chunking.")
@@ -203,7 +286,7 @@ func (h *AudioTranscribeHandler) transcribeWithChunking(ctx context.Context, fil
 	offset := 0.0
 
 	for _, chunkPath := range chunkPaths {
-		resp, err := h.requestTranscription(ctx, chunkPath, apiKey)
+		resp, err := h.transcriber.Transcribe(ctx, transcribe.Request{FilePath: chunkPath, APIKey: apiKey})
 		if err != nil {
 			return nil, warnings, err
 		}
@@ -244,10 +327,10 @@ func (h *AudioTranscribeHandler) transcribeWithChunking(ctx context.Context, fil
 
 func splitAudioWithSilence(filePath string) ([]string, error) {
 	if _, err := exec.LookPath("ffmpeg"); err != nil {
-		return nil, fmt.Errorf("ffmpeg not available for chunking; please upload a file under 25MB or install ffmpeg")
+		return nil, fmt.Errorf("ffmpeg not available for chunking; please upload a smaller file or install ffmpeg")
 	}
 	if _, err := exec.LookPath("ffprobe"); err != nil {
-		return nil, fmt.Errorf("ffprobe not available for chunking; please upload a file under 25MB or install ffmpeg")
+		return nil, fmt.Errorf("ffprobe not available for chunking; please upload a smaller file or install ffmpeg")
 	}
 
 	duration, err := probeDuration(filePath)
@@ -270,7 +353,7 @@ func splitAudioWithSilence(filePath string) ([]string, error) {
 
 func splitAudioFixed(filePath string) ([]string, error) {
 	if _, err := exec.LookPath("ffmpeg"); err != nil {
-		return nil, fmt.Errorf("ffmpeg not available for chunking; please upload a file under 25MB or install ffmpeg")
+		return nil, fmt.Errorf("ffmpeg not available for chunking; please upload a smaller file or install ffmpeg")
 	}
 
 	tempDir, err := os.MkdirTemp("", "audio-chunks-*")
@@ -462,77 +545,6 @@ func cleanupFiles(files []string) {
 	}
 }
 
-func (h *AudioTranscribeHandler) requestTranscription(ctx context.Context, filePath, apiKey string) (*OpenAITranscriptionResponse, error) {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read audio: %w", err)
-	}
-	defer file.Close()
-
-	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
-	if err != nil {
-		return nil, fmt.Errorf("failed to prepare upload: %w", err)
-	}
-	if _, err := io.Copy(part, file); err != nil {
-		return nil, fmt.Errorf("failed to read audio: %w", err)
-	}
-
-	if err := writer.WriteField("model", openAITranscribeModel); err != nil {
-		return nil, fmt.Errorf("failed to write model field: %w", err)
-	}
-	if err := writer.WriteField("response_format", "verbose_json"); err != nil {
-		return nil, fmt.Errorf("failed to write response_format field: %w", err)
-	}
-	if err := writer.WriteField("timestamp_granularities[]", "word"); err != nil {
-		return nil, fmt.Errorf("failed to write timestamp_granularities field: %w", err)
-	}
-	if err := writer.WriteField("timestamp_granularities[]", "segment"); err != nil {
-		return nil, fmt.Errorf("failed to write timestamp_granularities field: %w", err)
-	}
-
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to prepare request")
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, openAITranscribeURL, body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	resp, err := h.httpClient.Do(req)
-	if err != nil {
-		if errors.Is(err, context.DeadlineExceeded) {
-			return nil, fmt.Errorf("transcription request timeout: %w", err)
-		}
-		return nil, fmt.Errorf("transcription request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		payload, _ := io.ReadAll(io.LimitReader(resp.Body, 8<<10))
-		errMsg := strings.TrimSpace(string(payload))
-		if resp.StatusCode == 401 || resp.StatusCode == 403 {
-			return nil, fmt.Errorf("openai authentication failed: %s", errMsg)
-		}
-		if resp.StatusCode == 429 {
-			return nil, fmt.Errorf("openai rate limited: %s", errMsg)
-		}
-		return nil, fmt.Errorf("transcription failed (status %d): %s", resp.StatusCode, errMsg)
-	}
-
-	var response OpenAITranscriptionResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to parse transcription: %w", err)
-	}
-
-	return &response, nil
-}
-
 func (h *AudioTranscribeHandler) buildResponse(resp *OpenAITranscriptionResponse) *AudioTranscribeResponse {
 	sentences := buildSentenceSplits(resp.Words, resp.Segments)
 	paragraphs := buildParagraphSplits(sentences)
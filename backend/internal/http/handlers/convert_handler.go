@@ -48,6 +48,7 @@ type ConvertHandler struct {
 	cfg          *config.Config
 	byokCache    *AIServiceProvider
 	quotaHandler *QuotaHandler
+	events       EventPublisher
 }
 
 // NewConvertHandler creates a new ConvertHandler
@@ -74,6 +75,11 @@ func (h *ConvertHandler) SetQuotaHandler(qh *QuotaHandler) {
 	h.quotaHandler = qh
 }
 
+// SetEventPublisher sets the publisher for conversion webhooks
+func (h *ConvertHandler) SetEventPublisher(p EventPublisher) {
+	h.events = p
+}
+
 // ConvertPaste handles POST /api/mdflow/paste
 // If detect_only=true query param, returns input type analysis
 // Otherwise converts pasted TSV/CSV text to MDFlow format
@@ -140,6 +146,12 @@ func (h *ConvertHandler) ConvertPaste(c *gin.Context) {
 		return
 	}
 
+	validationRules, err := resolveValidationRules(req.ValidationRules, req.ValidationRulesText)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error(), Code: invalidRulesCode})
+		return
+	}
+
 	// Full conversion with format support (BYOK-aware)
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 150*time.Second)
 	defer cancel()
@@ -156,10 +168,9 @@ func (h *ConvertHandler) ConvertPaste(c *gin.Context) {
 	}
 
 	warnings := result.Warnings
-	if req.ValidationRules != nil && hasValidationRules(req.ValidationRules) {
-		specDoc, buildErr := converter.BuildSpecDocFromPaste(req.PasteText)
-		if buildErr == nil {
-			valResult := converter.Validate(specDoc, req.ValidationRules)
+	if !validationRules.IsEmpty() {
+		if specDoc := pasteValidationDoc(result, req.PasteText); specDoc != nil {
+			valResult := converter.ValidateWithContext(ctx, specDoc, validationRules)
 			if len(valResult.Warnings) > 0 {
 				warnings = append(warnings, valResult.Warnings...)
 			}
@@ -169,16 +180,18 @@ func (h *ConvertHandler) ConvertPaste(c *gin.Context) {
 	slog.Info("mdflow.ConvertPaste ai", "ai_mode", result.Meta.AIMode, "ai_used", result.Meta.AIUsed, "ai_confidence", result.Meta.AIAvgConfidence)
 
 	// Track token usage for quota enforcement (input + output tokens)
-	h.recordTokenUsage(c, result.Meta)
+	h.recordTokenUsage(c, req.Template, result.Meta)
 
-	c.JSON(http.StatusOK, MDFlowConvertResponse{
+	resp := MDFlowConvertResponse{
 		MDFlow:      result.MDFlow,
 		Warnings:    warnings,
 		Meta:        result.Meta,
 		Format:      req.Format,
 		Template:    req.Template,
 		NeedsReview: RequiresReview(result.Meta, warnings),
-	})
+	}
+	publishConversionEvents(h.events, c, "paste", resp)
+	c.JSON(http.StatusOK, resp)
 }
 
 // ConvertXLSX handles POST /api/mdflow/xlsx
@@ -207,8 +220,8 @@ func (h *ConvertHandler) ConvertXLSX(c *gin.Context) {
 
 	// Check file extension
 	ext := strings.ToLower(filepath.Ext(header.Filename))
-	if ext != ".xlsx" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "only .xlsx files are supported"})
+	if !converter.IsSpreadsheetFile(header.Filename) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "only .xlsx, .xls and .ods files are supported"})
 		return
 	}
 
@@ -238,6 +251,11 @@ func (h *ConvertHandler) ConvertXLSX(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
+	validationRules, err := formValidationRules(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error(), Code: invalidRulesCode})
+		return
+	}
 
 	buf := make([]byte, 4)
 	n, err := io.ReadFull(file, buf)
@@ -249,15 +267,15 @@ func (h *ConvertHandler) ConvertXLSX(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "failed to read file"})
 		return
 	}
-	if !bytes.HasPrefix(buf[:n], xlsxMagic) {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid xlsx file"})
+	if !bytes.HasPrefix(buf[:n], converter.SpreadsheetSignature(ext)) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid %s file", ext[1:])})
 		return
 	}
 
 	reader := io.MultiReader(bytes.NewReader(buf[:n]), file)
 
 	// Create temp file
-	tempFile, err := os.CreateTemp("", "upload-*.xlsx")
+	tempFile, err := os.CreateTemp("", "upload-*"+ext)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to process file"})
 		return
@@ -283,7 +301,7 @@ func (h *ConvertHandler) ConvertXLSX(c *gin.Context) {
 	}
 
 	conv := h.byokCache.GetConverterForRequest(c, h.converter)
-	matrix, err := conv.ParseXLSX(tempName, sheetName)
+	matrix, merges, err := conv.ParseXLSXWithMerges(c.Request.Context(), tempName, sheetName)
 	if err != nil {
 		slog.Error("mdflow.ConvertXLSX parse error", "error", err)
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to parse file"})
@@ -295,6 +313,7 @@ func (h *ConvertHandler) ConvertXLSX(c *gin.Context) {
 	defer cancel()
 
 	options := resolveConvertOptions(includeMetadata, numberRows)
+	options.Merges = merges
 	result, err := conv.ConvertMatrixWithOverridesAndOptions(ctx, matrix, sheetName, template, format, columnOverrides, options)
 	if err != nil {
 		slog.Error("mdflow.ConvertXLSX failed", "error", err)
@@ -302,19 +321,26 @@ func (h *ConvertHandler) ConvertXLSX(c *gin.Context) {
 		return
 	}
 
+	if !validationRules.IsEmpty() && result.Doc != nil {
+		valResult := converter.ValidateWithContext(ctx, result.Doc, validationRules)
+		result.Warnings = append(result.Warnings, valResult.Warnings...)
+	}
+
 	slog.Info("mdflow.ConvertXLSX ai", "ai_mode", result.Meta.AIMode, "ai_used", result.Meta.AIUsed, "ai_confidence", result.Meta.AIAvgConfidence)
 
 	// Track token usage for quota enforcement
-	h.recordTokenUsage(c, result.Meta)
+	h.recordTokenUsage(c, template, result.Meta)
 
-	c.JSON(http.StatusOK, MDFlowConvertResponse{
+	resp := MDFlowConvertResponse{
 		MDFlow:      result.MDFlow,
 		Warnings:    result.Warnings,
 		Meta:        result.Meta,
 		Format:      format,
 		Template:    template,
 		NeedsReview: RequiresReview(result.Meta, result.Warnings),
-	})
+	}
+	publishConversionEvents(h.events, c, "xlsx", resp)
+	c.JSON(http.StatusOK, resp)
 }
 
 // ConvertTSV handles POST /api/mdflow/tsv
@@ -365,6 +391,11 @@ func (h *ConvertHandler) ConvertTSV(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
+	validationRules, err := formValidationRules(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error(), Code: invalidRulesCode})
+		return
+	}
 
 	content, err := io.ReadAll(io.LimitReader(file, h.cfg.MaxUploadBytes+1))
 	if err != nil {
@@ -397,18 +428,40 @@ func (h *ConvertHandler) ConvertTSV(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to convert file"})
 		return
 	}
+	if !validationRules.IsEmpty() {
+		if specDoc := pasteValidationDoc(result, string(content)); specDoc != nil {
+			valResult := converter.ValidateWithContext(ctx, specDoc, validationRules)
+			result.Warnings = append(result.Warnings, valResult.Warnings...)
+		}
+	}
 
 	// Track token usage for quota enforcement
-	h.recordTokenUsage(c, result.Meta)
+	h.recordTokenUsage(c, template, result.Meta)
 
-	c.JSON(http.StatusOK, MDFlowConvertResponse{
+	resp := MDFlowConvertResponse{
 		MDFlow:      result.MDFlow,
 		Warnings:    result.Warnings,
 		Meta:        result.Meta,
 		Format:      format,
 		Template:    template,
 		NeedsReview: RequiresReview(result.Meta, result.Warnings),
-	})
+	}
+	publishConversionEvents(h.events, c, "tsv", resp)
+	c.JSON(http.StatusOK, resp)
+}
+
+// pasteValidationDoc returns the document validation rules check: the one the
+// conversion mapped, so column_overrides and AI mapping apply, or for markdown
+// input the rows parsed from the text.
+func pasteValidationDoc(result *converter.ConvertResponse, text string) *converter.SpecDoc {
+	if result.Doc != nil {
+		return result.Doc
+	}
+	specDoc, err := converter.BuildSpecDocFromPaste(text)
+	if err != nil {
+		return nil
+	}
+	return specDoc
 }
 
 // GetXLSXSheets handles POST /api/mdflow/xlsx/sheets
@@ -434,6 +487,7 @@ func (h *ConvertHandler) GetXLSXSheets(c *gin.Context) {
 		return
 	}
 
+	ext := uploadSpreadsheetExt(header.Filename)
 	buf := make([]byte, 4)
 	n, err := io.ReadFull(file, buf)
 	if err != nil {
@@ -444,15 +498,15 @@ func (h *ConvertHandler) GetXLSXSheets(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "failed to read file"})
 		return
 	}
-	if !bytes.HasPrefix(buf[:n], xlsxMagic) {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid xlsx file"})
+	if !bytes.HasPrefix(buf[:n], converter.SpreadsheetSignature(ext)) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid %s file", ext[1:])})
 		return
 	}
 
 	reader := io.MultiReader(bytes.NewReader(buf[:n]), file)
 
 	// Create temp file
-	tempFile, err := os.CreateTemp("", "upload-*.xlsx")
+	tempFile, err := os.CreateTemp("", "upload-*"+ext)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to process file"})
 		return
@@ -494,14 +548,19 @@ func (h *ConvertHandler) GetXLSXSheets(c *gin.Context) {
 	})
 }
 
-// recordTokenUsage tracks conversion count and AI token usage for quota enforcement.
-// It also stores AI metadata in gin context so APITelemetryEvents middleware can include it.
-func (h *ConvertHandler) recordTokenUsage(c *gin.Context, meta converter.SpecDocMeta) {
-	// Store AI metadata in context for telemetry middleware
+// setTelemetryContext stores template and AI metadata in gin context so APITelemetryEvents middleware can include it.
+func setTelemetryContext(c *gin.Context, template string, meta converter.SpecDocMeta) {
+	c.Set("template_type", template)
 	c.Set("ai_model", meta.AIModel)
 	c.Set("ai_estimated_cost_usd", meta.AIEstimatedCostUSD)
 	c.Set("ai_input_tokens", int64(meta.AIEstimatedInputTokens))
 	c.Set("ai_output_tokens", int64(meta.AIEstimatedOutputTokens))
+}
+
+// recordTokenUsage tracks conversion count and AI token usage for quota enforcement.
+// It also tags the request for telemetry via setTelemetryContext.
+func (h *ConvertHandler) recordTokenUsage(c *gin.Context, template string, meta converter.SpecDocMeta) {
+	setTelemetryContext(c, template, meta)
 
 	if h.quotaHandler == nil {
 		slog.Warn("recordTokenUsage: quotaHandler is nil, skipping quota recording")
@@ -526,24 +585,3 @@ func (h *ConvertHandler) recordTokenUsage(c *gin.Context, meta converter.SpecDoc
 		slog.Warn("failed to record conversion usage", "session_id", sessionID, "tokens", totalTokens, "error", err)
 	}
 }
-
-func hasValidationRules(rules *converter.ValidationRules) bool {
-	if rules == nil {
-		return false
-	}
-	if len(rules.RequiredFields) > 0 {
-		return true
-	}
-	if rules.FormatRules != nil {
-		if rules.FormatRules.IDPattern != "" {
-			return true
-		}
-		if len(rules.FormatRules.EmailFields) > 0 || len(rules.FormatRules.URLFields) > 0 {
-			return true
-		}
-	}
-	if len(rules.CrossField) > 0 {
-		return true
-	}
-	return false
-}
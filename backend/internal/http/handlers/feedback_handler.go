@@ -6,11 +6,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourorg/md-spec-tool/internal/feedback"
+	"github.com/yourorg/md-spec-tool/internal/webhook"
 )
 
 // FeedbackHandler handles feedback submission and statistics endpoints.
 type FeedbackHandler struct {
-	store feedback.StoreInterface
+	store  feedback.StoreInterface
+	events EventPublisher
 }
 
 // NewFeedbackHandler creates a FeedbackHandler backed by the given store.
@@ -18,6 +20,20 @@ func NewFeedbackHandler(store feedback.StoreInterface) *FeedbackHandler {
 	return &FeedbackHandler{store: store}
 }
 
+// SetEventPublisher sets the publisher for feedback webhooks.
+func (h *FeedbackHandler) SetEventPublisher(p EventPublisher) {
+	h.events = p
+}
+
+// FeedbackSubmittedEvent is the data payload of feedback.submitted.
+type FeedbackSubmittedEvent struct {
+	ID             int64  `json:"id"`
+	RequestHash    string `json:"request_hash"`
+	Rating         int    `json:"rating"`
+	SessionID      string `json:"session_id,omitempty"`
+	HasCorrections bool   `json:"has_corrections"`
+}
+
 // SubmitFeedbackRequest is the request body for POST /api/v1/mdflow/feedback.
 type SubmitFeedbackRequest struct {
 	RequestHash string `json:"request_hash" binding:"required"`
@@ -78,6 +94,16 @@ func (h *FeedbackHandler) SubmitFeedback(c *gin.Context) {
 		return
 	}
 
+	if h.events != nil {
+		h.events.Publish(webhook.EventFeedbackSubmitted, FeedbackSubmittedEvent{
+			ID:             f.ID,
+			RequestHash:    f.RequestHash,
+			Rating:         f.Rating,
+			SessionID:      f.SessionID,
+			HasCorrections: f.Corrections != "" || f.ColumnFixes != "",
+		})
+	}
+
 	c.JSON(http.StatusCreated, SubmitFeedbackResponse{
 		ID:          f.ID,
 		RequestHash: f.RequestHash,
@@ -39,6 +39,7 @@ type GSheetHandler struct {
 	sheetsInitErr    error
 	byokCache        *BYOKServiceCacheInterface    // Placeholder for dependency injection
 	getAIService     func(string) (Service, error) // Injected AI service factory
+	events           EventPublisher
 }
 
 // BYOKServiceCacheInterface defines the contract for BYOK caching
@@ -84,6 +85,11 @@ func NewGSheetHandler(
 	}
 }
 
+// SetEventPublisher sets the publisher for conversion webhooks
+func (h *GSheetHandler) SetEventPublisher(p EventPublisher) {
+	h.events = p
+}
+
 // FetchGoogleSheet handles GET /api/v1/gsheet/fetch
 // Fetches a Google Sheet and returns metadata
 func (h *GSheetHandler) FetchGoogleSheet(c *gin.Context) {
@@ -239,6 +245,7 @@ func (h *GSheetHandler) PreviewGoogleSheet(c *gin.Context) {
 	}
 
 	slog.Info("gsheet.Preview", "sheetID", sheetID, "gid", gid, "template", req.Template, "format", req.Format)
+	c.Set("template_type", req.Template)
 	conv := h.getConverterForRequest(c)
 
 	// If access token provided, use authenticated request
@@ -346,14 +353,17 @@ func (h *GSheetHandler) ConvertGoogleSheet(c *gin.Context) {
 					return
 				}
 				result.Meta.SourceURL = req.URL
-				c.JSON(http.StatusOK, MDFlowConvertResponse{
+				resp := MDFlowConvertResponse{
 					MDFlow:      result.MDFlow,
 					Warnings:    result.Warnings,
 					Meta:        result.Meta,
 					Format:      req.Format,
 					Template:    req.Template,
 					NeedsReview: RequiresReview(result.Meta, result.Warnings),
-				})
+				}
+				setTelemetryContext(c, req.Template, result.Meta)
+				publishConversionEvents(h.events, c, "gsheet", resp)
+				c.JSON(http.StatusOK, resp)
 				return
 			}
 			slog.Warn("gsheet.Convert auth error", "error", err)
@@ -376,14 +386,17 @@ func (h *GSheetHandler) ConvertGoogleSheet(c *gin.Context) {
 	}
 	result.Meta.SourceURL = req.URL
 
-	c.JSON(http.StatusOK, MDFlowConvertResponse{
+	resp := MDFlowConvertResponse{
 		MDFlow:      result.MDFlow,
 		Warnings:    result.Warnings,
 		Meta:        result.Meta,
 		Format:      req.Format,
 		Template:    req.Template,
 		NeedsReview: RequiresReview(result.Meta, result.Warnings),
-	})
+	}
+	setTelemetryContext(c, req.Template, result.Meta)
+	publishConversionEvents(h.events, c, "gsheet", resp)
+	c.JSON(http.StatusOK, resp)
 }
 
 // getSheetsService returns a shared Google Sheets service (lazy initialized)
@@ -429,7 +442,12 @@ func (h *GSheetHandler) getSheetsServiceWithToken(accessToken string) (*sheets.S
 		AccessToken: accessToken,
 	}))
 
-	service, err := sheets.NewService(ctx, option.WithHTTPClient(client))
+	opts := []option.ClientOption{option.WithHTTPClient(client)}
+	if endpoint := strings.TrimSpace(h.cfg.GSheetAPIEndpoint); endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint))
+	}
+
+	service, err := sheets.NewService(ctx, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -520,7 +538,7 @@ func (h *GSheetHandler) buildPreviewResponse(ctx context.Context, conv *converte
 		headerRow, confidence := headerDetector.DetectHeaderRow(block.Matrix)
 		headers := block.Matrix.GetRow(headerRow)
 		dataRows := block.Matrix.SliceRows(headerRow+1, block.Matrix.RowCount())
-		columnMapping, unmapped := conv.GetPreviewColumnMappingWithContext(ctx, headers, dataRows, template, "")
+		columnMapping, unmapped := conv.GetPreviewColumnMappingWithContext(ctx, headers, converter.HeaderBand{Start: headerRow, End: headerRow}, dataRows, template, "")
 		quality := converter.BuildPreviewMappingQuality(confidence, headers, dataRows, columnMapping, unmapped)
 		englishScore := converter.EstimateEnglishScore(headers, dataRows)
 		languageHint := converter.DetectLanguageHint(englishScore, headers, dataRows)
@@ -718,45 +736,7 @@ func (h *GSheetHandler) getConverterForRequest(c *gin.Context) *converter.Conver
 }
 
 func (h *GSheetHandler) buildQualityReport(stats convertValidationStats, result *converter.ConvertResponse) *converter.QualityReport {
-	convertedRows := result.Meta.TotalRows
-	mappedColumns := len(result.Meta.ColumnMap)
-	mappedRatio := 0.0
-	if stats.HeaderCount > 0 {
-		mappedRatio = float64(mappedColumns) / float64(stats.HeaderCount)
-	}
-	rowLossRatio := 0.0
-	if stats.SourceRows > 0 {
-		rowLossRatio = 1 - (float64(convertedRows) / float64(stats.SourceRows))
-		if rowLossRatio < 0 {
-			rowLossRatio = 0
-		}
-	}
-
-	validationPassed := true
-	validationReason := ""
-	if stats.HeaderConfidence < h.cfg.SpecMinHeaderConfidence {
-		validationPassed = false
-		validationReason = "low_header_confidence"
-	} else if stats.SourceRows >= 2 && rowLossRatio > h.cfg.SpecMaxRowLossRatio {
-		validationPassed = false
-		validationReason = "row_loss"
-	}
-
-	return &converter.QualityReport{
-		StrictMode:          h.cfg.SpecStrictMode,
-		ValidationPassed:    validationPassed,
-		ValidationReason:    validationReason,
-		HeaderConfidence:    stats.HeaderConfidence,
-		MinHeaderConfidence: h.cfg.SpecMinHeaderConfidence,
-		SourceRows:          stats.SourceRows,
-		ConvertedRows:       convertedRows,
-		RowLossRatio:        rowLossRatio,
-		MaxRowLossRatio:     h.cfg.SpecMaxRowLossRatio,
-		HeaderCount:         stats.HeaderCount,
-		MappedColumns:       mappedColumns,
-		MappedRatio:         mappedRatio,
-		CoreFieldCoverage:   buildCoreFieldCoverage(result.Meta.ColumnMap),
-	}
+	return converter.BuildQualityReport(stats, result.Meta, qualityThresholds(h.cfg))
 }
 
 func (h *GSheetHandler) buildConvertValidationError(format string, stats convertValidationStats, result *converter.ConvertResponse) *ErrorResponse {
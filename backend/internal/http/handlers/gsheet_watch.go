@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	"github.com/yourorg/md-spec-tool/internal/sheetwatch"
+)
+
+// Ensure GSheetHandler can feed the watched-sheet scheduler
+var _ sheetwatch.Fetcher = (*GSheetHandler)(nil)
+
+// FetchWatchedSheet loads a watched sheet without a user token: through the service account
+// when GOOGLE_APPLICATION_CREDENTIALS is configured, otherwise via the public CSV export.
+// The block selection matches /gsheet/convert so hashes and specs line up with manual conversions.
+func (h *GSheetHandler) FetchWatchedSheet(ctx context.Context, watch sheetwatch.Watch) (converter.CellMatrix, string, error) {
+	var matrix converter.CellMatrix
+	sheetName := watch.GID
+
+	service, err := h.getSheetsService()
+	switch {
+	case err == nil:
+		valuesResult, fetchErr := h.fetchGoogleSheetValuesWithService(ctx, service, watch.SheetID, watch.GID, watch.Range)
+		if fetchErr != nil {
+			return nil, "", fetchErr
+		}
+		matrix = converter.NewCellMatrix(valuesResult.Rows).Normalize()
+		sheetName = valuesResult.SheetName
+	case errors.Is(err, errSheetsNotConfigured):
+		body, statusCode, fetchErr := h.fetchGoogleSheetCSV(watch.SheetID, watch.GID, watch.Range)
+		if fetchErr != nil {
+			return nil, "", fmt.Errorf("public export failed (status %d): %w", statusCode, fetchErr)
+		}
+		matrix, err = converter.NewPasteParser().Parse(string(body))
+		if err != nil {
+			return nil, "", err
+		}
+	default:
+		return nil, "", err
+	}
+
+	selected := selectMatrixForConvert(ctx, h.converter, matrix, watch.Template, "", watch.Range)
+	return selected, sheetName, nil
+}
@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	"google.golang.org/api/sheets/v4"
+)
+
+// GoogleSheetWriteBackRequest represents the request for writing normalization results back to a sheet
+type GoogleSheetWriteBackRequest struct {
+	URL             string                     `json:"url" binding:"required"`
+	Template        string                     `json:"template"`
+	GID             string                     `json:"gid,omitempty"`
+	Range           string                     `json:"range,omitempty"`
+	SelectedBlockID string                     `json:"selected_block_id,omitempty"`
+	ColumnOverrides map[string]string          `json:"column_overrides,omitempty"`
+	ValidationRules *converter.ValidationRules `json:"validation_rules,omitempty"`
+	TabTitle        string                     `json:"tab_title,omitempty"` // default: "<source> (mdflow <timestamp>)"
+}
+
+// GoogleSheetWriteBackResponse describes the tab created by a write-back
+type GoogleSheetWriteBackResponse struct {
+	SheetID      string                `json:"sheet_id"`
+	SourceGID    string                `json:"source_gid"`
+	TabTitle     string                `json:"tab_title"`
+	TabGID       string                `json:"tab_gid"`
+	Rows         int                   `json:"rows"`
+	Columns      []string              `json:"columns"`
+	WarningCount int                   `json:"warning_count"` // conversion and validation warnings, as weighed by NeedsReview
+	Meta         converter.SpecDocMeta `json:"meta"`
+	NeedsReview  bool                  `json:"needs_review"`
+}
+
+// writeBackRowColumn is the leading column of a write-back tab holding the
+// 1-based data row number: 1 is the first row below the header, not sheet row 1.
+const writeBackRowColumn = "row"
+
+// WriteBackGoogleSheet handles POST /api/mdflow/gsheet/writeback
+// Converts a sheet and writes normalized canonical columns into a new tab of the same spreadsheet.
+// Mapping decisions are attached as notes on the header cells and validation warnings as notes on row cells.
+// Requires the user's OAuth bearer token with spreadsheet write scope.
+func (h *GSheetHandler) WriteBackGoogleSheet(c *gin.Context) {
+	var req GoogleSheetWriteBackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "url is required"})
+		return
+	}
+
+	normalizedTemplate, _, err := normalizeTemplateAndFormat(req.Template, "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	req.Template = normalizedTemplate
+
+	sheetID, gid, ok := parseGoogleSheetURL(req.URL)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid Google Sheets URL"})
+		return
+	}
+	gid = selectGID(req.GID, gid)
+	if err := validateGID(gid); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	accessToken := getBearerToken(c)
+	if accessToken == "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Google access token is required to write to a sheet"})
+		return
+	}
+	service, err := h.getSheetsServiceWithToken(accessToken)
+	if err != nil {
+		slog.Error("gsheet.WriteBack service error", "error", err)
+		c.JSON(http.StatusBadGateway, ErrorResponse{Error: "failed to connect to Google Sheets"})
+		return
+	}
+
+	slog.Info("gsheet.WriteBack", "sheetID", sheetID, "gid", gid, "template", req.Template)
+	ctx := c.Request.Context()
+	conv := h.getConverterForRequest(c)
+
+	valuesResult, err := h.fetchGoogleSheetValuesWithService(ctx, service, sheetID, gid, req.Range)
+	if err != nil {
+		h.writeBackError(c, "fetch", err)
+		return
+	}
+
+	matrix := converter.NewCellMatrix(valuesResult.Rows).Normalize()
+	selected := selectMatrixForConvert(ctx, conv, matrix, req.Template, req.SelectedBlockID, req.Range)
+	stats := analyzeSelectedMatrix(selected)
+	result, err := conv.ConvertMatrixWithOverridesAndOptions(ctx, selected, valuesResult.SheetName, req.Template, string(converter.OutputFormatSpec), req.ColumnOverrides, converter.DefaultConvertOptions())
+	if err != nil {
+		slog.Error("gsheet.WriteBack conversion error", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to convert data"})
+		return
+	}
+	result.Meta.QualityReport = h.buildQualityReport(stats, result)
+	result.Meta.SourceURL = req.URL
+
+	headers := result.Meta.Headers(selected)
+	dataRows := selected.SliceRows(result.Meta.HeaderRow+1, selected.RowCount())
+	specRows := converter.BuildSpecRows(headers, dataRows, result.Meta.ColumnMap)
+
+	var validation converter.ValidationResult
+	if req.ValidationRules != nil {
+		validation = converter.ValidateWithContext(ctx, &converter.SpecDoc{Rows: specRows}, req.ValidationRules)
+	}
+
+	existing, err := getGoogleSheetTabsWithService(service, sheetID)
+	if err != nil {
+		h.writeBackError(c, "list tabs", err)
+		return
+	}
+	title := uniqueTabTitle(existing, req.TabTitle, valuesResult.SheetName, time.Now())
+	tabID := newWriteBackSheetID(existing, sheetID, title)
+
+	notes := append(rulePackWriteBackWarnings(result), validation.Warnings...)
+	grid := buildWriteBackGrid(headers, dataRows, result.Meta, notes)
+	batch := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				AddSheet: &sheets.AddSheetRequest{
+					Properties: &sheets.SheetProperties{
+						SheetId: tabID,
+						Title:   title,
+						GridProperties: &sheets.GridProperties{
+							RowCount:       int64(len(grid.rows)),
+							ColumnCount:    int64(len(grid.columns)),
+							FrozenRowCount: 1,
+						},
+					},
+				},
+			},
+			{
+				UpdateCells: &sheets.UpdateCellsRequest{
+					Start:  &sheets.GridCoordinate{SheetId: tabID},
+					Rows:   grid.rows,
+					Fields: "userEnteredValue,note",
+				},
+			},
+		},
+	}
+
+	// AddSheet fails once the tab exists, so a retry first checks whether the
+	// previous attempt landed (a batchUpdate applies all or nothing) and its
+	// response was lost.
+	attempts := 0
+	err = retryGSheetAPI(func() error {
+		attempts++
+		if attempts > 1 {
+			if tabs, e := getGoogleSheetTabsWithService(service, sheetID); e == nil && hasTabGID(tabs, tabID) {
+				return nil
+			}
+		}
+		_, e := service.Spreadsheets.BatchUpdate(sheetID, batch).Context(ctx).Do()
+		return e
+	}, h.writeBackRetries())
+	if err != nil {
+		h.writeBackError(c, "batchUpdate", err)
+		return
+	}
+
+	warnings := make([]converter.Warning, 0, len(result.Warnings)+len(validation.Warnings))
+	warnings = append(warnings, result.Warnings...)
+	warnings = append(warnings, validation.Warnings...)
+	c.JSON(http.StatusOK, GoogleSheetWriteBackResponse{
+		SheetID:      sheetID,
+		SourceGID:    findActiveGID(existing, gid),
+		TabTitle:     title,
+		TabGID:       strconv.FormatInt(tabID, 10),
+		Rows:         len(dataRows),
+		Columns:      grid.columns,
+		WarningCount: len(warnings),
+		Meta:         result.Meta,
+		NeedsReview:  RequiresReview(result.Meta, warnings),
+	})
+}
+
+// rulePackWriteBackWarnings returns the template rule-pack findings of result
+// with their row renumbered from the spec document, which skips empty and
+// continuation rows, to the data row the write-back tab shows.
+func rulePackWriteBackWarnings(result *converter.ConvertResponse) []converter.Warning {
+	if result.Doc == nil {
+		return nil
+	}
+	var out []converter.Warning
+	for _, w := range result.Warnings {
+		if _, ok := w.Details["rule_pack"]; !ok {
+			continue
+		}
+		details := make(map[string]any, len(w.Details))
+		for k, v := range w.Details {
+			details[k] = v
+		}
+		if rowNum, ok := details["row"].(int); ok {
+			if rowNum < 1 || rowNum > len(result.Doc.Rows) {
+				continue
+			}
+			details["row"] = result.Doc.Rows[rowNum-1].SourceRow - result.Meta.HeaderRow - 1
+		}
+		w.Details = details
+		out = append(out, w)
+	}
+	return out
+}
+
+func (h *GSheetHandler) writeBackRetries() int {
+	if h.cfg.GSheetMaxRetries > 0 {
+		return h.cfg.GSheetMaxRetries
+	}
+	return 2
+}
+
+func (h *GSheetHandler) writeBackError(c *gin.Context, stage string, err error) {
+	slog.Warn("gsheet.WriteBack error", "stage", stage, "error", err)
+	if isAuthError(err) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Google account does not have edit access to this sheet"})
+		return
+	}
+	if c.Request.Context().Err() != nil {
+		c.JSON(http.StatusGatewayTimeout, ErrorResponse{Error: "Google Sheets request was cancelled"})
+		return
+	}
+	c.JSON(http.StatusBadGateway, ErrorResponse{Error: "failed to write to Google Sheet"})
+}
+
+// writeBackGrid holds the cell payload for a write-back tab.
+type writeBackGrid struct {
+	columns []string
+	rows    []*sheets.RowData
+}
+
+// buildWriteBackGrid lays out the tab: a "row" column followed by mapped canonical fields
+// in source column order. Header notes describe each mapping decision; validation warnings
+// are attached to the offending field cell, or the row cell when no field applies.
+func buildWriteBackGrid(headers []string, dataRows converter.CellMatrix, meta converter.SpecDocMeta, warnings []converter.Warning) writeBackGrid {
+	type mappedColumn struct {
+		field converter.CanonicalField
+		index int
+	}
+	mapped := make([]mappedColumn, 0, len(meta.ColumnMap))
+	for field, idx := range meta.ColumnMap {
+		if idx < 0 || idx >= len(headers) {
+			continue
+		}
+		mapped = append(mapped, mappedColumn{field: field, index: idx})
+	}
+	sort.Slice(mapped, func(i, j int) bool {
+		if mapped[i].index != mapped[j].index {
+			return mapped[i].index < mapped[j].index
+		}
+		return mapped[i].field < mapped[j].field
+	})
+
+	columns := make([]string, 0, len(mapped)+1)
+	columns = append(columns, writeBackRowColumn)
+	headerCells := make([]*sheets.CellData, 0, len(mapped)+1)
+	headerCells = append(headerCells, writeBackCell(writeBackRowColumn, writeBackSummaryNote(meta)))
+	fieldColumn := make(map[string]int, len(mapped))
+	for i, m := range mapped {
+		columns = append(columns, string(m.field))
+		fieldColumn[string(m.field)] = i + 1
+		note := fmt.Sprintf("Mapped from column %s %q", columnToLetters(m.index), headers[m.index])
+		if meta.AIUsed {
+			note += fmt.Sprintf("\nAI mapping (avg confidence %.0f%%)", meta.AIAvgConfidence*100)
+		}
+		headerCells = append(headerCells, writeBackCell(string(m.field), note))
+	}
+
+	// Group warning messages by (row, column)
+	notes := make(map[int]map[int][]string)
+	for _, w := range warnings {
+		rowNum, ok := w.Details["row"].(int)
+		if !ok || rowNum < 1 || rowNum > len(dataRows) {
+			continue
+		}
+		col := 0
+		if field, ok := w.Details["field"].(string); ok {
+			if idx, found := fieldColumn[strings.ToLower(strings.TrimSpace(field))]; found {
+				col = idx
+			}
+		} else if field, ok := w.Details["then_field"].(string); ok {
+			if idx, found := fieldColumn[strings.ToLower(strings.TrimSpace(field))]; found {
+				col = idx
+			}
+		}
+		if notes[rowNum] == nil {
+			notes[rowNum] = make(map[int][]string)
+		}
+		notes[rowNum][col] = append(notes[rowNum][col], fmt.Sprintf("[%s] %s", w.Severity, w.Message))
+	}
+
+	rows := make([]*sheets.RowData, 0, len(dataRows)+1)
+	rows = append(rows, &sheets.RowData{Values: headerCells})
+	for i, row := range dataRows {
+		rowNum := i + 1
+		cells := make([]*sheets.CellData, 0, len(columns))
+		cells = append(cells, writeBackCell(strconv.Itoa(rowNum), strings.Join(notes[rowNum][0], "\n")))
+		for j, m := range mapped {
+			value := ""
+			if m.index < len(row) {
+				value = strings.TrimSpace(row[m.index])
+			}
+			cells = append(cells, writeBackCell(value, strings.Join(notes[rowNum][j+1], "\n")))
+		}
+		rows = append(rows, &sheets.RowData{Values: cells})
+	}
+
+	return writeBackGrid{columns: columns, rows: rows}
+}
+
+func writeBackSummaryNote(meta converter.SpecDocMeta) string {
+	lines := []string{
+		fmt.Sprintf("Generated by MDFlow from %q (header row %d)", meta.SheetName, meta.HeaderRow+1),
+		"Row numbers count data rows from 1 below the header; they are not sheet row numbers.",
+	}
+	if len(meta.UnmappedColumns) > 0 {
+		lines = append(lines, "Unmapped columns: "+strings.Join(meta.UnmappedColumns, ", "))
+	}
+	if meta.AIUsed {
+		lines = append(lines, fmt.Sprintf("AI model: %s", meta.AIModel))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func writeBackCell(value string, note string) *sheets.CellData {
+	v := value
+	return &sheets.CellData{
+		UserEnteredValue: &sheets.ExtendedValue{StringValue: &v},
+		Note:             note,
+	}
+}
+
+// uniqueTabTitle returns the requested title (or a timestamped default) that does not clash with existing tabs.
+func uniqueTabTitle(existing []GoogleSheetTab, requested, sourceTitle string, now time.Time) string {
+	base := strings.TrimSpace(requested)
+	if base == "" {
+		if sourceTitle == "" {
+			sourceTitle = "Sheet"
+		}
+		base = fmt.Sprintf("%s (mdflow %s)", sourceTitle, now.UTC().Format("2006-01-02 15:04"))
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, tab := range existing {
+		taken[strings.ToLower(tab.Title)] = true
+	}
+	title := base
+	for n := 2; taken[strings.ToLower(title)]; n++ {
+		title = fmt.Sprintf("%s (%d)", base, n)
+	}
+	return title
+}
+
+func hasTabGID(tabs []GoogleSheetTab, gid int64) bool {
+	want := strconv.FormatInt(gid, 10)
+	for _, tab := range tabs {
+		if tab.GID == want {
+			return true
+		}
+	}
+	return false
+}
+
+// newWriteBackSheetID picks a positive sheet ID not used by existing tabs, so AddSheet and
+// UpdateCells can be sent in one batchUpdate. Zero is avoided because the API omits it.
+func newWriteBackSheetID(existing []GoogleSheetTab, spreadsheetID, title string) int64 {
+	taken := make(map[string]bool, len(existing))
+	for _, tab := range existing {
+		taken[tab.GID] = true
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(spreadsheetID + "\x00" + title))
+	id := int64(hasher.Sum32() & 0x7fffffff)
+	for id == 0 || taken[strconv.FormatInt(id, 10)] {
+		id = (id + 1) & 0x7fffffff
+	}
+	return id
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourorg/md-spec-tool/internal/http/middleware"
+	"github.com/yourorg/md-spec-tool/internal/metrics"
 )
 
 func HealthHandler(c *gin.Context) {
@@ -18,3 +19,9 @@ func HealthHandler(c *gin.Context) {
 func MetricsHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, middleware.GetMetrics())
 }
+
+// PrometheusHandler serves the Prometheus exposition of the route, AI, cache,
+// budget and rejection collectors.
+func PrometheusHandler(c *gin.Context) {
+	metrics.Handler().ServeHTTP(c.Writer, c.Request)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -138,7 +139,14 @@ func humanSize(b int64) string {
 
 var errSheetsNotConfigured = errors.New("google sheets credentials not configured")
 
-var xlsxMagic = []byte{0x50, 0x4B, 0x03, 0x04}
+// uploadSpreadsheetExt is the workbook extension of an uploaded file name;
+// names without one are read as .xlsx.
+func uploadSpreadsheetExt(filename string) string {
+	if converter.IsSpreadsheetFile(filename) {
+		return strings.ToLower(filepath.Ext(filename))
+	}
+	return ".xlsx"
+}
 
 func (h *MDFlowHandler) validateTemplate(template string) error {
 	_, err := normalizeTemplate(template)
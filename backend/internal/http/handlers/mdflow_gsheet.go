@@ -18,6 +18,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/yourorg/md-spec-tool/internal/converter"
 	"github.com/yourorg/md-spec-tool/internal/gsheetutils"
+	"github.com/yourorg/md-spec-tool/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/oauth2"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
@@ -69,12 +71,7 @@ type gsheetValuesResult struct {
 	StartRow  int
 }
 
-type convertValidationStats struct {
-	SourceRows       int
-	HeaderRow        int
-	HeaderConfidence int
-	HeaderCount      int
-}
+type convertValidationStats = converter.QualityStats
 
 // parseGoogleSheetURL is deprecated. Use gsheetutils.ParseGoogleSheetURL instead.
 // This is kept for backward compatibility during migration.
@@ -607,7 +604,7 @@ func selectPreferredBlockMatrix(ctx context.Context, conv *converter.Converter,
 		headerRow, confidence := headerDetector.DetectHeaderRow(block.Matrix)
 		headers := block.Matrix.GetRow(headerRow)
 		dataRows := block.Matrix.SliceRows(headerRow+1, block.Matrix.RowCount())
-		columnMapping, unmapped := conv.GetPreviewColumnMappingWithContext(ctx, headers, dataRows, templateName, "")
+		columnMapping, unmapped := conv.GetPreviewColumnMappingWithContext(ctx, headers, converter.HeaderBand{Start: headerRow, End: headerRow}, dataRows, templateName, "")
 		quality := converter.BuildPreviewMappingQuality(confidence, headers, dataRows, columnMapping, unmapped)
 		englishScore := converter.EstimateEnglishScore(headers, dataRows)
 
@@ -641,94 +638,38 @@ func selectBlockMatrixByID(matrix converter.CellMatrix, blockID string) (convert
 }
 
 func selectMatrixForConvert(ctx context.Context, conv *converter.Converter, matrix converter.CellMatrix, templateName string, selectedBlockID string, rangeOverride string) converter.CellMatrix {
-	if selected, ok := selectBlockMatrixByID(matrix, selectedBlockID); ok {
-		return selected
-	}
-	if strings.TrimSpace(selectedBlockID) != "" {
-		return matrix
-	}
-	if strings.TrimSpace(rangeOverride) != "" {
-		return matrix
-	}
-	return selectPreferredBlockMatrix(ctx, conv, matrix, templateName)
+	ctx, span := tracing.Start(ctx, "converter.select_block", attribute.Int("input.rows", matrix.RowCount()))
+	defer span.End()
+
+	strategy := "auto"
+	selected := matrix
+	if byID, ok := selectBlockMatrixByID(matrix, selectedBlockID); ok {
+		strategy = "block_id"
+		selected = byID
+	} else if strings.TrimSpace(selectedBlockID) != "" {
+		strategy = "block_id_not_found"
+	} else if strings.TrimSpace(rangeOverride) != "" {
+		strategy = "range_override"
+	} else {
+		selected = selectPreferredBlockMatrix(ctx, conv, matrix, templateName)
+	}
+	span.SetAttributes(
+		attribute.String("block.strategy", strategy),
+		attribute.Int("block.rows", selected.RowCount()),
+	)
+	return selected
 }
 
 func analyzeSelectedMatrix(matrix converter.CellMatrix) convertValidationStats {
-	headerDetector := converter.NewHeaderDetector()
-	headerRow, confidence := headerDetector.DetectHeaderRow(matrix)
-	sourceRows := matrix.RowCount() - headerRow - 1
-	if sourceRows < 0 {
-		sourceRows = 0
-	}
-	return convertValidationStats{
-		SourceRows:       sourceRows,
-		HeaderRow:        headerRow,
-		HeaderConfidence: confidence,
-		HeaderCount:      len(matrix.GetRow(headerRow)),
-	}
+	return converter.AnalyzeQuality(matrix)
 }
 
 func buildCoreFieldCoverage(colMap converter.ColumnMap) map[string]bool {
-	coverage := map[string]bool{
-		string(converter.FieldFeature):           false,
-		string(converter.FieldScenario):          false,
-		string(converter.FieldDescription):       false,
-		string(converter.FieldInstructions):      false,
-		string(converter.FieldExpected):          false,
-		string(converter.FieldItemName):          false,
-		string(converter.FieldDisplayConditions): false,
-		string(converter.FieldAction):            false,
-		string(converter.FieldNavigationDest):    false,
-	}
-	for field := range colMap {
-		key := string(field)
-		if _, ok := coverage[key]; ok {
-			coverage[key] = true
-		}
-	}
-	return coverage
+	return converter.CoreFieldCoverage(colMap)
 }
 
 func (h *MDFlowHandler) buildQualityReport(stats convertValidationStats, result *converter.ConvertResponse) *converter.QualityReport {
-	convertedRows := result.Meta.TotalRows
-	mappedColumns := len(result.Meta.ColumnMap)
-	mappedRatio := 0.0
-	if stats.HeaderCount > 0 {
-		mappedRatio = float64(mappedColumns) / float64(stats.HeaderCount)
-	}
-	rowLossRatio := 0.0
-	if stats.SourceRows > 0 {
-		rowLossRatio = 1 - (float64(convertedRows) / float64(stats.SourceRows))
-		if rowLossRatio < 0 {
-			rowLossRatio = 0
-		}
-	}
-
-	validationPassed := true
-	validationReason := ""
-	if stats.HeaderConfidence < h.cfg.SpecMinHeaderConfidence {
-		validationPassed = false
-		validationReason = "low_header_confidence"
-	} else if stats.SourceRows >= 2 && rowLossRatio > h.cfg.SpecMaxRowLossRatio {
-		validationPassed = false
-		validationReason = "row_loss"
-	}
-
-	return &converter.QualityReport{
-		StrictMode:          h.cfg.SpecStrictMode,
-		ValidationPassed:    validationPassed,
-		ValidationReason:    validationReason,
-		HeaderConfidence:    stats.HeaderConfidence,
-		MinHeaderConfidence: h.cfg.SpecMinHeaderConfidence,
-		SourceRows:          stats.SourceRows,
-		ConvertedRows:       convertedRows,
-		RowLossRatio:        rowLossRatio,
-		MaxRowLossRatio:     h.cfg.SpecMaxRowLossRatio,
-		HeaderCount:         stats.HeaderCount,
-		MappedColumns:       mappedColumns,
-		MappedRatio:         mappedRatio,
-		CoreFieldCoverage:   buildCoreFieldCoverage(result.Meta.ColumnMap),
-	}
+	return converter.BuildQualityReport(stats, result.Meta, qualityThresholds(h.cfg))
 }
 
 func qualityReportLogArgs(report *converter.QualityReport) []any {
@@ -1048,7 +989,7 @@ func (h *MDFlowHandler) PreviewGoogleSheet(c *gin.Context) {
 		headerRow, confidence := headerDetector.DetectHeaderRow(block.Matrix)
 		headers := block.Matrix.GetRow(headerRow)
 		dataRows := block.Matrix.SliceRows(headerRow+1, block.Matrix.RowCount())
-		columnMapping, unmapped := conv.GetPreviewColumnMappingWithContext(ctx, headers, dataRows, templateName, "")
+		columnMapping, unmapped := conv.GetPreviewColumnMappingWithContext(ctx, headers, converter.HeaderBand{Start: headerRow, End: headerRow}, dataRows, templateName, "")
 		quality := converter.BuildPreviewMappingQuality(confidence, headers, dataRows, columnMapping, unmapped)
 		englishScore := converter.EstimateEnglishScore(headers, dataRows)
 		languageHint := converter.DetectLanguageHint(englishScore, headers, dataRows)
@@ -47,23 +47,25 @@ func NewPreviewHandler(conv *converter.Converter, cfg *config.Config, byokCache
 
 // buildPreviewFromMatrix builds a PreviewResponse from a parsed CellMatrix.
 // Shared logic for PreviewPaste, PreviewTSV, PreviewXLSX to avoid duplication.
-func (h *PreviewHandler) buildPreviewFromMatrix(c *gin.Context, matrix converter.CellMatrix, templateName string) PreviewResponse {
+func (h *PreviewHandler) buildPreviewFromMatrix(c *gin.Context, matrix converter.CellMatrix, templateName string, merges []converter.MergedRegion) PreviewResponse {
+	c.Set("template_type", templateName)
 	headerDetector := converter.NewHeaderDetector()
-	headerRow, confidence := headerDetector.DetectHeaderRow(matrix)
-	headers := matrix.GetRow(headerRow)
+	band, confidence := headerDetector.DetectHeaderBand(matrix, merges)
+	headerRow := band.End
+	headers := band.Headers(matrix)
 
 	skipAI := c.Query("skip_ai") != "false"
 
 	var columnMapping map[string]string
 	var unmapped []string
 	if skipAI {
-		columnMapping, unmapped = h.converter.GetPreviewColumnMappingRuleBased(headers, templateName)
+		columnMapping, unmapped = h.converter.GetPreviewColumnMappingRuleBased(headers, band, templateName)
 	} else {
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
 		defer cancel()
 		conv := h.byokCache.GetConverterForRequest(c, h.converter)
 		dataRows := matrix.SliceRows(headerRow+1, matrix.RowCount())
-		columnMapping, unmapped = conv.GetPreviewColumnMappingWithContext(ctx, headers, dataRows, templateName, "")
+		columnMapping, unmapped = conv.GetPreviewColumnMappingWithContext(ctx, headers, band, dataRows, templateName, "")
 	}
 
 	dataRows := matrix.SliceRows(headerRow+1, matrix.RowCount())
@@ -86,10 +88,12 @@ func (h *PreviewHandler) buildPreviewFromMatrix(c *gin.Context, matrix converter
 		TotalRows:      totalDataRows,
 		PreviewRows:    previewCount,
 		HeaderRow:      headerRow,
+		HeaderBand:     band,
 		Confidence:     confidence,
 		ColumnMapping:  columnMapping,
 		UnmappedCols:   unmapped,
 		MappingQuality: &quality,
+		MergedCells:    merges,
 		InputType:      "table",
 		AIAvailable:    h.byokCache.HasAIForRequest(c),
 	}
@@ -103,6 +107,7 @@ func (h *PreviewHandler) emptyTablePreview(c *gin.Context, confidence int, input
 		TotalRows:     0,
 		PreviewRows:   0,
 		HeaderRow:     -1,
+		HeaderBand:    converter.HeaderBand{Start: -1, End: -1},
 		Confidence:    confidence,
 		ColumnMapping: map[string]string{},
 		UnmappedCols:  []string{},
@@ -157,7 +162,7 @@ func (h *PreviewHandler) PreviewPaste(c *gin.Context) {
 	}
 
 	templateName := strings.TrimSpace(req.Template)
-	c.JSON(http.StatusOK, h.buildPreviewFromMatrix(c, matrix, templateName))
+	c.JSON(http.StatusOK, h.buildPreviewFromMatrix(c, matrix, templateName, nil))
 }
 
 // PreviewTSV handles POST /api/mdflow/tsv/preview
@@ -221,7 +226,7 @@ func (h *PreviewHandler) PreviewTSV(c *gin.Context) {
 	}
 
 	templateName := strings.TrimSpace(c.PostForm("template"))
-	c.JSON(http.StatusOK, h.buildPreviewFromMatrix(c, matrix, templateName))
+	c.JSON(http.StatusOK, h.buildPreviewFromMatrix(c, matrix, templateName, nil))
 }
 
 // PreviewXLSX handles POST /api/mdflow/xlsx/preview
@@ -247,8 +252,8 @@ func (h *PreviewHandler) PreviewXLSX(c *gin.Context) {
 	}
 
 	ext := strings.ToLower(filepath.Ext(header.Filename))
-	if ext != ".xlsx" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "only .xlsx files are supported"})
+	if !converter.IsSpreadsheetFile(header.Filename) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "only .xlsx, .xls and .ods files are supported"})
 		return
 	}
 
@@ -264,15 +269,15 @@ func (h *PreviewHandler) PreviewXLSX(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "failed to read file"})
 		return
 	}
-	if !bytes.HasPrefix(buf[:n], xlsxMagic) {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid xlsx file"})
+	if !bytes.HasPrefix(buf[:n], converter.SpreadsheetSignature(ext)) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid %s file", ext[1:])})
 		return
 	}
 
 	reader := io.MultiReader(bytes.NewReader(buf[:n]), file)
 
 	// Create temp file
-	tempFile, err := os.CreateTemp("", "preview-*.xlsx")
+	tempFile, err := os.CreateTemp("", "preview-*"+ext)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to process file"})
 		return
@@ -297,7 +302,7 @@ func (h *PreviewHandler) PreviewXLSX(c *gin.Context) {
 	}
 
 	// Parse XLSX
-	matrix, err := h.converter.ParseXLSX(tempName, sheetName)
+	matrix, merges, err := h.converter.ParseXLSXWithMerges(c.Request.Context(), tempName, sheetName)
 	if err != nil {
 		slog.Error("mdflow.PreviewXLSX parse error", "error", err)
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to parse file"})
@@ -310,5 +315,5 @@ func (h *PreviewHandler) PreviewXLSX(c *gin.Context) {
 	}
 
 	templateName := strings.TrimSpace(c.PostForm("template"))
-	c.JSON(http.StatusOK, h.buildPreviewFromMatrix(c, matrix, templateName))
+	c.JSON(http.StatusOK, h.buildPreviewFromMatrix(c, matrix, templateName, merges))
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/yourorg/md-spec-tool/internal/ai"
+	"github.com/yourorg/md-spec-tool/internal/config"
 	"github.com/yourorg/md-spec-tool/internal/converter"
 )
 
@@ -16,6 +17,11 @@ func RequiresReview(meta converter.SpecDocMeta, warnings []converter.Warning) bo
 		return true
 	}
 
+	// Check template rule pack: error-severity rules always need review
+	if !meta.RulePack.Passed() {
+		return true
+	}
+
 	// Check AI confidence if available
 	if meta.AIUsed && meta.AIAvgConfidence > 0 {
 		if thresholds.IsLowConfidence(meta.AIAvgConfidence) {
@@ -51,3 +57,12 @@ func RequiresReview(meta converter.SpecDocMeta, warnings []converter.Warning) bo
 
 	return false
 }
+
+// qualityThresholds returns the spec quality gates configured for the server.
+func qualityThresholds(cfg *config.Config) converter.QualityThresholds {
+	return converter.QualityThresholds{
+		StrictMode:          cfg.SpecStrictMode,
+		MinHeaderConfidence: cfg.SpecMinHeaderConfidence,
+		MaxRowLossRatio:     cfg.SpecMaxRowLossRatio,
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/yourorg/md-spec-tool/internal/http/middleware"
 	"github.com/yourorg/md-spec-tool/internal/quota"
+	"github.com/yourorg/md-spec-tool/internal/telemetry"
 )
 
 type QuotaHandler struct {
@@ -106,7 +107,7 @@ func (h *QuotaHandler) AddQuotaUsage(ctx context.Context, sessionID string, toke
 	}
 
 	// Emit telemetry event for quota tracking
-	middleware.RecordTelemetryEvent(middleware.TelemetryEvent{
+	middleware.RecordTelemetryEvent(telemetry.Event{
 		EventName:   "quota_used",
 		EventTime:   time.Now().UTC(),
 		SessionID:   sessionID,
@@ -139,7 +140,7 @@ func (h *QuotaHandler) RecordConversion(ctx context.Context, sessionID string, t
 	}
 
 	if tokens > 0 {
-		middleware.RecordTelemetryEvent(middleware.TelemetryEvent{
+		middleware.RecordTelemetryEvent(telemetry.Event{
 			EventName:       "quota_used",
 			EventTime:       time.Now().UTC(),
 			SessionID:       sessionID,
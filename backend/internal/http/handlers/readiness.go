@@ -0,0 +1,336 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/ai"
+	"github.com/yourorg/md-spec-tool/internal/feedback"
+	"github.com/yourorg/md-spec-tool/internal/share"
+)
+
+// Component status values reported by readiness probes.
+const (
+	ComponentOK       = "ok"
+	ComponentDegraded = "degraded" // usable with reduced functionality
+	ComponentDown     = "down"
+	ComponentDisabled = "disabled" // not configured; not an error
+)
+
+const readinessProbeTimeout = 2 * time.Second
+
+// ComponentStatus is the result of one readiness probe. /health/ready is
+// unauthenticated, so Message is a short reason and Details never carries
+// paths, error text or spend; probes log those server-side.
+type ComponentStatus struct {
+	Status   string         `json:"status"`
+	Required bool           `json:"required"`
+	Message  string         `json:"message,omitempty"`
+	Details  map[string]any `json:"details,omitempty"`
+}
+
+// ReadinessResponse is returned by /health/ready.
+type ReadinessResponse struct {
+	Status     string                     `json:"status"` // ok | degraded | unavailable
+	Service    string                     `json:"service"`
+	Components map[string]ComponentStatus `json:"components"`
+	Degraded   []string                   `json:"degraded,omitempty"` // components not fully ok, sorted
+}
+
+// ReadinessProbe checks one dependency. Required probes that report down make
+// the instance unready (503); optional ones only mark it degraded.
+type ReadinessProbe struct {
+	Name     string
+	Required bool
+	Check    func(ctx context.Context) ComponentStatus
+}
+
+// ReadinessHandler serves liveness and readiness probes.
+type ReadinessHandler struct {
+	startedAt time.Time
+	mu        sync.RWMutex
+	probes    []ReadinessProbe
+}
+
+// NewReadinessHandler creates a handler with no probes.
+func NewReadinessHandler() *ReadinessHandler {
+	return &ReadinessHandler{startedAt: time.Now()}
+}
+
+// AddProbe registers a dependency check for /health/ready.
+func (h *ReadinessHandler) AddProbe(probe ReadinessProbe) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.probes = append(h.probes, probe)
+}
+
+// Live reports that the process is running. It never checks dependencies so a
+// failing database cannot cause restart loops.
+func (h *ReadinessHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":         "alive",
+		"service":        "md-spec-tool",
+		"uptime_seconds": int64(time.Since(h.startedAt).Seconds()),
+	})
+}
+
+// Ready runs every probe concurrently and reports per-component status.
+func (h *ReadinessHandler) Ready(c *gin.Context) {
+	h.mu.RLock()
+	probes := append([]ReadinessProbe(nil), h.probes...)
+	h.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessProbeTimeout)
+	defer cancel()
+
+	results := make([]ComponentStatus, len(probes))
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func(i int, probe ReadinessProbe) {
+			defer wg.Done()
+			status := probe.Check(ctx)
+			status.Required = probe.Required
+			results[i] = status
+		}(i, probe)
+	}
+	wg.Wait()
+
+	resp := ReadinessResponse{
+		Status:     "ok",
+		Service:    "md-spec-tool",
+		Components: make(map[string]ComponentStatus, len(probes)),
+	}
+	for i, probe := range probes {
+		status := results[i]
+		resp.Components[probe.Name] = status
+		switch status.Status {
+		case ComponentOK, ComponentDisabled:
+			continue
+		case ComponentDown:
+			if probe.Required {
+				resp.Status = "unavailable"
+			}
+		}
+		if resp.Status == "ok" {
+			resp.Status = "degraded"
+		}
+		resp.Degraded = append(resp.Degraded, probe.Name)
+	}
+	sort.Strings(resp.Degraded)
+
+	code := http.StatusOK
+	if resp.Status == "unavailable" {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, resp)
+}
+
+// probeFailure logs err with attrs and returns status with only reason as its message.
+func probeFailure(component, status, reason string, err error, attrs ...any) ComponentStatus {
+	attrs = append([]any{"component", component, "reason", reason, "error", err}, attrs...)
+	slog.Warn("readiness probe failed", attrs...)
+	return ComponentStatus{Status: status, Message: reason}
+}
+
+// FeedbackDBProbe checks the feedback SQLite store. initErr is the error from
+// feedback.NewStore when the store could not be opened (store is then nil).
+func FeedbackDBProbe(store *feedback.Store, initErr error) ReadinessProbe {
+	return ReadinessProbe{
+		Name:     "feedback_db",
+		Required: true,
+		Check: func(ctx context.Context) ComponentStatus {
+			if store == nil {
+				if initErr != nil {
+					return probeFailure("feedback_db", ComponentDown, "failed to open", initErr)
+				}
+				return ComponentStatus{Status: ComponentDown, Message: "feedback store not initialized"}
+			}
+			if err := store.Ping(ctx); err != nil {
+				return probeFailure("feedback_db", ComponentDown, "ping failed", err)
+			}
+			return ComponentStatus{Status: ComponentOK}
+		},
+	}
+}
+
+// ShareStoreProbe checks that shares can be persisted.
+func ShareStoreProbe(store *share.Store) ReadinessProbe {
+	return ReadinessProbe{
+		Name:     "share_store",
+		Required: true,
+		Check: func(ctx context.Context) ComponentStatus {
+			path := store.Path()
+			if path == "" {
+				return ComponentStatus{
+					Status:  ComponentOK,
+					Message: "in-memory; shares are lost on restart",
+					Details: map[string]any{"persistent": false},
+				}
+			}
+			details := map[string]any{"persistent": true}
+			if err := store.CheckWritable(); err != nil {
+				status := probeFailure("share_store", ComponentDown, "not writable", err, "path", path)
+				status.Details = details
+				return status
+			}
+			return ComponentStatus{Status: ComponentOK, Details: details}
+		},
+	}
+}
+
+// ShareDirProbe checks that the directory served by a read-only share store
+// can still be listed.
+func ShareDirProbe(store *share.DirStore) ReadinessProbe {
+	return ReadinessProbe{
+		Name:     "share_dir",
+		Required: true,
+		Check: func(ctx context.Context) ComponentStatus {
+			details := map[string]any{"read_only": true}
+			if _, err := os.ReadDir(store.Dir()); err != nil {
+				status := probeFailure("share_dir", ComponentDown, "not readable", err, "path", store.Dir())
+				status.Details = details
+				return status
+			}
+			return ComponentStatus{Status: ComponentOK, Details: details}
+		},
+	}
+}
+
+// AIServiceProbe reports AI mode and circuit breaker state per service role.
+// AI is optional: conversions fall back to heuristic mapping when it is off or open.
+func AIServiceProbe(services map[string]ai.Service) ReadinessProbe {
+	return ReadinessProbe{
+		Name: "ai_service",
+		Check: func(ctx context.Context) ComponentStatus {
+			roles := sortedRoles(services)
+			details := make(map[string]any, len(roles))
+			var open []string
+			configured := 0
+			for _, role := range roles {
+				impl, ok := services[role].(*ai.ServiceImpl)
+				if !ok || impl == nil {
+					details[role] = map[string]any{"mode": "off"}
+					continue
+				}
+				configured++
+				state := impl.MetricsState()
+				details[role] = map[string]any{
+					"mode":    impl.GetMode(),
+					"model":   state.Model,
+					"circuit": state.CircuitState,
+				}
+				if state.CircuitState != string(ai.CircuitStateClosed) {
+					open = append(open, fmt.Sprintf("%s (%s)", role, state.CircuitState))
+				}
+			}
+			switch {
+			case configured == 0:
+				return ComponentStatus{Status: ComponentDisabled, Message: "AI disabled; heuristic mapping only", Details: details}
+			case len(open) > 0:
+				return ComponentStatus{
+					Status:  ComponentDegraded,
+					Message: "circuit breaker not closed for " + strings.Join(open, ", ") + "; using heuristic fallback",
+					Details: details,
+				}
+			}
+			return ComponentStatus{Status: ComponentOK, Details: details}
+		},
+	}
+}
+
+// AIBudgetProbe reports which service roles exhausted their daily AI budget.
+// Spend figures are logged, not returned.
+func AIBudgetProbe(services map[string]ai.Service) ReadinessProbe {
+	return ReadinessProbe{
+		Name: "ai_budget",
+		Check: func(ctx context.Context) ComponentStatus {
+			configured := 0
+			var exhausted []string
+			for _, role := range sortedRoles(services) {
+				impl, ok := services[role].(*ai.ServiceImpl)
+				if !ok || impl == nil {
+					continue
+				}
+				configured++
+				if budget := impl.GetBudgetStatus(); budget.IsOverBudget {
+					slog.Warn("readiness: daily AI budget exhausted", "role", role, "budget", budget)
+					exhausted = append(exhausted, role)
+				}
+			}
+			if configured == 0 {
+				return ComponentStatus{Status: ComponentDisabled}
+			}
+			if len(exhausted) > 0 {
+				return ComponentStatus{
+					Status:  ComponentDegraded,
+					Message: "daily AI budget exhausted for " + strings.Join(exhausted, ", "),
+				}
+			}
+			return ComponentStatus{Status: ComponentOK}
+		},
+	}
+}
+
+// AICacheDBProbe checks the persistent AI cache of the given service.
+func AICacheDBProbe(service ai.Service) ReadinessProbe {
+	return ReadinessProbe{
+		Name: "ai_cache_db",
+		Check: func(ctx context.Context) ComponentStatus {
+			impl, ok := service.(*ai.ServiceImpl)
+			if !ok || impl == nil {
+				return ComponentStatus{Status: ComponentDisabled, Message: "AI disabled"}
+			}
+			enabled, err := impl.CheckPersistentCache()
+			switch {
+			case !enabled:
+				return ComponentStatus{Status: ComponentDisabled, Message: "persistent cache not enabled; memory cache only"}
+			case err != nil:
+				return probeFailure("ai_cache_db", ComponentDegraded, "persistent cache unavailable", err)
+			}
+			return ComponentStatus{Status: ComponentOK}
+		},
+	}
+}
+
+// GoogleCredentialsProbe checks GOOGLE_APPLICATION_CREDENTIALS. Without it,
+// public sheets still work through the CSV export, so an unset variable is
+// ok with details.configured false; only an unreadable file is reported.
+func GoogleCredentialsProbe() ReadinessProbe {
+	return ReadinessProbe{
+		Name: "google_credentials",
+		Check: func(ctx context.Context) ComponentStatus {
+			path := strings.TrimSpace(os.Getenv(googleSheetsCredsEnv))
+			if path == "" {
+				return ComponentStatus{
+					Status:  ComponentOK,
+					Message: googleSheetsCredsEnv + " not set; private sheets need user OAuth",
+					Details: map[string]any{"configured": false},
+				}
+			}
+			if _, err := os.Stat(path); err != nil {
+				status := probeFailure("google_credentials", ComponentDown, "credentials file unreadable", err, "path", path)
+				status.Details = map[string]any{"configured": true}
+				return status
+			}
+			return ComponentStatus{Status: ComponentOK, Details: map[string]any{"configured": true}}
+		},
+	}
+}
+
+func sortedRoles(services map[string]ai.Service) []string {
+	roles := make([]string, 0, len(services))
+	for role := range services {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
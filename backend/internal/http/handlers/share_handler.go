@@ -8,10 +8,12 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourorg/md-spec-tool/internal/share"
+	"github.com/yourorg/md-spec-tool/internal/webhook"
 )
 
 type ShareHandler struct {
-	store share.StoreInterface
+	store  share.StoreInterface
+	events EventPublisher
 }
 
 // NewShareHandler creates a new ShareHandler with a store
@@ -24,6 +26,29 @@ func NewShareHandlerWithStore(store *share.Store) *ShareHandler {
 	return NewShareHandler(store)
 }
 
+// SetEventPublisher sets the publisher for share and comment webhooks
+func (h *ShareHandler) SetEventPublisher(p EventPublisher) {
+	h.events = p
+}
+
+// ShareCreatedEvent is the data payload of share.created. The token is left
+// out: it grants access to the share and every endpoint sees every event.
+type ShareCreatedEvent struct {
+	Slug          string `json:"slug,omitempty"`
+	Title         string `json:"title"`
+	Template      string `json:"template"`
+	IsPublic      bool   `json:"is_public"`
+	AllowComments bool   `json:"allow_comments"`
+}
+
+// CommentAddedEvent is the data payload of comment.added. It names the share by
+// slug only and leaves the comment text on the share.
+type CommentAddedEvent struct {
+	Slug      string `json:"slug,omitempty"`
+	CommentID string `json:"comment_id"`
+	Author    string `json:"author"`
+}
+
 type CreateShareRequest struct {
 	Title         string `json:"title"`
 	Template      string `json:"template"`
@@ -44,7 +69,9 @@ type ShareResponse struct {
 	AllowComments    bool            `json:"allow_comments"`
 	Permission       string          `json:"permission"`
 	CreatedAt        string          `json:"created_at"`
+	Revision         int             `json:"revision"`
 	ResolutionEvents []EventResponse `json:"resolution_events"`
+	OwnerKey         string          `json:"owner_key,omitempty"` // only in the create response
 }
 
 type EventResponse struct {
@@ -89,6 +116,7 @@ type CloneTemplateResponse struct {
 	Slug            string `json:"slug"`
 	RedirectURL     string `json:"redirect_url"`
 	SourceShareSlug string `json:"source_share_slug"`
+	OwnerKey        string `json:"owner_key"`
 }
 
 func (h *ShareHandler) CreateShare(c *gin.Context) {
@@ -144,6 +172,16 @@ func (h *ShareHandler) CreateShare(c *gin.Context) {
 		return
 	}
 
+	if h.events != nil {
+		h.events.Publish(webhook.EventShareCreated, ShareCreatedEvent{
+			Slug:          created.Slug,
+			Title:         created.Title,
+			Template:      created.Template,
+			IsPublic:      created.IsPublic,
+			AllowComments: created.AllowComments,
+		})
+	}
+
 	c.JSON(http.StatusOK, toShareResponse(created))
 }
 
@@ -256,6 +294,14 @@ func (h *ShareHandler) CreateComment(c *gin.Context) {
 		return
 	}
 
+	if h.events != nil {
+		event := CommentAddedEvent{CommentID: comment.ID, Author: comment.Author}
+		if sh, err := h.store.GetShare(key); err == nil {
+			event.Slug = sh.Slug
+		}
+		h.events.Publish(webhook.EventCommentAdded, event)
+	}
+
 	c.JSON(http.StatusOK, CommentResponse{
 		ID:        comment.ID,
 		Author:    comment.Author,
@@ -428,6 +474,7 @@ func (h *ShareHandler) CloneTemplate(c *gin.Context) {
 		Slug:            created.Slug,
 		RedirectURL:     redirectURL,
 		SourceShareSlug: sourceSlug,
+		OwnerKey:        created.OwnerKey,
 	})
 }
 
@@ -464,6 +511,8 @@ func toShareResponse(s *share.Share) ShareResponse {
 		AllowComments:    s.AllowComments,
 		Permission:       string(s.Permission),
 		CreatedAt:        s.CreatedAt.Format(time.RFC3339),
+		Revision:         s.Revision,
 		ResolutionEvents: events,
+		OwnerKey:         s.OwnerKey,
 	}
 }
@@ -20,6 +20,7 @@ type StreamHandler struct {
 	converter *converter.Converter
 	cfg       *config.Config
 	byokCache *AIServiceProvider
+	events    EventPublisher
 }
 
 // NewStreamHandler creates a StreamHandler.  All parameters are optional
@@ -41,6 +42,11 @@ func NewStreamHandler(conv *converter.Converter, cfg *config.Config, byokCache *
 	}
 }
 
+// SetEventPublisher sets the publisher for conversion webhooks
+func (h *StreamHandler) SetEventPublisher(p EventPublisher) {
+	h.events = p
+}
+
 // ConvertStream handles POST /api/v1/mdflow/convert/stream.
 // It accepts the same JSON body as POST /api/v1/mdflow/paste and emits
 // Server-Sent Events as the pipeline progresses.
@@ -153,6 +159,8 @@ func (h *StreamHandler) ConvertStream(c *gin.Context) {
 		Template:    req.Template,
 		NeedsReview: RequiresReview(result.Meta, result.Warnings),
 	}
+	setTelemetryContext(c, req.Template, result.Meta)
+	publishConversionEvents(h.events, c, "stream", finalResponse)
 	writeSSEEvent(c, "result", finalResponse)
 	if canFlush {
 		flusher.Flush()
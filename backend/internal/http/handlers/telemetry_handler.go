@@ -3,13 +3,13 @@ package handlers
 import (
 	"errors"
 	"net/http"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourorg/md-spec-tool/internal/http/middleware"
+	"github.com/yourorg/md-spec-tool/internal/telemetry"
 )
 
 type TelemetryHandler struct{}
@@ -42,8 +42,12 @@ type telemetryIngestRequest struct {
 }
 
 type telemetryDashboardResponse struct {
-	GeneratedAt string `json:"generated_at"`
-	WindowHours int    `json:"window_hours"`
+	GeneratedAt string                    `json:"generated_at"`
+	WindowHours int                       `json:"window_hours"`
+	Bucket      string                    `json:"bucket"`
+	Filters     telemetryFilters          `json:"filters"`
+	Series      []telemetryBucket         `json:"series"`
+	Conversion  telemetryConversionFunnel `json:"conversion_funnel"`
 	Totals      struct {
 		EventsTotal    int `json:"events_total"`
 		FrontendEvents int `json:"frontend_events"`
@@ -89,6 +93,40 @@ type telemetryDashboardResponse struct {
 	} `json:"ai_cost"`
 }
 
+type telemetryFilters struct {
+	Template    string `json:"template,omitempty"`
+	InputSource string `json:"input_source,omitempty"`
+	AIModel     string `json:"ai_model,omitempty"`
+}
+
+// telemetryBucket aggregates one hour or day of events; durations come from backend API events.
+type telemetryBucket struct {
+	Start         string `json:"start"`
+	Events        int    `json:"events"`
+	Errors        int    `json:"errors"`
+	Previews      int    `json:"previews"`
+	Converts      int    `json:"converts"`
+	Shares        int    `json:"shares"`
+	P50DurationMS int64  `json:"p50_duration_ms"`
+	P95DurationMS int64  `json:"p95_duration_ms"`
+}
+
+// telemetryConversionFunnel is the preview → convert → share funnel from backend API events,
+// counted both as requests and as distinct sessions.
+type telemetryConversionFunnel struct {
+	Previews             int     `json:"previews"`
+	Converts             int     `json:"converts"`
+	Shares               int     `json:"shares"`
+	SessionsPreviewed    int     `json:"sessions_previewed"`
+	SessionsConverted    int     `json:"sessions_converted"`
+	SessionsShared       int     `json:"sessions_shared"`
+	PreviewToConvertRate float64 `json:"preview_to_convert_rate"`
+	ConvertToShareRate   float64 `json:"convert_to_share_rate"`
+}
+
+// maxTelemetryClockSkew is how far ahead of the server clock an ingested event may be dated.
+const maxTelemetryClockSkew = 5 * time.Minute
+
 // IngestEvents handles POST /api/telemetry/events.
 func (h *TelemetryHandler) IngestEvents(c *gin.Context) {
 	const maxBodyBytes = 256 << 10 // 256KB
@@ -105,6 +143,7 @@ func (h *TelemetryHandler) IngestEvents(c *gin.Context) {
 		return
 	}
 
+	now := time.Now().UTC()
 	recorded := 0
 	for _, raw := range batch.Events {
 		eventName := strings.TrimSpace(raw.EventName)
@@ -119,14 +158,18 @@ func (h *TelemetryHandler) IngestEvents(c *gin.Context) {
 			status = "success"
 		}
 
-		eventTime := time.Now().UTC()
+		// Retention prunes by event time, so client clocks may not date events
+		// into the future: small skew is clamped to now, anything further is dropped.
+		eventTime := now
 		if strings.TrimSpace(raw.EventTime) != "" {
-			if parsed, err := time.Parse(time.RFC3339, raw.EventTime); err == nil {
+			if parsed, err := time.Parse(time.RFC3339, raw.EventTime); err == nil && parsed.Before(now) {
 				eventTime = parsed.UTC()
+			} else if err == nil && parsed.Sub(now) > maxTelemetryClockSkew {
+				continue
 			}
 		}
 
-		middleware.RecordTelemetryEvent(middleware.TelemetryEvent{
+		middleware.RecordTelemetryEvent(telemetry.Event{
 			EventName:          eventName,
 			EventTime:          eventTime,
 			SessionID:          strings.TrimSpace(raw.SessionID),
@@ -152,120 +195,69 @@ func (h *TelemetryHandler) IngestEvents(c *gin.Context) {
 }
 
 // Dashboard handles GET /api/telemetry/dashboard.
+// Query params: hours (1..2160), bucket (hour|day; default hour up to 48h), template, input_source, ai_model.
 func (h *TelemetryHandler) Dashboard(c *gin.Context) {
 	hours := 24
 	if raw := strings.TrimSpace(c.Query("hours")); raw != "" {
-		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 && parsed <= 168 {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 && parsed <= maxDashboardHours {
 			hours = parsed
 		}
 	}
 
-	since := time.Now().UTC().Add(-time.Duration(hours) * time.Hour)
-	events := middleware.SnapshotTelemetryEvents(since)
-
-	resp := telemetryDashboardResponse{
-		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-		WindowHours: hours,
+	bucket := strings.TrimSpace(c.Query("bucket"))
+	switch bucket {
+	case "hour", "day":
+	case "":
+		bucket = "hour"
+		if hours > 48 {
+			bucket = "day"
+		}
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "bucket must be hour or day"})
+		return
 	}
 
-	type sessionTimes struct {
-		studio  time.Time
-		convert time.Time
+	bucketWidth := time.Hour
+	if bucket == "day" {
+		bucketWidth = 24 * time.Hour
 	}
-	sessionMap := make(map[string]sessionTimes)
-
-	previewDurations := make([]int64, 0)
-	convertDurations := make([]int64, 0)
-	ttvDurations := make([]int64, 0)
-	errorCounts := map[string]int{}
-
-	var previewSuccess, previewFailed, convertSuccess, convertFailed int
-	var backendTotal, backend5xx, frontendTotal int
 
-	for _, e := range events {
-		resp.Totals.EventsTotal++
-		if e.Source == "frontend" {
-			frontendTotal++
-		}
-		if e.Source == "backend" {
-			backendTotal++
-			if e.HTTPStatus >= 500 {
-				backend5xx++
-			}
-		}
-
-		if e.Status == "error" {
-			errorCounts[e.EventName]++
-		}
-
-		switch e.EventName {
-		case "studio_opened":
-			if e.Status == "success" {
-				resp.Funnel.StudioOpened++
-				if e.SessionID != "" {
-					s := sessionMap[e.SessionID]
-					if s.studio.IsZero() || e.EventTime.Before(s.studio) {
-						s.studio = e.EventTime
-						sessionMap[e.SessionID] = s
-					}
-				}
-			}
-		case "input_provided":
-			if e.Status == "success" {
-				resp.Funnel.InputProvided++
-			}
-		case "preview_succeeded":
-			resp.Funnel.PreviewSucceeded++
-			previewSuccess++
-		case "preview_failed":
-			previewFailed++
-		case "convert_succeeded":
-			resp.Funnel.ConvertSucceeded++
-			convertSuccess++
-			if e.SessionID != "" {
-				s := sessionMap[e.SessionID]
-				if s.convert.IsZero() || e.EventTime.Before(s.convert) {
-					s.convert = e.EventTime
-					sessionMap[e.SessionID] = s
-				}
-			}
-		case "convert_failed":
-			convertFailed++
-		case "share_created_ui":
-			if e.Status == "success" {
-				resp.Funnel.ShareCreated++
-			}
-		}
+	filters := telemetryFilters{
+		Template:    strings.TrimSpace(c.Query("template")),
+		InputSource: strings.TrimSpace(c.Query("input_source")),
+		AIModel:     strings.TrimSpace(c.Query("ai_model")),
+	}
+	now := time.Now().UTC()
+	query := telemetry.Query{
+		Since:        now.Add(-time.Duration(hours) * time.Hour),
+		TemplateType: filters.Template,
+		InputSource:  filters.InputSource,
+		AIModel:      filters.AIModel,
+	}
+	summary := middleware.QueryTelemetrySummary(query)
 
-		switch e.EventName {
-		case "api_preview_completed":
-			if e.DurationMS > 0 {
-				previewDurations = append(previewDurations, e.DurationMS)
-			}
-		case "api_convert_completed":
-			if e.DurationMS > 0 {
-				convertDurations = append(convertDurations, e.DurationMS)
-			}
-		}
+	resp := telemetryDashboardResponse{
+		GeneratedAt: now.Format(time.RFC3339),
+		WindowHours: hours,
+		Bucket:      bucket,
+		Filters:     filters,
+		Series:      telemetrySeries(middleware.QueryTelemetrySeries(query, bucketWidth)),
+		Conversion:  conversionFunnel(summary.Conversion),
 	}
 
-	resp.Totals.FrontendEvents = frontendTotal
-	resp.Totals.BackendEvents = backendTotal
+	resp.Totals.EventsTotal = summary.EventsTotal
+	resp.Totals.FrontendEvents = summary.FrontendEvents
+	resp.Totals.BackendEvents = summary.BackendEvents
 
-	for _, s := range sessionMap {
-		if s.studio.IsZero() || s.convert.IsZero() || s.convert.Before(s.studio) {
-			continue
-		}
-		ttv := s.convert.Sub(s.studio).Milliseconds()
-		ttvDurations = append(ttvDurations, ttv)
-	}
+	resp.Funnel.StudioOpened = summary.StudioOpened
+	resp.Funnel.InputProvided = summary.InputProvided
+	resp.Funnel.PreviewSucceeded = summary.PreviewSucceeded
+	resp.Funnel.ConvertSucceeded = summary.ConvertSucceeded
+	resp.Funnel.ShareCreated = summary.ShareCreated
 
 	activated := 0
-	for _, s := range sessionMap {
-		if s.studio.IsZero() || s.convert.IsZero() || s.convert.Before(s.studio) {
-			continue
-		}
-		if s.convert.Sub(s.studio) <= 10*time.Minute {
+	for _, ttv := range summary.TimeToValueMS {
+		if ttv <= (10 * time.Minute).Milliseconds() {
 			activated++
 		}
 	}
@@ -273,70 +265,34 @@ func (h *TelemetryHandler) Dashboard(c *gin.Context) {
 		resp.KPIs.ActivationRate10m = ratio(activated, resp.Funnel.StudioOpened)
 	}
 
-	resp.KPIs.TimeToValueMS.Median = percentile(ttvDurations, 50)
-	resp.KPIs.TimeToValueMS.P75 = percentile(ttvDurations, 75)
-	resp.KPIs.TimeToValueMS.P95 = percentile(ttvDurations, 95)
+	resp.KPIs.TimeToValueMS.Median = percentile(summary.TimeToValueMS, 50)
+	resp.KPIs.TimeToValueMS.P75 = percentile(summary.TimeToValueMS, 75)
+	resp.KPIs.TimeToValueMS.P95 = percentile(summary.TimeToValueMS, 95)
 
-	resp.KPIs.PreviewSuccessRate = ratio(previewSuccess, previewSuccess+previewFailed)
-	resp.KPIs.ConvertSuccessRate = ratio(convertSuccess, convertSuccess+convertFailed)
+	resp.KPIs.PreviewSuccessRate = ratio(summary.PreviewSucceeded, summary.PreviewSucceeded+summary.PreviewFailed)
+	resp.KPIs.ConvertSuccessRate = ratio(summary.ConvertSucceeded, summary.ConvertSucceeded+summary.ConvertFailed)
 
-	resp.Reliability.API5xxRate = ratio(backend5xx, backendTotal)
-	resp.Reliability.P95PreviewLatencyMS = percentile(previewDurations, 95)
-	resp.Reliability.P95ConvertLatencyMS = percentile(convertDurations, 95)
+	resp.Reliability.API5xxRate = ratio(summary.Backend5xx, summary.BackendEvents)
+	resp.Reliability.P95PreviewLatencyMS = summary.P95PreviewLatencyMS
+	resp.Reliability.P95ConvertLatencyMS = summary.P95ConvertLatencyMS
 
-	type errorItem struct {
-		name  string
-		count int
-	}
-	items := make([]errorItem, 0, len(errorCounts))
-	for name, count := range errorCounts {
-		items = append(items, errorItem{name: name, count: count})
-	}
-	sort.Slice(items, func(i, j int) bool {
-		if items[i].count == items[j].count {
-			return items[i].name < items[j].name
-		}
-		return items[i].count > items[j].count
-	})
-	for i, item := range items {
-		if i >= 10 {
-			break
-		}
+	for _, item := range summary.Errors {
 		resp.Errors = append(resp.Errors, struct {
 			EventName string `json:"event_name"`
 			Count     int    `json:"count"`
-		}{EventName: item.name, Count: item.count})
+		}{EventName: item.EventName, Count: item.Count})
 	}
 
 	// AI cost aggregation
-	var totalCostUSD float64
-	var totalInputTokens, totalOutputTokens int64
-	var totalAIRequests int
-	costByModel := map[string]struct{ cost float64; count int }{}
-
-	for _, e := range events {
-		if e.AIEstimatedCostUSD > 0 {
-			totalCostUSD += e.AIEstimatedCostUSD
-			totalInputTokens += e.AIInputTokens
-			totalOutputTokens += e.AIOutputTokens
-			totalAIRequests++
-			m := costByModel[e.AIModel]
-			m.cost += e.AIEstimatedCostUSD
-			m.count++
-			costByModel[e.AIModel] = m
-		}
+	resp.AICost.TotalCostUSD = summary.AICost.TotalCostUSD
+	resp.AICost.TotalInputTokens = summary.AICost.TotalInputTokens
+	resp.AICost.TotalOutputTokens = summary.AICost.TotalOutputTokens
+	resp.AICost.TotalAIRequests = summary.AICost.Requests
+	if summary.AICost.Requests > 0 {
+		resp.AICost.AvgCostPerConvert = summary.AICost.TotalCostUSD / float64(summary.AICost.Requests)
 	}
-
-	resp.AICost.TotalCostUSD = totalCostUSD
-	resp.AICost.TotalInputTokens = totalInputTokens
-	resp.AICost.TotalOutputTokens = totalOutputTokens
-	resp.AICost.TotalAIRequests = totalAIRequests
-	if totalAIRequests > 0 {
-		resp.AICost.AvgCostPerConvert = totalCostUSD / float64(totalAIRequests)
-	}
-
-	for model, data := range costByModel {
-		modelName := model
+	for _, m := range summary.AICost.ByModel {
+		modelName := m.Model
 		if modelName == "" {
 			modelName = "unknown"
 		}
@@ -344,15 +300,46 @@ func (h *TelemetryHandler) Dashboard(c *gin.Context) {
 			Model    string  `json:"model"`
 			CostUSD  float64 `json:"cost_usd"`
 			Requests int     `json:"requests"`
-		}{Model: modelName, CostUSD: data.cost, Requests: data.count})
+		}{Model: modelName, CostUSD: m.CostUSD, Requests: m.Requests})
 	}
-	sort.Slice(resp.AICost.CostByModel, func(i, j int) bool {
-		return resp.AICost.CostByModel[i].CostUSD > resp.AICost.CostByModel[j].CostUSD
-	})
 
 	c.JSON(http.StatusOK, resp)
 }
 
+const maxDashboardHours = 90 * 24
+
+// telemetrySeries converts the bucketed events for the dashboard response.
+func telemetrySeries(buckets []telemetry.SeriesBucket) []telemetryBucket {
+	series := make([]telemetryBucket, 0, len(buckets))
+	for _, b := range buckets {
+		series = append(series, telemetryBucket{
+			Start:         b.Start.Format(time.RFC3339),
+			Events:        b.Events,
+			Errors:        b.Errors,
+			Previews:      b.Previews,
+			Converts:      b.Converts,
+			Shares:        b.Shares,
+			P50DurationMS: b.P50DurationMS,
+			P95DurationMS: b.P95DurationMS,
+		})
+	}
+	return series
+}
+
+// conversionFunnel adds the session conversion rates to the funnel counts.
+func conversionFunnel(counts telemetry.ConversionCounts) telemetryConversionFunnel {
+	return telemetryConversionFunnel{
+		Previews:             counts.Previews,
+		Converts:             counts.Converts,
+		Shares:               counts.Shares,
+		SessionsPreviewed:    counts.SessionsPreviewed,
+		SessionsConverted:    counts.SessionsConverted,
+		SessionsShared:       counts.SessionsShared,
+		PreviewToConvertRate: ratio(counts.SessionsConverted, counts.SessionsPreviewed),
+		ConvertToShareRate:   ratio(counts.SessionsShared, counts.SessionsConverted),
+	}
+}
+
 func ratio(part, total int) float64 {
 	if total <= 0 || part <= 0 {
 		return 0
@@ -361,19 +348,7 @@ func ratio(part, total int) float64 {
 }
 
 func percentile(values []int64, p int) int64 {
-	if len(values) == 0 {
-		return 0
-	}
-	sorted := append([]int64(nil), values...)
-	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
-	if p <= 0 {
-		return sorted[0]
-	}
-	if p >= 100 {
-		return sorted[len(sorted)-1]
-	}
-	idx := int(float64(p) / 100.0 * float64(len(sorted)-1))
-	return sorted[idx]
+	return telemetry.DurationPercentile(values, p)
 }
 
 func maxInt(v, min int) int {
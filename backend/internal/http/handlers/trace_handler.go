@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	"github.com/yourorg/md-spec-tool/internal/traceability"
+)
+
+// TraceRequest is the body of POST /api/v1/mdflow/trace. Requirements and
+// tests are pasted TSV/CSV sheets.
+type TraceRequest struct {
+	Requirements string               `json:"requirements" binding:"required"`
+	Tests        string               `json:"tests" binding:"required"`
+	Options      traceability.Options `json:"options"`
+}
+
+// TraceResponse is the coverage matrix plus its markdown rendering.
+type TraceResponse struct {
+	traceability.Matrix
+	Markdown string `json:"markdown"`
+}
+
+// TraceHandler builds requirements-to-tests traceability matrices.
+type TraceHandler struct {
+	converter *converter.Converter
+	cfg       *config.Config
+}
+
+// NewTraceHandler creates a new TraceHandler
+func NewTraceHandler(conv *converter.Converter, cfg *config.Config) *TraceHandler {
+	if conv == nil {
+		conv = converter.NewConverter()
+	}
+	if cfg == nil {
+		cfg = config.LoadConfig()
+	}
+	return &TraceHandler{converter: conv, cfg: cfg}
+}
+
+// TracePaste handles POST /api/mdflow/trace
+// Links pasted test cases to pasted requirements.
+func (h *TraceHandler) TracePaste(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.cfg.MaxPasteBytes*2+1<<10)
+
+	var req TraceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: fmt.Sprintf("content exceeds %s limit", humanSize(h.cfg.MaxPasteBytes))})
+			return
+		}
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "requirements and tests are required"})
+		return
+	}
+	if int64(len(req.Requirements)) > h.cfg.MaxPasteBytes || int64(len(req.Tests)) > h.cfg.MaxPasteBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: fmt.Sprintf("content exceeds %s limit", humanSize(h.cfg.MaxPasteBytes))})
+		return
+	}
+	if req.Options.FuzzyThreshold < 0 || req.Options.FuzzyThreshold > 1 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "fuzzy_threshold must be between 0 and 1"})
+		return
+	}
+
+	reqDoc, err := converter.BuildSpecDocFromTable(req.Requirements)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "failed to parse requirements: " + err.Error()})
+		return
+	}
+	testDoc, err := converter.BuildSpecDocFromTable(req.Tests)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "failed to parse tests: " + err.Error()})
+		return
+	}
+
+	h.respond(c, reqDoc, testDoc, req.Options)
+}
+
+// TraceXLSX handles POST /api/mdflow/xlsx/trace
+// Links the tests sheet of an uploaded workbook to its requirements sheet.
+func (h *TraceHandler) TraceXLSX(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.cfg.MaxUploadBytes+1<<20)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: fmt.Sprintf("file exceeds %s limit", humanSize(h.cfg.MaxUploadBytes))})
+			return
+		}
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "file is required"})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > h.cfg.MaxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: fmt.Sprintf("file exceeds %s limit", humanSize(h.cfg.MaxUploadBytes))})
+		return
+	}
+
+	reqSheet := strings.TrimSpace(c.PostForm("requirements_sheet"))
+	testSheet := strings.TrimSpace(c.PostForm("tests_sheet"))
+	if reqSheet == "" || testSheet == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "requirements_sheet and tests_sheet are required"})
+		return
+	}
+	for _, name := range []string{reqSheet, testSheet} {
+		if err := validateSheetName(name); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	opts, err := traceFormOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ext := uploadSpreadsheetExt(header.Filename)
+	buf := make([]byte, 4)
+	n, err := io.ReadFull(file, buf)
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "file is empty"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "failed to read file"})
+		return
+	}
+	if !bytes.HasPrefix(buf[:n], converter.SpreadsheetSignature(ext)) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid %s file", ext[1:])})
+		return
+	}
+
+	reader := io.MultiReader(bytes.NewReader(buf[:n]), file)
+
+	tempFile, err := os.CreateTemp("", "upload-*"+ext)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to process file"})
+		return
+	}
+	tempName := tempFile.Name()
+	defer os.Remove(tempName)
+
+	bytesCopied, err := io.Copy(tempFile, io.LimitReader(reader, h.cfg.MaxUploadBytes+1))
+	if err != nil {
+		_ = tempFile.Close()
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to save file"})
+		return
+	}
+	if bytesCopied > h.cfg.MaxUploadBytes {
+		_ = tempFile.Close()
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: fmt.Sprintf("file exceeds %s limit", humanSize(h.cfg.MaxUploadBytes))})
+		return
+	}
+	if err := tempFile.Close(); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to process file"})
+		return
+	}
+
+	docs := make([]*converter.SpecDoc, 0, 2)
+	for _, sheet := range []string{reqSheet, testSheet} {
+		matrix, merges, err := h.converter.ParseXLSXWithMerges(c.Request.Context(), tempName, sheet)
+		if err != nil {
+			slog.Warn("trace sheet parse failed", "sheet", sheet, "error", err)
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("failed to read sheet %q", sheet), Details: map[string]any{"sheet": sheet, "error": err.Error()}})
+			return
+		}
+		docs = append(docs, converter.BuildSpecDocFromMatrixWithMerges(matrix, merges))
+	}
+
+	h.respond(c, docs[0], docs[1], opts)
+}
+
+func (h *TraceHandler) respond(c *gin.Context, requirements, tests *converter.SpecDoc, opts traceability.Options) {
+	if len(requirements.Rows) == 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "no requirement rows found"})
+		return
+	}
+	matrix := traceability.Build(requirements, tests, opts)
+	c.JSON(http.StatusOK, TraceResponse{Matrix: *matrix, Markdown: traceability.FormatMarkdown(matrix)})
+}
+
+// traceFormOptions reads reference_fields (comma-separated), fuzzy_threshold
+// and disable_fuzzy from a multipart form.
+func traceFormOptions(c *gin.Context) (traceability.Options, error) {
+	var opts traceability.Options
+	for _, field := range strings.Split(c.PostForm("reference_fields"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			opts.ReferenceFields = append(opts.ReferenceFields, field)
+		}
+	}
+	if raw := strings.TrimSpace(c.PostForm("fuzzy_threshold")); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v < 0 || v > 1 {
+			return opts, fmt.Errorf("fuzzy_threshold must be between 0 and 1")
+		}
+		opts.FuzzyThreshold = v
+	}
+	disable, err := parseOptionalFormBool(c, "disable_fuzzy")
+	if err != nil {
+		return opts, err
+	}
+	if disable != nil {
+		opts.DisableFuzzy = *disable
+	}
+	return opts, nil
+}
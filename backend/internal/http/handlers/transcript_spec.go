@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/ai"
+	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+const maxTranscriptParagraphs = 2000
+
+// TranscriptSpecRequest is the body of POST /api/audio/spec. Either paragraphs
+// (as returned by /api/audio/transcribe) or pasted text must be set.
+type TranscriptSpecRequest struct {
+	Paragraphs []TranscriptSplit `json:"paragraphs"`
+	Text       string            `json:"text"`
+	Language   string            `json:"language"`
+	Title      string            `json:"title"`
+	Template   string            `json:"template"`
+	Format     string            `json:"format"`
+}
+
+// TranscriptSpecResponse is the draft spec rendered from a transcript.
+type TranscriptSpecResponse struct {
+	MDFlow          string                   `json:"mdflow"`
+	Format          string                   `json:"format"`
+	Template        string                   `json:"template"`
+	Rows            []converter.SpecRow      `json:"rows"`
+	Warnings        []converter.Warning      `json:"warnings"`
+	Meta            converter.SpecDocMeta    `json:"meta"`
+	Paragraphs      []ai.TranscriptParagraph `json:"paragraphs"`
+	Confidence      float64                  `json:"confidence"`
+	AIModel         string                   `json:"ai_model,omitempty"`
+	AIPromptVersion string                   `json:"ai_prompt_version"`
+}
+
+// TranscriptSpecHandler turns meeting transcripts into draft specs.
+type TranscriptSpecHandler struct {
+	provider *AIServiceProvider
+	cfg      *config.Config
+}
+
+func NewTranscriptSpecHandler(provider *AIServiceProvider, cfg *config.Config) *TranscriptSpecHandler {
+	if cfg == nil {
+		cfg = config.LoadConfig()
+	}
+	if provider == nil {
+		provider = NewAIServiceProvider(cfg)
+	}
+	return &TranscriptSpecHandler{provider: provider, cfg: cfg}
+}
+
+// DraftSpec handles POST /api/audio/spec
+// Extracts requirements and user stories with the AI service (BYOK-aware) and
+// renders them through the standard spec/table renderers. Every row cites the
+// transcript paragraphs and time ranges it was derived from.
+func (h *TranscriptSpecHandler) DraftSpec(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.cfg.MaxPasteBytes+64<<10)
+
+	var req TranscriptSpecRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "request body exceeds limit"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request format"})
+		return
+	}
+
+	template, format, err := normalizeTemplateAndFormat(req.Template, req.Format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	paragraphs := transcriptParagraphs(req)
+	if len(paragraphs) == 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "paragraphs or text is required"})
+		return
+	}
+	if len(paragraphs) > maxTranscriptParagraphs {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: fmt.Sprintf("transcript exceeds %d paragraphs", maxTranscriptParagraphs)})
+		return
+	}
+
+	aiService := h.provider.GetAIServiceForRequest(c)
+	if aiService == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error: "OpenAI API key not configured. Add your key in Studio settings.",
+			Code:  "AI_UNAVAILABLE",
+		})
+		return
+	}
+
+	extraction, err := aiService.ExtractRequirements(c.Request.Context(), ai.ExtractRequirementsRequest{
+		Paragraphs: paragraphs,
+		Language:   strings.TrimSpace(req.Language),
+	})
+	if err != nil {
+		slog.Warn("transcript requirements extraction failed", "error", err)
+		status := http.StatusBadGateway
+		if errors.Is(err, ai.ErrAIRateLimited) {
+			status = http.StatusTooManyRequests
+		}
+		c.JSON(status, ErrorResponse{Error: "failed to extract requirements from transcript", Code: "AI_EXTRACTION_FAILED"})
+		return
+	}
+
+	doc := converter.BuildTranscriptSpecDoc(strings.TrimSpace(req.Title), template, paragraphs, extraction)
+	doc.Meta.OutputFormat = format
+	doc.Meta.AIMode = aiService.GetMode()
+	doc.Meta.AIUsed = true
+	doc.Meta.AIModel = aiService.GetModel()
+	doc.Meta.AIPromptVersion = ai.PromptVersionRequirementsExtraction
+	doc.Meta.AIAvgConfidence = extraction.Confidence
+
+	mdflow, err := converter.RenderSpecDocTable(doc, format, converter.DefaultConvertOptions())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: fmt.Sprintf("render failed: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, TranscriptSpecResponse{
+		MDFlow:          mdflow,
+		Format:          format,
+		Template:        template,
+		Rows:            doc.Rows,
+		Warnings:        doc.Warnings,
+		Meta:            doc.Meta,
+		Paragraphs:      paragraphs,
+		Confidence:      extraction.Confidence,
+		AIModel:         doc.Meta.AIModel,
+		AIPromptVersion: ai.PromptVersionRequirementsExtraction,
+	})
+}
+
+// transcriptParagraphs prefers paragraphs from the transcription endpoint and
+// falls back to splitting pasted text.
+func transcriptParagraphs(req TranscriptSpecRequest) []ai.TranscriptParagraph {
+	if len(req.Paragraphs) == 0 {
+		return converter.ParseTranscriptText(req.Text)
+	}
+	paragraphs := make([]ai.TranscriptParagraph, 0, len(req.Paragraphs))
+	for _, p := range req.Paragraphs {
+		text := strings.TrimSpace(p.Text)
+		if text == "" {
+			continue
+		}
+		id := strings.ToUpper(strings.TrimSpace(p.ID))
+		if id == "" {
+			id = fmt.Sprintf("P%d", len(paragraphs)+1)
+		}
+		paragraphs = append(paragraphs, ai.TranscriptParagraph{ID: id, Start: p.Start, End: p.End, Text: text})
+	}
+	return paragraphs
+}
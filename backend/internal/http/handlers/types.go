@@ -9,6 +9,8 @@ type PasteConvertRequest struct {
 	Format          string                     `json:"format"`
 	ColumnOverrides map[string]string          `json:"column_overrides,omitempty"`
 	ValidationRules *converter.ValidationRules `json:"validation_rules,omitempty"`
+	// ValidationRulesText is a YAML or JSON rules document, merged with ValidationRules.
+	ValidationRulesText string `json:"validation_rules_text,omitempty"`
 	// Phase 3: Convert options
 	IncludeMetadata *bool `json:"include_metadata,omitempty"` // default true when nil
 	NumberRows      *bool `json:"number_rows,omitempty"`      // default false when nil
@@ -42,6 +44,7 @@ type PreviewResponse struct {
 	TotalRows          int                              `json:"total_rows"`
 	PreviewRows        int                              `json:"preview_rows"`
 	HeaderRow          int                              `json:"header_row"`
+	HeaderBand         converter.HeaderBand             `json:"header_band"`
 	Confidence         int                              `json:"confidence"`
 	ColumnMapping      map[string]string                `json:"column_mapping"`
 	UnmappedCols       []string                         `json:"unmapped_columns"`
@@ -49,6 +52,7 @@ type PreviewResponse struct {
 	Blocks             []PreviewBlock                   `json:"blocks,omitempty"`
 	SelectedBlockID    string                           `json:"selected_block_id,omitempty"`
 	SelectedBlockRange string                           `json:"selected_block_range,omitempty"`
+	MergedCells        []converter.MergedRegion         `json:"merged_cells,omitempty"`
 	InputType          string                           `json:"input_type"`
 	AIAvailable        bool                             `json:"ai_available"`
 }
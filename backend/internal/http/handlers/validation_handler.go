@@ -1,14 +1,17 @@
 package handlers
 
 import (
+	"bytes"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourorg/md-spec-tool/internal/ai"
 	"github.com/yourorg/md-spec-tool/internal/config"
 	"github.com/yourorg/md-spec-tool/internal/converter"
+	"github.com/yourorg/md-spec-tool/internal/report"
 )
 
 // ValidateRequest represents the request for validation with custom rules
@@ -16,6 +19,10 @@ type ValidateRequest struct {
 	PasteText       string                     `json:"paste_text" binding:"required"`
 	ValidationRules *converter.ValidationRules `json:"validation_rules"`
 	Template        string                     `json:"template"`
+	// ValidationRulesText is a YAML or JSON rules document, merged with ValidationRules.
+	ValidationRulesText string `json:"validation_rules_text,omitempty"`
+	// SourceName is the artifact URI used in SARIF/JUnit reports (default "paste_text").
+	SourceName string `json:"source_name,omitempty"`
 }
 
 // ValidateResponse represents the validation response with optional AI results
@@ -48,6 +55,8 @@ func NewValidationHandler(cfg *config.Config, byokCache *AIServiceProvider) *Val
 // Validate handles POST /api/mdflow/validate
 // Builds SpecDoc from paste_text and runs custom validation rules
 // Automatically runs AI semantic validation when AI service is available
+// Responds with SARIF or JUnit XML when the Accept header asks for it;
+// ?format= (json, text, sarif or junit) overrides the header
 func (h *ValidationHandler) Validate(c *gin.Context) {
 	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.cfg.MaxPasteBytes+4<<10)
 
@@ -62,18 +71,31 @@ func (h *ValidationHandler) Validate(c *gin.Context) {
 		return
 	}
 
+	format := report.NegotiateFormat(c.GetHeader("Accept"))
+	if query := c.Query("format"); query != "" {
+		var err error
+		if format, err = report.NormalizeFormat(query); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
 	specDoc, err := converter.BuildSpecDocFromPaste(req.PasteText)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "failed to parse input: " + err.Error()})
 		return
 	}
 
-	rules := req.ValidationRules
+	rules, err := resolveValidationRules(req.ValidationRules, req.ValidationRulesText)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error(), Code: invalidRulesCode})
+		return
+	}
 	if rules == nil {
 		rules = &converter.ValidationRules{}
 	}
 
-	result := converter.Validate(specDoc, rules)
+	result := converter.ValidateWithContext(c.Request.Context(), specDoc, rules)
 
 	resp := ValidateResponse{
 		Valid:    result.Valid,
@@ -95,5 +117,29 @@ func (h *ValidationHandler) Validate(c *gin.Context) {
 		}
 	}
 
+	if format != report.FormatJSON {
+		writeValidationReport(c, format, req.SourceName, req.PasteText, specDoc, resp)
+		return
+	}
+
 	c.JSON(http.StatusOK, resp)
 }
+
+// writeValidationReport renders validation and semantic findings as SARIF,
+// JUnit XML or text, locating rows by their line in the pasted text.
+func writeValidationReport(c *gin.Context, format, sourceName, text string, doc *converter.SpecDoc, resp ValidateResponse) {
+	uri := strings.TrimSpace(sourceName)
+	if uri == "" {
+		uri = "paste_text"
+	}
+	findings := append(report.FromWarnings(resp.Warnings), report.FromSemantic(resp.SemanticResult)...)
+	report.Locate(findings, uri, report.NewSourceLocator(uri, doc, false).WithText(text))
+
+	var buf bytes.Buffer
+	if err := report.Write(&buf, format, &report.Report{Tool: "mdflow", URI: uri, Findings: findings}); err != nil {
+		slog.Error("validate report render failed", "format", format, "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to render report"})
+		return
+	}
+	c.Data(http.StatusOK, report.ContentType(format), buf.Bytes())
+}
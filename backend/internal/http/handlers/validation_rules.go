@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+// maxRulesFileBytes caps an uploaded or inline validation rules document.
+const maxRulesFileBytes = 256 << 10
+
+// invalidRulesCode is the ErrorResponse code for malformed validation rules.
+const invalidRulesCode = "INVALID_VALIDATION_RULES"
+
+// resolveValidationRules merges inline JSON rules with a YAML/JSON rules
+// document (the contents of a rules file) and checks the result.
+func resolveValidationRules(inline *converter.ValidationRules, text string) (*converter.ValidationRules, error) {
+	rules := inline
+	if strings.TrimSpace(text) != "" {
+		if len(text) > maxRulesFileBytes {
+			return nil, fmt.Errorf("validation rules exceed %s limit", humanSize(maxRulesFileBytes))
+		}
+		parsed, err := converter.ParseValidationRules([]byte(text))
+		if err != nil {
+			return nil, err
+		}
+		rules = rules.Merge(parsed)
+	}
+	if err := rules.Check(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// formValidationRules reads rules for multipart endpoints from the
+// validation_rules text field and/or a validation_rules_file part.
+func formValidationRules(c *gin.Context) (*converter.ValidationRules, error) {
+	text := c.PostForm("validation_rules")
+	rules, err := resolveValidationRules(nil, text)
+	if err != nil {
+		return nil, err
+	}
+
+	file, _, err := c.Request.FormFile("validation_rules_file")
+	if err == http.ErrMissingFile {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read validation_rules_file")
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxRulesFileBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read validation_rules_file")
+	}
+	if len(data) > maxRulesFileBytes {
+		return nil, fmt.Errorf("validation rules exceed %s limit", humanSize(maxRulesFileBytes))
+	}
+	return resolveValidationRules(rules, string(data))
+}
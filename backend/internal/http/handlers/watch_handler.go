@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/share"
+	"github.com/yourorg/md-spec-tool/internal/sheetwatch"
+)
+
+// defaultWatchInterval is used when a watch is created without interval_seconds.
+const defaultWatchInterval = 5 * time.Minute
+
+// WatchOwnerKeyHeader carries the owner key returned when a watch is created.
+// Every other watch endpoint requires it and only sees the caller's watches.
+const WatchOwnerKeyHeader = "X-Watch-Owner-Key"
+
+// minWatchOwnerKeyLength is the shortest owner key a caller may choose when
+// creating a watch alongside ones it already owns.
+const minWatchOwnerKeyLength = 32
+
+// ShareOwnerVerifier checks that a caller owns the share a watch publishes to.
+type ShareOwnerVerifier interface {
+	VerifyOwner(token, ownerKey string) error
+}
+
+// WatchHandler manages watched Google Sheets (change detection and scheduled re-sync)
+type WatchHandler struct {
+	store  *sheetwatch.Store
+	syncer *sheetwatch.Syncer
+	shares ShareOwnerVerifier
+	cfg    *config.Config
+}
+
+// NewWatchHandler creates a WatchHandler. Without shares, watches cannot be
+// linked to a share.
+func NewWatchHandler(store *sheetwatch.Store, syncer *sheetwatch.Syncer, shares ShareOwnerVerifier, cfg *config.Config) *WatchHandler {
+	if cfg == nil {
+		cfg = config.LoadConfig()
+	}
+	return &WatchHandler{store: store, syncer: syncer, shares: shares, cfg: cfg}
+}
+
+// CreateWatchRequest registers a sheet for scheduled re-sync. A linked share
+// is named by its token (not its public slug) and the owner key returned when
+// it was created, since every change overwrites the share's content.
+type CreateWatchRequest struct {
+	URL             string            `json:"url" binding:"required"`
+	GID             string            `json:"gid,omitempty"`
+	Range           string            `json:"range,omitempty"`
+	Template        string            `json:"template"`
+	Format          string            `json:"format"`
+	ColumnOverrides map[string]string `json:"column_overrides,omitempty"`
+	IntervalSeconds int               `json:"interval_seconds,omitempty"`
+	ShareKey        string            `json:"share_key,omitempty"`
+	ShareOwnerKey   string            `json:"share_owner_key,omitempty"`
+}
+
+// UpdateWatchRequest changes the schedule or linked share of a watch
+type UpdateWatchRequest struct {
+	IntervalSeconds *int    `json:"interval_seconds,omitempty"`
+	ShareKey        *string `json:"share_key,omitempty"`
+	ShareOwnerKey   string  `json:"share_owner_key,omitempty"`
+}
+
+// WatchResponse summarizes a watch; version bodies are served by
+// /watches/:id/versions. The linked share's token is never returned, and the
+// owner key only by CreateWatch.
+type WatchResponse struct {
+	ID              string              `json:"id"`
+	SheetURL        string              `json:"sheet_url"`
+	SheetID         string              `json:"sheet_id"`
+	GID             string              `json:"gid,omitempty"`
+	Range           string              `json:"range,omitempty"`
+	Template        string              `json:"template"`
+	Format          string              `json:"format"`
+	ColumnOverrides map[string]string   `json:"column_overrides,omitempty"`
+	IntervalSeconds int                 `json:"interval_seconds"`
+	Shared          bool                `json:"shared"`
+	LastHash        string              `json:"last_hash,omitempty"`
+	LastError       string              `json:"last_error,omitempty"`
+	LastCheckedAt   string              `json:"last_checked_at,omitempty"`
+	LastChangedAt   string              `json:"last_changed_at,omitempty"`
+	NextCheckAt     string              `json:"next_check_at,omitempty"`
+	CreatedAt       string              `json:"created_at"`
+	VersionCount    int                 `json:"version_count"`
+	LatestVersion   *sheetwatch.Version `json:"latest_version,omitempty"`
+	OwnerKey        string              `json:"owner_key,omitempty"` // only in the create response
+}
+
+// WatchCheckResponse is returned by a manual check
+type WatchCheckResponse struct {
+	Watch         WatchResponse       `json:"watch"`
+	Changed       bool                `json:"changed"`
+	Baseline      bool                `json:"baseline"`
+	Version       *sheetwatch.Version `json:"version,omitempty"`
+	ShareRevision int                 `json:"share_revision,omitempty"`
+}
+
+// CreateWatch handles POST /api/v1/mdflow/watches
+// Registers the sheet and immediately records a baseline version. The response
+// carries the owner key the other watch endpoints require; a caller that sends
+// an owner key it already holds adds the watch to the same owner.
+func (h *WatchHandler) CreateWatch(c *gin.Context) {
+	ownerKey := watchOwnerKey(c)
+	if ownerKey != "" && len(ownerKey) < minWatchOwnerKeyLength {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: WatchOwnerKeyHeader + " must be at least 32 characters"})
+		return
+	}
+
+	var req CreateWatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "url is required"})
+		return
+	}
+
+	template, format, err := normalizeTemplateAndFormat(req.Template, req.Format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	sheetID, gid, ok := parseGoogleSheetURL(req.URL)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid Google Sheets URL"})
+		return
+	}
+	gid = selectGID(req.GID, gid)
+	if err := validateGID(gid); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !h.verifyShareOwner(c, req.ShareKey, req.ShareOwnerKey) {
+		return
+	}
+
+	interval := defaultWatchInterval
+	if req.IntervalSeconds > 0 {
+		interval = time.Duration(req.IntervalSeconds) * time.Second
+	} else if interval < h.cfg.WatchMinInterval {
+		interval = h.cfg.WatchMinInterval
+	}
+
+	watch, err := h.store.Create(sheetwatch.CreateWatchInput{
+		SheetURL:        req.URL,
+		SheetID:         sheetID,
+		GID:             gid,
+		Range:           req.Range,
+		Template:        template,
+		Format:          format,
+		ColumnOverrides: req.ColumnOverrides,
+		Interval:        interval,
+		ShareKey:        strings.TrimSpace(req.ShareKey),
+		OwnerKey:        ownerKey,
+	})
+	if err != nil {
+		h.storeError(c, err)
+		return
+	}
+	ownerKey = watch.OwnerKey
+
+	slog.Info("watch.Create", "watch_id", watch.ID, "sheetID", sheetID, "gid", gid, "interval", interval)
+	if result, err := h.syncer.Check(c.Request.Context(), watch.ID); err != nil {
+		slog.Warn("watch.Create baseline failed", "watch_id", watch.ID, "error", err)
+		if result != nil {
+			watch = result.Watch
+		}
+	} else {
+		watch = result.Watch
+	}
+
+	resp := toWatchResponse(watch)
+	resp.OwnerKey = ownerKey
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListWatches handles GET /api/v1/mdflow/watches
+// Lists the watches created with the caller's owner key.
+func (h *WatchHandler) ListWatches(c *gin.Context) {
+	ownerKey := watchOwnerKey(c)
+	if ownerKey == "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: WatchOwnerKeyHeader + " is required"})
+		return
+	}
+	watches := h.store.ListByOwner(ownerKey)
+	items := make([]WatchResponse, 0, len(watches))
+	for _, watch := range watches {
+		items = append(items, toWatchResponse(watch))
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// GetWatch handles GET /api/v1/mdflow/watches/:id
+func (h *WatchHandler) GetWatch(c *gin.Context) {
+	id, ok := h.authorizeWatch(c)
+	if !ok {
+		return
+	}
+	watch, err := h.store.Get(id)
+	if err != nil {
+		h.storeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toWatchResponse(watch))
+}
+
+// ListWatchVersions handles GET /api/v1/mdflow/watches/:id/versions (newest first)
+func (h *WatchHandler) ListWatchVersions(c *gin.Context) {
+	id, ok := h.authorizeWatch(c)
+	if !ok {
+		return
+	}
+	watch, err := h.store.Get(id)
+	if err != nil {
+		h.storeError(c, err)
+		return
+	}
+	versions := make([]sheetwatch.Version, 0, len(watch.Versions))
+	for i := len(watch.Versions) - 1; i >= 0; i-- {
+		versions = append(versions, watch.Versions[i])
+	}
+	c.JSON(http.StatusOK, gin.H{"items": versions})
+}
+
+// UpdateWatch handles PATCH /api/v1/mdflow/watches/:id
+func (h *WatchHandler) UpdateWatch(c *gin.Context) {
+	id, ok := h.authorizeWatch(c)
+	if !ok {
+		return
+	}
+	var req UpdateWatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	input := sheetwatch.UpdateWatchInput{ShareKey: req.ShareKey}
+	if req.IntervalSeconds != nil {
+		interval := time.Duration(*req.IntervalSeconds) * time.Second
+		input.Interval = &interval
+	}
+	if req.ShareKey != nil && !h.verifyShareOwner(c, *req.ShareKey, req.ShareOwnerKey) {
+		return
+	}
+
+	watch, err := h.store.Update(id, input)
+	if err != nil {
+		h.storeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toWatchResponse(watch))
+}
+
+// DeleteWatch handles DELETE /api/v1/mdflow/watches/:id
+func (h *WatchHandler) DeleteWatch(c *gin.Context) {
+	id, ok := h.authorizeWatch(c)
+	if !ok {
+		return
+	}
+	if err := h.store.Delete(id); err != nil {
+		h.storeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// CheckWatch handles POST /api/v1/mdflow/watches/:id/check
+// Runs a sync immediately instead of waiting for the scheduler.
+func (h *WatchHandler) CheckWatch(c *gin.Context) {
+	id, ok := h.authorizeWatch(c)
+	if !ok {
+		return
+	}
+	result, err := h.syncer.Check(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sheetwatch.ErrWatchNotFound) {
+			h.storeError(c, err)
+			return
+		}
+		c.JSON(http.StatusBadGateway, ErrorResponse{Error: "failed to sync watched sheet", Details: map[string]any{"reason": err.Error()}})
+		return
+	}
+
+	c.JSON(http.StatusOK, WatchCheckResponse{
+		Watch:         toWatchResponse(result.Watch),
+		Changed:       result.Changed,
+		Baseline:      result.Baseline,
+		Version:       result.Version,
+		ShareRevision: result.ShareRev,
+	})
+}
+
+// authorizeWatch returns the :id of the request when the caller's owner key
+// owns that watch, writing the error response when not. Watches of other
+// owners are reported as not found so their IDs cannot be probed.
+func (h *WatchHandler) authorizeWatch(c *gin.Context) (string, bool) {
+	id := strings.TrimSpace(c.Param("id"))
+	ownerKey := watchOwnerKey(c)
+	if ownerKey == "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: WatchOwnerKeyHeader + " is required"})
+		return "", false
+	}
+	if err := h.store.VerifyOwner(id, ownerKey); err != nil {
+		h.storeError(c, err)
+		return "", false
+	}
+	return id, true
+}
+
+func watchOwnerKey(c *gin.Context) string {
+	return strings.TrimSpace(c.GetHeader(WatchOwnerKeyHeader))
+}
+
+// verifyShareOwner checks that key is a share token and ownerKey its owner
+// key, writing the error response when not. An empty key links no share.
+func (h *WatchHandler) verifyShareOwner(c *gin.Context, key, ownerKey string) bool {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return true
+	}
+	if h.shares == nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "watches cannot be linked to shares on this server"})
+		return false
+	}
+	switch err := h.shares.VerifyOwner(key, strings.TrimSpace(ownerKey)); {
+	case err == nil:
+		return true
+	case errors.Is(err, share.ErrShareNotFound):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "share_key must be the token of an existing share"})
+	default:
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "share_owner_key does not match the share's owner key"})
+	}
+	return false
+}
+
+func (h *WatchHandler) storeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sheetwatch.ErrWatchNotFound), errors.Is(err, sheetwatch.ErrNotOwner):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "watch not found"})
+	case errors.Is(err, sheetwatch.ErrInvalidInterval):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "interval_seconds is below the minimum of " + h.cfg.WatchMinInterval.String()})
+	case errors.Is(err, sheetwatch.ErrMissingSheetID):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, sheetwatch.ErrStoreFull):
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: "watch limit reached"})
+	default:
+		slog.Error("watch store error", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to persist watch"})
+	}
+}
+
+func toWatchResponse(w *sheetwatch.Watch) WatchResponse {
+	return WatchResponse{
+		ID:              w.ID,
+		SheetURL:        w.SheetURL,
+		SheetID:         w.SheetID,
+		GID:             w.GID,
+		Range:           w.Range,
+		Template:        w.Template,
+		Format:          w.Format,
+		ColumnOverrides: w.ColumnOverrides,
+		IntervalSeconds: int(w.Interval / time.Second),
+		Shared:          w.ShareKey != "",
+		LastHash:        w.LastHash,
+		LastError:       w.LastError,
+		LastCheckedAt:   formatOptionalTime(w.LastCheckedAt),
+		LastChangedAt:   formatOptionalTime(w.LastChangedAt),
+		NextCheckAt:     formatOptionalTime(w.NextCheckAt),
+		CreatedAt:       w.CreatedAt.Format(time.RFC3339),
+		VersionCount:    len(w.Versions),
+		LatestVersion:   w.LatestVersion(),
+	}
+}
+
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
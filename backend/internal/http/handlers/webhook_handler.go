@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/http/middleware"
+	"github.com/yourorg/md-spec-tool/internal/webhook"
+)
+
+// EventPublisher receives integration events (conversion, share, feedback) for outbound webhooks.
+// Publish must not block the request path.
+type EventPublisher interface {
+	Publish(eventType string, data any)
+}
+
+// ConversionEvent is the data payload of conversion.completed and conversion.needs_review
+type ConversionEvent struct {
+	Source       string  `json:"source"` // paste, tsv, xlsx, gsheet, stream
+	Template     string  `json:"template"`
+	Format       string  `json:"format"`
+	SourceURL    string  `json:"source_url,omitempty"`
+	SheetName    string  `json:"sheet_name,omitempty"`
+	TotalRows    int     `json:"total_rows"`
+	WarningCount int     `json:"warning_count"`
+	NeedsReview  bool    `json:"needs_review"`
+	AIModel      string  `json:"ai_model,omitempty"`
+	AIConfidence float64 `json:"ai_confidence,omitempty"`
+	RequestID    string  `json:"request_id,omitempty"`
+	SessionID    string  `json:"session_id,omitempty"`
+}
+
+// publishConversionEvents emits conversion.completed and, when flagged, conversion.needs_review.
+func publishConversionEvents(p EventPublisher, c *gin.Context, source string, resp MDFlowConvertResponse) {
+	if p == nil {
+		return
+	}
+	event := ConversionEvent{
+		Source:       source,
+		Template:     resp.Template,
+		Format:       resp.Format,
+		SourceURL:    resp.Meta.SourceURL,
+		SheetName:    resp.Meta.SheetName,
+		TotalRows:    resp.Meta.TotalRows,
+		WarningCount: len(resp.Warnings),
+		NeedsReview:  resp.NeedsReview,
+		AIModel:      resp.Meta.AIModel,
+		AIConfidence: resp.Meta.AIAvgConfidence,
+		RequestID:    c.Writer.Header().Get(middleware.RequestIDHeader),
+		SessionID:    c.GetString("session_id"),
+	}
+	p.Publish(webhook.EventConversionCompleted, event)
+	if resp.NeedsReview {
+		p.Publish(webhook.EventConversionReview, event)
+	}
+}
+
+// WebhookHandler manages webhook endpoints, delivery logs and dead letters
+type WebhookHandler struct {
+	store      *webhook.Store
+	dispatcher *webhook.Dispatcher
+}
+
+// NewWebhookHandler creates a WebhookHandler
+func NewWebhookHandler(store *webhook.Store, dispatcher *webhook.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{store: store, dispatcher: dispatcher}
+}
+
+// CreateWebhookRequest registers an endpoint for one or more event types ("*" for all)
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events" binding:"required"`
+	Secret      string   `json:"secret,omitempty"` // generated when empty
+	Description string   `json:"description,omitempty"`
+}
+
+// UpdateWebhookRequest pauses or resumes an endpoint
+type UpdateWebhookRequest struct {
+	Active *bool `json:"active"`
+}
+
+// WebhookEndpointResponse describes an endpoint; Secret is only set on creation
+type WebhookEndpointResponse struct {
+	ID          string   `json:"id"`
+	URL         string   `json:"url"`
+	Events      []string `json:"events"`
+	Description string   `json:"description,omitempty"`
+	Active      bool     `json:"active"`
+	Secret      string   `json:"secret,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// CreateWebhook handles POST /api/webhooks
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "url and events are required"})
+		return
+	}
+
+	ep, err := h.store.CreateEndpoint(webhook.CreateEndpointInput{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      req.Events,
+		Description: req.Description,
+	})
+	if err != nil {
+		if errors.Is(err, webhook.ErrInvalidEndpoint) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "url must be http(s) and events must be known event types",
+				Details: map[string]any{"known_events": webhook.KnownEvents},
+			})
+			return
+		}
+		slog.Error("webhook.Create failed", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to create webhook"})
+		return
+	}
+
+	resp := toWebhookEndpointResponse(*ep)
+	resp.Secret = ep.Secret
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListWebhooks handles GET /api/webhooks
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	endpoints, err := h.store.ListEndpoints()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to list webhooks"})
+		return
+	}
+	items := make([]WebhookEndpointResponse, 0, len(endpoints))
+	for _, ep := range endpoints {
+		items = append(items, toWebhookEndpointResponse(ep))
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// UpdateWebhook handles PATCH /api/webhooks/:id
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Active == nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "active is required"})
+		return
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if err := h.store.SetEndpointActive(id, *req.Active); err != nil {
+		h.storeError(c, err)
+		return
+	}
+	ep, err := h.store.GetEndpoint(id)
+	if err != nil {
+		h.storeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toWebhookEndpointResponse(*ep))
+}
+
+// DeleteWebhook handles DELETE /api/webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.store.DeleteEndpoint(strings.TrimSpace(c.Param("id"))); err != nil {
+		h.storeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /api/webhooks/:id/deliveries?limit=50
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if _, err := h.store.GetEndpoint(id); err != nil {
+		h.storeError(c, err)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	deliveries, err := h.store.ListDeliveries(id, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to list deliveries"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": deliveries})
+}
+
+// ListDeadLetters handles GET /api/webhooks/dead-letters?limit=50
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	letters, err := h.store.ListDeadLetters(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to list dead letters"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": letters})
+}
+
+// ReplayDeadLetter handles POST /api/webhooks/dead-letters/:id/replay
+func (h *WebhookHandler) ReplayDeadLetter(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid dead letter id"})
+		return
+	}
+	delivery, err := h.dispatcher.Replay(id)
+	if err != nil {
+		h.storeError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+func (h *WebhookHandler) storeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrEndpointNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "webhook not found"})
+	case errors.Is(err, webhook.ErrDeadLetterNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "dead letter not found"})
+	case errors.Is(err, webhook.ErrDispatcherClosed):
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: "webhook dispatcher is shutting down"})
+	default:
+		slog.Error("webhook store error", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "webhook storage error"})
+	}
+}
+
+func toWebhookEndpointResponse(ep webhook.Endpoint) WebhookEndpointResponse {
+	return WebhookEndpointResponse{
+		ID:          ep.ID,
+		URL:         ep.URL,
+		Events:      ep.Events,
+		Description: ep.Description,
+		Active:      ep.Active,
+		CreatedAt:   ep.CreatedAt.Format(time.RFC3339),
+	}
+}
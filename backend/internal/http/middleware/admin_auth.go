@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireBearerToken admits only requests carrying "Authorization: Bearer <token>".
+// Returns 401 UNAUTHORIZED otherwise. Used for operator endpoints such as
+// webhook management; an empty token rejects every request.
+func RequireBearerToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(got)), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="mdflow-admin"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, NewErrorPayload(http.StatusUnauthorized,
+				"missing or invalid admin token",
+				GetRequestID(c),
+			))
+			return
+		}
+		c.Next()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/telemetry"
 )
 
 // APITelemetryEvents emits structured event logs for key MVP funnel endpoints.
@@ -34,9 +35,10 @@ func APITelemetryEvents() gin.HandlerFunc {
 			"path", c.Request.URL.Path,
 		)
 
-		event := TelemetryEvent{
+		event := telemetry.Event{
 			EventName:   eventName,
 			EventTime:   time.Now().UTC(),
+			SessionID:   c.GetString("session_id"),
 			Status:      status,
 			InputSource: inputSource,
 			DurationMS:  time.Since(startedAt).Milliseconds(),
@@ -46,7 +48,10 @@ func APITelemetryEvents() gin.HandlerFunc {
 			Source:      "backend",
 		}
 
-		// Attach AI metadata if set by handler (e.g. convert_handler.recordTokenUsage)
+		// Attach template and AI metadata if set by handler (e.g. convert_handler.recordTokenUsage)
+		if template, ok := c.Get("template_type"); ok {
+			event.TemplateType, _ = template.(string)
+		}
 		if model, ok := c.Get("ai_model"); ok {
 			event.AIModel, _ = model.(string)
 		}
@@ -78,6 +83,12 @@ func eventMetadata(method, fullPath, rawPath string) (eventName string, inputSou
 	case "/api/mdflow/preview", "/api/mdflow/tsv/preview", "/api/mdflow/xlsx/preview",
 		"/api/v1/mdflow/preview", "/api/v1/mdflow/tsv/preview", "/api/v1/mdflow/xlsx/preview":
 		return "api_preview_completed", previewInputSource(path), true
+	case "/api/mdflow/gsheet/preview", "/api/v1/mdflow/gsheet/preview":
+		return "api_preview_completed", "gsheet", true
+	case "/api/mdflow/gsheet/convert", "/api/v1/mdflow/gsheet/convert":
+		return "api_convert_completed", "gsheet", true
+	case "/api/v1/mdflow/convert/stream":
+		return "api_convert_completed", "stream", true
 	case "/api/mdflow/paste", "/api/mdflow/tsv", "/api/mdflow/xlsx",
 		"/api/v1/mdflow/paste", "/api/v1/mdflow/tsv", "/api/v1/mdflow/xlsx":
 		return "api_convert_completed", convertInputSource(path), true
@@ -5,10 +5,12 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/yourorg/md-spec-tool/internal/metrics"
 )
 
-// Metrics holds simple request metrics for observability.
-// Lightweight alternative to Prometheus for basic monitoring.
+// Metrics holds simple request metrics for the JSON summary (/metrics).
+// Per-route Prometheus histograms are recorded alongside by MetricsMiddleware.
 type Metrics struct {
 	totalRequests atomic.Uint64
 	totalLatency  atomic.Uint64 // sum of request durations in milliseconds
@@ -17,13 +19,20 @@ type Metrics struct {
 var defaultMetrics = &Metrics{}
 
 // MetricsMiddleware records request count and latency.
+// Unmatched paths share the "unmatched" route label to keep cardinality bounded.
 func MetricsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		c.Next()
-		duration := time.Since(start).Milliseconds()
+		elapsed := time.Since(start)
 		defaultMetrics.totalRequests.Add(1)
-		defaultMetrics.totalLatency.Add(uint64(duration))
+		defaultMetrics.totalLatency.Add(uint64(elapsed.Milliseconds()))
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), elapsed)
 	}
 }
 
@@ -7,6 +7,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/yourorg/md-spec-tool/internal/metrics"
 )
 
 // QuotaUsage represents quota usage details
@@ -59,6 +61,7 @@ func QuotaMiddleware(checker QuotaChecker) gin.HandlerFunc {
 			slog.Info("quota exceeded",
 				"session_id", sessionID,
 			)
+			metrics.IncQuotaRejection(metrics.QuotaKindDaily)
 
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, ErrorPayload{
 				Error:     "daily quota exceeded",
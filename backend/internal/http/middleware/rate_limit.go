@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/yourorg/md-spec-tool/internal/metrics"
 )
 
 type rateLimitEntry struct {
@@ -79,6 +81,7 @@ func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
 				RetryAfter: retryAfter,
 			}
 
+			metrics.IncRateLimitRejection(c.FullPath())
 			c.Header("Retry-After", strconv.Itoa(retryAfter))
 			c.Error(err)
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, NewErrorPayload(http.StatusTooManyRequests,
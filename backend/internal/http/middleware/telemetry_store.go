@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/yourorg/md-spec-tool/internal/telemetry"
 )
 
 // MaxTelemetryEvents is the maximum number of events kept in the rolling buffer.
@@ -16,50 +19,172 @@ func SetMaxTelemetryEvents(n int) {
 	}
 }
 
-// TelemetryEvent is a normalized event payload used by the MVP dashboard.
-type TelemetryEvent struct {
-	EventName       string    `json:"event_name"`
-	EventTime       time.Time `json:"event_time"`
-	SessionID       string    `json:"session_id,omitempty"`
-	Status          string    `json:"status"`
-	InputSource     string    `json:"input_source,omitempty"`
-	TemplateType    string    `json:"template_type,omitempty"`
-	DurationMS      int64     `json:"duration_ms,omitempty"`
-	ErrorCode       string    `json:"error_code,omitempty"`
-	WarningCount    int       `json:"warning_count,omitempty"`
-	ConfidenceScore float64   `json:"confidence_score,omitempty"`
-	NeedsReview     bool      `json:"needs_review,omitempty"`
-	TotalRows            int       `json:"total_rows,omitempty"`
-	AIModel              string    `json:"ai_model,omitempty"`
-	AIEstimatedCostUSD   float64   `json:"ai_estimated_cost_usd,omitempty"`
-	AIInputTokens        int64     `json:"ai_input_tokens,omitempty"`
-	AIOutputTokens       int64     `json:"ai_output_tokens,omitempty"`
-	HTTPStatus           int       `json:"http_status,omitempty"`
-	Path            string    `json:"path,omitempty"`
-	RequestID       string    `json:"request_id,omitempty"`
-	Source          string    `json:"source"` // frontend | backend
+// TelemetryBackend persists events beyond the in-memory buffer so history survives restarts.
+type TelemetryBackend interface {
+	Record(event telemetry.Event) error
+	Query(q telemetry.Query) ([]telemetry.Event, error)
+}
+
+// TelemetrySeriesBackend is a TelemetryBackend that aggregates the dashboard
+// series itself instead of returning every event in the window.
+type TelemetrySeriesBackend interface {
+	TelemetryBackend
+	Series(q telemetry.Query, bucket time.Duration) ([]telemetry.SeriesBucket, error)
+}
+
+// telemetryWriteQueueSize bounds the events waiting for the backend writer;
+// events beyond it are dropped from the backend but kept in memory.
+const telemetryWriteQueueSize = 1024
+
+var (
+	telemetryBackendMu sync.RWMutex
+	telemetryBackend   TelemetryBackend
+	telemetryWriter    *backendWriter
+)
+
+// backendWriter drains queued events into a backend on its own goroutine, so
+// requests never wait on backend storage.
+type backendWriter struct {
+	backend TelemetryBackend
+	queue   chan backendWrite
+	done    chan struct{}
+}
+
+// backendWrite is a queued event, or a flush marker when flushed is set.
+type backendWrite struct {
+	event   telemetry.Event
+	flushed chan struct{}
+}
+
+func newBackendWriter(backend TelemetryBackend) *backendWriter {
+	w := &backendWriter{
+		backend: backend,
+		queue:   make(chan backendWrite, telemetryWriteQueueSize),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *backendWriter) run() {
+	defer close(w.done)
+	for item := range w.queue {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		if err := w.backend.Record(item.event); err != nil {
+			slog.Warn("telemetry backend record failed", "event_name", item.event.EventName, "error", err)
+		}
+	}
+}
+
+// stop writes the queued events and waits for the writer to exit.
+func (w *backendWriter) stop() {
+	close(w.queue)
+	<-w.done
+}
+
+// SetTelemetryBackend installs a durable backend (nil restores memory-only mode).
+// Events are still mirrored into the rolling buffer, which serves queries if the backend fails.
+// Events queued for the previous backend are written before it is replaced.
+func SetTelemetryBackend(b TelemetryBackend) {
+	telemetryBackendMu.Lock()
+	defer telemetryBackendMu.Unlock()
+	if telemetryWriter != nil {
+		telemetryWriter.stop()
+		telemetryWriter = nil
+	}
+	telemetryBackend = b
+	if b != nil {
+		telemetryWriter = newBackendWriter(b)
+	}
+}
+
+// FlushTelemetryEvents waits until events recorded so far have been written
+// to the durable backend, if any.
+func FlushTelemetryEvents() {
+	telemetryBackendMu.RLock()
+	writer := telemetryWriter
+	if writer == nil {
+		telemetryBackendMu.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	writer.queue <- backendWrite{flushed: flushed}
+	telemetryBackendMu.RUnlock()
+	<-flushed
+}
+
+func currentTelemetryBackend() TelemetryBackend {
+	telemetryBackendMu.RLock()
+	defer telemetryBackendMu.RUnlock()
+	return telemetryBackend
 }
 
 type telemetryStore struct {
 	mu     sync.RWMutex
-	events []TelemetryEvent
+	events []telemetry.Event
 }
 
 var defaultTelemetryStore = &telemetryStore{
-	events: make([]TelemetryEvent, 0, maxTelemetryEvents),
+	events: make([]telemetry.Event, 0, maxTelemetryEvents),
 }
 
-// RecordTelemetryEvent appends an event to the in-memory rolling store.
-func RecordTelemetryEvent(event TelemetryEvent) {
+// RecordTelemetryEvent appends an event to the in-memory rolling store and
+// queues it for the durable backend, if any. It never blocks on the backend:
+// when the queue is full the event is kept in memory only.
+func RecordTelemetryEvent(event telemetry.Event) {
+	if event.EventTime.IsZero() {
+		event.EventTime = time.Now().UTC()
+	}
 	defaultTelemetryStore.record(event)
+
+	telemetryBackendMu.RLock()
+	defer telemetryBackendMu.RUnlock()
+	if telemetryWriter == nil {
+		return
+	}
+	select {
+	case telemetryWriter.queue <- backendWrite{event: event}:
+	default:
+		slog.Warn("telemetry backend queue full; event kept in memory only", "event_name", event.EventName)
+	}
 }
 
 // SnapshotTelemetryEvents returns events after `since`.
-func SnapshotTelemetryEvents(since time.Time) []TelemetryEvent {
-	return defaultTelemetryStore.snapshot(since)
+func SnapshotTelemetryEvents(since time.Time) []telemetry.Event {
+	return QueryTelemetryEvents(telemetry.Query{Since: since})
+}
+
+// QueryTelemetryEvents returns events matching q in chronological order.
+func QueryTelemetryEvents(q telemetry.Query) []telemetry.Event {
+	if backend := currentTelemetryBackend(); backend != nil {
+		events, err := backend.Query(q)
+		if err == nil {
+			return events
+		}
+		slog.Warn("telemetry backend query failed; using in-memory buffer", "error", err)
+	}
+	return defaultTelemetryStore.query(q)
+}
+
+// QueryTelemetrySeries aggregates events matching q into buckets of the given
+// width (oldest first), skipping empty buckets. A TelemetrySeriesBackend does
+// the aggregation in storage; otherwise events are bucketed in memory.
+func QueryTelemetrySeries(q telemetry.Query, bucket time.Duration) []telemetry.SeriesBucket {
+	backend := currentTelemetryBackend()
+	if sb, ok := backend.(TelemetrySeriesBackend); ok {
+		series, err := sb.Series(q, bucket)
+		if err == nil {
+			return series
+		}
+		slog.Warn("telemetry backend series failed; bucketing events in memory", "error", err)
+	}
+	return telemetry.BucketEvents(QueryTelemetryEvents(q), bucket)
 }
 
-func (s *telemetryStore) record(event TelemetryEvent) {
+func (s *telemetryStore) record(event telemetry.Event) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -76,7 +201,7 @@ func (s *telemetryStore) record(event TelemetryEvent) {
 	s.events = append(s.events, event)
 }
 
-func (s *telemetryStore) snapshot(since time.Time) []TelemetryEvent {
+func (s *telemetryStore) query(q telemetry.Query) []telemetry.Event {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -84,9 +209,9 @@ func (s *telemetryStore) snapshot(since time.Time) []TelemetryEvent {
 		return nil
 	}
 
-	result := make([]TelemetryEvent, 0, len(s.events))
+	result := make([]telemetry.Event, 0, len(s.events))
 	for _, e := range s.events {
-		if !since.IsZero() && e.EventTime.Before(since) {
+		if !q.Matches(e) {
 			continue
 		}
 		result = append(result, e)
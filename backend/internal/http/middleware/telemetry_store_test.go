@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourorg/md-spec-tool/internal/telemetry"
+)
+
+// blockingBackend records events only after release is closed.
+type blockingBackend struct {
+	release chan struct{}
+	mu      sync.Mutex
+	events  []telemetry.Event
+}
+
+func (b *blockingBackend) Record(event telemetry.Event) error {
+	<-b.release
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, event)
+	return nil
+}
+
+func (b *blockingBackend) Query(telemetry.Query) ([]telemetry.Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]telemetry.Event(nil), b.events...), nil
+}
+
+func TestRecordTelemetryEvent_DoesNotWaitOnBackend(t *testing.T) {
+	backend := &blockingBackend{release: make(chan struct{})}
+	SetTelemetryBackend(backend)
+	t.Cleanup(func() { SetTelemetryBackend(nil) })
+
+	done := make(chan struct{})
+	go func() {
+		// More events than the queue holds: the overflow is dropped, not waited on.
+		for i := 0; i < telemetryWriteQueueSize+10; i++ {
+			RecordTelemetryEvent(telemetry.Event{EventName: "api_convert_completed", Source: "backend"})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RecordTelemetryEvent blocked on a stalled backend")
+	}
+
+	close(backend.release)
+	FlushTelemetryEvents()
+	got, _ := backend.Query(telemetry.Query{})
+	if len(got) < telemetryWriteQueueSize || len(got) > telemetryWriteQueueSize+1 {
+		t.Errorf("backend recorded %d events, want the %d queued", len(got), telemetryWriteQueueSize)
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/yourorg/md-spec-tool/internal/telemetry"
+)
+
+// TelemetrySummaryBackend is a TelemetryBackend that aggregates the dashboard
+// totals, funnels and KPIs itself instead of returning every event in the window.
+type TelemetrySummaryBackend interface {
+	TelemetryBackend
+	Summary(q telemetry.Query) (telemetry.Summary, error)
+}
+
+// QueryTelemetrySummary aggregates the events matching q. A
+// TelemetrySummaryBackend does the aggregation in storage; otherwise, or when
+// it fails, the events of the bounded in-memory buffer are summarized.
+func QueryTelemetrySummary(q telemetry.Query) telemetry.Summary {
+	backend := currentTelemetryBackend()
+	if sb, ok := backend.(TelemetrySummaryBackend); ok {
+		summary, err := sb.Summary(q)
+		if err == nil {
+			return summary
+		}
+		slog.Warn("telemetry backend summary failed; summarizing in-memory buffer", "error", err)
+		return telemetry.SummarizeEvents(defaultTelemetryStore.query(q.Window()), q)
+	}
+	return telemetry.SummarizeEvents(QueryTelemetryEvents(q.Window()), q)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,8 +14,12 @@ import (
 	"github.com/yourorg/md-spec-tool/internal/feedback"
 	"github.com/yourorg/md-spec-tool/internal/http/handlers"
 	"github.com/yourorg/md-spec-tool/internal/http/middleware"
+	"github.com/yourorg/md-spec-tool/internal/metrics"
 	"github.com/yourorg/md-spec-tool/internal/share"
+	"github.com/yourorg/md-spec-tool/internal/sheetwatch"
 	"github.com/yourorg/md-spec-tool/internal/suggest"
+	"github.com/yourorg/md-spec-tool/internal/telemetry"
+	"github.com/yourorg/md-spec-tool/internal/webhook"
 )
 
 // SetupRouterWithCleanup sets up the router and returns a cleanup function for graceful shutdown.
@@ -44,6 +49,7 @@ func setupRouterInternal(cfg *config.Config, withCleanup bool) (*gin.Engine, fun
 	// Apply middlewares (order matters: CORS first, then RequestID, metrics, then error handler)
 	router.Use(middleware.CORS(cfg))
 	router.Use(middleware.RequestID())
+	router.Use(middleware.Tracing())
 	router.Use(middleware.SessionID()) // Add session ID middleware
 	router.Use(middleware.MetricsMiddleware())
 	router.Use(middleware.APITelemetryEvents())
@@ -51,9 +57,24 @@ func setupRouterInternal(cfg *config.Config, withCleanup bool) (*gin.Engine, fun
 
 	// Public routes
 	router.GET("/health", handlers.HealthHandler)
+	readinessHandler := handlers.NewReadinessHandler()
+	router.GET("/health/live", readinessHandler.Live)
+	router.GET("/health/ready", readinessHandler.Ready)
 	router.GET("/metrics", handlers.MetricsHandler)
+	router.GET("/metrics/prometheus", handlers.PrometheusHandler)
+	// Durable telemetry history is process-global, so it is only installed for servers that run cleanup.
+	var telemetryStore *telemetry.Store
+	if withCleanup && cfg.TelemetryDBPath != "" {
+		store, err := telemetry.NewStore(cfg.TelemetryDBPath, cfg.TelemetryRetention, cfg.TelemetryMaxRows)
+		if err != nil {
+			slog.Warn("telemetry store initialization failed; dashboard history will be in-memory only", "error", err)
+		} else {
+			telemetryStore = store
+			middleware.SetTelemetryBackend(telemetryStore)
+		}
+	}
 	telemetryHandler := handlers.NewTelemetryHandler()
-	router.POST("/api/telemetry/events", telemetryHandler.IngestEvents)
+	router.POST("/api/telemetry/events", middleware.RateLimit(cfg.TelemetryRateLimit, cfg.RateLimitWindow), telemetryHandler.IngestEvents)
 	router.GET("/api/telemetry/dashboard", telemetryHandler.Dashboard)
 
 	// Create quota handler (will be injected into handlers below)
@@ -106,6 +127,14 @@ func setupRouterInternal(cfg *config.Config, withCleanup bool) (*gin.Engine, fun
 	previewAIService := buildAIService(previewModel, cfg.AIPreviewTimeout, cfg.AIPreviewMaxTokens)
 	suggestAIService := buildAIService(suggestModel, cfg.AISuggestTimeout, cfg.AISuggestMaxTokens)
 
+	// Circuit breaker, budget and cache state is read from these services on each /metrics scrape.
+	aiServices := map[string]ai.Service{
+		"convert": convertAIService,
+		"preview": previewAIService,
+		"suggest": suggestAIService,
+	}
+	metrics.SetAIStateSource(aiMetricsSource(aiServices))
+
 	convForConvert := converter.NewConverter().WithAIService(convertAIService)
 	convForPreview := converter.NewConverter().WithAIService(previewAIService)
 
@@ -150,11 +179,33 @@ func setupRouterInternal(cfg *config.Config, withCleanup bool) (*gin.Engine, fun
 	)
 
 	audioHandler := handlers.NewAudioTranscribeHandler(cfg)
+	transcriptSpecHandler := handlers.NewTranscriptSpecHandler(aiProvider, cfg)
 
 	// Create share handler and store (needed early for clone-template route)
 	shareStore := share.NewStore(cfg.ShareStorePath)
 	shareHandler := handlers.NewShareHandler(shareStore)
 
+	// Watched sheets: scheduled re-sync with change detection. The store and poll loop
+	// live until cleanup, so only servers that run it get them.
+	var watchStore *sheetwatch.Store
+	var watchSyncer *sheetwatch.Syncer
+	var watchHandler *handlers.WatchHandler
+	if withCleanup {
+		store, err := sheetwatch.NewStore(cfg.WatchDBPath, cfg.WatchMinInterval, cfg.WatchMaxWatches)
+		if err != nil {
+			slog.Warn("watch store initialization failed; watches will be kept in memory only", "error", err)
+			if store, err = sheetwatch.NewStore("", cfg.WatchMinInterval, cfg.WatchMaxWatches); err != nil {
+				slog.Error("in-memory watch store initialization failed; watch endpoints will be unavailable", "error", err)
+			}
+		}
+		if store != nil {
+			watchStore = store
+			watchSyncer = sheetwatch.NewSyncer(watchStore, gsheetHandler, convForConvert, shareStore)
+			watchHandler = handlers.NewWatchHandler(watchStore, watchSyncer, shareStore, cfg)
+			watchSyncer.Start(cfg.WatchPollInterval)
+		}
+	}
+
 	// Create feedback store and handler (Phase 6.3: Feedback System)
 	var feedbackHandler *handlers.FeedbackHandler
 	feedbackStore, err := feedback.NewStore(cfg.FeedbackDBPath)
@@ -164,8 +215,49 @@ func setupRouterInternal(cfg *config.Config, withCleanup bool) (*gin.Engine, fun
 		feedbackHandler = handlers.NewFeedbackHandler(feedbackStore)
 	}
 
+	// Readiness probes: feedback and share storage are required, the rest only degrade features
+	readinessHandler.AddProbe(handlers.FeedbackDBProbe(feedbackStore, err))
+	readinessHandler.AddProbe(handlers.ShareStoreProbe(shareStore))
+	readinessHandler.AddProbe(handlers.AICacheDBProbe(convertAIService))
+	readinessHandler.AddProbe(handlers.AIServiceProbe(aiServices))
+	readinessHandler.AddProbe(handlers.AIBudgetProbe(aiServices))
+	readinessHandler.AddProbe(handlers.GoogleCredentialsProbe())
+
+	// Outbound webhooks: signed event deliveries with retries and a dead-letter table.
+	// Endpoints can only be registered through the admin routes, so without an admin token
+	// there is nothing to deliver to. The store and delivery workers live until cleanup,
+	// so only servers that run it get them.
+	var webhookHandler *handlers.WebhookHandler
+	var webhookDispatcher *webhook.Dispatcher
+	var webhookStore *webhook.Store
+	if withCleanup && cfg.WebhookAdminToken != "" {
+		store, err := webhook.NewStore(cfg.WebhookDBPath)
+		if err != nil {
+			slog.Warn("webhook store initialization failed; webhook endpoints will be unavailable", "error", err)
+		} else {
+			webhookStore = store
+			webhookDispatcher = webhook.NewDispatcher(webhookStore, httpClient, webhook.Config{
+				MaxAttempts: cfg.WebhookMaxAttempts,
+				BaseDelay:   cfg.WebhookRetryBaseDelay,
+				Timeout:     cfg.WebhookTimeout,
+			})
+			webhookHandler = handlers.NewWebhookHandler(webhookStore, webhookDispatcher)
+			convertHandler.SetEventPublisher(webhookDispatcher)
+			streamHandler.SetEventPublisher(webhookDispatcher)
+			gsheetHandler.SetEventPublisher(webhookDispatcher)
+			shareHandler.SetEventPublisher(webhookDispatcher)
+			if watchSyncer != nil {
+				watchSyncer.SetEventPublisher(webhookDispatcher)
+			}
+			if feedbackHandler != nil {
+				feedbackHandler.SetEventPublisher(webhookDispatcher)
+			}
+		}
+	}
+
 	// Create diff handler (always created; supports BYOK even when no server AI key)
 	diffHandler := handlers.NewDiffHandler(aiProvider, cfg)
+	traceHandler := handlers.NewTraceHandler(convForConvert, cfg)
 	if suggestAIService != nil {
 		aiSuggester := suggest.NewSuggester(suggestAIService)
 		mdflowHandler.SetAISuggester(aiSuggester)
@@ -206,12 +298,26 @@ func setupRouterInternal(cfg *config.Config, withCleanup bool) (*gin.Engine, fun
 
 		// Other routes (diff, gsheet, suggestions)
 		v1.POST("/diff", quotaCheck, diffHandler.DiffMDFlow)
+		v1.POST("/trace", convertRateLimit, quotaCheck, traceHandler.TracePaste)
+		v1.POST("/xlsx/trace", convertRateLimit, quotaCheck, traceHandler.TraceXLSX)
 		v1.POST("/gsheet", gsheetHandler.FetchGoogleSheet)
 		v1.POST("/gsheet/sheets", gsheetHandler.GetGoogleSheetSheets)
 		v1.POST("/gsheet/preview", previewRateLimit, quotaCheck, gsheetHandler.PreviewGoogleSheet)
 		v1.POST("/gsheet/convert", convertRateLimit, quotaCheck, gsheetHandler.ConvertGoogleSheet)
+		v1.POST("/gsheet/writeback", convertRateLimit, quotaCheck, gsheetHandler.WriteBackGoogleSheet)
 		v1.POST("/ai/suggest", aiSuggestRateLimit, quotaCheck, mdflowHandler.GetAISuggestions)
 
+		// Watched sheets (change detection and scheduled re-sync)
+		if watchHandler != nil {
+			v1.POST("/watches", convertRateLimit, watchHandler.CreateWatch)
+			v1.GET("/watches", watchHandler.ListWatches)
+			v1.GET("/watches/:id", watchHandler.GetWatch)
+			v1.PATCH("/watches/:id", watchHandler.UpdateWatch)
+			v1.DELETE("/watches/:id", watchHandler.DeleteWatch)
+			v1.GET("/watches/:id/versions", watchHandler.ListWatchVersions)
+			v1.POST("/watches/:id/check", convertRateLimit, watchHandler.CheckWatch)
+		}
+
 		// Feedback endpoints (Phase 6.3: Feedback System)
 		if feedbackHandler != nil {
 			v1.POST("/feedback", feedbackHandler.SubmitFeedback)
@@ -245,11 +351,25 @@ func setupRouterInternal(cfg *config.Config, withCleanup bool) (*gin.Engine, fun
 
 		// Other routes
 		mdflow.POST("/diff", quotaCheck, diffHandler.DiffMDFlow)
+		mdflow.POST("/trace", convertRateLimit, quotaCheck, traceHandler.TracePaste)
+		mdflow.POST("/xlsx/trace", convertRateLimit, quotaCheck, traceHandler.TraceXLSX)
 		mdflow.POST("/gsheet", gsheetHandler.FetchGoogleSheet)
 		mdflow.POST("/gsheet/sheets", gsheetHandler.GetGoogleSheetSheets)
 		mdflow.POST("/gsheet/preview", previewRateLimit, quotaCheck, gsheetHandler.PreviewGoogleSheet)
 		mdflow.POST("/gsheet/convert", convertRateLimit, quotaCheck, gsheetHandler.ConvertGoogleSheet)
+		mdflow.POST("/gsheet/writeback", convertRateLimit, quotaCheck, gsheetHandler.WriteBackGoogleSheet)
 		mdflow.POST("/ai/suggest", aiSuggestRateLimit, quotaCheck, mdflowHandler.GetAISuggestions)
+
+		// Watched sheets
+		if watchHandler != nil {
+			mdflow.POST("/watches", convertRateLimit, watchHandler.CreateWatch)
+			mdflow.GET("/watches", watchHandler.ListWatches)
+			mdflow.GET("/watches/:id", watchHandler.GetWatch)
+			mdflow.PATCH("/watches/:id", watchHandler.UpdateWatch)
+			mdflow.DELETE("/watches/:id", watchHandler.DeleteWatch)
+			mdflow.GET("/watches/:id/versions", watchHandler.ListWatchVersions)
+			mdflow.POST("/watches/:id/check", convertRateLimit, watchHandler.CheckWatch)
+		}
 	}
 
 	shareRoutes := router.Group("/api/share")
@@ -267,10 +387,28 @@ func setupRouterInternal(cfg *config.Config, withCleanup bool) (*gin.Engine, fun
 	audio := router.Group("/api/audio")
 	{
 		audio.POST("/transcribe", audioHandler.Transcribe)
+		audio.POST("/spec", aiSuggestRateLimit, quotaCheck, transcriptSpecHandler.DraftSpec)
+	}
+
+	// Webhook management is operator-only: registered when an admin token is configured
+	if webhookHandler != nil {
+		webhooks := router.Group("/api/webhooks", middleware.RequireBearerToken(cfg.WebhookAdminToken))
+		{
+			webhooks.POST("", webhookHandler.CreateWebhook)
+			webhooks.GET("", webhookHandler.ListWebhooks)
+			webhooks.GET("/dead-letters", webhookHandler.ListDeadLetters)
+			webhooks.POST("/dead-letters/:id/replay", webhookHandler.ReplayDeadLetter)
+			webhooks.PATCH("/:id", webhookHandler.UpdateWebhook)
+			webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+			webhooks.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+		}
 	}
 
 	// Return cleanup function that closes all handlers with lifecycle management
 	cleanup := func() {
+		if watchSyncer != nil {
+			watchSyncer.Stop()
+		}
 		if aiProvider != nil {
 			aiProvider.Close()
 		}
@@ -286,8 +424,81 @@ func setupRouterInternal(cfg *config.Config, withCleanup bool) (*gin.Engine, fun
 				slog.Warn("feedback store close error", "error", err)
 			}
 		}
+		if webhookDispatcher != nil {
+			webhookDispatcher.Close()
+		}
+		metrics.SetAIStateSource(nil)
+		if telemetryStore != nil {
+			middleware.SetTelemetryBackend(nil)
+			if err := telemetryStore.Close(); err != nil {
+				slog.Warn("telemetry store close error", "error", err)
+			}
+		}
+		if webhookStore != nil {
+			if err := webhookStore.Close(); err != nil {
+				slog.Warn("webhook store close error", "error", err)
+			}
+		}
+		if watchStore != nil {
+			if err := watchStore.Close(); err != nil {
+				slog.Warn("watch store close error", "error", err)
+			}
+		}
 		slog.Debug("All handlers closed successfully")
 	}
 
 	return router, cleanup
 }
+
+// SetupShareRouter serves the .mdflow.md files of store as read-only shares,
+// with health checks and the share read endpoints only: no conversion, AI,
+// storage or background work.
+func SetupShareRouter(cfg *config.Config, store *share.DirStore) *gin.Engine {
+	router := gin.Default()
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		slog.Error("Failed to set trusted proxies", "error", err)
+	}
+
+	router.Use(middleware.CORS(cfg))
+	router.Use(middleware.RequestID())
+	router.Use(middleware.ErrorHandler())
+
+	router.GET("/health", handlers.HealthHandler)
+	readinessHandler := handlers.NewReadinessHandler()
+	readinessHandler.AddProbe(handlers.ShareDirProbe(store))
+	router.GET("/health/live", readinessHandler.Live)
+	router.GET("/health/ready", readinessHandler.Ready)
+
+	shareHandler := handlers.NewShareHandler(store)
+	shareRoutes := router.Group("/api/share")
+	{
+		shareRoutes.GET("/public", shareHandler.ListPublic)
+		shareRoutes.GET("/:key", shareHandler.GetShare)
+		shareRoutes.GET("/:key/comments", shareHandler.ListComments)
+		shareRoutes.GET("/:key/events", shareHandler.GetShareEvents)
+	}
+
+	return router
+}
+
+// aiMetricsSource reports the state of each configured AI service, keyed by role.
+func aiMetricsSource(services map[string]ai.Service) metrics.AIStateSource {
+	roles := make([]string, 0, len(services))
+	for role := range services {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return func() []metrics.AIServiceState {
+		var states []metrics.AIServiceState
+		for _, role := range roles {
+			impl, ok := services[role].(*ai.ServiceImpl)
+			if !ok || impl == nil {
+				continue
+			}
+			state := impl.MetricsState()
+			state.Service = role
+			states = append(states, state)
+		}
+		return states
+	}
+}
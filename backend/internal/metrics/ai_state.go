@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Circuit breaker states reported by mdflow_ai_circuit_breaker_state.
+var CircuitStates = []string{"closed", "half_open", "open"}
+
+// AIServiceState is a point-in-time view of one AI service, collected at scrape time.
+type AIServiceState struct {
+	Service      string // role of the service, e.g. "convert", "preview", "suggest"
+	Model        string
+	CircuitState string // one of CircuitStates
+
+	BudgetSpentUSD float64
+	BudgetLimitUSD float64 // 0 = unlimited
+
+	CacheHitsByLevel map[string]int64 // "L1", "L2", ...
+	CacheMisses      int64
+}
+
+// AIStateSource returns the current state of every AI service.
+type AIStateSource func() []AIServiceState
+
+var (
+	aiStateMu     sync.RWMutex
+	aiStateSource AIStateSource
+)
+
+// SetAIStateSource installs the function read on every scrape. Pass nil to remove it.
+func SetAIStateSource(src AIStateSource) {
+	aiStateMu.Lock()
+	defer aiStateMu.Unlock()
+	aiStateSource = src
+}
+
+var (
+	circuitStateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ai", "circuit_breaker_state"),
+		"AI circuit breaker state; 1 for the current state, 0 otherwise.",
+		[]string{"service", "model", "state"}, nil,
+	)
+	budgetSpentDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ai", "budget_spent_usd"),
+		"AI spend in the current budget period.",
+		[]string{"service", "model"}, nil,
+	)
+	budgetLimitDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ai", "budget_limit_usd"),
+		"AI budget per period (0 = unlimited).",
+		[]string{"service", "model"}, nil,
+	)
+	budgetUtilizationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ai", "budget_utilization_ratio"),
+		"Fraction of the AI budget spent in the current period.",
+		[]string{"service", "model"}, nil,
+	)
+	cacheHitsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ai", "cache_hits_total"),
+		"AI cache hits by level.",
+		[]string{"service", "model", "level"}, nil,
+	)
+	cacheMissesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ai", "cache_misses_total"),
+		"AI cache lookups that missed every level.",
+		[]string{"service", "model"}, nil,
+	)
+	cacheHitRatioDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ai", "cache_hit_ratio"),
+		"Fraction of AI cache lookups served by each level.",
+		[]string{"service", "model", "level"}, nil,
+	)
+)
+
+// aiStateCollector reads AIServiceState from the installed source on each scrape.
+type aiStateCollector struct{}
+
+func (aiStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		circuitStateDesc, budgetSpentDesc, budgetLimitDesc, budgetUtilizationDesc,
+		cacheHitsDesc, cacheMissesDesc, cacheHitRatioDesc,
+	} {
+		ch <- d
+	}
+}
+
+func (aiStateCollector) Collect(ch chan<- prometheus.Metric) {
+	aiStateMu.RLock()
+	src := aiStateSource
+	aiStateMu.RUnlock()
+	if src == nil {
+		return
+	}
+
+	for _, st := range src() {
+		labels := []string{st.Service, st.Model}
+
+		for _, state := range CircuitStates {
+			value := 0.0
+			if state == st.CircuitState {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(circuitStateDesc, prometheus.GaugeValue, value, st.Service, st.Model, state)
+		}
+
+		ch <- prometheus.MustNewConstMetric(budgetSpentDesc, prometheus.GaugeValue, st.BudgetSpentUSD, labels...)
+		ch <- prometheus.MustNewConstMetric(budgetLimitDesc, prometheus.GaugeValue, st.BudgetLimitUSD, labels...)
+		utilization := 0.0
+		if st.BudgetLimitUSD > 0 {
+			utilization = st.BudgetSpentUSD / st.BudgetLimitUSD
+		}
+		ch <- prometheus.MustNewConstMetric(budgetUtilizationDesc, prometheus.GaugeValue, utilization, labels...)
+
+		lookups := st.CacheMisses
+		for _, hits := range st.CacheHitsByLevel {
+			lookups += hits
+		}
+		levels := make([]string, 0, len(st.CacheHitsByLevel))
+		for level := range st.CacheHitsByLevel {
+			levels = append(levels, level)
+		}
+		sort.Strings(levels)
+		for _, level := range levels {
+			hits := st.CacheHitsByLevel[level]
+			ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(hits), st.Service, st.Model, level)
+			ratio := 0.0
+			if lookups > 0 {
+				ratio = float64(hits) / float64(lookups)
+			}
+			ch <- prometheus.MustNewConstMetric(cacheHitRatioDesc, prometheus.GaugeValue, ratio, st.Service, st.Model, level)
+		}
+		ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(st.CacheMisses), labels...)
+	}
+}
@@ -0,0 +1,156 @@
+// Package metrics exposes Prometheus collectors for the HTTP API and AI pipeline.
+//
+// Event-style metrics (requests, AI calls, rejections) are observed as they happen
+// through the Observe*/Inc* helpers. State-style metrics (circuit breaker, budget,
+// cache hit ratios) are read from the registered AI state source at scrape time.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mdflow"
+
+// Quota rejection kinds.
+const (
+	QuotaKindDaily    = "daily_quota" // per-session daily token quota
+	QuotaKindAIBudget = "ai_budget"   // global daily AI spend budget
+)
+
+// Registry holds every collector served by Handler. A dedicated registry keeps
+// tests and the endpoint independent of prometheus.DefaultRegisterer.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "code"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 150},
+	}, []string{"method", "route"})
+
+	aiCallsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_calls_total",
+		Help:      "AI operations by operation, model and outcome (success, error, cache_hit).",
+	}, []string{"operation", "model", "outcome"})
+
+	aiCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ai_call_duration_seconds",
+		Help:      "Latency of AI provider calls (cache hits excluded).",
+		Buckets:   []float64{0.25, 0.5, 1, 2, 4, 8, 15, 30, 60, 120},
+	}, []string{"operation", "model"})
+
+	aiCallTokens = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ai_call_tokens",
+		Help:      "Tokens per AI provider call by direction (input, output).",
+		Buckets:   prometheus.ExponentialBuckets(32, 2, 10), // 32 .. 16384
+	}, []string{"operation", "model", "direction"})
+
+	aiCallCost = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ai_call_cost_usd",
+		Help:      "Cost in USD per AI provider call.",
+		Buckets:   []float64{0.00001, 0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1},
+	}, []string{"operation", "model"})
+
+	quotaRejectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "quota_rejections_total",
+		Help:      "Requests rejected because a quota or budget was exhausted.",
+	}, []string{"kind"})
+
+	rateLimitRejectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requests rejected by per-client rate limits, by route.",
+	}, []string{"route"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		aiCallsTotal,
+		aiCallDuration,
+		aiCallTokens,
+		aiCallCost,
+		quotaRejectionsTotal,
+		rateLimitRejectionsTotal,
+		aiStateCollector{},
+	)
+}
+
+// Handler serves Registry in the Prometheus text exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records one completed request. route should be the
+// matched route template (not the raw path) to keep label cardinality bounded.
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	httpRequestsTotal.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpRequestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// AICall describes one AI operation for ObserveAICall.
+type AICall struct {
+	Operation    string
+	Model        string
+	Latency      time.Duration
+	InputTokens  int64
+	OutputTokens int64
+	CostUSD      float64
+	CacheHit     bool
+	Failed       bool
+}
+
+// ObserveAICall records an AI operation. Cache hits only count towards
+// ai_calls_total so they do not skew provider latency, token and cost histograms.
+func ObserveAICall(call AICall) {
+	outcome := "success"
+	switch {
+	case call.CacheHit:
+		outcome = "cache_hit"
+	case call.Failed:
+		outcome = "error"
+	}
+	aiCallsTotal.WithLabelValues(call.Operation, call.Model, outcome).Inc()
+	if call.CacheHit {
+		return
+	}
+	aiCallDuration.WithLabelValues(call.Operation, call.Model).Observe(call.Latency.Seconds())
+	if call.InputTokens > 0 || call.OutputTokens > 0 {
+		aiCallTokens.WithLabelValues(call.Operation, call.Model, "input").Observe(float64(call.InputTokens))
+		aiCallTokens.WithLabelValues(call.Operation, call.Model, "output").Observe(float64(call.OutputTokens))
+	}
+	if call.CostUSD > 0 {
+		aiCallCost.WithLabelValues(call.Operation, call.Model).Observe(call.CostUSD)
+	}
+}
+
+// IncQuotaRejection counts a request refused for quota kind (QuotaKind*).
+func IncQuotaRejection(kind string) {
+	quotaRejectionsTotal.WithLabelValues(kind).Inc()
+}
+
+// IncRateLimitRejection counts a request refused by a rate limiter on route.
+func IncRateLimitRejection(route string) {
+	rateLimitRejectionsTotal.WithLabelValues(route).Inc()
+}
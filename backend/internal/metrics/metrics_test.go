@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("scrape status %d", w.Code)
+	}
+	body, _ := io.ReadAll(w.Body)
+	return string(body)
+}
+
+func assertContains(t *testing.T, body string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(body, line) {
+			t.Errorf("exposition missing %q", line)
+		}
+	}
+}
+
+func TestObserveHTTPRequest(t *testing.T) {
+	ObserveHTTPRequest(http.MethodPost, "/test/observe", http.StatusCreated, 30*time.Millisecond)
+	ObserveHTTPRequest(http.MethodPost, "/test/observe", http.StatusBadRequest, 2*time.Second)
+
+	assertContains(t, scrape(t),
+		`mdflow_http_requests_total{code="201",method="POST",route="/test/observe"} 1`,
+		`mdflow_http_requests_total{code="400",method="POST",route="/test/observe"} 1`,
+		`mdflow_http_request_duration_seconds_bucket{method="POST",route="/test/observe",le="0.05"} 1`,
+		`mdflow_http_request_duration_seconds_count{method="POST",route="/test/observe"} 2`,
+	)
+}
+
+func TestObserveAICall_CacheHitsSkipHistograms(t *testing.T) {
+	ObserveAICall(AICall{Operation: "test_op", Model: "m1", Latency: 1500 * time.Millisecond, InputTokens: 400, OutputTokens: 90, CostUSD: 0.002})
+	ObserveAICall(AICall{Operation: "test_op", Model: "m1", CacheHit: true})
+	ObserveAICall(AICall{Operation: "test_op", Model: "m1", Latency: time.Second, Failed: true})
+
+	body := scrape(t)
+	assertContains(t, body,
+		`mdflow_ai_calls_total{model="m1",operation="test_op",outcome="success"} 1`,
+		`mdflow_ai_calls_total{model="m1",operation="test_op",outcome="cache_hit"} 1`,
+		`mdflow_ai_calls_total{model="m1",operation="test_op",outcome="error"} 1`,
+		`mdflow_ai_call_duration_seconds_count{model="m1",operation="test_op"} 2`,
+		`mdflow_ai_call_tokens_sum{direction="input",model="m1",operation="test_op"} 400`,
+		`mdflow_ai_call_tokens_sum{direction="output",model="m1",operation="test_op"} 90`,
+		`mdflow_ai_call_cost_usd_count{model="m1",operation="test_op"} 1`,
+	)
+}
+
+func TestRejectionCounters(t *testing.T) {
+	IncQuotaRejection(QuotaKindAIBudget)
+	IncRateLimitRejection("/test/limited")
+	IncRateLimitRejection("/test/limited")
+
+	assertContains(t, scrape(t),
+		`mdflow_quota_rejections_total{kind="ai_budget"}`,
+		`mdflow_rate_limit_rejections_total{route="/test/limited"} 2`,
+	)
+}
+
+func TestAIStateCollector(t *testing.T) {
+	SetAIStateSource(func() []AIServiceState {
+		return []AIServiceState{{
+			Service:          "convert",
+			Model:            "m1",
+			CircuitState:     "half_open",
+			BudgetSpentUSD:   2.5,
+			BudgetLimitUSD:   10,
+			CacheHitsByLevel: map[string]int64{"L1": 6, "L2": 2},
+			CacheMisses:      2,
+		}}
+	})
+	t.Cleanup(func() { SetAIStateSource(nil) })
+
+	body := scrape(t)
+	assertContains(t, body,
+		`mdflow_ai_circuit_breaker_state{model="m1",service="convert",state="closed"} 0`,
+		`mdflow_ai_circuit_breaker_state{model="m1",service="convert",state="half_open"} 1`,
+		`mdflow_ai_budget_spent_usd{model="m1",service="convert"} 2.5`,
+		`mdflow_ai_budget_utilization_ratio{model="m1",service="convert"} 0.25`,
+		`mdflow_ai_cache_hits_total{level="L1",model="m1",service="convert"} 6`,
+		`mdflow_ai_cache_hit_ratio{level="L1",model="m1",service="convert"} 0.6`,
+		`mdflow_ai_cache_hit_ratio{level="L2",model="m1",service="convert"} 0.2`,
+		`mdflow_ai_cache_misses_total{model="m1",service="convert"} 2`,
+	)
+
+	SetAIStateSource(nil)
+	if strings.Contains(scrape(t), "mdflow_ai_circuit_breaker_state{") {
+		t.Fatal("AI state still exported after source was removed")
+	}
+}
@@ -0,0 +1,198 @@
+package projectconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InputEdit changes the settings of one input in a config file.
+type InputEdit struct {
+	ColumnOverrides map[string]string // header -> field; "" removes the override
+	Sheet           *string           // nil keeps the sheet, "" removes it
+	Range           *string           // nil keeps the range, "" removes it
+}
+
+// EditInput applies edit to the inputs entry for file in the config at path,
+// creating the file when it does not exist. Comments, key order and other
+// settings are kept. When file is only covered by a directory or glob entry,
+// a file entry inheriting that entry's settings and output is added before
+// it, so the edit does not leak to the other files it covers.
+func EditInput(path, file string, edit InputEdit) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	dir := filepath.Dir(path)
+	cfg, err := Parse(data, dir)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: expected a mapping at the top level", path)
+	}
+
+	inputs := mappingValue(root, "inputs")
+	if inputs == nil {
+		inputs = &yaml.Node{Kind: yaml.SequenceNode}
+		setMappingValue(root, "inputs", inputs)
+	}
+	entry, err := inputEntry(cfg, inputs, dir, file)
+	if err != nil {
+		return err
+	}
+
+	if len(edit.ColumnOverrides) > 0 {
+		overrides := mappingValue(entry, "column_overrides")
+		if overrides == nil {
+			overrides = &yaml.Node{Kind: yaml.MappingNode}
+			setMappingValue(entry, "column_overrides", overrides)
+		}
+		for _, header := range sortedKeys(edit.ColumnOverrides) {
+			if field := edit.ColumnOverrides[header]; field != "" {
+				setMappingValue(overrides, header, scalar(field))
+			} else {
+				deleteMappingKey(overrides, header)
+			}
+		}
+		if len(overrides.Content) == 0 {
+			deleteMappingKey(entry, "column_overrides")
+		}
+	}
+	for _, setting := range []struct {
+		key   string
+		value *string
+	}{{"sheet", edit.Sheet}, {"range", edit.Range}} {
+		key, value := setting.key, setting.value
+		switch {
+		case value == nil:
+		case *value != "":
+			setMappingValue(entry, key, scalar(*value))
+		default:
+			deleteMappingKey(entry, key)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if _, err := Parse(buf.Bytes(), dir); err != nil {
+		return fmt.Errorf("%s: edit would make the config invalid: %w", path, err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// inputEntry finds or adds the inputs entry that is exactly for file.
+func inputEntry(cfg *Config, inputs *yaml.Node, dir, file string) (*yaml.Node, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(absDir, abs)
+	if err != nil {
+		rel = abs
+	}
+	entry := &yaml.Node{Kind: yaml.MappingNode}
+	setMappingValue(entry, "path", scalar(filepath.ToSlash(rel)))
+
+	for i, in := range cfg.Inputs {
+		if !Match(in.Path, file) || i >= len(inputs.Content) {
+			continue
+		}
+		if inAbs, err := filepath.Abs(in.Path); err == nil && inAbs == abs {
+			return inputs.Content[i], nil
+		}
+		// Covered by a directory or glob: inherit its settings.
+		for _, key := range []string{"template", "format", "sheet", "range", "column_overrides", "rules"} {
+			if value := mappingValue(inputs.Content[i], key); value != nil {
+				setMappingValue(entry, key, cloneNode(value))
+			}
+		}
+		if output := cfg.OutputFor(file, ".mdflow.md"); output != "" {
+			if outAbs, err := filepath.Abs(output); err == nil {
+				if outRel, err := filepath.Rel(absDir, outAbs); err == nil {
+					output = outRel
+				}
+			}
+			setMappingValue(entry, "output", scalar(filepath.ToSlash(output)))
+		}
+		inputs.Content = append(inputs.Content[:i], append([]*yaml.Node{entry}, inputs.Content[i:]...)...)
+		return entry, nil
+	}
+	inputs.Content = append(inputs.Content, entry)
+	return entry, nil
+}
+
+func cloneNode(n *yaml.Node) *yaml.Node {
+	c := *n
+	c.Content = make([]*yaml.Node, len(n.Content))
+	for i, child := range n.Content {
+		c.Content[i] = cloneNode(child)
+	}
+	return &c
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, scalar(key), value)
+}
+
+func deleteMappingKey(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
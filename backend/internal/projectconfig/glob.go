@@ -0,0 +1,81 @@
+package projectconfig
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// IsPattern reports whether path contains glob metacharacters.
+func IsPattern(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// Base returns the directory part of a glob before its first wildcard, or
+// the path itself when it has none.
+func Base(pattern string) string {
+	if !IsPattern(pattern) {
+		return pattern
+	}
+	parts := strings.Split(filepath.ToSlash(pattern), "/")
+	var static []string
+	for _, part := range parts[:len(parts)-1] {
+		if IsPattern(part) {
+			break
+		}
+		static = append(static, part)
+	}
+	base := strings.Join(static, "/")
+	if base == "" {
+		if strings.HasPrefix(pattern, "/") {
+			return "/"
+		}
+		return "."
+	}
+	return filepath.FromSlash(base)
+}
+
+// Match reports whether file is the input path, lies under it (for a
+// directory), or matches it (for a glob, where "**" spans directories).
+func Match(pattern, file string) bool {
+	absPattern, err := filepath.Abs(pattern)
+	if err != nil {
+		return false
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return false
+	}
+	if IsPattern(pattern) {
+		return MatchGlob(absPattern, absFile)
+	}
+	if absFile == absPattern {
+		return true
+	}
+	rel, err := filepath.Rel(absPattern, absFile)
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..") && isDir(absPattern)
+}
+
+// MatchGlob matches a slash- or separator-delimited path against a glob in
+// which "**" matches zero or more directories.
+func MatchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(filepath.ToSlash(pattern), "/"), strings.Split(filepath.ToSlash(name), "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	ok, _ := filepath.Match(pattern[0], name[0])
+	return ok && matchSegments(pattern[1:], name[1:])
+}
@@ -0,0 +1,338 @@
+// Package projectconfig loads .mdflow.yaml, the per-project CLI settings
+// (inputs and outputs, templates, column overrides, rules, synonyms and AI
+// options) discovered from the working directory upwards.
+package projectconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+// FileNames are the config file names looked for in each directory, in order.
+var FileNames = []string{".mdflow.yaml", ".mdflow.yml"}
+
+// Settings are conversion options for an input. Empty values fall back to the
+// project defaults, then to the CLI defaults.
+type Settings struct {
+	Template        string            `yaml:"template,omitempty"`
+	Format          string            `yaml:"format,omitempty"` // spec or table
+	Sheet           string            `yaml:"sheet,omitempty"`
+	Range           string            `yaml:"range,omitempty"` // A1 range such as "A3:H200"
+	ColumnOverrides map[string]string `yaml:"column_overrides,omitempty"`
+	Rules           []string          `yaml:"rules,omitempty"` // validation rule files
+}
+
+// Input maps a file, directory or glob to its output. Output is a file for a
+// single input and a directory (mirroring the input tree) otherwise.
+type Input struct {
+	Path     string `yaml:"path"`
+	Output   string `yaml:"output,omitempty"`
+	Settings `yaml:",inline"`
+}
+
+// AISettings configure AI column mapping for the CLI.
+type AISettings struct {
+	Enabled   bool    `yaml:"enabled,omitempty"`
+	Model     string  `yaml:"model,omitempty"`
+	APIKeyEnv string  `yaml:"api_key_env,omitempty"` // environment variable holding the key (default OPENAI_API_KEY)
+	BudgetUSD float64 `yaml:"budget_usd,omitempty"`  // per-run spend limit, 0 = unlimited
+}
+
+// Config is a parsed .mdflow.yaml. Relative paths are resolved against the
+// directory holding the file.
+type Config struct {
+	Settings     `yaml:",inline"`
+	OutDir       string              `yaml:"out_dir,omitempty"` // default output directory for inputs without one
+	Synonyms     map[string][]string `yaml:"synonyms,omitempty"`
+	SynonymFiles []string            `yaml:"synonym_files,omitempty"`
+	AI           AISettings          `yaml:"ai,omitempty"`
+	Inputs       []Input             `yaml:"inputs,omitempty"`
+
+	Path string `yaml:"-"` // the file the config was loaded from
+	Dir  string `yaml:"-"`
+}
+
+// Find looks for a config file in dir and its parents and returns its path,
+// or "" when there is none.
+func Find(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	rel := dir
+	for {
+		for _, name := range FileNames {
+			candidate := filepath.Join(rel, name)
+			info, err := os.Stat(candidate)
+			if err == nil && !info.IsDir() {
+				return candidate, nil
+			}
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return "", nil
+		}
+		abs, rel = parent, filepath.Join(rel, "..")
+	}
+}
+
+// Discover finds and loads the config for dir. It returns nil, nil when no
+// config file exists.
+func Discover(dir string) (*Config, error) {
+	path, err := Find(dir)
+	if err != nil || path == "" {
+		return nil, err
+	}
+	return Load(path)
+}
+
+// Load reads and checks a config file and the synonym files it names.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := Parse(data, filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.Path = path
+	return cfg, nil
+}
+
+// Parse decodes a config whose relative paths are relative to dir. Unknown
+// keys are rejected so typos do not silently drop settings.
+func Parse(data []byte, dir string) (*Config, error) {
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	cfg.Dir = dir
+
+	resolve := func(p *string) {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
+	resolveSettings := func(s *Settings) {
+		for i := range s.Rules {
+			resolve(&s.Rules[i])
+		}
+	}
+	resolve(&cfg.OutDir)
+	resolveSettings(&cfg.Settings)
+	for i := range cfg.SynonymFiles {
+		resolve(&cfg.SynonymFiles[i])
+	}
+	for i := range cfg.Inputs {
+		in := &cfg.Inputs[i]
+		resolve(&in.Path)
+		resolve(&in.Output)
+		resolveSettings(&in.Settings)
+	}
+
+	for _, file := range cfg.SynonymFiles {
+		extra, err := loadSynonyms(file)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Synonyms == nil {
+			cfg.Synonyms = map[string][]string{}
+		}
+		for field, names := range extra {
+			cfg.Synonyms[field] = append(cfg.Synonyms[field], names...)
+		}
+	}
+
+	if err := cfg.check(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func loadSynonyms(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("synonym file: %w", err)
+	}
+	var synonyms map[string][]string
+	if err := yaml.Unmarshal(data, &synonyms); err != nil {
+		return nil, fmt.Errorf("synonym file %s: %w", path, err)
+	}
+	return synonyms, nil
+}
+
+func (c *Config) check() error {
+	templates := converter.NewTemplateRegistry()
+	checkSettings := func(where string, s Settings) error {
+		if s.Template != "" {
+			if _, err := templates.LoadTemplate(s.Template); err != nil {
+				return fmt.Errorf("%s: %w", where, err)
+			}
+		}
+		switch converter.OutputFormat(s.Format) {
+		case "", converter.OutputFormatSpec, converter.OutputFormatTable:
+		default:
+			return fmt.Errorf("%s: format must be spec or table, got %q", where, s.Format)
+		}
+		if s.Range != "" {
+			if _, err := converter.ParseA1Range(s.Range); err != nil {
+				return fmt.Errorf("%s: %w", where, err)
+			}
+		}
+		return nil
+	}
+
+	if err := checkSettings("defaults", c.Settings); err != nil {
+		return err
+	}
+	for i, in := range c.Inputs {
+		where := fmt.Sprintf("inputs[%d]", i)
+		if in.Path == "" {
+			return fmt.Errorf("%s: path is required", where)
+		}
+		if err := checkSettings(where, in.Settings); err != nil {
+			return err
+		}
+	}
+	fields := make([]string, 0, len(c.Synonyms))
+	for field := range c.Synonyms {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if !converter.IsCanonicalField(field) {
+			return fmt.Errorf("synonyms: unknown field %q", field)
+		}
+	}
+	if c.AI.BudgetUSD < 0 {
+		return fmt.Errorf("ai.budget_usd must not be negative")
+	}
+	return nil
+}
+
+// Input returns the first input whose path, directory or glob covers file.
+func (c *Config) Input(file string) *Input {
+	if c == nil {
+		return nil
+	}
+	for i := range c.Inputs {
+		if Match(c.Inputs[i].Path, file) {
+			return &c.Inputs[i]
+		}
+	}
+	return nil
+}
+
+// SettingsFor merges the project defaults with the settings of the input
+// covering file. Column overrides merge by header; input rules replace the
+// default rules.
+func (c *Config) SettingsFor(file string) Settings {
+	if c == nil {
+		return Settings{}
+	}
+	s := c.Settings
+	s.ColumnOverrides = mergeOverrides(c.ColumnOverrides, nil)
+	in := c.Input(file)
+	if in == nil {
+		return s
+	}
+	for _, pair := range [][2]*string{
+		{&s.Template, &in.Template},
+		{&s.Format, &in.Format},
+		{&s.Sheet, &in.Sheet},
+		{&s.Range, &in.Range},
+	} {
+		if *pair[1] != "" {
+			*pair[0] = *pair[1]
+		}
+	}
+	s.ColumnOverrides = mergeOverrides(s.ColumnOverrides, in.ColumnOverrides)
+	if len(in.Rules) > 0 {
+		s.Rules = in.Rules
+	}
+	return s
+}
+
+// OutputFor returns where file's output goes, or "" when the config does
+// not say. ext is the output extension (".mdflow.md" or ".json").
+func (c *Config) OutputFor(file, ext string) string {
+	if c == nil {
+		return ""
+	}
+	in := c.Input(file)
+	name := func(rel string) string {
+		return rel[:len(rel)-len(filepath.Ext(rel))] + ext
+	}
+	if in == nil {
+		return ""
+	}
+	if !IsPattern(in.Path) && !isDir(in.Path) {
+		if in.Output != "" {
+			return in.Output
+		}
+		if c.OutDir != "" {
+			return filepath.Join(c.OutDir, name(filepath.Base(file)))
+		}
+		return ""
+	}
+	dir := in.Output
+	if dir == "" {
+		dir = c.OutDir
+	}
+	if dir == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(Base(in.Path), file)
+	if err != nil {
+		rel = filepath.Base(file)
+	}
+	return filepath.Join(dir, name(rel))
+}
+
+func mergeOverrides(base, extra map[string]string) map[string]string {
+	if len(base) == 0 && len(extra) == 0 {
+		return nil
+	}
+	merged := map[string]string{}
+	for _, m := range []map[string]string{base, extra} {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// Patterns reports the config's input paths, in order.
+func (c *Config) Patterns() []string {
+	if c == nil {
+		return nil
+	}
+	var paths []string
+	for _, in := range c.Inputs {
+		if !slices.Contains(paths, in.Path) {
+			paths = append(paths, in.Path)
+		}
+	}
+	return paths
+}
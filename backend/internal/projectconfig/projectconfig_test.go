@@ -0,0 +1,208 @@
+package projectconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscover_WalksUpAndResolvesPaths(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "synonyms.yaml"), "title: [Summary]\n")
+	writeFile(t, filepath.Join(root, FileNames[0]), `
+template: spec
+out_dir: specs
+rules: [rules.yaml]
+column_overrides:
+  Prio: priority
+synonyms:
+  id: [Ticket]
+synonym_files: [synonyms.yaml]
+ai:
+  enabled: true
+  budget_usd: 0.5
+inputs:
+  - path: sheets/main.xlsx
+    output: docs/main.mdflow.md
+    sheet: Requirements
+    range: A3:H200
+    column_overrides:
+      Owner: assignee
+  - path: "sheets/**/*.tsv"
+    format: table
+    rules: [strict.yaml]
+`)
+	nested := filepath.Join(root, "sheets", "deep")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Discover(nested)
+	if err != nil || cfg == nil {
+		t.Fatalf("Discover = %v, %v", cfg, err)
+	}
+	if got, _ := filepath.Abs(cfg.Dir); got != root {
+		t.Fatalf("Dir = %s, want %s", got, root)
+	}
+	if !reflect.DeepEqual(cfg.Synonyms, map[string][]string{"id": {"Ticket"}, "title": {"Summary"}}) {
+		t.Fatalf("Synonyms = %v", cfg.Synonyms)
+	}
+	if !cfg.AI.Enabled || cfg.AI.BudgetUSD != 0.5 {
+		t.Fatalf("AI = %+v", cfg.AI)
+	}
+
+	main := filepath.Join(cfg.Dir, "sheets", "main.xlsx")
+	s := cfg.SettingsFor(main)
+	if s.Sheet != "Requirements" || s.Range != "A3:H200" || s.Template != "spec" {
+		t.Fatalf("SettingsFor(main) = %+v", s)
+	}
+	if !reflect.DeepEqual(s.ColumnOverrides, map[string]string{"Prio": "priority", "Owner": "assignee"}) {
+		t.Fatalf("overrides = %v", s.ColumnOverrides)
+	}
+	if len(s.Rules) != 1 || filepath.Base(s.Rules[0]) != "rules.yaml" {
+		t.Fatalf("rules = %v", s.Rules)
+	}
+	if got := cfg.OutputFor(main, ".mdflow.md"); got != filepath.Join(cfg.Dir, "docs", "main.mdflow.md") {
+		t.Fatalf("OutputFor(main) = %s", got)
+	}
+
+	tsv := filepath.Join(cfg.Dir, "sheets", "a", "b.tsv")
+	s = cfg.SettingsFor(tsv)
+	if s.Format != "table" || filepath.Base(s.Rules[0]) != "strict.yaml" {
+		t.Fatalf("SettingsFor(tsv) = %+v", s)
+	}
+	if got := cfg.OutputFor(tsv, ".json"); got != filepath.Join(cfg.Dir, "specs", "a", "b.json") {
+		t.Fatalf("OutputFor(tsv) = %s", got)
+	}
+	if cfg.Input(filepath.Join(cfg.Dir, "other.csv")) != nil {
+		t.Fatal("unrelated file matched an input")
+	}
+}
+
+func TestFind_NoConfig(t *testing.T) {
+	// Temp dirs normally have no .mdflow.yaml above them; skip if one does.
+	dir := t.TempDir()
+	path, err := Find(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "" {
+		t.Skipf("found %s above the temp dir", path)
+	}
+}
+
+func TestParse_Rejects(t *testing.T) {
+	cases := map[string]string{
+		"unknown key":    "tempalte: spec\n",
+		"bad format":     "format: html\n",
+		"bad range":      "inputs:\n  - path: a.xlsx\n    range: 3A\n",
+		"missing path":   "inputs:\n  - output: a.md\n",
+		"unknown field":  "synonyms:\n  ticket: [Ticket]\n",
+		"bad template":   "template: nope\n",
+		"negative spend": "ai:\n  budget_usd: -1\n",
+	}
+	for name, data := range cases {
+		if _, err := Parse([]byte(data), t.TempDir()); err == nil {
+			t.Errorf("%s: accepted %q", name, data)
+		}
+	}
+	if _, err := Parse([]byte(""), "."); err != nil {
+		t.Errorf("empty config: %v", err)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"in/**/*.xlsx", "in/a.xlsx", true},
+		{"in/**/*.xlsx", "in/a/b/c.xlsx", true},
+		{"in/**/*.xlsx", "in/a/b/c.csv", false},
+		{"in/*/x.tsv", "in/a/b/x.tsv", false},
+		{"**/spec.tsv", "deep/dir/spec.tsv", true},
+	}
+	for _, tc := range cases {
+		if got := MatchGlob(tc.pattern, tc.path); got != tc.want {
+			t.Errorf("MatchGlob(%q, %q) = %v", tc.pattern, tc.path, got)
+		}
+	}
+	if Base("in/**/*.xlsx") != "in" || Base("*.tsv") != "." || !strings.HasSuffix(Base("a/b.tsv"), "b.tsv") {
+		t.Error("Base mismatch")
+	}
+}
+
+func TestEditInput(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, ".mdflow.yaml")
+	writeFile(t, path, `# team config
+template: spec
+inputs:
+  - path: sheets/main.xlsx # the big one
+    column_overrides:
+      Prio: priority
+  - path: "sheets/**/*.tsv"
+    output: specs
+    format: table
+`)
+	main := filepath.Join(root, "sheets", "main.xlsx")
+	tsv := filepath.Join(root, "sheets", "a", "b.tsv")
+	rng := "A3"
+	if err := EditInput(path, main, InputEdit{ColumnOverrides: map[string]string{"Prio": "", "Req No": "id"}, Range: &rng}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EditInput(path, tsv, InputEdit{ColumnOverrides: map[string]string{"Ticket": "id"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{"# team config", "# the big one"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("comment %q lost:\n%s", want, data)
+		}
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.SettingsFor(main); !reflect.DeepEqual(got.ColumnOverrides, map[string]string{"Req No": "id"}) || got.Range != "A3" {
+		t.Errorf("main settings = %+v", got)
+	}
+	// The tsv gets its own entry ahead of the glob, keeping the glob's settings.
+	if len(cfg.Inputs) != 3 || cfg.Inputs[1].Path != tsv {
+		t.Fatalf("inputs = %+v", cfg.Inputs)
+	}
+	if got := cfg.SettingsFor(tsv); got.Format != "table" || got.ColumnOverrides["Ticket"] != "id" {
+		t.Errorf("tsv settings = %+v", got)
+	}
+	if got := cfg.OutputFor(tsv, ".mdflow.md"); got != filepath.Join(root, "specs", "a", "b.mdflow.md") {
+		t.Errorf("tsv output = %s", got)
+	}
+	if got := cfg.SettingsFor(filepath.Join(root, "sheets", "c.tsv")); len(got.ColumnOverrides) != 0 {
+		t.Errorf("edit leaked to other glob files: %+v", got)
+	}
+
+	// A missing config is created.
+	fresh := filepath.Join(root, "new", ".mdflow.yaml")
+	if err := os.MkdirAll(filepath.Dir(fresh), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := EditInput(fresh, filepath.Join(root, "new", "x.csv"), InputEdit{ColumnOverrides: map[string]string{"Ref": "id"}}); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err := Load(fresh); err != nil || len(cfg.Inputs) != 1 || cfg.Inputs[0].ColumnOverrides["Ref"] != "id" {
+		t.Fatalf("fresh config = %+v, %v", cfg, err)
+	}
+}
@@ -0,0 +1,155 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML: one suite per finding source and
+// one test case per spec row (plus "document" for document-level findings),
+// prefixed with the file name when findings span several files.
+// Warn and error findings fail their test case; info findings go to system-out.
+// The validation suite always has at least one case so clean runs still show
+// a passing test.
+func WriteJUnit(w io.Writer, r *Report) error {
+	tool := r.Tool
+	if tool == "" {
+		tool = "mdflow"
+	}
+
+	sources := []string{SourceValidation}
+	var uris []string
+	for _, f := range r.Findings {
+		if !slices.Contains(sources, f.Source) {
+			sources = append(sources, f.Source)
+		}
+		if f.Location.URI != "" && !slices.Contains(uris, f.Location.URI) {
+			uris = append(uris, f.Location.URI)
+		}
+	}
+	multiFile := len(uris) > 1
+
+	out := junitTestSuites{Name: tool}
+	for _, source := range sources {
+		suite := junitTestSuite{Name: tool + "." + source}
+		var keys []string
+		cases := map[string][]Finding{}
+		for _, f := range r.Findings {
+			if f.Source != source {
+				continue
+			}
+			key := "document"
+			if f.Row > 0 {
+				key = fmt.Sprintf("row %d", f.Row)
+			}
+			if multiFile && f.Location.URI != "" {
+				key = f.Location.URI + " " + key
+			}
+			if _, ok := cases[key]; !ok {
+				keys = append(keys, key)
+			}
+			cases[key] = append(cases[key], f)
+		}
+		if len(keys) == 0 {
+			keys = append(keys, "document")
+		}
+
+		for _, key := range keys {
+			tc := junitTestCase{Name: key, ClassName: suite.Name}
+			var failures, infos []string
+			var first *Finding
+			for i, f := range cases[key] {
+				line := formatFindingLine(f)
+				if f.Severity == converter.SeverityInfo {
+					infos = append(infos, line)
+					continue
+				}
+				if first == nil {
+					first = &cases[key][i]
+				}
+				failures = append(failures, line)
+			}
+			if first != nil {
+				tc.Failure = &junitFailure{Message: first.Message, Type: first.RuleID, Text: strings.Join(failures, "\n")}
+				suite.Failures++
+			}
+			if len(infos) > 0 {
+				tc.SystemOut = strings.Join(infos, "\n")
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+		suite.Tests = len(suite.TestCases)
+		out.Tests += suite.Tests
+		out.Failures += suite.Failures
+		out.Suites = append(out.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// formatFindingLine renders "[error] CODE: message (spec.xlsx Sheet1!C5)".
+func formatFindingLine(f Finding) string {
+	line := fmt.Sprintf("[%s] %s: %s", f.Severity, f.RuleID, f.Message)
+	if where := formatLocation(f.Location); where != "" {
+		line += " (" + where + ")"
+	}
+	if f.Hint != "" {
+		line += "\n  hint: " + f.Hint
+	}
+	return line
+}
+
+func formatLocation(l Location) string {
+	switch {
+	case l.Cell != "" && l.Sheet != "":
+		return fmt.Sprintf("%s %s!%s", l.URI, l.Sheet, l.Cell)
+	case l.Cell != "":
+		return fmt.Sprintf("%s %s", l.URI, l.Cell)
+	case l.Line > 0:
+		return fmt.Sprintf("%s:%d", l.URI, l.Line)
+	default:
+		return l.URI
+	}
+}
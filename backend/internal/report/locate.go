@@ -0,0 +1,156 @@
+package report
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+// Locator resolves spec rows to locations in the validated artifact.
+type Locator interface {
+	Locate(row int, field string) Location
+}
+
+// Locate fills in each finding's location. Document-level findings point at
+// the artifact itself.
+func Locate(findings []Finding, uri string, loc Locator) {
+	for i := range findings {
+		f := &findings[i]
+		if f.Row > 0 && loc != nil {
+			f.Location = loc.Locate(f.Row, f.Field)
+		}
+		if f.Location.URI == "" {
+			f.Location.URI = uri
+		}
+	}
+}
+
+// SourceLocator points rows at the sheet or text file they were parsed from.
+// Sheet sources get A1 cell references; text sources (TSV/CSV) get lines.
+type SourceLocator struct {
+	URI   string
+	Sheet bool // spreadsheet source: report cells rather than lines
+	doc   *converter.SpecDoc
+	rows  []int // source line/row of each parsed row, see WithRowNumbers
+}
+
+// NewSourceLocator creates a SourceLocator for doc parsed from uri.
+func NewSourceLocator(uri string, doc *converter.SpecDoc, sheet bool) *SourceLocator {
+	return &SourceLocator{URI: uri, Sheet: sheet, doc: doc}
+}
+
+// WithRowNumbers maps parsed rows back to source rows: parsing drops blank
+// rows, so parsed row N sits on source row rows[N-1] (see converter.XLSXSourceRows).
+func (l *SourceLocator) WithRowNumbers(rows []int) *SourceLocator {
+	l.rows = rows
+	return l
+}
+
+// WithText maps parsed rows back to lines of the original TSV/CSV text: the
+// Nth parsed row is the Nth non-blank line.
+func (l *SourceLocator) WithText(text string) *SourceLocator {
+	var lines []int
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, i+1)
+		}
+	}
+	return l.WithRowNumbers(lines)
+}
+
+// Locate implements Locator.
+func (l *SourceLocator) Locate(row int, field string) Location {
+	loc := Location{URI: l.URI}
+	if l.doc == nil || row < 1 || row > len(l.doc.Rows) {
+		return loc
+	}
+	line := l.doc.Rows[row-1].SourceRow
+	if line == 0 {
+		return loc
+	}
+	if line <= len(l.rows) {
+		line = l.rows[line-1]
+	}
+	loc.Line = line
+	if col, ok := l.column(field); ok {
+		loc.Column = col + 1
+		if l.Sheet {
+			loc.Cell = ColumnName(col) + strconv.Itoa(line)
+		}
+	}
+	if l.Sheet {
+		loc.Sheet = l.doc.Meta.SheetName
+	}
+	return loc
+}
+
+// column returns the 0-based source column for a canonical field or header.
+func (l *SourceLocator) column(field string) (int, bool) {
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return 0, false
+	}
+	field = strings.TrimPrefix(field, "metadata.")
+	if idx, ok := l.doc.Meta.ColumnMap[converter.CanonicalField(strings.ToLower(field))]; ok {
+		return idx, true
+	}
+	for i, h := range l.doc.Headers {
+		if strings.EqualFold(strings.TrimSpace(h), field) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// MarkdownLocator points rows at their heading in rendered MDFlow markdown.
+type MarkdownLocator struct {
+	URI   string
+	lines []string
+	doc   *converter.SpecDoc
+}
+
+// NewMarkdownLocator creates a MarkdownLocator for doc rendered as markdown
+// and written to uri.
+func NewMarkdownLocator(uri, markdown string, doc *converter.SpecDoc) *MarkdownLocator {
+	return &MarkdownLocator{URI: uri, lines: strings.Split(markdown, "\n"), doc: doc}
+}
+
+// Locate implements Locator. Rows are found by ID (or title) on a heading
+// line first, then on any line, since renderers group rows by feature.
+func (l *MarkdownLocator) Locate(row int, _ string) Location {
+	loc := Location{URI: l.URI}
+	if l.doc == nil || row < 1 || row > len(l.doc.Rows) {
+		return loc
+	}
+	r := l.doc.Rows[row-1]
+	key := strings.TrimSpace(r.ID)
+	if key == "" {
+		key = strings.TrimSpace(r.Title)
+	}
+	if key == "" {
+		return loc
+	}
+	for _, headingOnly := range []bool{true, false} {
+		for i, line := range l.lines {
+			if headingOnly && !strings.HasPrefix(line, "#") {
+				continue
+			}
+			if strings.Contains(line, key) {
+				loc.Line = i + 1
+				return loc
+			}
+		}
+	}
+	return loc
+}
+
+// ColumnName converts a 0-based column index to a spreadsheet column name (0 → A, 26 → AA).
+func ColumnName(col int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+	return name
+}
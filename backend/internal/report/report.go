@@ -0,0 +1,309 @@
+// Package report turns validation findings into CI report formats (SARIF 2.1
+// and JUnit XML) so builds can fail on bad specs.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/md-spec-tool/internal/ai"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+// Report formats.
+const (
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+	FormatJUnit = "junit"
+	FormatText  = "text"
+)
+
+// Finding sources, used as JUnit suite names and SARIF result properties.
+const (
+	SourceValidation = "validation"
+	SourceQuality    = "quality"
+	SourceSemantic   = "semantic"
+)
+
+// Finding is one issue to report.
+type Finding struct {
+	RuleID   string                    `json:"rule_id"`
+	Severity converter.WarningSeverity `json:"severity"`
+	Message  string                    `json:"message"`
+	Hint     string                    `json:"hint,omitempty"`
+	Source   string                    `json:"source"`
+	Row      int                       `json:"row,omitempty"`   // 1-based spec row, 0 for document-level findings
+	Field    string                    `json:"field,omitempty"` // canonical field or header, when known
+	Location Location                  `json:"location"`
+}
+
+// Location points a finding at a file line or a sheet cell.
+type Location struct {
+	URI    string `json:"uri,omitempty"`
+	Line   int    `json:"line,omitempty"`   // 1-based line, 0 when unknown
+	Column int    `json:"column,omitempty"` // 1-based column, 0 when unknown
+	Sheet  string `json:"sheet,omitempty"`  // sheet name for spreadsheet sources
+	Cell   string `json:"cell,omitempty"`   // A1-style cell reference for spreadsheet sources
+}
+
+// Report is the set of findings for one input.
+type Report struct {
+	Tool     string    `json:"tool"`
+	Version  string    `json:"version,omitempty"`
+	URI      string    `json:"uri,omitempty"` // the validated artifact
+	Findings []Finding `json:"findings"`
+}
+
+// Failed reports whether any finding has warn or error severity, matching
+// ValidationResult.Valid.
+func (r *Report) Failed() bool {
+	for _, f := range r.Findings {
+		if f.Severity != converter.SeverityInfo {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeFormat maps user input to a report format. Empty means JSON.
+func NormalizeFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatText, "txt":
+		return FormatText, nil
+	case FormatSARIF, "sarif-json":
+		return FormatSARIF, nil
+	case FormatJUnit, "junit-xml", "xml":
+		return FormatJUnit, nil
+	default:
+		return "", fmt.Errorf("unsupported report format %q (use text, json, sarif or junit)", format)
+	}
+}
+
+// NegotiateFormat picks a report format from an Accept header: of
+// application/json, application/sarif+json and application/junit+xml, the
+// one with the highest q-value wins, the earliest on a tie. Generic XML
+// types do not select JUnit; anything unrecognised means JSON.
+func NegotiateFormat(accept string) string {
+	format, best := FormatJSON, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		var candidate string
+		switch mediaType {
+		case "application/sarif+json":
+			candidate = FormatSARIF
+		case "application/junit+xml":
+			candidate = FormatJUnit
+		case "application/json":
+			candidate = FormatJSON
+		default:
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > best {
+			format, best = candidate, q
+		}
+	}
+	return format
+}
+
+// ContentType returns the MIME type served for a report format.
+func ContentType(format string) string {
+	switch format {
+	case FormatSARIF:
+		return "application/sarif+json; charset=utf-8"
+	case FormatJUnit:
+		return "application/xml; charset=utf-8"
+	case FormatText:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// Write renders the report in the given format.
+func Write(w io.Writer, format string, r *Report) error {
+	switch format {
+	case FormatSARIF:
+		return WriteSARIF(w, r)
+	case FormatJUnit:
+		return WriteJUnit(w, r)
+	case FormatText:
+		return WriteText(w, r)
+	case FormatJSON:
+		return WriteJSON(w, r)
+	default:
+		return fmt.Errorf("report format %q has no writer", format)
+	}
+}
+
+// WriteJSON writes the report as indented JSON with a top-level "failed" flag.
+func WriteJSON(w io.Writer, r *Report) error {
+	findings := r.Findings
+	if findings == nil {
+		findings = []Finding{}
+	}
+	out := struct {
+		*Report
+		Findings []Finding `json:"findings"`
+		Failed   bool      `json:"failed"`
+	}{Report: r, Findings: findings, Failed: r.Failed()}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// WriteText writes one line per finding followed by a severity summary.
+func WriteText(w io.Writer, r *Report) error {
+	counts := map[converter.WarningSeverity]int{}
+	for _, f := range r.Findings {
+		counts[f.Severity]++
+		if _, err := fmt.Fprintln(w, formatFindingLine(f)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d error(s), %d warning(s), %d info\n",
+		counts[converter.SeverityError], counts[converter.SeverityWarn], counts[converter.SeverityInfo])
+	return err
+}
+
+// FromWarnings converts validation (or conversion) warnings into findings.
+// Warnings carry their spec row and field in Details; lint warnings carry a
+// file line instead.
+func FromWarnings(warnings []converter.Warning) []Finding {
+	findings := make([]Finding, 0, len(warnings))
+	for _, w := range warnings {
+		f := Finding{
+			RuleID:   w.Code,
+			Severity: w.Severity,
+			Message:  w.Message,
+			Hint:     w.Hint,
+			Source:   SourceValidation,
+		}
+		switch row := w.Details["row"].(type) {
+		case int:
+			f.Row = row
+		case float64: // decoded from JSON
+			f.Row = int(row)
+		}
+		switch line := w.Details["line"].(type) {
+		case int:
+			f.Location.Line = line
+		case float64:
+			f.Location.Line = int(line)
+		}
+		for _, key := range []string{"field", "then_field"} {
+			if field, ok := w.Details[key].(string); ok && field != "" {
+				f.Field = field
+				break
+			}
+		}
+		if rule, ok := w.Details["rule"].(string); ok && rule != "" {
+			f.RuleID = w.Code + "/" + rule
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// FromQualityReport converts failed quality gates into document-level findings.
+func FromQualityReport(q *converter.QualityReport) []Finding {
+	if q == nil {
+		return nil
+	}
+	severity := converter.SeverityWarn
+	if q.StrictMode {
+		severity = converter.SeverityError
+	}
+	var findings []Finding
+	add := func(rule, msg string) {
+		findings = append(findings, Finding{RuleID: rule, Severity: severity, Message: msg, Source: SourceQuality})
+	}
+	if !q.ValidationPassed {
+		reason := q.ValidationReason
+		if reason == "" {
+			reason = "quality gate failed"
+		}
+		add("QUALITY_GATE", reason)
+	}
+	if q.MinHeaderConfidence > 0 && q.HeaderConfidence < q.MinHeaderConfidence {
+		add("QUALITY_HEADER_CONFIDENCE", fmt.Sprintf("header confidence %d%% is below %d%%", q.HeaderConfidence, q.MinHeaderConfidence))
+	}
+	if q.MaxRowLossRatio > 0 && q.RowLossRatio > q.MaxRowLossRatio {
+		add("QUALITY_ROW_LOSS", fmt.Sprintf("%.0f%% of source rows were dropped (max %.0f%%)", q.RowLossRatio*100, q.MaxRowLossRatio*100))
+	}
+	fields := make([]string, 0, len(q.CoreFieldCoverage))
+	for field, covered := range q.CoreFieldCoverage {
+		if !covered {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		findings = append(findings, Finding{
+			RuleID:   "QUALITY_CORE_FIELD",
+			Severity: converter.SeverityInfo,
+			Message:  fmt.Sprintf("core field %q is not mapped", field),
+			Source:   SourceQuality,
+			Field:    field,
+		})
+	}
+	if q.RulePack != nil && q.RulePack.Errors > 0 {
+		findings = append(findings, Finding{
+			RuleID:   "QUALITY_RULE_PACK",
+			Severity: converter.SeverityError,
+			Message:  fmt.Sprintf("%s rule pack failed with %d error(s)", q.RulePack.Template, q.RulePack.Errors),
+			Source:   SourceQuality,
+		})
+	}
+	return findings
+}
+
+// FromSemantic converts AI semantic validation issues into findings.
+func FromSemantic(result *ai.SemanticValidationResult) []Finding {
+	if result == nil {
+		return nil
+	}
+	findings := make([]Finding, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		rule := "SEMANTIC"
+		if t := strings.TrimSpace(issue.Type); t != "" {
+			rule += "_" + strings.ToUpper(t)
+		}
+		findings = append(findings, Finding{
+			RuleID:   rule,
+			Severity: semanticSeverity(issue.Severity),
+			Message:  issue.Message,
+			Hint:     issue.Suggestion,
+			Source:   SourceSemantic,
+			Row:      issue.RowRef,
+			Field:    issue.Field,
+		})
+	}
+	return findings
+}
+
+func semanticSeverity(s string) converter.WarningSeverity {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "error":
+		return converter.SeverityError
+	case "info":
+		return converter.SeverityInfo
+	default:
+		return converter.SeverityWarn
+	}
+}
@@ -0,0 +1,222 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/md-spec-tool/internal/ai"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+func sheetDoc() *converter.SpecDoc {
+	return &converter.SpecDoc{
+		Headers: []string{"ID", "Title", "Priority", "Component"},
+		Meta: converter.SpecDocMeta{
+			SheetName: "Specs",
+			ColumnMap: converter.ColumnMap{converter.FieldID: 0, converter.FieldTitle: 1, converter.FieldPriority: 2},
+		},
+		Rows: []converter.SpecRow{
+			{ID: "REQ-1", Title: "Login", SourceRow: 3},
+			{ID: "REQ-2", Title: "Logout", SourceRow: 5},
+		},
+	}
+}
+
+func TestSourceLocator_SheetCells(t *testing.T) {
+	loc := NewSourceLocator("book.xlsx", sheetDoc(), true)
+
+	got := loc.Locate(2, "priority")
+	if got.Cell != "C5" || got.Sheet != "Specs" || got.Line != 5 || got.Column != 3 {
+		t.Fatalf("priority location = %+v", got)
+	}
+	if got := loc.Locate(1, "metadata.Component"); got.Cell != "D3" {
+		t.Fatalf("metadata location = %+v", got)
+	}
+	if got := loc.Locate(1, ""); got.Cell != "" || got.Line != 3 {
+		t.Fatalf("row location = %+v", got)
+	}
+	if got := loc.Locate(9, "id"); got.Line != 0 || got.URI != "book.xlsx" {
+		t.Fatalf("out of range location = %+v", got)
+	}
+}
+
+func TestSourceLocator_TextSkipsBlankLines(t *testing.T) {
+	doc := &converter.SpecDoc{Rows: []converter.SpecRow{{ID: "A", SourceRow: 2}, {ID: "B", SourceRow: 3}}}
+	loc := NewSourceLocator("spec.tsv", doc, false).WithText("ID\tTitle\nA\tx\n\n\nB\ty\n")
+	if got := loc.Locate(2, ""); got.Line != 5 || got.Cell != "" {
+		t.Fatalf("location = %+v", got)
+	}
+}
+
+func TestMarkdownLocator(t *testing.T) {
+	md := "# Spec\n\n| id | REQ-2 |\n\n#### REQ-2: Logout\n\n#### REQ-1: Login\n"
+	loc := NewMarkdownLocator("spec.mdflow.md", md, sheetDoc())
+	if got := loc.Locate(2, "title"); got.Line != 5 {
+		t.Fatalf("REQ-2 line = %d, want heading line 5", got.Line)
+	}
+	if got := loc.Locate(1, ""); got.Line != 7 {
+		t.Fatalf("REQ-1 line = %d", got.Line)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	findings := FromWarnings([]converter.Warning{
+		{Code: "VALIDATION_ENUM", Severity: converter.SeverityError, Message: "bad priority", Details: map[string]any{"row": 2, "field": "priority", "rule": "prio"}},
+		{Code: "MAPPING_LOW", Severity: converter.SeverityInfo, Message: "low confidence"},
+	})
+	findings = append(findings, FromSemantic(&ai.SemanticValidationResult{Issues: []ai.SemanticIssue{
+		{Type: "ambiguous", Severity: "warn", Message: "vague title", RowRef: 1, Field: "title"},
+	}})...)
+	Locate(findings, "book.xlsx", NewSourceLocator("book.xlsx", sheetDoc(), true))
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, &Report{Tool: "mdflow", Version: "1.0.0", URI: "book.xlsx", Findings: findings}); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("log = %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 3 || len(run.Results) != 3 {
+		t.Fatalf("rules=%d results=%d", len(run.Tool.Driver.Rules), len(run.Results))
+	}
+
+	enum := run.Results[0]
+	if enum.RuleID != "VALIDATION_ENUM/prio" || enum.Level != "error" {
+		t.Errorf("enum result = %+v", enum)
+	}
+	phys := enum.Locations[0].PhysicalLocation
+	if phys.ArtifactLocation.URI != "book.xlsx" || phys.Region == nil || phys.Region.StartLine != 5 || phys.Region.StartColumn != 3 {
+		t.Errorf("enum location = %+v", phys)
+	}
+	if logical := enum.Locations[0].LogicalLocations; len(logical) != 1 || logical[0].FullyQualifiedName != "Specs!C5" {
+		t.Errorf("logical locations = %+v", logical)
+	}
+
+	if info := run.Results[1]; info.Level != "note" || info.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("document-level result = %+v", info)
+	}
+	if sem := run.Results[2]; sem.RuleID != "SEMANTIC_AMBIGUOUS" || sem.Level != "warning" || sem.Properties["source"] != SourceSemantic {
+		t.Errorf("semantic result = %+v", sem)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	findings := []Finding{
+		{RuleID: "VALIDATION_REQUIRED", Severity: converter.SeverityWarn, Message: "missing title", Source: SourceValidation, Row: 2, Location: Location{URI: "spec.tsv", Line: 3}},
+		{RuleID: "VALIDATION_PATTERN", Severity: converter.SeverityError, Message: "bad id", Source: SourceValidation, Row: 2},
+		{RuleID: "MAPPING_LOW", Severity: converter.SeverityInfo, Message: "low confidence", Source: SourceValidation},
+		{RuleID: "QUALITY_GATE", Severity: converter.SeverityError, Message: "too few rows", Source: SourceQuality},
+	}
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, &Report{Findings: findings}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, xml.Header) {
+		t.Fatalf("missing XML header:\n%s", out)
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if suites.Tests != 3 || suites.Failures != 2 || len(suites.Suites) != 2 {
+		t.Fatalf("suites = %+v", suites)
+	}
+	validation := suites.Suites[0]
+	if validation.Name != "mdflow.validation" || validation.TestCases[0].Name != "row 2" {
+		t.Fatalf("validation suite = %+v", validation)
+	}
+	failure := validation.TestCases[0].Failure
+	if failure == nil || failure.Type != "VALIDATION_REQUIRED" || !strings.Contains(failure.Text, "spec.tsv:3") || !strings.Contains(failure.Text, "bad id") {
+		t.Errorf("row failure = %+v", failure)
+	}
+	if doc := validation.TestCases[1]; doc.Failure != nil || doc.SystemOut == "" {
+		t.Errorf("info-only case should pass with output: %+v", doc)
+	}
+}
+
+func TestWriteJUnit_CleanRunHasPassingCase(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, &Report{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `<testcase name="document" classname="mdflow.validation"></testcase>`) {
+		t.Fatalf("output:\n%s", buf.String())
+	}
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	cases := map[string]string{
+		"":                                 FormatJSON,
+		"*/*":                              FormatJSON,
+		"application/sarif+json":           FormatSARIF,
+		"text/html, application/junit+xml": FormatJUnit,
+		"application/json, application/junit+xml":                   FormatJSON,
+		"application/junit+xml;q=0.9":                               FormatJUnit,
+		"application/sarif+json; charset=utf":                       FormatSARIF,
+		"application/xml":                                           FormatJSON,
+		"text/xml, application/sarif":                               FormatJSON,
+		"application/json;q=0.5, application/sarif+json":            FormatSARIF,
+		"application/sarif+json;q=0.2, application/junit+xml;q=0.8": FormatJUnit,
+		"application/junit+xml;q=0":                                 FormatJSON,
+	}
+	for accept, want := range cases {
+		if got := NegotiateFormat(accept); got != want {
+			t.Errorf("NegotiateFormat(%q) = %q, want %q", accept, got, want)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for col, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := ColumnName(col); got != want {
+			t.Errorf("ColumnName(%d) = %q, want %q", col, got, want)
+		}
+	}
+}
+
+func TestFromWarnings_LintLines(t *testing.T) {
+	findings := FromWarnings([]converter.Warning{
+		{Code: converter.LintTableColumns, Severity: converter.SeverityError, Message: "bad row", Details: map[string]any{"line": 14}},
+	})
+	Locate(findings, "a.md", nil)
+	if got := findings[0].Location; got.URI != "a.md" || got.Line != 14 {
+		t.Fatalf("location = %+v", got)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, &Report{Findings: findings}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "[error] LINT_TABLE_COLUMNS: bad row (a.md:14)\n1 error(s), 0 warning(s), 0 info\n"; buf.String() != want {
+		t.Fatalf("text = %q", buf.String())
+	}
+}
+
+func TestWriteJUnit_MultiFileCases(t *testing.T) {
+	findings := []Finding{
+		{RuleID: "LINT_NO_TITLE", Severity: converter.SeverityWarn, Source: SourceValidation, Location: Location{URI: "a.md"}},
+		{RuleID: "LINT_NO_TITLE", Severity: converter.SeverityWarn, Source: SourceValidation, Location: Location{URI: "b.md"}},
+	}
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, &Report{Findings: findings}); err != nil {
+		t.Fatal(err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatal(err)
+	}
+	cases := suites.Suites[0].TestCases
+	if len(cases) != 2 || cases[0].Name != "a.md document" || cases[1].Name != "b.md document" {
+		t.Fatalf("cases = %+v", cases)
+	}
+}
@@ -0,0 +1,166 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool      sarifTool       `json:"tool"`
+	Artifacts []sarifArtifact `json:"artifacts,omitempty"`
+	Results   []sarifResult   `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifArtifact struct {
+	Location sarifArtifactLocation `json:"location"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	RuleIndex  int             `json:"ruleIndex"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations,omitempty"`
+	Properties map[string]any  `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSARIF writes the report as a SARIF 2.1.0 log with one run. Sheet
+// findings keep their line (the sheet row) and add the cell as a logical location.
+func WriteSARIF(w io.Writer, r *Report) error {
+	tool := r.Tool
+	if tool == "" {
+		tool = "mdflow"
+	}
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: tool, Version: r.Version, Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+	if r.URI != "" {
+		run.Artifacts = []sarifArtifact{{Location: sarifArtifactLocation{URI: r.URI}}}
+	}
+
+	ruleIndex := map[string]int{}
+	for _, f := range r.Findings {
+		idx, ok := ruleIndex[f.RuleID]
+		if !ok {
+			idx = len(run.Tool.Driver.Rules)
+			ruleIndex[f.RuleID] = idx
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: f.RuleID, ShortDescription: sarifMessage{Text: f.Message}})
+		}
+
+		result := sarifResult{
+			RuleID:     f.RuleID,
+			RuleIndex:  idx,
+			Level:      sarifLevel(f.Severity),
+			Message:    sarifMessage{Text: f.Message},
+			Properties: map[string]any{"source": f.Source},
+		}
+		if f.Hint != "" {
+			result.Properties["hint"] = f.Hint
+		}
+		if f.Row > 0 {
+			result.Properties["row"] = f.Row
+		}
+		if f.Field != "" {
+			result.Properties["field"] = f.Field
+		}
+		if loc, ok := sarifLocationFor(f.Location, r.URI); ok {
+			result.Locations = []sarifLocation{loc}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+func sarifLocationFor(l Location, fallbackURI string) (sarifLocation, bool) {
+	uri := l.URI
+	if uri == "" {
+		uri = fallbackURI
+	}
+	if uri == "" {
+		return sarifLocation{}, false
+	}
+	loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}}}
+	if l.Line > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: l.Line, StartColumn: l.Column}
+	}
+	if l.Cell != "" {
+		name := l.Cell
+		if l.Sheet != "" {
+			name = l.Sheet + "!" + l.Cell
+		}
+		loc.LogicalLocations = []sarifLogicalLocation{{Name: l.Cell, FullyQualifiedName: name, Kind: "element"}}
+	}
+	return loc, true
+}
+
+func sarifLevel(s converter.WarningSeverity) string {
+	switch s {
+	case converter.SeverityError:
+		return "error"
+	case converter.SeverityInfo:
+		return "note"
+	default:
+		return "warning"
+	}
+}
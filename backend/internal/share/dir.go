@@ -0,0 +1,153 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrReadOnly is returned by the write methods of a DirStore.
+var ErrReadOnly = errors.New("share store is read-only")
+
+// DirFileSuffix marks the files a DirStore serves.
+const DirFileSuffix = ".mdflow.md"
+
+// DirStore serves the .mdflow.md files under a directory as public, view-only
+// shares titled by their path. A file's slug is its path without the suffix,
+// slugified ("specs/Login Flow.mdflow.md" is "specs-login-flow"). Files are
+// re-read on every call, so edits show up without a restart.
+type DirStore struct {
+	dir string
+}
+
+// NewDirStore returns a store for dir, which must exist.
+func NewDirStore(dir string) (*DirStore, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return &DirStore{dir: dir}, nil
+}
+
+// Dir returns the directory being served.
+func (s *DirStore) Dir() string {
+	return s.dir
+}
+
+func (s *DirStore) GetShare(key string) (*Share, error) {
+	files, err := s.scan()
+	if err != nil {
+		return nil, err
+	}
+	path, ok := files[key]
+	if !ok {
+		return nil, ErrShareNotFound
+	}
+	return s.load(key, path)
+}
+
+func (s *DirStore) ListPublic() []*Share {
+	files, err := s.scan()
+	if err != nil {
+		return []*Share{}
+	}
+	result := make([]*Share, 0, len(files))
+	for slug, path := range files {
+		if share, err := s.load(slug, path); err == nil {
+			result = append(result, share)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].Slug < result[j].Slug
+	})
+	return result
+}
+
+func (s *DirStore) ListComments(key string) ([]Comment, error) {
+	if _, err := s.GetShare(key); err != nil {
+		return nil, err
+	}
+	return []Comment{}, nil
+}
+
+func (s *DirStore) CreateShare(CreateShareInput) (*Share, error) { return nil, ErrReadOnly }
+
+func (s *DirStore) UpdateShare(string, *bool, *bool) (*Share, error) { return nil, ErrReadOnly }
+
+func (s *DirStore) AddComment(string, CommentInput) (Comment, error) { return Comment{}, ErrReadOnly }
+
+func (s *DirStore) UpdateComment(string, string, bool) (Comment, error) {
+	return Comment{}, ErrReadOnly
+}
+
+func (s *DirStore) UpdateContent(string, string, string) (*Share, error) { return nil, ErrReadOnly }
+
+// scan maps slugs to files. When two files slugify alike, the first in path
+// order wins.
+func (s *DirStore) scan() (map[string]string, error) {
+	files := map[string]string{}
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != s.dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), DirFileSuffix) {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		slug := normalizeSlug(filepath.ToSlash(strings.TrimSuffix(rel, DirFileSuffix)), "")
+		if _, exists := files[slug]; slug != "" && !exists {
+			files[slug] = path
+		}
+		return nil
+	})
+	return files, err
+}
+
+func (s *DirStore) load(slug, path string) (*Share, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(s.dir, path)
+	if err != nil {
+		rel = filepath.Base(path)
+	}
+	return &Share{
+		Token:            slug,
+		Slug:             slug,
+		Title:            filepath.ToSlash(strings.TrimSuffix(rel, DirFileSuffix)),
+		MDFlow:           string(data),
+		IsPublic:         true,
+		Permission:       PermissionView,
+		CreatedAt:        info.ModTime().UTC(),
+		UpdatedAt:        info.ModTime().UTC(),
+		Comments:         []Comment{},
+		ResolutionEvents: []Event{},
+	}, nil
+}
+
+// Ensure DirStore implements the interface
+var _ StoreInterface = (*DirStore)(nil)
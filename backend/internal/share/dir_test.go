@@ -0,0 +1,61 @@
+package share
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirStore(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("specs/Login Flow.mdflow.md", "---\nname: \"Login\"\n---\n# Login\n")
+	write("overview.mdflow.md", "# Overview\n")
+	write("notes.md", "not served")
+	write(".git/x.mdflow.md", "hidden")
+
+	store, err := NewDirStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := store.ListPublic()
+	if len(items) != 2 {
+		t.Fatalf("ListPublic = %+v", items)
+	}
+
+	got, err := store.GetShare("specs-login-flow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "specs/Login Flow" || !got.IsPublic || got.Permission != PermissionView || got.MDFlow != "---\nname: \"Login\"\n---\n# Login\n" {
+		t.Errorf("share = %+v", got)
+	}
+	if got, _ := store.GetShare("overview"); got == nil || got.Title != "overview" {
+		t.Errorf("overview = %+v", got)
+	}
+
+	// Edits are picked up without reopening the store.
+	write("overview.mdflow.md", "# Overview v2\n")
+	if got, _ := store.GetShare("overview"); got == nil || got.MDFlow != "# Overview v2\n" {
+		t.Errorf("overview after edit = %+v", got)
+	}
+
+	if _, err := store.GetShare("notes"); !errors.Is(err, ErrShareNotFound) {
+		t.Errorf("GetShare(notes) err = %v", err)
+	}
+	if _, err := store.AddComment("overview", CommentInput{Message: "hi"}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("AddComment err = %v", err)
+	}
+	if _, err := NewDirStore(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
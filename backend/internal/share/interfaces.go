@@ -13,6 +13,7 @@ type StoreWriter interface {
 	UpdateShare(key string, isPublic *bool, allowComments *bool) (*Share, error)
 	AddComment(key string, input CommentInput) (Comment, error)
 	UpdateComment(key, commentID string, resolved bool) (Comment, error)
+	UpdateContent(key, mdflow, source string) (*Share, error)
 }
 
 // Store is a complete interface combining read and write operations
@@ -2,7 +2,10 @@ package share
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"log/slog"
@@ -29,6 +32,7 @@ var (
 	ErrCommentsDisabled  = errors.New("comments disabled")
 	ErrInvalidPermission = errors.New("invalid permission")
 	ErrStoreFull         = errors.New("share limit exceeded")
+	ErrNotOwner          = errors.New("not the share owner")
 )
 
 type Share struct {
@@ -41,8 +45,15 @@ type Share struct {
 	AllowComments    bool       `json:"allow_comments"`
 	Permission       Permission `json:"permission"`
 	CreatedAt        time.Time  `json:"created_at"`
+	Revision         int        `json:"revision"`
+	UpdatedAt        time.Time  `json:"updated_at,omitempty"`
 	Comments         []Comment  `json:"comments"`
 	ResolutionEvents []Event    `json:"resolution_events"`
+	OwnerKeyHash     string     `json:"owner_key_hash,omitempty"`
+
+	// OwnerKey is set only on the share returned by CreateShare: the secret
+	// that proves ownership (VerifyOwner). Only its hash is stored.
+	OwnerKey string `json:"-"`
 }
 
 type Comment struct {
@@ -119,6 +130,10 @@ func (s *Store) CreateShare(input CreateShareInput) (*Share, error) {
 	if err != nil {
 		return nil, err
 	}
+	ownerKey, err := generateToken(24)
+	if err != nil {
+		return nil, err
+	}
 
 	slug := strings.TrimSpace(input.Slug)
 	if input.IsPublic {
@@ -162,6 +177,7 @@ func (s *Store) CreateShare(input CreateShareInput) (*Share, error) {
 		CreatedAt:        time.Now().UTC(),
 		Comments:         []Comment{},
 		ResolutionEvents: []Event{},
+		OwnerKeyHash:     hashOwnerKey(ownerKey),
 	}
 
 	s.shares[token] = share
@@ -172,7 +188,27 @@ func (s *Store) CreateShare(input CreateShareInput) (*Share, error) {
 		return nil, err
 	}
 
-	return share, nil
+	created := s.cloneShare(share)
+	created.OwnerKey = ownerKey
+	return created, nil
+}
+
+// VerifyOwner checks that token is a share's token (not its public slug) and
+// ownerKey the owner key returned when it was created. Shares created before
+// owner keys existed have none and cannot be verified.
+func (s *Store) VerifyOwner(token, ownerKey string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	share, ok := s.shares[token]
+	if !ok {
+		return ErrShareNotFound
+	}
+	if share.OwnerKeyHash == "" || ownerKey == "" ||
+		subtle.ConstantTimeCompare([]byte(share.OwnerKeyHash), []byte(hashOwnerKey(ownerKey))) != 1 {
+		return ErrNotOwner
+	}
+	return nil
 }
 
 func (s *Store) GetShare(key string) (*Share, error) {
@@ -258,6 +294,33 @@ func (s *Store) UpdateShare(key string, isPublic *bool, allowComments *bool) (*S
 	return share, nil
 }
 
+// UpdateContent replaces the share's MDFlow with a new revision and records a
+// "content_updated" event whose data names the source of the update.
+func (s *Store) UpdateContent(key, mdflow, source string) (*Share, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	share, err := s.getShareLocked(key)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	share.MDFlow = mdflow
+	share.Revision++
+	share.UpdatedAt = now
+	share.ResolutionEvents = append(share.ResolutionEvents, Event{
+		EventType: "content_updated",
+		Timestamp: now,
+		Data:      source,
+	})
+
+	if err := s.saveToDiskLocked(); err != nil {
+		return nil, err
+	}
+	return s.cloneShare(share), nil
+}
+
 func (s *Store) ListComments(key string) ([]Comment, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -382,6 +445,31 @@ func (s *Store) loadFromDisk() {
 	}
 }
 
+// CheckWritable reports whether the backing file's directory accepts writes,
+// so readiness probes can flag shares that would be lost on restart.
+// An in-memory store (empty path) is always writable.
+func (s *Store) CheckWritable() error {
+	if s.path == "" {
+		return nil
+	}
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	probe, err := os.CreateTemp(dir, ".share-probe-*")
+	if err != nil {
+		return err
+	}
+	name := probe.Name()
+	_ = probe.Close()
+	return os.Remove(name)
+}
+
+// Path returns the backing file path ("" for an in-memory store).
+func (s *Store) Path() string {
+	return s.path
+}
+
 func (s *Store) saveToDiskLocked() error {
 	if s.path == "" {
 		return nil
@@ -454,6 +542,11 @@ func generateToken(bytesLen int) (string, error) {
 	return base64.RawURLEncoding.EncodeToString(buf), nil
 }
 
+func hashOwnerKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
 func generateCommentID() string {
 	token, err := generateToken(6)
 	if err != nil {
@@ -480,8 +573,11 @@ func (s *Store) cloneShare(share *Share) *Share {
 		AllowComments:    share.AllowComments,
 		Permission:       share.Permission,
 		CreatedAt:        share.CreatedAt,
+		Revision:         share.Revision,
+		UpdatedAt:        share.UpdatedAt,
 		Comments:         comments,
 		ResolutionEvents: events,
+		OwnerKeyHash:     share.OwnerKeyHash,
 	}
 }
 
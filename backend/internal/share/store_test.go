@@ -205,3 +205,36 @@ func TestCommentResolutionBackwardCompat(t *testing.T) {
 		t.Errorf("Expected empty ResolutionEvents, got %d", len(share.ResolutionEvents))
 	}
 }
+
+func TestUpdateContentRecordsRevision(t *testing.T) {
+	store := NewStore("")
+
+	share, err := store.CreateShare(CreateShareInput{
+		Title:  "Synced Spec",
+		MDFlow: "# v1",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create share: %v", err)
+	}
+	if share.Revision != 0 {
+		t.Fatalf("Expected new share at revision 0, got %d", share.Revision)
+	}
+
+	updated, err := store.UpdateContent(share.Token, "# v2", "sheetwatch:watch-1")
+	if err != nil {
+		t.Fatalf("UpdateContent failed: %v", err)
+	}
+	if updated.MDFlow != "# v2" || updated.Revision != 1 {
+		t.Errorf("Expected mdflow '# v2' at revision 1, got %q at %d", updated.MDFlow, updated.Revision)
+	}
+	if len(updated.ResolutionEvents) != 1 || updated.ResolutionEvents[0].EventType != "content_updated" {
+		t.Fatalf("Expected one content_updated event, got %+v", updated.ResolutionEvents)
+	}
+	if updated.ResolutionEvents[0].Data != "sheetwatch:watch-1" {
+		t.Errorf("Expected event data to name the source, got %q", updated.ResolutionEvents[0].Data)
+	}
+
+	if _, err := store.UpdateContent("missing", "# v3", ""); err != ErrShareNotFound {
+		t.Errorf("Expected ErrShareNotFound, got %v", err)
+	}
+}
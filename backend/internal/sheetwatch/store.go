@@ -0,0 +1,555 @@
+package sheetwatch
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourorg/md-spec-tool/internal/diff"
+
+	_ "modernc.org/sqlite"
+)
+
+var (
+	ErrWatchNotFound   = errors.New("watch not found")
+	ErrInvalidInterval = errors.New("invalid watch interval")
+	ErrMissingSheetID  = errors.New("sheet id is required")
+	ErrStoreFull       = errors.New("watch limit exceeded")
+	ErrNotOwner        = errors.New("not the watch owner")
+)
+
+// MaxVersions is the number of spec versions retained per watch (oldest are dropped).
+const MaxVersions = 20
+
+// Watch is a Google Sheet that is periodically re-fetched and re-converted.
+type Watch struct {
+	ID              string            `json:"id"`
+	SheetURL        string            `json:"sheet_url"`
+	SheetID         string            `json:"sheet_id"`
+	GID             string            `json:"gid,omitempty"`
+	Range           string            `json:"range,omitempty"`
+	Template        string            `json:"template"`
+	Format          string            `json:"format"`
+	ColumnOverrides map[string]string `json:"column_overrides,omitempty"`
+	Interval        time.Duration     `json:"interval"`
+	ShareKey        string            `json:"share_key,omitempty"` // share updated with a new revision on change
+	LastHash        string            `json:"last_hash,omitempty"`
+	LastError       string            `json:"last_error,omitempty"`
+	LastCheckedAt   time.Time         `json:"last_checked_at"`
+	LastChangedAt   time.Time         `json:"last_changed_at"`
+	NextCheckAt     time.Time         `json:"next_check_at"`
+	CreatedAt       time.Time         `json:"created_at"`
+	Versions        []Version         `json:"versions"`
+	OwnerKeyHash    string            `json:"owner_key_hash,omitempty"`
+
+	// OwnerKey is set only on the watch returned by Create: the secret that
+	// proves ownership (VerifyOwner). Only its hash is stored.
+	OwnerKey string `json:"-"`
+}
+
+// Version is one generated spec for a distinct sheet content hash.
+type Version struct {
+	Number    int               `json:"number"`
+	Hash      string            `json:"hash"`
+	MDFlow    string            `json:"mdflow"`
+	Diff      *diff.UnifiedDiff `json:"diff,omitempty"` // against the previous version; nil for the first
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// LatestVersion returns the most recent version, or nil before the first sync.
+func (w *Watch) LatestVersion() *Version {
+	if len(w.Versions) == 0 {
+		return nil
+	}
+	return &w.Versions[len(w.Versions)-1]
+}
+
+type CreateWatchInput struct {
+	SheetURL        string
+	SheetID         string
+	GID             string
+	Range           string
+	Template        string
+	Format          string
+	ColumnOverrides map[string]string
+	Interval        time.Duration
+	ShareKey        string
+	// OwnerKey groups the watch with others created with the same key; a new
+	// key is generated when empty.
+	OwnerKey string
+}
+
+// UpdateWatchInput holds optional changes; nil fields are left untouched.
+type UpdateWatchInput struct {
+	Interval *time.Duration
+	ShareKey *string
+}
+
+// Store persists watches and their spec versions in SQLite. Each check
+// updates one watch row (and inserts one version on change) rather than
+// rewriting every watch.
+type Store struct {
+	db          *sql.DB
+	mu          sync.Mutex // serialises writes
+	minInterval time.Duration
+	maxWatches  int // 0 = unlimited
+}
+
+// NewStore opens (or creates) a SQLite watch database at dbPath.
+// If dbPath is empty, ":memory:" is used. Intervals shorter than minInterval are rejected.
+func NewStore(dbPath string, minInterval time.Duration, maxWatches int) (*Store, error) {
+	dbPath = strings.TrimSpace(dbPath)
+	if dbPath == "" {
+		dbPath = ":memory:"
+	}
+
+	if dbPath != ":memory:" {
+		dir := filepath.Dir(dbPath)
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("sheetwatch: create dir %q: %w", dir, err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("sheetwatch: open db: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := initWatchSchema(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &Store{db: db, minInterval: minInterval, maxWatches: maxWatches}, nil
+}
+
+func initWatchSchema(db *sql.DB) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS watches (
+			id               TEXT PRIMARY KEY,
+			sheet_url        TEXT NOT NULL DEFAULT '',
+			sheet_id         TEXT NOT NULL,
+			gid              TEXT NOT NULL DEFAULT '',
+			sheet_range      TEXT NOT NULL DEFAULT '',
+			template         TEXT NOT NULL DEFAULT '',
+			format           TEXT NOT NULL DEFAULT '',
+			column_overrides TEXT NOT NULL DEFAULT '',
+			interval_ns      INTEGER NOT NULL,
+			share_key        TEXT NOT NULL DEFAULT '',
+			last_hash        TEXT NOT NULL DEFAULT '',
+			last_error       TEXT NOT NULL DEFAULT '',
+			last_checked_at  INTEGER NOT NULL DEFAULT 0,
+			last_changed_at  INTEGER NOT NULL DEFAULT 0,
+			next_check_at    INTEGER NOT NULL DEFAULT 0,
+			created_at       INTEGER NOT NULL,
+			owner_key_hash   TEXT NOT NULL DEFAULT ''
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_watches_owner ON watches(owner_key_hash)`,
+		`CREATE INDEX IF NOT EXISTS idx_watches_next_check ON watches(next_check_at)`,
+		`CREATE TABLE IF NOT EXISTS watch_versions (
+			watch_id   TEXT NOT NULL,
+			number     INTEGER NOT NULL,
+			hash       TEXT NOT NULL,
+			mdflow     TEXT NOT NULL,
+			diff       TEXT NOT NULL DEFAULT '',
+			created_at INTEGER NOT NULL,
+			PRIMARY KEY (watch_id, number)
+		)`,
+	}
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("sheetwatch: init schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close releases the database handle.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) Create(input CreateWatchInput) (*Watch, error) {
+	if strings.TrimSpace(input.SheetID) == "" {
+		return nil, ErrMissingSheetID
+	}
+	if input.Interval < s.minInterval || input.Interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+	ownerKey := input.OwnerKey
+	if ownerKey == "" {
+		key, err := generateOwnerKey()
+		if err != nil {
+			return nil, err
+		}
+		ownerKey = key
+	}
+	overrides, err := encodeOverrides(input.ColumnOverrides)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond) // stored at millisecond precision
+	watch := &Watch{
+		ID:              generateWatchID(),
+		SheetURL:        input.SheetURL,
+		SheetID:         input.SheetID,
+		GID:             input.GID,
+		Range:           input.Range,
+		Template:        input.Template,
+		Format:          input.Format,
+		ColumnOverrides: input.ColumnOverrides,
+		Interval:        input.Interval,
+		ShareKey:        input.ShareKey,
+		NextCheckAt:     now,
+		CreatedAt:       now,
+		Versions:        []Version{},
+		OwnerKeyHash:    hashOwnerKey(ownerKey),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxWatches > 0 {
+		var count int
+		if err := s.db.QueryRow(`SELECT COUNT(*) FROM watches`).Scan(&count); err != nil {
+			return nil, fmt.Errorf("sheetwatch: count watches: %w", err)
+		}
+		if count >= s.maxWatches {
+			return nil, ErrStoreFull
+		}
+	}
+	_, err = s.db.Exec(
+		`INSERT INTO watches (id, sheet_url, sheet_id, gid, sheet_range, template, format, column_overrides,
+			interval_ns, share_key, next_check_at, created_at, owner_key_hash)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		watch.ID, watch.SheetURL, watch.SheetID, watch.GID, watch.Range, watch.Template, watch.Format, overrides,
+		int64(watch.Interval), watch.ShareKey, toMillis(watch.NextCheckAt), toMillis(watch.CreatedAt), watch.OwnerKeyHash,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sheetwatch: insert watch: %w", err)
+	}
+	watch.OwnerKey = ownerKey
+	return watch, nil
+}
+
+// VerifyOwner checks that ownerKey is the owner key of watch id. Watches
+// created before owner keys existed have none and cannot be verified.
+func (s *Store) VerifyOwner(id, ownerKey string) error {
+	watch := Watch{}
+	err := s.db.QueryRow(`SELECT owner_key_hash FROM watches WHERE id = ?`, id).Scan(&watch.OwnerKeyHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrWatchNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("sheetwatch: read owner: %w", err)
+	}
+	if !watch.ownedBy(ownerKey) {
+		return ErrNotOwner
+	}
+	return nil
+}
+
+// Get returns a watch with its versions, oldest first.
+func (s *Store) Get(id string) (*Watch, error) {
+	watch, err := scanWatch(s.db.QueryRow(`SELECT `+watchColumns+` FROM watches WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrWatchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sheetwatch: read watch: %w", err)
+	}
+	if watch.Versions, err = s.versions(id); err != nil {
+		return nil, err
+	}
+	return watch, nil
+}
+
+// ListByOwner returns the watches created with ownerKey, newest first.
+func (s *Store) ListByOwner(ownerKey string) []*Watch {
+	result := make([]*Watch, 0)
+	if ownerKey == "" {
+		return result
+	}
+	rows, err := s.db.Query(`SELECT `+watchColumns+` FROM watches WHERE owner_key_hash = ? ORDER BY created_at DESC, id`, hashOwnerKey(ownerKey))
+	if err != nil {
+		slog.Warn("sheetwatch: list watches failed", "error", err)
+		return result
+	}
+	for rows.Next() {
+		watch, err := scanWatch(rows)
+		if err != nil {
+			slog.Warn("sheetwatch: scan watch failed", "error", err)
+			continue
+		}
+		result = append(result, watch)
+	}
+	rows.Close()
+
+	for _, watch := range result {
+		versions, err := s.versions(watch.ID)
+		if err != nil {
+			slog.Warn("sheetwatch: list versions failed", "watch_id", watch.ID, "error", err)
+			continue
+		}
+		watch.Versions = versions
+	}
+	return result
+}
+
+// Due returns the IDs of watches whose next check is at or before now.
+func (s *Store) Due(now time.Time) []string {
+	ids := make([]string, 0)
+	rows, err := s.db.Query(`SELECT id FROM watches WHERE next_check_at <= ? ORDER BY id`, toMillis(now))
+	if err != nil {
+		slog.Warn("sheetwatch: list due watches failed", "error", err)
+		return ids
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			slog.Warn("sheetwatch: scan due watch failed", "error", err)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (s *Store) Update(id string, input UpdateWatchInput) (*Watch, error) {
+	if input.Interval != nil && (*input.Interval < s.minInterval || *input.Interval <= 0) {
+		return nil, ErrInvalidInterval
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watch, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Interval != nil {
+		watch.Interval = *input.Interval
+		watch.NextCheckAt = watch.LastCheckedAt.Add(watch.Interval)
+	}
+	if input.ShareKey != nil {
+		watch.ShareKey = strings.TrimSpace(*input.ShareKey)
+	}
+	_, err = s.db.Exec(`UPDATE watches SET interval_ns = ?, next_check_at = ?, share_key = ? WHERE id = ?`,
+		int64(watch.Interval), toMillis(watch.NextCheckAt), watch.ShareKey, id)
+	if err != nil {
+		return nil, fmt.Errorf("sheetwatch: update watch: %w", err)
+	}
+	return watch, nil
+}
+
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("sheetwatch: begin delete: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM watches WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("sheetwatch: delete watch: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrWatchNotFound
+	}
+	if _, err := tx.Exec(`DELETE FROM watch_versions WHERE watch_id = ?`, id); err != nil {
+		return fmt.Errorf("sheetwatch: delete versions: %w", err)
+	}
+	return tx.Commit()
+}
+
+// recordCheck stores the outcome of a sync. A non-nil version is appended to
+// the history and versions beyond MaxVersions are dropped.
+func (s *Store) recordCheck(id string, checkedAt time.Time, hash string, version *Version, checkErr error) (*Watch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("sheetwatch: begin check: %w", err)
+	}
+	defer tx.Rollback()
+
+	var interval int64
+	var lastHash string
+	var lastChanged int64
+	err = tx.QueryRow(`SELECT interval_ns, last_hash, last_changed_at FROM watches WHERE id = ?`, id).Scan(&interval, &lastHash, &lastChanged)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrWatchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sheetwatch: read watch: %w", err)
+	}
+
+	lastError := ""
+	if checkErr != nil {
+		lastError = checkErr.Error()
+	} else {
+		lastHash = hash
+	}
+	if version != nil {
+		var latest int
+		if err := tx.QueryRow(`SELECT COALESCE(MAX(number), 0) FROM watch_versions WHERE watch_id = ?`, id).Scan(&latest); err != nil {
+			return nil, fmt.Errorf("sheetwatch: read latest version: %w", err)
+		}
+		version.Number = latest + 1
+		encodedDiff := ""
+		if version.Diff != nil {
+			data, err := json.Marshal(version.Diff)
+			if err != nil {
+				return nil, fmt.Errorf("sheetwatch: encode diff: %w", err)
+			}
+			encodedDiff = string(data)
+		}
+		_, err := tx.Exec(`INSERT INTO watch_versions (watch_id, number, hash, mdflow, diff, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, version.Number, version.Hash, version.MDFlow, encodedDiff, toMillis(version.CreatedAt))
+		if err != nil {
+			return nil, fmt.Errorf("sheetwatch: insert version: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM watch_versions WHERE watch_id = ? AND number <= ?`, id, version.Number-MaxVersions); err != nil {
+			return nil, fmt.Errorf("sheetwatch: drop old versions: %w", err)
+		}
+		lastChanged = toMillis(checkedAt)
+	}
+
+	_, err = tx.Exec(`UPDATE watches SET last_checked_at = ?, next_check_at = ?, last_error = ?, last_hash = ?, last_changed_at = ? WHERE id = ?`,
+		toMillis(checkedAt), toMillis(checkedAt.Add(time.Duration(interval))), lastError, lastHash, lastChanged, id)
+	if err != nil {
+		return nil, fmt.Errorf("sheetwatch: update watch: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("sheetwatch: commit check: %w", err)
+	}
+	return s.Get(id)
+}
+
+// versions returns the stored versions of a watch, oldest first.
+func (s *Store) versions(id string) ([]Version, error) {
+	rows, err := s.db.Query(`SELECT number, hash, mdflow, diff, created_at FROM watch_versions WHERE watch_id = ? ORDER BY number`, id)
+	if err != nil {
+		return nil, fmt.Errorf("sheetwatch: read versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]Version, 0)
+	for rows.Next() {
+		var v Version
+		var encodedDiff string
+		var createdAt int64
+		if err := rows.Scan(&v.Number, &v.Hash, &v.MDFlow, &encodedDiff, &createdAt); err != nil {
+			return nil, fmt.Errorf("sheetwatch: scan version: %w", err)
+		}
+		if encodedDiff != "" {
+			v.Diff = &diff.UnifiedDiff{}
+			if err := json.Unmarshal([]byte(encodedDiff), v.Diff); err != nil {
+				return nil, fmt.Errorf("sheetwatch: decode diff: %w", err)
+			}
+		}
+		v.CreatedAt = fromMillis(createdAt)
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+const watchColumns = `id, sheet_url, sheet_id, gid, sheet_range, template, format, column_overrides, interval_ns,
+	share_key, last_hash, last_error, last_checked_at, last_changed_at, next_check_at, created_at, owner_key_hash`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanWatch reads a row selected with watchColumns; versions are loaded separately.
+func scanWatch(row rowScanner) (*Watch, error) {
+	var w Watch
+	var overrides string
+	var interval, lastChecked, lastChanged, nextCheck, created int64
+	err := row.Scan(&w.ID, &w.SheetURL, &w.SheetID, &w.GID, &w.Range, &w.Template, &w.Format, &overrides, &interval,
+		&w.ShareKey, &w.LastHash, &w.LastError, &lastChecked, &lastChanged, &nextCheck, &created, &w.OwnerKeyHash)
+	if err != nil {
+		return nil, err
+	}
+	if overrides != "" {
+		if err := json.Unmarshal([]byte(overrides), &w.ColumnOverrides); err != nil {
+			return nil, fmt.Errorf("decode column overrides: %w", err)
+		}
+	}
+	w.Interval = time.Duration(interval)
+	w.LastCheckedAt = fromMillis(lastChecked)
+	w.LastChangedAt = fromMillis(lastChanged)
+	w.NextCheckAt = fromMillis(nextCheck)
+	w.CreatedAt = fromMillis(created)
+	w.Versions = []Version{}
+	return &w, nil
+}
+
+func encodeOverrides(overrides map[string]string) (string, error) {
+	if len(overrides) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return "", fmt.Errorf("sheetwatch: encode column overrides: %w", err)
+	}
+	return string(data), nil
+}
+
+// toMillis stores t as Unix milliseconds; the zero time is stored as 0.
+func toMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromMillis(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
+
+func (w *Watch) ownedBy(ownerKey string) bool {
+	return w.OwnerKeyHash != "" && ownerKey != "" &&
+		subtle.ConstantTimeCompare([]byte(w.OwnerKeyHash), []byte(hashOwnerKey(ownerKey))) == 1
+}
+
+func generateOwnerKey() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func hashOwnerKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateWatchID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "watch-" + time.Now().Format("20060102150405.000000")
+	}
+	return "watch-" + hex.EncodeToString(buf)
+}
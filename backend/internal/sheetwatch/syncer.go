@@ -0,0 +1,266 @@
+package sheetwatch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	"github.com/yourorg/md-spec-tool/internal/diff"
+	"github.com/yourorg/md-spec-tool/internal/share"
+	"github.com/yourorg/md-spec-tool/internal/webhook"
+)
+
+// maxConcurrentChecks bounds how many due watches CheckDue syncs at once.
+const maxConcurrentChecks = 4
+
+// Fetcher loads the current cell matrix of a watched sheet.
+// The returned sheet name is used as the conversion title.
+type Fetcher interface {
+	FetchWatchedSheet(ctx context.Context, watch Watch) (converter.CellMatrix, string, error)
+}
+
+// ShareUpdater publishes a new spec revision to a linked share.
+type ShareUpdater interface {
+	UpdateContent(key, mdflow, source string) (*share.Share, error)
+}
+
+// EventPublisher sends sheet.changed events to the registered webhook endpoints.
+type EventPublisher interface {
+	Publish(eventType string, data any)
+}
+
+// CheckResult describes the outcome of a single sync.
+type CheckResult struct {
+	Watch    *Watch   `json:"watch"`
+	Changed  bool     `json:"changed"`
+	Baseline bool     `json:"baseline"` // first successful sync; recorded without notifying
+	Version  *Version `json:"version,omitempty"`
+	ShareRev int      `json:"share_revision,omitempty"`
+}
+
+// SheetChangedEvent is the data payload of sheet.changed. It carries no share
+// key: endpoints see every event, and the key grants access to the share.
+type SheetChangedEvent struct {
+	WatchID       string    `json:"watch_id"`
+	SheetID       string    `json:"sheet_id"`
+	GID           string    `json:"gid,omitempty"`
+	Version       int       `json:"version"`
+	Hash          string    `json:"hash"`
+	AddedLines    int       `json:"added_lines"`
+	RemovedLines  int       `json:"removed_lines"`
+	ShareRevision int       `json:"share_revision,omitempty"`
+	ChangedAt     time.Time `json:"changed_at"`
+}
+
+// Syncer re-fetches watched sheets on their interval and records new spec versions.
+type Syncer struct {
+	store   *Store
+	fetcher Fetcher
+	conv    *converter.Converter
+	shares  ShareUpdater
+	events  EventPublisher
+	now     func() time.Time
+
+	locks   sync.Map // watch ID -> *sync.Mutex; one sync per watch keeps its versions and share revisions ordered
+	stopCh  chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewSyncer creates a syncer. shares may be nil (share updates are skipped).
+func NewSyncer(store *Store, fetcher Fetcher, conv *converter.Converter, shares ShareUpdater) *Syncer {
+	if conv == nil {
+		conv = converter.NewConverter()
+	}
+	return &Syncer{
+		store:   store,
+		fetcher: fetcher,
+		conv:    conv,
+		shares:  shares,
+		now:     func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// SetEventPublisher sets the publisher for sheet.changed webhooks
+func (s *Syncer) SetEventPublisher(p EventPublisher) {
+	s.events = p
+}
+
+// Start polls for due watches every tick until Stop is called.
+func (s *Syncer) Start(tick time.Duration) {
+	if tick <= 0 {
+		return
+	}
+	s.stopCh = make(chan struct{})
+	s.stopped = make(chan struct{})
+	go func() {
+		defer close(s.stopped)
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.CheckDue(context.Background())
+			}
+		}
+	}()
+	slog.Info("sheetwatch: scheduler started", "tick", tick)
+}
+
+// Stop halts the polling loop and waits for an in-flight pass to finish.
+func (s *Syncer) Stop() {
+	if s.stopCh == nil {
+		return
+	}
+	s.once.Do(func() {
+		close(s.stopCh)
+		<-s.stopped
+	})
+}
+
+// CheckDue syncs every watch whose next check time has passed, up to
+// maxConcurrentChecks at a time; each sync holds only its own watch's lock.
+func (s *Syncer) CheckDue(ctx context.Context) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, maxConcurrentChecks)
+	for _, id := range s.store.Due(s.now()) {
+		if ctx.Err() != nil {
+			break
+		}
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if _, err := s.Check(ctx, id); err != nil {
+				slog.Warn("sheetwatch: check failed", "watch_id", id, "error", err)
+			}
+		}(id)
+	}
+	wg.Wait()
+}
+
+// lock serializes syncs of one watch.
+func (s *Syncer) lock(id string) func() {
+	mu, _ := s.locks.LoadOrStore(id, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+// Check fetches a watch's sheet now. When the content hash differs from the last sync
+// it regenerates the spec, diffs it against the previous version, updates the linked
+// share and publishes sheet.changed. Fetch and conversion failures are recorded on the watch.
+func (s *Syncer) Check(ctx context.Context, id string) (*CheckResult, error) {
+	defer s.lock(id)()
+
+	watch, err := s.store.Get(id)
+	if err != nil {
+		s.locks.Delete(id)
+		return nil, err
+	}
+
+	checkedAt := s.now()
+	matrix, sheetName, err := s.fetcher.FetchWatchedSheet(ctx, *watch)
+	if err != nil {
+		return s.recordFailure(watch, checkedAt, fmt.Errorf("fetch: %w", err))
+	}
+
+	hash := HashMatrix(matrix)
+	if hash == watch.LastHash && watch.LatestVersion() != nil {
+		updated, err := s.store.recordCheck(id, checkedAt, hash, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &CheckResult{Watch: updated}, nil
+	}
+
+	result, err := s.conv.ConvertMatrixWithOverridesAndOptions(ctx, matrix, sheetName, watch.Template, watch.Format, watch.ColumnOverrides, converter.DefaultConvertOptions())
+	if err != nil {
+		return s.recordFailure(watch, checkedAt, fmt.Errorf("convert: %w", err))
+	}
+
+	version := &Version{Hash: hash, MDFlow: result.MDFlow, CreatedAt: checkedAt}
+	previous := watch.LatestVersion()
+	if previous != nil {
+		version.Diff = diff.Diff(previous.MDFlow, result.MDFlow)
+	}
+
+	updated, err := s.store.recordCheck(id, checkedAt, hash, version, nil)
+	if err != nil {
+		return nil, err
+	}
+	recorded := updated.LatestVersion()
+	check := &CheckResult{Watch: updated, Version: recorded, Baseline: previous == nil, Changed: previous != nil}
+	if check.Baseline {
+		slog.Info("sheetwatch: baseline recorded", "watch_id", id, "hash", hash)
+		return check, nil
+	}
+
+	slog.Info("sheetwatch: change detected", "watch_id", id, "version", recorded.Number,
+		"added_lines", recorded.Diff.Added, "removed_lines", recorded.Diff.Removed)
+
+	if updated.ShareKey != "" && s.shares != nil {
+		sh, err := s.shares.UpdateContent(updated.ShareKey, result.MDFlow, "sheetwatch:"+id)
+		if err != nil {
+			slog.Warn("sheetwatch: share update failed", "watch_id", id, "error", err)
+		} else {
+			check.ShareRev = sh.Revision
+		}
+	}
+	if s.events != nil {
+		s.events.Publish(webhook.EventSheetChanged, sheetChangedEvent(updated, recorded, check.ShareRev))
+	}
+	return check, nil
+}
+
+func (s *Syncer) recordFailure(watch *Watch, checkedAt time.Time, checkErr error) (*CheckResult, error) {
+	updated, err := s.store.recordCheck(watch.ID, checkedAt, "", nil, checkErr)
+	if err != nil {
+		return nil, err
+	}
+	return &CheckResult{Watch: updated}, checkErr
+}
+
+func sheetChangedEvent(watch *Watch, version *Version, shareRev int) SheetChangedEvent {
+	event := SheetChangedEvent{
+		WatchID:       watch.ID,
+		SheetID:       watch.SheetID,
+		GID:           watch.GID,
+		Version:       version.Number,
+		Hash:          version.Hash,
+		ShareRevision: shareRev,
+		ChangedAt:     version.CreatedAt,
+	}
+	if version.Diff != nil {
+		event.AddedLines = version.Diff.Added
+		event.RemovedLines = version.Diff.Removed
+	}
+	return event
+}
+
+// HashMatrix returns a hex SHA-256 of the matrix contents. Trailing empty cells are
+// ignored so that a sheet API returning ragged rows hashes the same as a padded export.
+func HashMatrix(matrix converter.CellMatrix) string {
+	h := sha256.New()
+	for _, row := range matrix {
+		end := len(row)
+		for end > 0 && row[end-1] == "" {
+			end--
+		}
+		for i := 0; i < end; i++ {
+			if i > 0 {
+				h.Write([]byte{0x1f})
+			}
+			h.Write([]byte(row[i]))
+		}
+		h.Write([]byte{0x1e})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,288 @@
+package sheetwatch
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	"github.com/yourorg/md-spec-tool/internal/share"
+	"github.com/yourorg/md-spec-tool/internal/webhook"
+)
+
+type stubFetcher struct {
+	mu     sync.Mutex
+	matrix converter.CellMatrix
+	err    error
+	calls  int
+}
+
+func (f *stubFetcher) set(matrix converter.CellMatrix, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.matrix = matrix
+	f.err = err
+}
+
+func (f *stubFetcher) FetchWatchedSheet(ctx context.Context, watch Watch) (converter.CellMatrix, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	return f.matrix, "Cases", f.err
+}
+
+type recordingPublisher struct {
+	mu     sync.Mutex
+	types  []string
+	events []SheetChangedEvent
+}
+
+func (p *recordingPublisher) Publish(eventType string, data any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.types = append(p.types, eventType)
+	p.events = append(p.events, data.(SheetChangedEvent))
+}
+
+func newTestStore(t *testing.T, path string, minInterval time.Duration) *Store {
+	t.Helper()
+	store, err := NewStore(path, minInterval, 0)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func testMatrix(expected string) converter.CellMatrix {
+	return converter.CellMatrix{
+		{"ID", "Feature", "Scenario", "Expected"},
+		{"TC-001", "Login", "Valid credentials", expected},
+		{"TC-002", "Login", "Invalid password", "Error shown"},
+	}
+}
+
+func TestHashMatrixIgnoresTrailingEmptyCells(t *testing.T) {
+	ragged := converter.CellMatrix{{"a", "b"}, {"c"}}
+	padded := converter.CellMatrix{{"a", "b", ""}, {"c", "", ""}}
+	if HashMatrix(ragged) != HashMatrix(padded) {
+		t.Error("expected ragged and padded matrices to hash the same")
+	}
+	if HashMatrix(ragged) == HashMatrix(converter.CellMatrix{{"a", "b"}, {"d"}}) {
+		t.Error("expected different content to hash differently")
+	}
+	if HashMatrix(converter.CellMatrix{{"ab"}}) == HashMatrix(converter.CellMatrix{{"a", "b"}}) {
+		t.Error("expected cell boundaries to affect the hash")
+	}
+}
+
+func TestSyncerCheck_BaselineUnchangedChanged(t *testing.T) {
+	shares := share.NewStore("")
+	sh, err := shares.CreateShare(share.CreateShareInput{Title: "Login", MDFlow: "# placeholder"})
+	if err != nil {
+		t.Fatalf("create share: %v", err)
+	}
+
+	store := newTestStore(t, "", time.Minute)
+	watch, err := store.Create(CreateWatchInput{
+		SheetID:  "SHEET123",
+		Template: "spec",
+		Format:   "spec",
+		Interval: 5 * time.Minute,
+		ShareKey: sh.Token,
+	})
+	if err != nil {
+		t.Fatalf("create watch: %v", err)
+	}
+
+	fetcher := &stubFetcher{}
+	fetcher.set(testMatrix("Dashboard shown"), nil)
+	syncer := NewSyncer(store, fetcher, converter.NewConverter(), shares)
+	events := &recordingPublisher{}
+	syncer.SetEventPublisher(events)
+	ctx := context.Background()
+
+	first, err := syncer.Check(ctx, watch.ID)
+	if err != nil {
+		t.Fatalf("baseline check: %v", err)
+	}
+	if !first.Baseline || first.Changed || first.Version == nil || first.Version.Number != 1 {
+		t.Fatalf("expected baseline version 1, got %+v", first)
+	}
+	if first.Version.Diff != nil {
+		t.Error("baseline version should not carry a diff")
+	}
+
+	second, err := syncer.Check(ctx, watch.ID)
+	if err != nil {
+		t.Fatalf("unchanged check: %v", err)
+	}
+	if second.Changed || second.Version != nil || len(second.Watch.Versions) != 1 {
+		t.Fatalf("expected no new version for identical content, got %+v", second)
+	}
+
+	fetcher.set(testMatrix("Dashboard and welcome banner shown"), nil)
+	third, err := syncer.Check(ctx, watch.ID)
+	if err != nil {
+		t.Fatalf("changed check: %v", err)
+	}
+	if !third.Changed || third.Version == nil || third.Version.Number != 2 {
+		t.Fatalf("expected changed version 2, got %+v", third)
+	}
+	if third.Version.Diff == nil || third.Version.Diff.Added == 0 || third.Version.Diff.Removed == 0 {
+		t.Errorf("expected diff against previous version, got %+v", third.Version.Diff)
+	}
+	if third.ShareRev != 1 {
+		t.Errorf("expected linked share revision 1, got %d", third.ShareRev)
+	}
+	updatedShare, _ := shares.GetShare(sh.Token)
+	if updatedShare.MDFlow != third.Version.MDFlow {
+		t.Error("expected share content to match the new version")
+	}
+
+	if len(events.events) != 1 {
+		t.Fatalf("expected 1 event (baseline and unchanged checks are silent), got %d", len(events.events))
+	}
+	if got := events.events[0]; events.types[0] != webhook.EventSheetChanged || got.Version != 2 || got.WatchID != watch.ID || got.ShareRevision != 1 {
+		t.Errorf("unexpected %s event %+v", events.types[0], got)
+	}
+}
+
+// blockingFetcher holds fetches of one sheet until release is closed.
+type blockingFetcher struct {
+	sheetID string
+	release chan struct{}
+}
+
+func (f *blockingFetcher) FetchWatchedSheet(ctx context.Context, watch Watch) (converter.CellMatrix, string, error) {
+	if watch.SheetID == f.sheetID {
+		<-f.release
+	}
+	return testMatrix("ok"), "Cases", nil
+}
+
+func TestSyncerCheck_SlowWatchDoesNotBlockOthers(t *testing.T) {
+	store := newTestStore(t, "", 0)
+	slow, _ := store.Create(CreateWatchInput{SheetID: "SLOW", Template: "spec", Format: "spec", Interval: time.Minute})
+	fast, _ := store.Create(CreateWatchInput{SheetID: "FAST", Template: "spec", Format: "spec", Interval: time.Minute})
+	fetcher := &blockingFetcher{sheetID: "SLOW", release: make(chan struct{})}
+	syncer := NewSyncer(store, fetcher, nil, nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = syncer.Check(context.Background(), slow.ID)
+	}()
+
+	checked := make(chan error, 1)
+	go func() {
+		_, err := syncer.Check(context.Background(), fast.ID)
+		checked <- err
+	}()
+	select {
+	case err := <-checked:
+		if err != nil {
+			t.Errorf("fast check: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("fast watch waited for the slow watch's fetch")
+	}
+	close(fetcher.release)
+	<-done
+}
+
+func TestSyncerCheck_RecordsFetchError(t *testing.T) {
+	store := newTestStore(t, "", 0)
+	watch, _ := store.Create(CreateWatchInput{SheetID: "S", Template: "spec", Format: "spec", Interval: time.Minute})
+
+	fetcher := &stubFetcher{}
+	fetcher.set(nil, errors.New("status 404"))
+	syncer := NewSyncer(store, fetcher, nil, nil)
+
+	result, err := syncer.Check(context.Background(), watch.ID)
+	if err == nil {
+		t.Fatal("expected fetch error")
+	}
+	if result == nil || result.Watch.LastError == "" {
+		t.Fatalf("expected error recorded on watch, got %+v", result)
+	}
+	if !result.Watch.NextCheckAt.After(result.Watch.LastCheckedAt) {
+		t.Error("expected failed check to be rescheduled")
+	}
+}
+
+func TestSyncerCheckDue_OnlyDueWatches(t *testing.T) {
+	store := newTestStore(t, "", 0)
+	due, _ := store.Create(CreateWatchInput{SheetID: "A", Template: "spec", Format: "spec", Interval: time.Hour})
+	fetcher := &stubFetcher{}
+	fetcher.set(testMatrix("ok"), nil)
+	syncer := NewSyncer(store, fetcher, nil, nil)
+
+	syncer.CheckDue(context.Background())
+	syncer.CheckDue(context.Background())
+
+	if fetcher.calls != 1 {
+		t.Errorf("expected one fetch (second pass not due yet), got %d", fetcher.calls)
+	}
+	got, _ := store.Get(due.ID)
+	if got.LatestVersion() == nil {
+		t.Error("expected baseline version after due check")
+	}
+}
+
+func TestStorePersistsAcrossReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watches.db")
+	store := newTestStore(t, path, 0)
+	watch, err := store.Create(CreateWatchInput{SheetID: "S", Template: "spec", Format: "spec", Interval: time.Minute})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	fetcher := &stubFetcher{}
+	fetcher.set(testMatrix("ok"), nil)
+	if _, err := NewSyncer(store, fetcher, nil, nil).Check(context.Background(), watch.ID); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+
+	_ = store.Close()
+
+	reloaded := newTestStore(t, path, 0)
+	got, err := reloaded.Get(watch.ID)
+	if err != nil {
+		t.Fatalf("expected watch after reload: %v", err)
+	}
+	if got.LastHash == "" || len(got.Versions) != 1 || got.Interval != time.Minute {
+		t.Errorf("unexpected reloaded watch %+v", got)
+	}
+}
+
+func TestStoreKeepsLatestVersions(t *testing.T) {
+	store := newTestStore(t, "", 0)
+	watch, _ := store.Create(CreateWatchInput{SheetID: "S", Template: "spec", Format: "spec", Interval: time.Minute})
+	at := time.Now().UTC()
+	for i := 0; i < MaxVersions+2; i++ {
+		if _, err := store.recordCheck(watch.ID, at, "h", &Version{Hash: "h", MDFlow: "v", CreatedAt: at}, nil); err != nil {
+			t.Fatalf("recordCheck: %v", err)
+		}
+	}
+	got, _ := store.Get(watch.ID)
+	if len(got.Versions) != MaxVersions || got.Versions[0].Number != 3 || got.LatestVersion().Number != MaxVersions+2 {
+		t.Fatalf("expected versions 3..%d, got %d versions from %d", MaxVersions+2, len(got.Versions), got.Versions[0].Number)
+	}
+
+	if err := store.Delete(watch.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if versions, _ := store.versions(watch.ID); len(versions) != 0 {
+		t.Errorf("expected versions deleted with the watch, got %d", len(versions))
+	}
+}
+
+func TestStoreRejectsShortInterval(t *testing.T) {
+	store := newTestStore(t, "", time.Minute)
+	if _, err := store.Create(CreateWatchInput{SheetID: "S", Interval: 10 * time.Second}); !errors.Is(err, ErrInvalidInterval) {
+		t.Errorf("expected ErrInvalidInterval, got %v", err)
+	}
+}
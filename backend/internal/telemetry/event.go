@@ -0,0 +1,147 @@
+package telemetry
+
+import (
+	"sort"
+	"time"
+)
+
+// Event is a normalized event payload used by the MVP dashboard.
+type Event struct {
+	EventName          string    `json:"event_name"`
+	EventTime          time.Time `json:"event_time"`
+	SessionID          string    `json:"session_id,omitempty"`
+	Status             string    `json:"status"`
+	InputSource        string    `json:"input_source,omitempty"`
+	TemplateType       string    `json:"template_type,omitempty"`
+	DurationMS         int64     `json:"duration_ms,omitempty"`
+	ErrorCode          string    `json:"error_code,omitempty"`
+	WarningCount       int       `json:"warning_count,omitempty"`
+	ConfidenceScore    float64   `json:"confidence_score,omitempty"`
+	NeedsReview        bool      `json:"needs_review,omitempty"`
+	TotalRows          int       `json:"total_rows,omitempty"`
+	AIModel            string    `json:"ai_model,omitempty"`
+	AIEstimatedCostUSD float64   `json:"ai_estimated_cost_usd,omitempty"`
+	AIInputTokens      int64     `json:"ai_input_tokens,omitempty"`
+	AIOutputTokens     int64     `json:"ai_output_tokens,omitempty"`
+	HTTPStatus         int       `json:"http_status,omitempty"`
+	Path               string    `json:"path,omitempty"`
+	RequestID          string    `json:"request_id,omitempty"`
+	Source             string    `json:"source"` // frontend | backend
+}
+
+// Query selects events for the dashboard. Empty string filters match everything.
+type Query struct {
+	Since        time.Time
+	Until        time.Time
+	TemplateType string
+	InputSource  string
+	AIModel      string
+}
+
+// Matches reports whether e satisfies the query.
+func (q Query) Matches(e Event) bool {
+	if !q.Since.IsZero() && e.EventTime.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !e.EventTime.Before(q.Until) {
+		return false
+	}
+	if q.TemplateType != "" && e.TemplateType != q.TemplateType {
+		return false
+	}
+	if q.InputSource != "" && e.InputSource != q.InputSource {
+		return false
+	}
+	if q.AIModel != "" && e.AIModel != q.AIModel {
+		return false
+	}
+	return true
+}
+
+// filtered reports whether q narrows events beyond their time window.
+func (q Query) filtered() bool {
+	return q.TemplateType != "" || q.InputSource != "" || q.AIModel != ""
+}
+
+// Window returns q without its template, input source and model filters.
+func (q Query) Window() Query {
+	return Query{Since: q.Since, Until: q.Until}
+}
+
+// SeriesBucket aggregates the events of one bucket. Previews, converts,
+// shares and durations come from backend API events.
+type SeriesBucket struct {
+	Start         time.Time
+	Events        int
+	Errors        int
+	Previews      int
+	Converts      int
+	Shares        int
+	P50DurationMS int64
+	P95DurationMS int64
+}
+
+// BucketEvents groups events into buckets of the given width (oldest first),
+// skipping empty buckets.
+func BucketEvents(events []Event, bucket time.Duration) []SeriesBucket {
+	type acc struct {
+		bucket    SeriesBucket
+		durations []int64
+	}
+	byStart := map[time.Time]*acc{}
+	for _, e := range events {
+		start := e.EventTime.UTC().Truncate(bucket)
+		a := byStart[start]
+		if a == nil {
+			a = &acc{bucket: SeriesBucket{Start: start}}
+			byStart[start] = a
+		}
+		a.bucket.Events++
+		if e.Status == "error" {
+			a.bucket.Errors++
+		}
+		if e.Source != "backend" {
+			continue
+		}
+		if e.DurationMS > 0 {
+			a.durations = append(a.durations, e.DurationMS)
+		}
+		if e.Status != "success" {
+			continue
+		}
+		switch e.EventName {
+		case "api_preview_completed":
+			a.bucket.Previews++
+		case "api_convert_completed":
+			a.bucket.Converts++
+		case "api_share_created":
+			a.bucket.Shares++
+		}
+	}
+
+	series := make([]SeriesBucket, 0, len(byStart))
+	for _, a := range byStart {
+		a.bucket.P50DurationMS = DurationPercentile(a.durations, 50)
+		a.bucket.P95DurationMS = DurationPercentile(a.durations, 95)
+		series = append(series, a.bucket)
+	}
+	sort.Slice(series, func(i, j int) bool { return series[i].Start.Before(series[j].Start) })
+	return series
+}
+
+// DurationPercentile returns the p-th percentile of values by nearest rank
+// below, or 0 for no values.
+func DurationPercentile(values []int64, p int) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	if p <= 0 {
+		return sorted[0]
+	}
+	if p >= 100 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[int(float64(p)/100.0*float64(len(sorted)-1))]
+}
@@ -0,0 +1,28 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketEvents(t *testing.T) {
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	events := []Event{
+		{EventName: "api_convert_completed", EventTime: base.Add(5 * time.Minute), Status: "success", Source: "backend", DurationMS: 100},
+		{EventName: "api_convert_completed", EventTime: base.Add(10 * time.Minute), Status: "success", Source: "backend", DurationMS: 300},
+		{EventName: "convert_failed", EventTime: base.Add(20 * time.Minute), Status: "error", Source: "frontend"},
+		{EventName: "api_share_created", EventTime: base.Add(70 * time.Minute), Status: "success", Source: "backend", DurationMS: 50},
+	}
+
+	series := BucketEvents(events, time.Hour)
+	if len(series) != 2 {
+		t.Fatalf("got %d buckets, want 2: %+v", len(series), series)
+	}
+	first := series[0]
+	if !first.Start.Equal(base) || first.Events != 3 || first.Errors != 1 || first.Converts != 2 || first.P50DurationMS != 100 || first.P95DurationMS != 100 {
+		t.Errorf("first bucket = %+v", first)
+	}
+	if second := series[1]; !second.Start.Equal(base.Add(time.Hour)) || second.Shares != 1 || second.P95DurationMS != 50 {
+		t.Errorf("second bucket = %+v", second)
+	}
+}
@@ -0,0 +1,353 @@
+// Package telemetry persists dashboard events to SQLite so history survives restarts.
+package telemetry
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// DefaultRetention is how long events are kept when no retention is configured.
+const DefaultRetention = 30 * 24 * time.Hour
+
+// DefaultMaxRows is the row cap applied when none is configured.
+const DefaultMaxRows = 1000000
+
+// pruneEvery bounds how often Record opportunistically deletes expired rows.
+const pruneEvery = 10 * time.Minute
+
+// Store is a middleware.TelemetrySummaryBackend (and TelemetrySeriesBackend) backed by SQLite.
+// Filterable and aggregated fields are stored as columns; the full event is kept as JSON.
+type Store struct {
+	db        *sql.DB
+	mu        sync.Mutex // serialises writes
+	retention time.Duration
+	maxRows   int64
+	lastPrune time.Time
+	inserted  int64 // rows recorded since the last prune
+	now       func() time.Time
+}
+
+// NewStore opens (or creates) a SQLite telemetry database at dbPath and prunes
+// events older than retention (<= 0 uses DefaultRetention) and the oldest
+// events beyond maxRows (<= 0 uses DefaultMaxRows).
+// If dbPath is empty, ":memory:" is used (useful for tests).
+func NewStore(dbPath string, retention time.Duration, maxRows int) (*Store, error) {
+	if dbPath == "" {
+		dbPath = ":memory:"
+	}
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	if maxRows <= 0 {
+		maxRows = DefaultMaxRows
+	}
+
+	if dbPath != ":memory:" {
+		dir := filepath.Dir(dbPath)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("telemetry: create dir %q: %w", dir, err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: open db: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := initTelemetrySchema(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	s := &Store{db: db, retention: retention, maxRows: int64(maxRows), now: time.Now}
+	if _, err := s.Prune(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func initTelemetrySchema(db *sql.DB) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS telemetry_events (
+			id            INTEGER PRIMARY KEY AUTOINCREMENT,
+			event_time    INTEGER NOT NULL,
+			event_name    TEXT    NOT NULL,
+			template_type TEXT    NOT NULL DEFAULT '',
+			input_source  TEXT    NOT NULL DEFAULT '',
+			ai_model      TEXT    NOT NULL DEFAULT '',
+			payload       TEXT    NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_telemetry_events_time ON telemetry_events(event_time)`,
+	}
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("telemetry: init schema: %w", err)
+		}
+	}
+	return addAggregateColumns(db)
+}
+
+// aggregateColumns are the columns Series and Summary aggregate, added to
+// databases created before them and backfilled from the stored payload.
+var aggregateColumns = []struct{ name, definition, payloadPath string }{
+	{"status", "TEXT NOT NULL DEFAULT ''", "$.status"},
+	{"source", "TEXT NOT NULL DEFAULT ''", "$.source"},
+	{"duration_ms", "INTEGER NOT NULL DEFAULT 0", "$.duration_ms"},
+	{"session_id", "TEXT NOT NULL DEFAULT ''", "$.session_id"},
+	{"http_status", "INTEGER NOT NULL DEFAULT 0", "$.http_status"},
+	{"ai_cost_usd", "REAL NOT NULL DEFAULT 0", "$.ai_estimated_cost_usd"},
+	{"ai_input_tokens", "INTEGER NOT NULL DEFAULT 0", "$.ai_input_tokens"},
+	{"ai_output_tokens", "INTEGER NOT NULL DEFAULT 0", "$.ai_output_tokens"},
+}
+
+func addAggregateColumns(db *sql.DB) error {
+	existing := map[string]bool{}
+	rows, err := db.Query(`SELECT name FROM pragma_table_info('telemetry_events')`)
+	if err != nil {
+		return fmt.Errorf("telemetry: read schema: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return fmt.Errorf("telemetry: read schema: %w", err)
+		}
+		existing[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("telemetry: read schema: %w", err)
+	}
+
+	for _, col := range aggregateColumns {
+		if existing[col.name] {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE telemetry_events ADD COLUMN ` + col.name + ` ` + col.definition); err != nil {
+			return fmt.Errorf("telemetry: add column %s: %w", col.name, err)
+		}
+		if _, err := db.Exec(`UPDATE telemetry_events SET `+col.name+` = COALESCE(json_extract(payload, ?), `+col.name+`)`, col.payloadPath); err != nil {
+			return fmt.Errorf("telemetry: backfill column %s: %w", col.name, err)
+		}
+	}
+	return nil
+}
+
+// Record persists one event. It prunes every pruneEvery, or sooner once a
+// tenth of maxRows has been recorded since the last prune.
+func (s *Store) Record(event Event) error {
+	if event.EventTime.IsZero() {
+		event.EventTime = s.now().UTC()
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("telemetry: encode event: %w", err)
+	}
+
+	s.mu.Lock()
+	_, err = s.db.Exec(
+		`INSERT INTO telemetry_events (event_time, event_name, template_type, input_source, ai_model, status, source, duration_ms,
+			session_id, http_status, ai_cost_usd, ai_input_tokens, ai_output_tokens, payload)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.EventTime.UnixMilli(), event.EventName, event.TemplateType, event.InputSource, event.AIModel,
+		event.Status, event.Source, event.DurationMS,
+		event.SessionID, event.HTTPStatus, event.AIEstimatedCostUSD, event.AIInputTokens, event.AIOutputTokens, string(payload),
+	)
+	if err == nil {
+		s.inserted++
+	}
+	due := s.now().Sub(s.lastPrune) >= pruneEvery || s.inserted > s.maxRows/10
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("telemetry: insert event: %w", err)
+	}
+
+	if due {
+		if _, err := s.Prune(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Query returns events matching q in chronological order.
+func (s *Store) Query(q Query) ([]Event, error) {
+	where, args := queryConditions(q)
+	query := `SELECT payload FROM telemetry_events`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY event_time, id"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var payload string
+		if err := rows.Scan(&payload); err != nil {
+			return nil, fmt.Errorf("telemetry: scan event: %w", err)
+		}
+		var event Event
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			continue // skip rows written by an incompatible version
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// Series aggregates events matching q into buckets of the given width (oldest
+// first) in SQL, with the same counts and duration percentiles as
+// BucketEvents.
+func (s *Store) Series(q Query, bucket time.Duration) ([]SeriesBucket, error) {
+	width := bucket.Milliseconds()
+	if width <= 0 {
+		return nil, fmt.Errorf("telemetry: invalid bucket width %s", bucket)
+	}
+	where, args := queryConditions(q)
+	filter := ""
+	if len(where) > 0 {
+		filter = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	counts := `SELECT bucket,
+			COUNT(*),
+			SUM(status = 'error'),
+			SUM(source = 'backend' AND status = 'success' AND event_name = 'api_preview_completed'),
+			SUM(source = 'backend' AND status = 'success' AND event_name = 'api_convert_completed'),
+			SUM(source = 'backend' AND status = 'success' AND event_name = 'api_share_created')
+		FROM (SELECT event_time - event_time % ? AS bucket, status, source, event_name FROM telemetry_events` + filter + `)
+		GROUP BY bucket
+		ORDER BY bucket`
+	rows, err := s.db.Query(counts, append([]any{width}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: query series: %w", err)
+	}
+	var series []SeriesBucket
+	index := map[int64]int{}
+	for rows.Next() {
+		var start int64
+		var b SeriesBucket
+		if err := rows.Scan(&start, &b.Events, &b.Errors, &b.Previews, &b.Converts, &b.Shares); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("telemetry: scan series: %w", err)
+		}
+		b.Start = time.UnixMilli(start).UTC()
+		index[start] = len(series)
+		series = append(series, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("telemetry: query series: %w", err)
+	}
+
+	// Percentiles pick the row at index floor(p/100 * (n-1)) of each bucket's
+	// sorted durations, as DurationPercentile does.
+	durationFilter := " WHERE source = 'backend' AND duration_ms > 0"
+	if len(where) > 0 {
+		durationFilter += " AND " + strings.Join(where, " AND ")
+	}
+	percentiles := `SELECT bucket,
+			MAX(CASE WHEN rank = CAST(0.5 * (n - 1) AS INTEGER) THEN duration_ms END),
+			MAX(CASE WHEN rank = CAST(0.95 * (n - 1) AS INTEGER) THEN duration_ms END)
+		FROM (
+			SELECT event_time - event_time % ? AS bucket, duration_ms,
+				ROW_NUMBER() OVER (PARTITION BY event_time - event_time % ? ORDER BY duration_ms) - 1 AS rank,
+				COUNT(*) OVER (PARTITION BY event_time - event_time % ?) AS n
+			FROM telemetry_events` + durationFilter + `
+		)
+		GROUP BY bucket`
+	rows, err = s.db.Query(percentiles, append([]any{width, width, width}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: query series durations: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var start, p50, p95 int64
+		if err := rows.Scan(&start, &p50, &p95); err != nil {
+			return nil, fmt.Errorf("telemetry: scan series durations: %w", err)
+		}
+		if i, ok := index[start]; ok {
+			series[i].P50DurationMS = p50
+			series[i].P95DurationMS = p95
+		}
+	}
+	return series, rows.Err()
+}
+
+// queryConditions returns the WHERE conditions and arguments selecting q.
+func queryConditions(q Query) ([]string, []any) {
+	var where []string
+	var args []any
+	if !q.Since.IsZero() {
+		where = append(where, "event_time >= ?")
+		args = append(args, q.Since.UnixMilli())
+	}
+	if !q.Until.IsZero() {
+		where = append(where, "event_time < ?")
+		args = append(args, q.Until.UnixMilli())
+	}
+	for _, filter := range []struct{ column, value string }{
+		{"template_type", q.TemplateType},
+		{"input_source", q.InputSource},
+		{"ai_model", q.AIModel},
+	} {
+		if filter.value != "" {
+			where = append(where, filter.column+" = ?")
+			args = append(args, filter.value)
+		}
+	}
+	return where, args
+}
+
+// Prune deletes events older than the retention window, then the oldest
+// events beyond maxRows, and returns how many were removed.
+func (s *Store) Prune() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	res, err := s.db.Exec(`DELETE FROM telemetry_events WHERE event_time < ?`, now.Add(-s.retention).UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("telemetry: prune: %w", err)
+	}
+	expired, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("telemetry: prune: %w", err)
+	}
+
+	res, err = s.db.Exec(
+		`DELETE FROM telemetry_events WHERE id IN (
+			SELECT id FROM telemetry_events ORDER BY event_time DESC, id DESC LIMIT -1 OFFSET ?
+		)`, s.maxRows)
+	if err != nil {
+		return 0, fmt.Errorf("telemetry: prune excess rows: %w", err)
+	}
+	excess, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("telemetry: prune excess rows: %w", err)
+	}
+
+	s.lastPrune = now
+	s.inserted = 0
+	return expired + excess, nil
+}
+
+// Close releases the database handle.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,313 @@
+package telemetry
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T, path string, retention time.Duration) *Store {
+	t.Helper()
+	s, err := NewStore(path, retention, 0)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestStore_QueryFilters(t *testing.T) {
+	s := newTestStore(t, "", 0)
+	base := time.Now().UTC().Add(-time.Hour)
+	events := []Event{
+		{EventName: "api_convert_completed", EventTime: base, TemplateType: "spec", InputSource: "paste", AIModel: "gpt-4o-mini", Source: "backend"},
+		{EventName: "api_convert_completed", EventTime: base.Add(time.Minute), TemplateType: "table", InputSource: "xlsx", Source: "backend"},
+		{EventName: "studio_opened", EventTime: base.Add(2 * time.Minute), Source: "frontend"},
+	}
+	for _, e := range events {
+		if err := s.Record(e); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	cases := []struct {
+		name  string
+		query Query
+		want  int
+	}{
+		{"all", Query{}, 3},
+		{"template", Query{TemplateType: "spec"}, 1},
+		{"input source", Query{InputSource: "xlsx"}, 1},
+		{"ai model", Query{AIModel: "gpt-4o-mini"}, 1},
+		{"since", Query{Since: base.Add(30 * time.Second)}, 2},
+		{"until", Query{Until: base.Add(30 * time.Second)}, 1},
+		{"combined", Query{TemplateType: "spec", InputSource: "xlsx"}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := s.Query(tc.query)
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			if len(got) != tc.want {
+				t.Fatalf("Query(%+v) returned %d events, want %d", tc.query, len(got), tc.want)
+			}
+			for _, e := range got {
+				if !tc.query.Matches(e) {
+					t.Errorf("event %+v does not match query", e)
+				}
+			}
+		})
+	}
+
+	all, _ := s.Query(Query{})
+	if all[0].EventName != "api_convert_completed" || all[2].EventName != "studio_opened" {
+		t.Fatalf("events not in chronological order: %+v", all)
+	}
+	if all[0].AIModel != "gpt-4o-mini" || all[0].Source != "backend" {
+		t.Fatalf("event fields not round-tripped: %+v", all[0])
+	}
+}
+
+func TestStore_PrunesExpiredEvents(t *testing.T) {
+	s := newTestStore(t, "", 24*time.Hour)
+	now := time.Now().UTC()
+	_ = s.Record(Event{EventName: "old", EventTime: now.Add(-48 * time.Hour)})
+	_ = s.Record(Event{EventName: "recent", EventTime: now.Add(-time.Hour)})
+
+	removed, err := s.Prune()
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("Prune removed %d events, want 1", removed)
+	}
+
+	// Record prunes on its own once pruneEvery has elapsed.
+	_ = s.Record(Event{EventName: "aging", EventTime: now.Add(-23 * time.Hour)})
+	s.now = func() time.Time { return now.Add(2 * time.Hour) }
+	_ = s.Record(Event{EventName: "latest", EventTime: now})
+
+	got, _ := s.Query(Query{})
+	names := make([]string, 0, len(got))
+	for _, e := range got {
+		names = append(names, e.EventName)
+	}
+	if len(names) != 2 || names[0] != "recent" || names[1] != "latest" {
+		t.Fatalf("remaining events = %v, want [recent latest]", names)
+	}
+}
+
+func TestStore_CapsRowCount(t *testing.T) {
+	s, err := NewStore("", 0, 3)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	base := time.Now().UTC().Add(-time.Hour)
+	for i, name := range []string{"a", "b", "c", "d", "e"} {
+		if err := s.Record(Event{EventName: name, EventTime: base.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	got, _ := s.Query(Query{})
+	names := make([]string, 0, len(got))
+	for _, e := range got {
+		names = append(names, e.EventName)
+	}
+	if len(names) != 3 || names[0] != "c" || names[2] != "e" {
+		t.Fatalf("remaining events = %v, want [c d e]", names)
+	}
+}
+
+func TestStore_SurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.db")
+	s, err := NewStore(path, 0, 0)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	if err := s.Record(Event{EventName: "api_share_created", Source: "backend"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	_ = s.Close()
+
+	reopened := newTestStore(t, path, 0)
+	got, err := reopened.Query(Query{})
+	if err != nil || len(got) != 1 || got[0].EventName != "api_share_created" {
+		t.Fatalf("after reopen got %+v, %v", got, err)
+	}
+}
+
+func TestStore_SeriesMatchesInMemoryBucketing(t *testing.T) {
+	s := newTestStore(t, "", 0)
+	base := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+	var events []Event
+	for i := 0; i < 23; i++ {
+		e := Event{
+			EventName:    "api_convert_completed",
+			EventTime:    base.Add(time.Duration(i) * 7 * time.Minute),
+			Status:       "success",
+			Source:       "backend",
+			DurationMS:   int64((i*37)%100 + 1),
+			TemplateType: "spec",
+		}
+		switch i % 5 {
+		case 1:
+			e.EventName = "api_preview_completed"
+		case 2:
+			e.EventName, e.Status = "api_share_created", "error"
+		case 3:
+			e.EventName, e.Source, e.DurationMS, e.TemplateType = "studio_opened", "frontend", 0, "table"
+		}
+		events = append(events, e)
+		if err := s.Record(e); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		query  Query
+		bucket time.Duration
+	}{
+		{"hour", Query{}, time.Hour},
+		{"day", Query{}, 24 * time.Hour},
+		{"filtered", Query{TemplateType: "spec", Since: base.Add(30 * time.Minute)}, time.Hour},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := s.Series(tc.query, tc.bucket)
+			if err != nil {
+				t.Fatalf("Series: %v", err)
+			}
+			var matching []Event
+			for _, e := range events {
+				if tc.query.Matches(e) {
+					matching = append(matching, e)
+				}
+			}
+			want := BucketEvents(matching, tc.bucket)
+			if len(got) != len(want) {
+				t.Fatalf("Series returned %d buckets, want %d: %+v", len(got), len(want), got)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("bucket %d = %+v, want %+v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStore_SummaryMatchesInMemorySummary(t *testing.T) {
+	s := newTestStore(t, "", 0)
+	base := time.Now().UTC().Truncate(time.Second).Add(-5 * time.Hour)
+	names := []string{
+		"studio_opened", "input_provided", "preview_succeeded", "convert_succeeded", "convert_failed",
+		"api_preview_completed", "api_convert_completed", "api_share_created", "share_created_ui", "preview_failed",
+	}
+	var events []Event
+	for i := 0; i < 60; i++ {
+		e := Event{
+			EventName:    names[(i*7)%len(names)],
+			EventTime:    base.Add(time.Duration(i*4) * time.Minute),
+			SessionID:    fmt.Sprintf("s%d", i/3%4),
+			Status:       "success",
+			Source:       "frontend",
+			TemplateType: []string{"spec", "table"}[i%2],
+			InputSource:  "paste",
+		}
+		if e.EventName[:4] == "api_" {
+			e.Source, e.DurationMS, e.HTTPStatus = "backend", int64((i*53)%400+1), 200
+			if e.EventName == "api_share_created" {
+				e.TemplateType, e.InputSource = "", ""
+			}
+		}
+		switch i % 9 {
+		case 2:
+			e.Status = "error"
+			e.HTTPStatus = 502
+		case 4:
+			e.SessionID = ""
+		case 5:
+			e.AIModel, e.AIEstimatedCostUSD, e.AIInputTokens, e.AIOutputTokens = "gpt-4o-mini", 0.25, 100, 40
+		case 7:
+			e.AIEstimatedCostUSD, e.AIInputTokens, e.AIOutputTokens = 0.5, 300, 90
+		}
+		events = append(events, e)
+	}
+	// Sessions that open the studio and convert, for time to value.
+	for i, session := range []string{"ttv1", "ttv2", "ttv3"} {
+		opened := base.Add(time.Duration(i*50) * time.Minute)
+		events = append(events,
+			Event{EventName: "studio_opened", EventTime: opened, SessionID: session, Status: "success", Source: "frontend", TemplateType: "spec"},
+			Event{EventName: "convert_succeeded", EventTime: opened.Add(time.Duration(i*7+1) * time.Minute), SessionID: session, Status: "success", Source: "frontend", TemplateType: "spec"},
+		)
+	}
+	for _, e := range events {
+		if err := s.Record(e); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name  string
+		query Query
+	}{
+		{"all", Query{}},
+		{"window", Query{Since: base.Add(time.Hour), Until: base.Add(3 * time.Hour)}},
+		{"template", Query{TemplateType: "spec"}},
+		{"model", Query{AIModel: "gpt-4o-mini", Since: base.Add(30 * time.Minute)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := s.Summary(tc.query)
+			if err != nil {
+				t.Fatalf("Summary: %v", err)
+			}
+			want := SummarizeEvents(events, tc.query)
+			if math.Abs(got.AICost.TotalCostUSD-want.AICost.TotalCostUSD) > 1e-9 {
+				t.Errorf("AI cost = %v, want %v", got.AICost.TotalCostUSD, want.AICost.TotalCostUSD)
+			}
+			got.AICost.TotalCostUSD = want.AICost.TotalCostUSD
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Summary = %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestStore_BackfillsAggregateColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The schema before status, source and duration_ms were columns.
+	_, err = db.Exec(`CREATE TABLE telemetry_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, event_time INTEGER NOT NULL, event_name TEXT NOT NULL,
+		template_type TEXT NOT NULL DEFAULT '', input_source TEXT NOT NULL DEFAULT '', ai_model TEXT NOT NULL DEFAULT '',
+		payload TEXT NOT NULL)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now().UTC().Add(-time.Hour)
+	payload := `{"event_name":"api_convert_completed","status":"success","source":"backend","duration_ms":42}`
+	if _, err := db.Exec(`INSERT INTO telemetry_events (event_time, event_name, payload) VALUES (?, ?, ?)`, at.UnixMilli(), "api_convert_completed", payload); err != nil {
+		t.Fatal(err)
+	}
+	_ = db.Close()
+
+	s := newTestStore(t, path, 0)
+	series, err := s.Series(Query{}, time.Hour)
+	if err != nil {
+		t.Fatalf("Series: %v", err)
+	}
+	if len(series) != 1 || series[0].Converts != 1 || series[0].P95DurationMS != 42 {
+		t.Fatalf("series after migration = %+v", series)
+	}
+}
@@ -0,0 +1,459 @@
+package telemetry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TopErrors is how many event names Summary.Errors keeps.
+const TopErrors = 10
+
+// Summary aggregates the events matching a query for the dashboard.
+type Summary struct {
+	EventsTotal    int
+	FrontendEvents int
+	BackendEvents  int
+	Backend5xx     int
+
+	// Frontend funnel events; the succeeded/failed counts ignore status.
+	StudioOpened     int
+	InputProvided    int
+	PreviewSucceeded int
+	PreviewFailed    int
+	ConvertSucceeded int
+	ConvertFailed    int
+	ShareCreated     int
+
+	// TimeToValueMS holds, per session, the time from its first successful
+	// studio_opened to its first convert_succeeded, when the convert came later.
+	TimeToValueMS []int64
+
+	P95PreviewLatencyMS int64
+	P95ConvertLatencyMS int64
+
+	// Errors counts error events by name, most frequent first, up to TopErrors.
+	Errors []ErrorCount
+
+	Conversion ConversionCounts
+	AICost     AICost
+}
+
+// ErrorCount is the number of error events with one name.
+type ErrorCount struct {
+	EventName string
+	Count     int
+}
+
+// ConversionCounts is the preview → convert → share funnel from
+// successful backend API events, counted as requests and as distinct sessions.
+// A session converted only if it also previewed, and shared only if it also converted.
+type ConversionCounts struct {
+	Previews          int
+	Converts          int
+	Shares            int
+	SessionsPreviewed int
+	SessionsConverted int
+	SessionsShared    int
+}
+
+// AICost totals the events that carry an AI cost estimate.
+type AICost struct {
+	TotalCostUSD      float64
+	TotalInputTokens  int64
+	TotalOutputTokens int64
+	Requests          int
+	ByModel           []ModelCost // most expensive first
+}
+
+// ModelCost is the AI cost of one model ("" when the event named none).
+type ModelCost struct {
+	Model    string
+	CostUSD  float64
+	Requests int
+}
+
+// SummarizeEvents aggregates the events matching q out of window,
+// the events in q's time window regardless of its other filters. Share events
+// carry no template, source or model, so with filters they are attributed to
+// the conversion funnel through the session that previewed or converted.
+func SummarizeEvents(window []Event, q Query) Summary {
+	var s Summary
+
+	type sessionTimes struct {
+		studio, convert int64 // UnixMilli; 0 when unseen
+	}
+	sessions := map[string]sessionTimes{}
+	previewed := map[string]bool{}
+	converted := map[string]bool{}
+	seen := map[string]bool{}
+	errorCounts := map[string]int{}
+	modelCosts := map[string]*ModelCost{}
+	var previewDurations, convertDurations []int64
+
+	for _, e := range window {
+		if !q.Matches(e) {
+			continue
+		}
+		s.EventsTotal++
+		switch e.Source {
+		case "frontend":
+			s.FrontendEvents++
+		case "backend":
+			s.BackendEvents++
+			if e.HTTPStatus >= 500 {
+				s.Backend5xx++
+			}
+		}
+		if e.Status == "error" {
+			errorCounts[e.EventName]++
+		}
+
+		at := e.EventTime.UnixMilli()
+		switch e.EventName {
+		case "studio_opened":
+			if e.Status == "success" {
+				s.StudioOpened++
+				if e.SessionID != "" {
+					t := sessions[e.SessionID]
+					if t.studio == 0 || at < t.studio {
+						t.studio = at
+						sessions[e.SessionID] = t
+					}
+				}
+			}
+		case "input_provided":
+			if e.Status == "success" {
+				s.InputProvided++
+			}
+		case "preview_succeeded":
+			s.PreviewSucceeded++
+		case "preview_failed":
+			s.PreviewFailed++
+		case "convert_succeeded":
+			s.ConvertSucceeded++
+			if e.SessionID != "" {
+				t := sessions[e.SessionID]
+				if t.convert == 0 || at < t.convert {
+					t.convert = at
+					sessions[e.SessionID] = t
+				}
+			}
+		case "convert_failed":
+			s.ConvertFailed++
+		case "share_created_ui":
+			if e.Status == "success" {
+				s.ShareCreated++
+			}
+		case "api_preview_completed":
+			if e.DurationMS > 0 {
+				previewDurations = append(previewDurations, e.DurationMS)
+			}
+		case "api_convert_completed":
+			if e.DurationMS > 0 {
+				convertDurations = append(convertDurations, e.DurationMS)
+			}
+		}
+
+		if e.AIEstimatedCostUSD > 0 {
+			s.AICost.TotalCostUSD += e.AIEstimatedCostUSD
+			s.AICost.TotalInputTokens += e.AIInputTokens
+			s.AICost.TotalOutputTokens += e.AIOutputTokens
+			s.AICost.Requests++
+			m := modelCosts[e.AIModel]
+			if m == nil {
+				m = &ModelCost{Model: e.AIModel}
+				modelCosts[e.AIModel] = m
+			}
+			m.CostUSD += e.AIEstimatedCostUSD
+			m.Requests++
+		}
+
+		if e.Source != "backend" || e.Status != "success" {
+			continue
+		}
+		if e.SessionID != "" {
+			seen[e.SessionID] = true
+		}
+		switch e.EventName {
+		case "api_preview_completed":
+			s.Conversion.Previews++
+			if e.SessionID != "" {
+				previewed[e.SessionID] = true
+			}
+		case "api_convert_completed":
+			s.Conversion.Converts++
+			if e.SessionID != "" {
+				converted[e.SessionID] = true
+			}
+		}
+	}
+
+	shared := map[string]bool{}
+	for _, e := range window {
+		if e.Source != "backend" || e.Status != "success" || e.EventName != "api_share_created" {
+			continue
+		}
+		if !q.Window().Matches(e) || q.filtered() && !seen[e.SessionID] {
+			continue
+		}
+		s.Conversion.Shares++
+		if e.SessionID != "" {
+			shared[e.SessionID] = true
+		}
+	}
+	s.Conversion.SessionsPreviewed = len(previewed)
+	for id := range converted {
+		if previewed[id] {
+			s.Conversion.SessionsConverted++
+		}
+	}
+	for id := range shared {
+		if previewed[id] && converted[id] {
+			s.Conversion.SessionsShared++
+		}
+	}
+
+	for _, t := range sessions {
+		if t.studio == 0 || t.convert == 0 || t.convert < t.studio {
+			continue
+		}
+		s.TimeToValueMS = append(s.TimeToValueMS, t.convert-t.studio)
+	}
+	sort.Slice(s.TimeToValueMS, func(i, j int) bool { return s.TimeToValueMS[i] < s.TimeToValueMS[j] })
+
+	s.P95PreviewLatencyMS = DurationPercentile(previewDurations, 95)
+	s.P95ConvertLatencyMS = DurationPercentile(convertDurations, 95)
+
+	for name, count := range errorCounts {
+		s.Errors = append(s.Errors, ErrorCount{EventName: name, Count: count})
+	}
+	sort.Slice(s.Errors, func(i, j int) bool {
+		if s.Errors[i].Count == s.Errors[j].Count {
+			return s.Errors[i].EventName < s.Errors[j].EventName
+		}
+		return s.Errors[i].Count > s.Errors[j].Count
+	})
+	if len(s.Errors) > TopErrors {
+		s.Errors = s.Errors[:TopErrors]
+	}
+
+	for _, m := range modelCosts {
+		s.AICost.ByModel = append(s.AICost.ByModel, *m)
+	}
+	sort.Slice(s.AICost.ByModel, func(i, j int) bool {
+		if s.AICost.ByModel[i].CostUSD == s.AICost.ByModel[j].CostUSD {
+			return s.AICost.ByModel[i].Model < s.AICost.ByModel[j].Model
+		}
+		return s.AICost.ByModel[i].CostUSD > s.AICost.ByModel[j].CostUSD
+	})
+	return s
+}
+
+// Summary aggregates the dashboard totals, funnels and KPIs of the events
+// matching q in SQL, with the same results as SummarizeEvents.
+func (s *Store) Summary(q Query) (Summary, error) {
+	var summary Summary
+	for _, step := range []func(Query, *Summary) error{
+		s.summaryCounts,
+		s.summaryErrors,
+		s.summaryTimeToValue,
+		s.summaryLatencies,
+		s.summaryAICost,
+		s.summaryConversion,
+	} {
+		if err := step(q, &summary); err != nil {
+			return Summary{}, err
+		}
+	}
+	return summary, nil
+}
+
+// whereClause joins conditions into a WHERE clause, or "" for none.
+func whereClause(conditions ...string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+func (s *Store) summaryCounts(q Query, out *Summary) error {
+	where, args := queryConditions(q)
+	row := s.db.QueryRow(`SELECT
+			COUNT(*),
+			COALESCE(SUM(source = 'frontend'), 0),
+			COALESCE(SUM(source = 'backend'), 0),
+			COALESCE(SUM(source = 'backend' AND http_status >= 500), 0),
+			COALESCE(SUM(event_name = 'studio_opened' AND status = 'success'), 0),
+			COALESCE(SUM(event_name = 'input_provided' AND status = 'success'), 0),
+			COALESCE(SUM(event_name = 'preview_succeeded'), 0),
+			COALESCE(SUM(event_name = 'preview_failed'), 0),
+			COALESCE(SUM(event_name = 'convert_succeeded'), 0),
+			COALESCE(SUM(event_name = 'convert_failed'), 0),
+			COALESCE(SUM(event_name = 'share_created_ui' AND status = 'success'), 0)
+		FROM telemetry_events`+whereClause(where...), args...)
+	err := row.Scan(
+		&out.EventsTotal, &out.FrontendEvents, &out.BackendEvents, &out.Backend5xx,
+		&out.StudioOpened, &out.InputProvided,
+		&out.PreviewSucceeded, &out.PreviewFailed, &out.ConvertSucceeded, &out.ConvertFailed,
+		&out.ShareCreated,
+	)
+	if err != nil {
+		return fmt.Errorf("telemetry: query totals: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) summaryErrors(q Query, out *Summary) error {
+	where, args := queryConditions(q)
+	where = append(where, "status = 'error'")
+	rows, err := s.db.Query(`SELECT event_name, COUNT(*) AS n FROM telemetry_events`+whereClause(where...)+`
+		GROUP BY event_name
+		ORDER BY n DESC, event_name
+		LIMIT ?`, append(args, TopErrors)...)
+	if err != nil {
+		return fmt.Errorf("telemetry: query errors: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e ErrorCount
+		if err := rows.Scan(&e.EventName, &e.Count); err != nil {
+			return fmt.Errorf("telemetry: scan errors: %w", err)
+		}
+		out.Errors = append(out.Errors, e)
+	}
+	return rows.Err()
+}
+
+// summaryTimeToValue reads each session's first successful studio_opened and
+// first convert_succeeded and keeps the sessions that converted afterwards.
+func (s *Store) summaryTimeToValue(q Query, out *Summary) error {
+	where, args := queryConditions(q)
+	where = append(where, "session_id != ''")
+	rows, err := s.db.Query(`SELECT convert_at - studio_at FROM (
+			SELECT
+				MIN(CASE WHEN event_name = 'studio_opened' AND status = 'success' THEN event_time END) AS studio_at,
+				MIN(CASE WHEN event_name = 'convert_succeeded' THEN event_time END) AS convert_at
+			FROM telemetry_events`+whereClause(where...)+`
+			GROUP BY session_id
+		)
+		WHERE studio_at IS NOT NULL AND convert_at >= studio_at
+		ORDER BY 1`, args...)
+	if err != nil {
+		return fmt.Errorf("telemetry: query time to value: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var ms int64
+		if err := rows.Scan(&ms); err != nil {
+			return fmt.Errorf("telemetry: scan time to value: %w", err)
+		}
+		out.TimeToValueMS = append(out.TimeToValueMS, ms)
+	}
+	return rows.Err()
+}
+
+// summaryLatencies picks the p95 preview and convert API durations the way
+// Series picks its bucket percentiles.
+func (s *Store) summaryLatencies(q Query, out *Summary) error {
+	where, args := queryConditions(q)
+	where = append(where, "event_name IN ('api_preview_completed', 'api_convert_completed')", "duration_ms > 0")
+	rows, err := s.db.Query(`SELECT event_name,
+			MAX(CASE WHEN rank = CAST(0.95 * (n - 1) AS INTEGER) THEN duration_ms END)
+		FROM (
+			SELECT event_name, duration_ms,
+				ROW_NUMBER() OVER (PARTITION BY event_name ORDER BY duration_ms) - 1 AS rank,
+				COUNT(*) OVER (PARTITION BY event_name) AS n
+			FROM telemetry_events`+whereClause(where...)+`
+		)
+		GROUP BY event_name`, args...)
+	if err != nil {
+		return fmt.Errorf("telemetry: query latencies: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var p95 int64
+		if err := rows.Scan(&name, &p95); err != nil {
+			return fmt.Errorf("telemetry: scan latencies: %w", err)
+		}
+		if name == "api_preview_completed" {
+			out.P95PreviewLatencyMS = p95
+		} else {
+			out.P95ConvertLatencyMS = p95
+		}
+	}
+	return rows.Err()
+}
+
+func (s *Store) summaryAICost(q Query, out *Summary) error {
+	where, args := queryConditions(q)
+	where = append(where, "ai_cost_usd > 0")
+	rows, err := s.db.Query(`SELECT ai_model, SUM(ai_cost_usd) AS cost, COUNT(*), SUM(ai_input_tokens), SUM(ai_output_tokens)
+		FROM telemetry_events`+whereClause(where...)+`
+		GROUP BY ai_model
+		ORDER BY cost DESC, ai_model`, args...)
+	if err != nil {
+		return fmt.Errorf("telemetry: query ai cost: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m ModelCost
+		var inputTokens, outputTokens int64
+		if err := rows.Scan(&m.Model, &m.CostUSD, &m.Requests, &inputTokens, &outputTokens); err != nil {
+			return fmt.Errorf("telemetry: scan ai cost: %w", err)
+		}
+		out.AICost.TotalCostUSD += m.CostUSD
+		out.AICost.TotalInputTokens += inputTokens
+		out.AICost.TotalOutputTokens += outputTokens
+		out.AICost.Requests += m.Requests
+		out.AICost.ByModel = append(out.AICost.ByModel, m)
+	}
+	return rows.Err()
+}
+
+// summaryConversion counts the preview → convert → share funnel. With filters,
+// shares (which carry no template, source or model) only count for sessions
+// with a matching successful backend event.
+func (s *Store) summaryConversion(q Query, out *Summary) error {
+	where, args := queryConditions(q)
+	where = append(where, "source = 'backend'", "status = 'success'")
+	matched := `FROM telemetry_events` + whereClause(where...)
+
+	row := s.db.QueryRow(`SELECT
+			COALESCE(SUM(event_name = 'api_preview_completed'), 0),
+			COALESCE(SUM(event_name = 'api_convert_completed'), 0)
+		`+matched, args...)
+	if err := row.Scan(&out.Conversion.Previews, &out.Conversion.Converts); err != nil {
+		return fmt.Errorf("telemetry: query conversion funnel: %w", err)
+	}
+
+	sessions := `SELECT session_id,
+			MAX(event_name = 'api_preview_completed') AS previewed,
+			MAX(event_name = 'api_convert_completed') AS converted
+		` + matched + ` AND session_id != ''
+		GROUP BY session_id`
+	row = s.db.QueryRow(`SELECT
+			COALESCE(SUM(previewed), 0),
+			COALESCE(SUM(previewed AND converted), 0)
+		FROM (`+sessions+`)`, args...)
+	if err := row.Scan(&out.Conversion.SessionsPreviewed, &out.Conversion.SessionsConverted); err != nil {
+		return fmt.Errorf("telemetry: query conversion sessions: %w", err)
+	}
+
+	shareWhere, shareArgs := queryConditions(Query{Since: q.Since, Until: q.Until})
+	shareWhere = append(shareWhere, "source = 'backend'", "status = 'success'", "event_name = 'api_share_created'")
+	if q.TemplateType != "" || q.InputSource != "" || q.AIModel != "" {
+		shareWhere = append(shareWhere, "session_id IN (SELECT session_id "+matched+" AND session_id != '')")
+		shareArgs = append(shareArgs, args...)
+	}
+	row = s.db.QueryRow(`SELECT
+			COUNT(*),
+			COUNT(DISTINCT CASE WHEN session_id IN (SELECT session_id FROM (`+sessions+`) WHERE previewed AND converted)
+				THEN session_id END)
+		FROM telemetry_events`+whereClause(shareWhere...), append(args, shareArgs...)...)
+	if err := row.Scan(&out.Conversion.Shares, &out.Conversion.SessionsShared); err != nil {
+		return fmt.Errorf("telemetry: query conversion shares: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package traceability
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatMarkdown renders the matrix as a markdown report: a summary, the
+// requirement-to-test table, then uncovered requirements and orphan tests.
+func FormatMarkdown(m *Matrix) string {
+	var b strings.Builder
+	b.WriteString("# Traceability Matrix\n\n")
+	if m == nil {
+		return b.String()
+	}
+
+	s := m.Summary
+	fmt.Fprintf(&b, "- Requirements: %d (%d covered, %d uncovered)\n", s.Requirements, s.Covered, s.Uncovered)
+	fmt.Fprintf(&b, "- Tests: %d (%d linked, %d orphan)\n", s.Tests, s.LinkedTests, s.OrphanTests)
+	fmt.Fprintf(&b, "- Coverage: %.0f%%\n", s.Coverage*100)
+	fmt.Fprintf(&b, "- Links: %d by reference, %d fuzzy\n", s.ByReference, s.ByFuzzy)
+
+	b.WriteString("\n## Coverage\n\n")
+	b.WriteString("| Requirement | Title | Tests | Status |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, req := range m.Requirements {
+		tests := make([]string, 0, len(req.Tests))
+		for _, t := range req.Tests {
+			if t.Method == MethodFuzzy {
+				tests = append(tests, fmt.Sprintf("%s (~%.2f)", t.ID, t.Score))
+			} else {
+				tests = append(tests, t.ID)
+			}
+		}
+		status := "covered"
+		if !req.Covered {
+			status = "**uncovered**"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", cell(req.ID), cell(req.Title), cell(strings.Join(tests, ", ")), status)
+	}
+
+	b.WriteString("\n## Uncovered Requirements\n\n")
+	if len(m.Uncovered) == 0 {
+		b.WriteString("None.\n")
+	}
+	for _, req := range m.Requirements {
+		if !req.Covered {
+			fmt.Fprintf(&b, "- %s: %s\n", req.ID, req.Title)
+		}
+	}
+
+	b.WriteString("\n## Orphan Tests\n\n")
+	if len(m.OrphanTests) == 0 {
+		b.WriteString("None.\n")
+	}
+	for _, t := range m.OrphanTests {
+		fmt.Fprintf(&b, "- %s: %s", t.ID, t.Title)
+		if len(t.UnknownReferences) > 0 {
+			fmt.Fprintf(&b, " (unknown references: %s)", strings.Join(t.UnknownReferences, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// cell escapes a value for a markdown table cell.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.Join(strings.Fields(s), " ")
+}
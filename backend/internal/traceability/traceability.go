@@ -0,0 +1,424 @@
+// Package traceability links test cases to the requirements they cover.
+//
+// Tests are linked through explicit reference columns (e.g. "Requirement ID",
+// "Covers") when present, otherwise by fuzzy matching of titles. The result is
+// a coverage matrix listing uncovered requirements and orphan tests.
+package traceability
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+// DefaultFuzzyThreshold is the minimum title similarity (0-1) for a fuzzy link.
+const DefaultFuzzyThreshold = 0.5
+
+// Link methods.
+const (
+	MethodReference = "reference"
+	MethodFuzzy     = "fuzzy"
+)
+
+// referenceHeaderWords identify test columns that hold requirement IDs. They
+// match whole words of the header, so "Req ID" and "Covers" count but
+// "Prerequisites", "Required" and "Preference" do not.
+var referenceHeaderWords = map[string]bool{
+	"requirement": true, "requirements": true, "req": true, "reqs": true,
+	"story": true, "stories": true, "covers": true, "trace": true, "traces": true,
+	"ref": true, "refs": true, "reference": true, "references": true,
+	"ticket": true, "tickets": true, "jira": true, "issue": true, "issues": true,
+}
+
+// referenceHeaderCJK are matched as substrings: CJK headers have no word breaks.
+var referenceHeaderCJK = []string{"要件", "要求"}
+
+// referenceSplit separates the IDs of a reference cell. Whitespace and "/"
+// stay inside an ID ("REQ 12", "AUTH/LOGIN-3"); lists use commas, semicolons,
+// pipes or one ID per line.
+var referenceSplit = regexp.MustCompile(`[,;|\n]+`)
+
+// Options tunes linking.
+type Options struct {
+	// ReferenceFields are test sheet headers or rule-style field names
+	// (canonical fields, "metadata.<header>") holding requirement IDs. Empty
+	// means any column whose header looks like a reference (e.g.
+	// "Requirement ID", "Covers", "Jira").
+	ReferenceFields []string `json:"reference_fields,omitempty"`
+	// FuzzyThreshold is the minimum title similarity for a fuzzy link; 0 uses DefaultFuzzyThreshold.
+	FuzzyThreshold float64 `json:"fuzzy_threshold,omitempty"`
+	// DisableFuzzy links tests by reference columns only.
+	DisableFuzzy bool `json:"disable_fuzzy,omitempty"`
+}
+
+// LinkedTest is one test covering a requirement.
+type LinkedTest struct {
+	ID     string  `json:"id"`
+	Title  string  `json:"title"`
+	Method string  `json:"method"`
+	Score  float64 `json:"score"` // 1 for reference links
+}
+
+// RequirementCoverage lists the tests linked to one requirement.
+type RequirementCoverage struct {
+	ID      string       `json:"id"`
+	Title   string       `json:"title"`
+	Row     int          `json:"row"`
+	Covered bool         `json:"covered"`
+	Tests   []LinkedTest `json:"tests"`
+}
+
+// TestRef identifies a test row that covers no requirement.
+type TestRef struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Row   int    `json:"row"`
+	// UnknownReferences are IDs in reference columns that match no requirement.
+	UnknownReferences []string `json:"unknown_references,omitempty"`
+}
+
+// Summary counts coverage.
+type Summary struct {
+	Requirements int     `json:"requirements"`
+	Covered      int     `json:"covered"`
+	Uncovered    int     `json:"uncovered"`
+	Tests        int     `json:"tests"`
+	LinkedTests  int     `json:"linked_tests"`
+	OrphanTests  int     `json:"orphan_tests"`
+	Coverage     float64 `json:"coverage"` // covered / requirements
+	ByReference  int     `json:"links_by_reference"`
+	ByFuzzy      int     `json:"links_by_fuzzy"`
+}
+
+// Matrix is the requirements-to-tests coverage matrix.
+type Matrix struct {
+	Requirements []RequirementCoverage `json:"requirements"`
+	Uncovered    []string              `json:"uncovered"`
+	OrphanTests  []TestRef             `json:"orphan_tests"`
+	Summary      Summary               `json:"summary"`
+}
+
+type item struct {
+	id     string
+	title  string
+	row    int
+	tokens map[string]bool
+}
+
+// Build links tests to requirements and returns the coverage matrix.
+func Build(requirements, tests *converter.SpecDoc, opts Options) *Matrix {
+	threshold := opts.FuzzyThreshold
+	if threshold <= 0 {
+		threshold = DefaultFuzzyThreshold
+	}
+
+	reqs := requirementItems(requirements)
+	byID := make(map[string]int, len(reqs))
+	for i, r := range reqs {
+		byID[strings.ToLower(r.id)] = i
+	}
+
+	m := &Matrix{
+		Requirements: make([]RequirementCoverage, len(reqs)),
+		Uncovered:    []string{},
+		OrphanTests:  []TestRef{},
+	}
+	for i, r := range reqs {
+		m.Requirements[i] = RequirementCoverage{ID: r.id, Title: r.title, Row: r.row, Tests: []LinkedTest{}}
+	}
+
+	var testRows []converter.SpecRow
+	if tests != nil {
+		testRows = tests.Rows
+	}
+	refCols := referenceColumns(tests, opts.ReferenceFields)
+	for i := range testRows {
+		row := &testRows[i]
+		test := newItem(row, i+1, "TC", row.Title, row.Scenario, row.Feature, row.Description)
+		m.Summary.Tests++
+
+		refs, found := referenceValues(row, refCols)
+		linked := map[int]bool{}
+		var unknown []string
+		for _, ref := range refs {
+			idx, ok := byID[strings.ToLower(ref)]
+			if !ok {
+				unknown = append(unknown, ref)
+				continue
+			}
+			if linked[idx] {
+				continue
+			}
+			linked[idx] = true
+			m.Requirements[idx].Tests = append(m.Requirements[idx].Tests, LinkedTest{ID: test.id, Title: test.title, Method: MethodReference, Score: 1})
+			m.Summary.ByReference++
+		}
+
+		// Fuzzy matching only for tests without an explicit reference column value.
+		if len(linked) == 0 && !found && !opts.DisableFuzzy {
+			if idx, score := bestMatch(test, reqs); idx >= 0 && score >= threshold {
+				linked[idx] = true
+				m.Requirements[idx].Tests = append(m.Requirements[idx].Tests, LinkedTest{ID: test.id, Title: test.title, Method: MethodFuzzy, Score: round2(score)})
+				m.Summary.ByFuzzy++
+			}
+		}
+
+		if len(linked) == 0 {
+			m.OrphanTests = append(m.OrphanTests, TestRef{ID: test.id, Title: test.title, Row: test.row, UnknownReferences: unknown})
+		} else {
+			m.Summary.LinkedTests++
+		}
+	}
+
+	for i := range m.Requirements {
+		cov := &m.Requirements[i]
+		cov.Covered = len(cov.Tests) > 0
+		if cov.Covered {
+			m.Summary.Covered++
+		} else {
+			m.Uncovered = append(m.Uncovered, cov.ID)
+		}
+	}
+	m.Summary.Requirements = len(m.Requirements)
+	m.Summary.Uncovered = len(m.Uncovered)
+	m.Summary.OrphanTests = len(m.OrphanTests)
+	if m.Summary.Requirements > 0 {
+		m.Summary.Coverage = round2(float64(m.Summary.Covered) / float64(m.Summary.Requirements))
+	}
+	return m
+}
+
+func requirementItems(doc *converter.SpecDoc) []item {
+	if doc == nil {
+		return nil
+	}
+	items := make([]item, 0, len(doc.Rows))
+	seen := map[string]bool{}
+	for i := range doc.Rows {
+		row := &doc.Rows[i]
+		it := newItem(row, i+1, "REQ", row.Title, row.Feature, row.Scenario, row.Description)
+		if seen[strings.ToLower(it.id)] {
+			continue // duplicate IDs are reported by validation; keep the first
+		}
+		seen[strings.ToLower(it.id)] = true
+		items = append(items, it)
+	}
+	return items
+}
+
+// newItem picks the first non-empty candidate as the title and falls back to
+// a row-based ID when the row has none.
+func newItem(row *converter.SpecRow, rowNum int, prefix string, candidates ...string) item {
+	it := item{id: strings.TrimSpace(row.ID), row: rowNum}
+	if it.id == "" {
+		it.id = fmt.Sprintf("%s-row-%d", prefix, rowNum)
+	}
+	for _, c := range candidates {
+		if c = strings.TrimSpace(c); c != "" {
+			it.title = strings.Join(strings.Fields(c), " ")
+			break
+		}
+	}
+	it.tokens = tokenize(it.title)
+	return it
+}
+
+// referenceColumns resolves the test sheet columns holding requirement IDs
+// into rule-style field names (see converter.FieldValue). Headers are matched
+// against the source sheet, so a "Requirement" column still counts when the
+// column mapper assigned it to a canonical field.
+func referenceColumns(doc *converter.SpecDoc, fields []string) []string {
+	if doc == nil {
+		return nil
+	}
+	headerField := func(header string) (string, bool) {
+		for field, idx := range doc.Meta.ColumnMap {
+			if idx >= 0 && idx < len(doc.Headers) && strings.EqualFold(strings.TrimSpace(doc.Headers[idx]), header) {
+				return string(field), true
+			}
+		}
+		return "", false
+	}
+
+	var cols []string
+	if len(fields) > 0 {
+		for _, f := range fields {
+			f = strings.TrimSpace(f)
+			if field, ok := headerField(f); ok {
+				cols = append(cols, field)
+			} else {
+				cols = append(cols, f)
+			}
+		}
+		return cols
+	}
+
+	seen := map[string]bool{}
+	for _, header := range doc.Headers {
+		header = strings.TrimSpace(header)
+		if header == "" || !isReferenceHeader(header) {
+			continue
+		}
+		col := "metadata." + header
+		if field, ok := headerField(header); ok {
+			if converter.CanonicalField(field) == converter.FieldID {
+				continue // the test's own ID, e.g. a "Ref" column
+			}
+			col = field
+		}
+		if !seen[col] {
+			seen[col] = true
+			cols = append(cols, col)
+		}
+	}
+	// Documents built without headers (e.g. in code) only carry metadata.
+	if len(doc.Headers) == 0 {
+		keys := map[string]bool{}
+		for _, row := range doc.Rows {
+			for k := range row.Metadata {
+				if isReferenceHeader(k) {
+					keys["metadata."+k] = true
+				}
+			}
+		}
+		for k := range keys {
+			cols = append(cols, k)
+		}
+		sort.Strings(cols)
+	}
+	return cols
+}
+
+// referenceValues returns requirement IDs listed in the test's reference
+// columns and whether any reference column had a value.
+func referenceValues(row *converter.SpecRow, cols []string) ([]string, bool) {
+	var refs []string
+	found := false
+	for _, col := range cols {
+		value := converter.FieldValue(row, col)
+		if converter.CanonicalField(col) == converter.FieldFeature && (value == row.Title || value == row.ItemName) {
+			continue // an empty Feature column was back-filled from the title
+		}
+		for _, ref := range referenceSplit.Split(value, -1) {
+			ref = strings.Trim(strings.TrimSpace(ref), "[]().#")
+			if ref == "" {
+				continue
+			}
+			found = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs, found
+}
+
+func isReferenceHeader(header string) bool {
+	words := strings.FieldsFunc(strings.ToLower(header), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		if referenceHeaderWords[w] {
+			return true
+		}
+	}
+	for _, hint := range referenceHeaderCJK {
+		if strings.Contains(header, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+func bestMatch(test item, reqs []item) (int, float64) {
+	best, bestScore := -1, 0.0
+	for i, r := range reqs {
+		if score := similarity(test.tokens, r.tokens); score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best, bestScore
+}
+
+// similarity is the Sørensen–Dice coefficient of two token sets.
+func similarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range a {
+		if b[t] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true, "to": true, "in": true,
+	"on": true, "for": true, "with": true, "is": true, "be": true, "can": true, "should": true,
+	"must": true, "when": true, "user": true, "test": true, "verify": true, "check": true,
+}
+
+// tokenize lowercases text into word tokens, dropping stop words and light
+// suffixes. Runs of CJK characters become bigrams since they have no spaces.
+func tokenize(text string) map[string]bool {
+	tokens := map[string]bool{}
+	var run []rune
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if isCJK(run[0]) {
+			if len(run) == 1 {
+				tokens[string(run)] = true
+			}
+			for i := 0; i+1 < len(run); i++ {
+				tokens[string(run[i:i+2])] = true
+			}
+		} else if word := stem(string(run)); len(word) > 1 && !stopWords[word] {
+			tokens[word] = true
+		}
+		run = run[:0]
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isCJK(r):
+			if len(run) > 0 && !isCJK(run[0]) {
+				flush()
+			}
+			run = append(run, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if len(run) > 0 && isCJK(run[0]) {
+				flush()
+			}
+			run = append(run, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func stem(word string) string {
+	if stopWords[word] {
+		return word
+	}
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if len(word) > len(suffix)+2 && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+func round2(v float64) float64 {
+	return float64(int(v*100+0.5)) / 100
+}
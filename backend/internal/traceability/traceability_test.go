@@ -0,0 +1,126 @@
+package traceability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+func requirementsDoc() *converter.SpecDoc {
+	return &converter.SpecDoc{Rows: []converter.SpecRow{
+		{ID: "REQ-1", Title: "Login with email and password"},
+		{ID: "REQ-2", Title: "Export invoices to PDF"},
+		{ID: "REQ-3", Title: "Reset forgotten password"},
+	}}
+}
+
+func TestBuild_ReferenceAndFuzzyLinks(t *testing.T) {
+	tests := &converter.SpecDoc{Rows: []converter.SpecRow{
+		{ID: "TC-1", Scenario: "Valid login", Metadata: map[string]string{"Requirement ID": "req-1"}},
+		{ID: "TC-2", Scenario: "Invoice exported as PDF"},
+		{ID: "TC-3", Scenario: "Dark mode toggle"},
+		{ID: "TC-4", Scenario: "Login lockout", Metadata: map[string]string{"Covers": "REQ-1, REQ-9"}},
+		{ID: "TC-5", Scenario: "Export invoices to PDF", Metadata: map[string]string{"Covers": "REQ-9"}},
+	}}
+
+	m := Build(requirementsDoc(), tests, Options{})
+
+	req1 := m.Requirements[0]
+	if len(req1.Tests) != 2 || req1.Tests[0].ID != "TC-1" || req1.Tests[0].Method != MethodReference || req1.Tests[1].ID != "TC-4" {
+		t.Fatalf("REQ-1 links = %+v", req1.Tests)
+	}
+	req2 := m.Requirements[1]
+	if len(req2.Tests) != 1 || req2.Tests[0].ID != "TC-2" || req2.Tests[0].Method != MethodFuzzy || req2.Tests[0].Score < DefaultFuzzyThreshold {
+		t.Fatalf("REQ-2 links = %+v", req2.Tests)
+	}
+	if len(m.Uncovered) != 1 || m.Uncovered[0] != "REQ-3" {
+		t.Fatalf("uncovered = %v", m.Uncovered)
+	}
+
+	// TC-5 has an explicit (unknown) reference, so it is not fuzzy matched.
+	if len(m.OrphanTests) != 2 || m.OrphanTests[0].ID != "TC-3" || m.OrphanTests[1].ID != "TC-5" {
+		t.Fatalf("orphans = %+v", m.OrphanTests)
+	}
+	if got := m.OrphanTests[1].UnknownReferences; len(got) != 1 || got[0] != "REQ-9" {
+		t.Fatalf("unknown references = %v", got)
+	}
+
+	s := m.Summary
+	if s.Requirements != 3 || s.Covered != 2 || s.Tests != 5 || s.LinkedTests != 3 || s.OrphanTests != 2 || s.Coverage != 0.67 || s.ByReference != 2 || s.ByFuzzy != 1 {
+		t.Fatalf("summary = %+v", s)
+	}
+}
+
+func TestBuild_ReferenceFieldsAndDisableFuzzy(t *testing.T) {
+	tests := &converter.SpecDoc{Rows: []converter.SpecRow{
+		{ID: "TC-1", Notes: "REQ-3", Metadata: map[string]string{"Requirement": "REQ-1"}},
+		{ID: "TC-2", Scenario: "Export invoices to PDF"},
+	}}
+
+	m := Build(requirementsDoc(), tests, Options{ReferenceFields: []string{"notes"}, DisableFuzzy: true})
+	if !m.Requirements[2].Covered || m.Requirements[0].Covered {
+		t.Fatalf("reference_fields should replace header detection: %+v", m.Requirements)
+	}
+	if m.Requirements[1].Covered || len(m.OrphanTests) != 1 {
+		t.Fatalf("fuzzy matching should be disabled: %+v", m)
+	}
+}
+
+func TestBuild_ReferenceHeadersMatchWholeWords(t *testing.T) {
+	tests := &converter.SpecDoc{Rows: []converter.SpecRow{
+		{ID: "TC-1", Scenario: "Login with email and password", Metadata: map[string]string{
+			"Prerequisites": "Account exists", "Required": "yes", "Preference": "dark", "Frequency": "daily",
+		}},
+		{ID: "TC-2", Scenario: "Unrelated", Metadata: map[string]string{"Req ID": "AUTH/LOGIN 1; REQ-3"}},
+	}}
+	reqs := requirementsDoc()
+	reqs.Rows = append(reqs.Rows, converter.SpecRow{ID: "AUTH/LOGIN 1", Title: "Legacy login"})
+
+	m := Build(reqs, tests, Options{})
+	if got := m.Requirements[0].Tests; len(got) != 1 || got[0].Method != MethodFuzzy {
+		t.Fatalf("non-reference columns must leave fuzzy matching on: %+v", got)
+	}
+	if !m.Requirements[2].Covered || !m.Requirements[3].Covered || len(m.OrphanTests) != 0 {
+		t.Fatalf("IDs with spaces and slashes should link whole: %+v", m)
+	}
+
+	for header, want := range map[string]bool{
+		"Requirement ID": true, "Req ID": true, "Covers": true, "Jira": true, "User story": true, "要件ID": true,
+		"Prerequisites": false, "Required": false, "Preference": false, "Frequency": false, "Linked": false,
+	} {
+		if got := isReferenceHeader(header); got != want {
+			t.Errorf("isReferenceHeader(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
+
+func TestBuild_MissingIDsAndCJKTitles(t *testing.T) {
+	reqs := &converter.SpecDoc{Rows: []converter.SpecRow{{Title: "ユーザーログイン機能"}}}
+	tests := &converter.SpecDoc{Rows: []converter.SpecRow{{Scenario: "ユーザーログイン"}}}
+
+	m := Build(reqs, tests, Options{})
+	if m.Requirements[0].ID != "REQ-row-1" || !m.Requirements[0].Covered || m.Requirements[0].Tests[0].ID != "TC-row-1" {
+		t.Fatalf("matrix = %+v", m.Requirements)
+	}
+}
+
+func TestFormatMarkdown(t *testing.T) {
+	tests := &converter.SpecDoc{Rows: []converter.SpecRow{
+		{ID: "TC-1", Scenario: "Login | happy path", Metadata: map[string]string{"Req": "REQ-1"}},
+		{ID: "TC-2", Scenario: "Dark mode toggle"},
+	}}
+	out := FormatMarkdown(Build(requirementsDoc(), tests, Options{}))
+
+	for _, want := range []string{
+		"- Coverage: 33%",
+		"| REQ-1 | Login with email and password | TC-1 | covered |",
+		"| REQ-2 | Export invoices to PDF |  | **uncovered** |",
+		"## Uncovered Requirements\n\n- REQ-2: Export invoices to PDF\n- REQ-3: Reset forgotten password\n",
+		"## Orphan Tests\n\n- TC-2: Dark mode toggle\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+)
+
+// DefaultOTLPEndpoint is the conventional OTLP/HTTP collector address.
+const DefaultOTLPEndpoint = "http://localhost:4318"
+
+// NewOTLPExporter returns an OTLP/HTTP exporter posting protobuf spans to
+// {endpoint}/v1/traces (empty uses DefaultOTLPEndpoint). A full URL ending in
+// /v1/traces is used as-is. opts are applied after the endpoint, e.g. to
+// change retries.
+func NewOTLPExporter(ctx context.Context, endpoint string, opts ...otlptracehttp.Option) (*otlptrace.Exporter, error) {
+	endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	if endpoint == "" {
+		endpoint = DefaultOTLPEndpoint
+	}
+	if !strings.HasSuffix(endpoint, "/v1/traces") {
+		endpoint += "/v1/traces"
+	}
+	opts = append([]otlptracehttp.Option{
+		otlptracehttp.WithEndpointURL(endpoint),
+		otlptracehttp.WithTimeout(10 * time.Second),
+	}, opts...)
+	return otlptracehttp.New(ctx, opts...)
+}
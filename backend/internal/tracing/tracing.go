@@ -0,0 +1,96 @@
+// Package tracing configures OpenTelemetry tracing for the conversion pipeline.
+//
+// Spans are always created through the global TracerProvider, which is a no-op
+// until Setup installs an exporting provider, so instrumentation costs nothing
+// when tracing is disabled.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies spans created by this service.
+const InstrumentationName = "github.com/yourorg/md-spec-tool"
+
+// Propagator extracts and injects W3C traceparent/baggage headers.
+var Propagator propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{}, propagation.Baggage{},
+)
+
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+)
+
+// Config selects and configures the span exporter.
+type Config struct {
+	Exporter    string // "none" (default) or "otlp"
+	Endpoint    string // OTLP/HTTP base URL, e.g. http://localhost:4318
+	ServiceName string
+	SampleRatio *float64 // 0..1, applied to root spans; nil samples every trace
+}
+
+// Setup installs a global TracerProvider for cfg and returns a shutdown func
+// that flushes pending spans. With the "none" exporter the no-op provider is
+// left in place and shutdown does nothing.
+func Setup(cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(Propagator)
+
+	switch strings.ToLower(strings.TrimSpace(cfg.Exporter)) {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err := NewOTLPExporter(context.Background(), cfg.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("tracing: otlp exporter: %w", err)
+		}
+		tp := NewProvider(cfg, sdktrace.WithBatcher(exporter))
+		otel.SetTracerProvider(tp)
+		return tp.Shutdown, nil
+	default:
+		return nil, fmt.Errorf("tracing: unknown exporter %q", cfg.Exporter)
+	}
+}
+
+// NewProvider builds a TracerProvider with the service resource and sampler from cfg.
+// Tests pass sdktrace.WithSyncer(tracetest.NewInMemoryExporter()) to capture spans.
+func NewProvider(cfg Config, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "md-spec-tool"
+	}
+	ratio := 1.0
+	if cfg.SampleRatio != nil {
+		ratio = *cfg.SampleRatio
+	}
+	opts = append([]sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	}, opts...)
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+// Start begins a span named name as a child of any span in ctx.
+// The tracer is looked up on every call so providers installed later take effect.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(InstrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err (if any) on span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,163 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStart_UsesProviderInstalledLater(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(NewProvider(Config{}, sdktrace.WithSyncer(exporter)))
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child", attribute.String("k", "v"))
+	End(child, errors.New("boom"))
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	got := spans[0]
+	if got.Name != "child" || got.Parent.SpanID() != spans[1].SpanContext.SpanID() {
+		t.Fatalf("child span not parented to parent: %+v", got)
+	}
+	if got.Status.Code != codes.Error || got.Status.Description != "boom" {
+		t.Fatalf("child status = %+v, want error boom", got.Status)
+	}
+}
+
+func TestNewProvider_SampleRatio(t *testing.T) {
+	zero := 0.0
+	for _, tc := range []struct {
+		name  string
+		ratio *float64
+		want  int
+	}{
+		{"unset samples everything", nil, 20},
+		{"zero samples nothing", &zero, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			tracer := NewProvider(Config{SampleRatio: tc.ratio}, sdktrace.WithSyncer(exporter)).Tracer("test")
+			for i := 0; i < 20; i++ {
+				_, span := tracer.Start(context.Background(), "root")
+				span.End()
+			}
+			if got := len(exporter.GetSpans()); got != tc.want {
+				t.Fatalf("sampled %d of 20 root spans, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetup_NoneKeepsNoopAndRejectsUnknown(t *testing.T) {
+	shutdown, err := Setup(Config{Exporter: "none"})
+	if err != nil {
+		t.Fatalf("Setup(none): %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if _, err := Setup(Config{Exporter: "zipkin"}); err == nil {
+		t.Fatal("expected error for unknown exporter")
+	}
+}
+
+func newTestExporter(t *testing.T, url string) sdktrace.SpanExporter {
+	t.Helper()
+	exporter, err := NewOTLPExporter(context.Background(), url, otlptracehttp.WithRetry(otlptracehttp.RetryConfig{Enabled: false}))
+	if err != nil {
+		t.Fatalf("NewOTLPExporter: %v", err)
+	}
+	return exporter
+}
+
+func TestOTLPExporter_PostsProtobufSpans(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		body coltracepb.ExportTraceServiceRequest
+		path string
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		path = r.URL.Path
+		_ = proto.Unmarshal(raw, &body)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	tp := NewProvider(Config{ServiceName: "svc-test"}, sdktrace.WithSyncer(newTestExporter(t, collector.URL)))
+	_, span := tp.Tracer(InstrumentationName).Start(context.Background(), "converter.render")
+	span.SetAttributes(attribute.Int("render.warnings", 2), attribute.Bool("ai.used", false))
+	span.SetStatus(codes.Error, "render failed")
+	span.End()
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if path != "/v1/traces" {
+		t.Fatalf("posted to %q, want /v1/traces", path)
+	}
+	if len(body.ResourceSpans) != 1 || len(body.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected payload shape: %v", &body)
+	}
+	res := body.ResourceSpans[0].Resource.Attributes
+	if len(res) == 0 || res[0].Key != "service.name" || res[0].Value.GetStringValue() != "svc-test" {
+		t.Fatalf("missing service.name resource: %v", res)
+	}
+	spans := body.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 1 || spans[0].Name != "converter.render" || len(spans[0].TraceId) != 16 {
+		t.Fatalf("unexpected spans: %v", spans)
+	}
+	if spans[0].Status.GetCode() != tracepb.Status_STATUS_CODE_ERROR || spans[0].Status.GetMessage() != "render failed" {
+		t.Fatalf("status not mapped to OTLP error: %v", spans[0].Status)
+	}
+	attrs := map[string]*commonpb.AnyValue{}
+	for _, kv := range spans[0].Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	if v := attrs["render.warnings"]; v.GetIntValue() != 2 {
+		t.Fatalf("int attribute not encoded: %v", v)
+	}
+	if v, ok := attrs["ai.used"].GetValue().(*commonpb.AnyValue_BoolValue); !ok || v.BoolValue {
+		t.Fatalf("bool attribute not encoded: %v", attrs["ai.used"])
+	}
+}
+
+func TestOTLPExporter_ReportsCollectorErrors(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer collector.Close()
+
+	stub := tracetest.SpanStub{Name: "x"}
+	err := newTestExporter(t, collector.URL+"/v1/traces").ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{stub.Snapshot()})
+	if err == nil {
+		t.Fatal("expected error for 503 collector response")
+	}
+}
@@ -0,0 +1,163 @@
+package transcribe
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Export formats for transcript cues.
+const (
+	ExportJSON     = "json"
+	ExportVTT      = "vtt"
+	ExportSRT      = "srt"
+	ExportMarkdown = "markdown"
+)
+
+// Cue is one timed block of transcript text (a sentence or a paragraph).
+type Cue struct {
+	ID    string
+	Start float64
+	End   float64
+	Text  string
+}
+
+// NormalizeExportFormat maps user input to an export format. Empty means JSON.
+func NormalizeExportFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", ExportJSON:
+		return ExportJSON, nil
+	case ExportVTT, "webvtt":
+		return ExportVTT, nil
+	case ExportSRT, "subrip":
+		return ExportSRT, nil
+	case ExportMarkdown, "md":
+		return ExportMarkdown, nil
+	default:
+		return "", fmt.Errorf("unsupported transcript format %q (use json, vtt, srt or markdown)", format)
+	}
+}
+
+// ExportContentType returns the MIME type served for an export format.
+func ExportContentType(format string) string {
+	switch format {
+	case ExportVTT:
+		return "text/vtt; charset=utf-8"
+	case ExportSRT:
+		return "application/x-subrip; charset=utf-8"
+	case ExportMarkdown:
+		return "text/markdown; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// ExportExtension returns the file extension (with dot) for an export format.
+func ExportExtension(format string) string {
+	switch format {
+	case ExportVTT:
+		return ".vtt"
+	case ExportSRT:
+		return ".srt"
+	case ExportMarkdown:
+		return ".md"
+	default:
+		return ".json"
+	}
+}
+
+// vttEscaper escapes the characters that start markup in a WebVTT cue payload.
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;")
+
+// FormatWebVTT renders cues as a WebVTT document. Cue IDs become cue
+// identifiers so players and spec rows can refer to the same block.
+func FormatWebVTT(cues []Cue) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+	for _, cue := range cues {
+		text := vttEscaper.Replace(cueText(cue.Text))
+		if text == "" {
+			continue
+		}
+		b.WriteString("\n")
+		if cue.ID != "" {
+			b.WriteString(cue.ID + "\n")
+		}
+		fmt.Fprintf(&b, "%s --> %s\n%s\n", clock(cue.Start, '.'), clock(cueEnd(cue), '.'), text)
+	}
+	return b.String()
+}
+
+// FormatSRT renders cues as SubRip subtitles, numbered from 1.
+func FormatSRT(cues []Cue) string {
+	var b strings.Builder
+	n := 0
+	for _, cue := range cues {
+		text := cueText(cue.Text)
+		if text == "" {
+			continue
+		}
+		n++
+		if n > 1 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n", n, clock(cue.Start, ','), clock(cueEnd(cue), ','), text)
+	}
+	return b.String()
+}
+
+// FormatTimestampedMarkdown renders cues as markdown, one block per cue led by
+// its ID and time range, e.g. "**P2** `[00:01:05 - 00:01:40]`".
+func FormatTimestampedMarkdown(title string, cues []Cue) string {
+	if strings.TrimSpace(title) == "" {
+		title = "Transcript"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", strings.TrimSpace(title))
+	for _, cue := range cues {
+		text := strings.Join(strings.Fields(cue.Text), " ")
+		if text == "" {
+			continue
+		}
+		b.WriteString("\n")
+		if cue.ID != "" {
+			fmt.Fprintf(&b, "**%s** ", cue.ID)
+		}
+		fmt.Fprintf(&b, "`[%s - %s]`\n%s\n", clock(cue.Start, 0), clock(cueEnd(cue), 0), text)
+	}
+	return b.String()
+}
+
+// cueText trims a cue and removes blank lines, which would end a VTT/SRT cue early.
+func cueText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.ReplaceAll(strings.Join(kept, "\n"), "-->", "->")
+}
+
+func cueEnd(cue Cue) float64 {
+	if cue.End < cue.Start {
+		return cue.Start
+	}
+	return cue.End
+}
+
+// clock formats seconds as hh:mm:ss, followed by sep and milliseconds when sep is non-zero.
+func clock(seconds float64, sep byte) string {
+	if seconds < 0 || math.IsNaN(seconds) {
+		seconds = 0
+	}
+	ms := int64(math.Round(seconds * 1000))
+	h := ms / 3_600_000
+	m := ms / 60_000 % 60
+	s := ms / 1000 % 60
+	if sep == 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", h, m, s, sep, ms%1000)
+}
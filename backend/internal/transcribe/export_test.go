@@ -0,0 +1,49 @@
+package transcribe
+
+import "testing"
+
+func TestFormatWebVTT(t *testing.T) {
+	got := FormatWebVTT([]Cue{
+		{ID: "S1", Start: 0, End: 1.5, Text: "Hello."},
+		{ID: "S2", Start: 3661.25, End: 3662, Text: "Blank\n\nlines --> gone"},
+		{ID: "S3", Start: 5, End: 6, Text: "   "},
+		{ID: "S4", Start: 7, End: 8, Text: "Q&A: <b> is not a tag"},
+	})
+	want := "WEBVTT\n\nS1\n00:00:00.000 --> 00:00:01.500\nHello.\n\nS2\n01:01:01.250 --> 01:01:02.000\nBlank\nlines -> gone\n" +
+		"\nS4\n00:00:07.000 --> 00:00:08.000\nQ&amp;A: &lt;b> is not a tag\n"
+	if got != want {
+		t.Fatalf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestFormatSRT_NumbersSkipEmptyCues(t *testing.T) {
+	got := FormatSRT([]Cue{
+		{Start: 0, End: 2, Text: ""},
+		{Start: 2, End: 1, Text: "End before start."},
+		{Start: 4.0004, End: 5, Text: "Q&A <unescaped>"},
+	})
+	want := "1\n00:00:02,000 --> 00:00:02,000\nEnd before start.\n\n2\n00:00:04,000 --> 00:00:05,000\nQ&A <unescaped>\n"
+	if got != want {
+		t.Fatalf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestFormatTimestampedMarkdown(t *testing.T) {
+	got := FormatTimestampedMarkdown("", []Cue{{ID: "P1", Start: 65, End: 100.4, Text: "Line one\nline two"}})
+	want := "# Transcript\n\n**P1** `[00:01:05 - 00:01:40]`\nLine one line two\n"
+	if got != want {
+		t.Fatalf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestNormalizeExportFormat(t *testing.T) {
+	for in, want := range map[string]string{"": ExportJSON, "WebVTT": ExportVTT, "srt": ExportSRT, "md": ExportMarkdown} {
+		got, err := NormalizeExportFormat(in)
+		if err != nil || got != want {
+			t.Errorf("NormalizeExportFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := NormalizeExportFormat("docx"); err == nil {
+		t.Error("expected error for docx")
+	}
+}
@@ -0,0 +1,44 @@
+package transcribe
+
+import (
+	"context"
+	"sync"
+)
+
+// Fake is an in-process Transcriber for tests. It returns Result (or Err) for
+// every call and records the requests it received.
+type Fake struct {
+	Result   *Transcription
+	Err      error
+	MaxBytes int64 // reported by MaxUploadBytes; 0 disables chunking
+
+	mu       sync.Mutex
+	requests []Request
+}
+
+func (f *Fake) Name() string { return "fake" }
+
+func (f *Fake) MaxUploadBytes() int64 { return f.MaxBytes }
+
+func (f *Fake) CheckAPIKey(string) error { return nil }
+
+func (f *Fake) Transcribe(ctx context.Context, req Request) (*Transcription, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if f.Result == nil {
+		return &Transcription{}, nil
+	}
+	result := *f.Result
+	return &result, nil
+}
+
+// Requests returns the requests received so far.
+func (f *Fake) Requests() []Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Request(nil), f.requests...)
+}
@@ -0,0 +1,127 @@
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HTTPTranscriber calls an OpenAI-compatible POST {baseURL}/audio/transcriptions
+// endpoint. It backs both OpenAI and self-hosted whisper servers that implement
+// the same API (faster-whisper-server, LocalAI, ...).
+type HTTPTranscriber struct {
+	name             string
+	baseURL          string
+	model            string
+	apiKey           string
+	requireAPIKey    bool
+	acceptRequestKey bool // use Request.APIKey when set (OpenAI BYOK)
+	maxUploadBytes   int64
+	client           *http.Client
+}
+
+func (t *HTTPTranscriber) Name() string { return t.name }
+
+func (t *HTTPTranscriber) MaxUploadBytes() int64 { return t.maxUploadBytes }
+
+// BaseURL returns the API base the backend posts to.
+func (t *HTTPTranscriber) BaseURL() string { return t.baseURL }
+
+func (t *HTTPTranscriber) CheckAPIKey(apiKey string) error {
+	if t.requireAPIKey && t.resolveAPIKey(apiKey) == "" {
+		return ErrAPIKeyRequired
+	}
+	return nil
+}
+
+func (t *HTTPTranscriber) resolveAPIKey(requestKey string) string {
+	if t.acceptRequestKey {
+		if key := strings.TrimSpace(requestKey); key != "" {
+			return key
+		}
+	}
+	return t.apiKey
+}
+
+func (t *HTTPTranscriber) Transcribe(ctx context.Context, req Request) (*Transcription, error) {
+	apiKey := t.resolveAPIKey(req.APIKey)
+	if t.requireAPIKey && apiKey == "" {
+		return nil, ErrAPIKeyRequired
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	file, err := os.Open(req.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %w", err)
+	}
+	defer file.Close()
+
+	part, err := writer.CreateFormFile("file", filepath.Base(req.FilePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare upload: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, fmt.Errorf("failed to read audio: %w", err)
+	}
+
+	fields := [][2]string{
+		{"model", t.model},
+		{"response_format", "verbose_json"},
+		{"timestamp_granularities[]", "word"},
+		{"timestamp_granularities[]", "segment"},
+	}
+	for _, field := range fields {
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return nil, fmt.Errorf("failed to write %s field: %w", field[0], err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to prepare request")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/audio/transcriptions", body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("transcription request timeout: %w", err)
+		}
+		return nil, fmt.Errorf("transcription request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		payload, _ := io.ReadAll(io.LimitReader(resp.Body, 8<<10))
+		errMsg := strings.TrimSpace(string(payload))
+		switch resp.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return nil, fmt.Errorf("%s authentication failed: %s", t.name, errMsg)
+		case http.StatusTooManyRequests:
+			return nil, fmt.Errorf("%s rate limited: %s", t.name, errMsg)
+		}
+		return nil, fmt.Errorf("transcription failed (status %d): %s", resp.StatusCode, errMsg)
+	}
+
+	var transcription Transcription
+	if err := json.NewDecoder(resp.Body).Decode(&transcription); err != nil {
+		return nil, fmt.Errorf("failed to parse transcription: %w", err)
+	}
+	return &transcription, nil
+}
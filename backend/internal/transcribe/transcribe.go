@@ -0,0 +1,133 @@
+// Package transcribe provides speech-to-text backends for audio uploads.
+//
+// Every backend returns the same Transcription shape (OpenAI verbose_json:
+// text, segments and word timestamps) so sentence and paragraph splitting
+// downstream does not depend on which provider produced it.
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Provider names accepted by New.
+const (
+	ProviderOpenAI      = "openai"
+	ProviderWhisperHTTP = "whisper_http" // self-hosted, OpenAI-compatible /audio/transcriptions
+)
+
+const (
+	DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+	DefaultModel         = "whisper-1"
+
+	// OpenAIMaxUploadBytes is the OpenAI per-request audio limit; larger files are chunked.
+	OpenAIMaxUploadBytes = 25 << 20
+)
+
+// ErrAPIKeyRequired is returned when the backend needs a key and none is available.
+var ErrAPIKeyRequired = errors.New("transcribe: API key required")
+
+// Word is one word with timestamps in seconds.
+type Word struct {
+	Word       string  `json:"word"`
+	Start      float64 `json:"start"`
+	End        float64 `json:"end"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// Segment is one provider-defined segment with timestamps in seconds.
+type Segment struct {
+	ID    int     `json:"id"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// Transcription is the result of transcribing one audio file.
+type Transcription struct {
+	Text     string    `json:"text"`
+	Language string    `json:"language"`
+	Duration float64   `json:"duration"`
+	Segments []Segment `json:"segments"`
+	Words    []Word    `json:"words"`
+}
+
+// Request describes one transcription call.
+type Request struct {
+	FilePath string
+	// APIKey is a per-request key (BYOK). Only the OpenAI backend uses it;
+	// self-hosted backends never receive user keys.
+	APIKey string
+}
+
+// Transcriber turns an audio file into a Transcription.
+type Transcriber interface {
+	// Name identifies the backend, e.g. "openai" or "whisper_http".
+	Name() string
+	// MaxUploadBytes is the largest file accepted in one call; 0 means no limit.
+	// Callers split larger files before calling Transcribe.
+	MaxUploadBytes() int64
+	// CheckAPIKey reports whether a request carrying apiKey can be served.
+	CheckAPIKey(apiKey string) error
+	Transcribe(ctx context.Context, req Request) (*Transcription, error)
+}
+
+// Config selects and configures a backend.
+type Config struct {
+	Provider       string // ProviderOpenAI (default) or ProviderWhisperHTTP
+	BaseURL        string // API base including version, e.g. http://whisper:8000/v1
+	Model          string
+	APIKey         string // server key; for OpenAI it may be overridden per request
+	MaxUploadBytes int64  // 0 uses the provider default
+}
+
+// New returns the backend selected by cfg.Provider.
+func New(cfg Config, client *http.Client) (Transcriber, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	model := strings.TrimSpace(cfg.Model)
+	if model == "" {
+		model = DefaultModel
+	}
+
+	switch strings.ToLower(strings.TrimSpace(cfg.Provider)) {
+	case "", ProviderOpenAI:
+		baseURL := strings.TrimSpace(cfg.BaseURL)
+		if baseURL == "" {
+			baseURL = DefaultOpenAIBaseURL
+		}
+		maxBytes := cfg.MaxUploadBytes
+		if maxBytes <= 0 {
+			maxBytes = OpenAIMaxUploadBytes
+		}
+		return &HTTPTranscriber{
+			name:             ProviderOpenAI,
+			baseURL:          strings.TrimRight(baseURL, "/"),
+			model:            model,
+			apiKey:           strings.TrimSpace(cfg.APIKey),
+			requireAPIKey:    true,
+			acceptRequestKey: true,
+			maxUploadBytes:   maxBytes,
+			client:           client,
+		}, nil
+	case ProviderWhisperHTTP:
+		baseURL := strings.TrimSpace(cfg.BaseURL)
+		if baseURL == "" {
+			return nil, fmt.Errorf("transcribe: base URL is required for provider %q", ProviderWhisperHTTP)
+		}
+		return &HTTPTranscriber{
+			name:           ProviderWhisperHTTP,
+			baseURL:        strings.TrimRight(baseURL, "/"),
+			model:          model,
+			apiKey:         strings.TrimSpace(cfg.APIKey),
+			maxUploadBytes: cfg.MaxUploadBytes,
+			client:         client,
+		}, nil
+	default:
+		return nil, fmt.Errorf("transcribe: unknown provider %q", cfg.Provider)
+	}
+}
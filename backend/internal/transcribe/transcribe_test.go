@@ -0,0 +1,156 @@
+package transcribe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type capturedRequest struct {
+	path          string
+	authorization string
+	model         string
+	format        string
+	filename      string
+}
+
+func fakeServer(t *testing.T, status int, captured *capturedRequest) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parse multipart: %v", err)
+		}
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("missing file part: %v", err)
+		}
+		*captured = capturedRequest{
+			path:          r.URL.Path,
+			authorization: r.Header.Get("Authorization"),
+			model:         r.FormValue("model"),
+			format:        r.FormValue("response_format"),
+			filename:      header.Filename,
+		}
+		if status != http.StatusOK {
+			http.Error(w, "nope", status)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Transcription{
+			Text:     "Hello there.",
+			Language: "en",
+			Duration: 1.5,
+			Words:    []Word{{Word: "Hello", Start: 0, End: 0.5}, {Word: "there.", Start: 0.6, End: 1.2}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func writeAudio(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "clip.wav")
+	if err := os.WriteFile(path, []byte("RIFF....WAVE"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNew_SelectsProvider(t *testing.T) {
+	openai, err := New(Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if openai.Name() != ProviderOpenAI || openai.MaxUploadBytes() != OpenAIMaxUploadBytes {
+		t.Fatalf("default backend = %s (max %d), want openai with 25MB limit", openai.Name(), openai.MaxUploadBytes())
+	}
+	if got := openai.(*HTTPTranscriber).BaseURL(); got != DefaultOpenAIBaseURL {
+		t.Fatalf("openai base URL = %q", got)
+	}
+
+	local, err := New(Config{Provider: "WHISPER_HTTP", BaseURL: "http://whisper:8000/v1/"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if local.Name() != ProviderWhisperHTTP || local.MaxUploadBytes() != 0 {
+		t.Fatalf("whisper backend = %s (max %d), want no upload limit", local.Name(), local.MaxUploadBytes())
+	}
+	if got := local.(*HTTPTranscriber).BaseURL(); got != "http://whisper:8000/v1" {
+		t.Fatalf("whisper base URL = %q, want trailing slash trimmed", got)
+	}
+
+	if _, err := New(Config{Provider: ProviderWhisperHTTP}, nil); err == nil {
+		t.Fatal("expected error for whisper_http without base URL")
+	}
+	if _, err := New(Config{Provider: "azure"}, nil); err == nil {
+		t.Fatal("expected error for unknown provider")
+	}
+}
+
+func TestHTTPTranscriber_OpenAIUsesRequestKey(t *testing.T) {
+	var captured capturedRequest
+	srv := fakeServer(t, http.StatusOK, &captured)
+	tr, _ := New(Config{BaseURL: srv.URL + "/v1", APIKey: "server-key"}, srv.Client())
+
+	result, err := tr.Transcribe(context.Background(), Request{FilePath: writeAudio(t), APIKey: "user-key"})
+	if err != nil {
+		t.Fatalf("Transcribe: %v", err)
+	}
+	if captured.path != "/v1/audio/transcriptions" || captured.authorization != "Bearer user-key" {
+		t.Fatalf("request = %+v, want BYOK key on /v1/audio/transcriptions", captured)
+	}
+	if captured.model != DefaultModel || captured.format != "verbose_json" || captured.filename != "clip.wav" {
+		t.Fatalf("form = %+v", captured)
+	}
+	if result.Text != "Hello there." || len(result.Words) != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+}
+
+func TestHTTPTranscriber_OpenAIRequiresKey(t *testing.T) {
+	tr, _ := New(Config{}, nil)
+	if err := tr.CheckAPIKey(""); !errors.Is(err, ErrAPIKeyRequired) {
+		t.Fatalf("CheckAPIKey = %v, want ErrAPIKeyRequired", err)
+	}
+	if err := tr.CheckAPIKey("user-key"); err != nil {
+		t.Fatalf("CheckAPIKey with request key = %v", err)
+	}
+}
+
+func TestHTTPTranscriber_WhisperNeverForwardsUserKey(t *testing.T) {
+	var captured capturedRequest
+	srv := fakeServer(t, http.StatusOK, &captured)
+	tr, _ := New(Config{Provider: ProviderWhisperHTTP, BaseURL: srv.URL, Model: "large-v3"}, srv.Client())
+
+	if err := tr.CheckAPIKey(""); err != nil {
+		t.Fatalf("self-hosted backend should not need a key: %v", err)
+	}
+	if _, err := tr.Transcribe(context.Background(), Request{FilePath: writeAudio(t), APIKey: "sk-user"}); err != nil {
+		t.Fatalf("Transcribe: %v", err)
+	}
+	if captured.authorization != "" {
+		t.Fatalf("user key forwarded to self-hosted backend: %q", captured.authorization)
+	}
+	if captured.model != "large-v3" {
+		t.Fatalf("model = %q", captured.model)
+	}
+}
+
+func TestHTTPTranscriber_AuthErrorNamesProvider(t *testing.T) {
+	var captured capturedRequest
+	srv := fakeServer(t, http.StatusUnauthorized, &captured)
+	tr, _ := New(Config{Provider: ProviderWhisperHTTP, BaseURL: srv.URL, APIKey: "bad"}, srv.Client())
+
+	_, err := tr.Transcribe(context.Background(), Request{FilePath: writeAudio(t)})
+	if err == nil || !strings.HasPrefix(err.Error(), "whisper_http authentication failed") {
+		t.Fatalf("err = %v", err)
+	}
+	if captured.authorization != "Bearer bad" {
+		t.Fatalf("server key not sent: %q", captured.authorization)
+	}
+}
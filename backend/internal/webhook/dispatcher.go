@@ -0,0 +1,448 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrDispatcherClosed is returned by Replay after Close.
+var ErrDispatcherClosed = errors.New("webhook: dispatcher closed")
+
+// Config controls delivery behaviour.
+type Config struct {
+	MaxAttempts int           // total attempts per delivery, including the first
+	BaseDelay   time.Duration // backoff before the 2nd attempt; doubles each retry
+	MaxDelay    time.Duration // backoff cap
+	Timeout     time.Duration // per-attempt HTTP timeout
+	Workers     int           // at most one works on an endpoint at a time
+	QueueSize   int           // capacity of the event queue and of the delivery queue
+}
+
+// DefaultConfig returns production defaults (5 attempts over roughly a minute).
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts: 5,
+		BaseDelay:   2 * time.Second,
+		MaxDelay:    30 * time.Second,
+		Timeout:     10 * time.Second,
+		Workers:     2,
+		QueueSize:   256,
+	}
+}
+
+type job struct {
+	endpoint Endpoint
+	delivery *Delivery
+	body     []byte
+}
+
+// Dispatcher fans events out to subscribed endpoints on background workers,
+// retrying failures with exponential backoff and dead-lettering exhausted deliveries.
+// Workers make one attempt per job; retries wait on timers outside them, so a
+// failing endpoint holds at most one worker at a time.
+type Dispatcher struct {
+	store  *Store
+	client *http.Client
+	cfg    Config
+
+	events    chan Event // published events awaiting fan-out
+	fannedOut chan struct{}
+	queue     chan job
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	retries   sync.WaitGroup // goroutines waiting to requeue a job
+
+	mu       sync.RWMutex
+	closed   bool // no more events or replays
+	stopping bool // no more retries; unfinished deliveries stay in the store
+
+	busyMu sync.Mutex
+	busy   map[string]bool // endpoints with an attempt in flight
+}
+
+// NewDispatcher starts the fan-out goroutine and cfg.Workers delivery workers.
+// Deliveries a previous dispatcher left pending or failed in store are resumed
+// first. Call Close to stop them.
+func NewDispatcher(store *Store, client *http.Client, cfg Config) *Dispatcher {
+	defaults := DefaultConfig()
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaults.MaxAttempts
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = defaults.BaseDelay
+	}
+	if cfg.MaxDelay < cfg.BaseDelay {
+		cfg.MaxDelay = cfg.BaseDelay * 16
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaults.Workers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaults.QueueSize
+	}
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		store:     store,
+		client:    client,
+		cfg:       cfg,
+		events:    make(chan Event, cfg.QueueSize),
+		fannedOut: make(chan struct{}),
+		queue:     make(chan job, cfg.QueueSize),
+		ctx:       ctx,
+		cancel:    cancel,
+		busy:      map[string]bool{},
+	}
+	go d.fanOut()
+	for i := 0; i < cfg.Workers; i++ {
+		d.wg.Add(1)
+		go d.worker()
+	}
+	return d
+}
+
+// Publish queues an event for the fan-out goroutine, which records a delivery
+// for every active endpoint subscribed to eventType. Publish does no storage
+// work: data is encoded on the caller's goroutine, and when the event queue is
+// full the event is dropped with a warning.
+func (d *Dispatcher) Publish(eventType string, data any) {
+	event := Event{ID: "evt_" + randomHex(8), Type: eventType, CreatedAt: time.Now().UTC()}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		slog.Warn("webhook: encode event failed", "event", eventType, "error", err)
+		return
+	}
+	event.Data = json.RawMessage(encoded)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return
+	}
+	select {
+	case d.events <- event:
+	default:
+		slog.Warn("webhook: event queue full; event dropped", "event", eventType, "event_id", event.ID)
+	}
+}
+
+// fanOut resumes unfinished deliveries, then records and queues the deliveries
+// of each published event until the event queue is closed.
+func (d *Dispatcher) fanOut() {
+	defer close(d.fannedOut)
+	d.resume()
+	for event := range d.events {
+		endpoints, err := d.store.ListEndpoints()
+		if err != nil {
+			slog.Warn("webhook: list endpoints failed", "event", event.Type, "error", err)
+			continue
+		}
+		body, err := json.Marshal(event)
+		if err != nil {
+			slog.Warn("webhook: encode event failed", "event", event.Type, "error", err)
+			continue
+		}
+
+		for _, ep := range endpoints {
+			if !ep.Active || !ep.Subscribed(event.Type) {
+				continue
+			}
+			now := time.Now().UTC()
+			delivery := &Delivery{
+				ID:         "dlv_" + randomHex(8),
+				EndpointID: ep.ID,
+				EventID:    event.ID,
+				EventType:  event.Type,
+				Payload:    string(body),
+				Status:     StatusPending,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}
+			if err := d.store.insertDelivery(delivery); err != nil {
+				slog.Warn("webhook: record delivery failed", "endpoint", ep.ID, "error", err)
+				continue
+			}
+			d.enqueue(job{endpoint: ep, delivery: delivery, body: body})
+		}
+	}
+}
+
+// resume queues the deliveries left pending or failed by a previous
+// dispatcher; failed ones wait out their backoff first.
+func (d *Dispatcher) resume() {
+	deliveries, err := d.store.unfinishedDeliveries()
+	if err != nil {
+		slog.Warn("webhook: resume deliveries failed", "error", err)
+		return
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+	endpoints, err := d.store.ListEndpoints()
+	if err != nil {
+		slog.Warn("webhook: resume deliveries failed", "error", err)
+		return
+	}
+	byID := make(map[string]Endpoint, len(endpoints))
+	for _, ep := range endpoints {
+		byID[ep.ID] = ep
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		ep, ok := byID[delivery.EndpointID]
+		if !ok {
+			delivery.LastError = "endpoint deleted before delivery"
+			d.finish(delivery, StatusDead)
+			continue
+		}
+		j := job{endpoint: ep, delivery: delivery, body: []byte(delivery.Payload)}
+		if delivery.Attempts > 0 {
+			d.retry(j, d.backoff(delivery.Attempts))
+			continue
+		}
+		d.enqueue(j)
+	}
+	slog.Info("webhook: resumed unfinished deliveries", "count", len(deliveries))
+}
+
+// Replay re-queues a dead letter as a new delivery to its endpoint.
+func (d *Dispatcher) Replay(deadLetterID int64) (*Delivery, error) {
+	d.mu.RLock()
+	closed := d.closed
+	d.mu.RUnlock()
+	if closed {
+		return nil, ErrDispatcherClosed
+	}
+
+	dl, err := d.store.GetDeadLetter(deadLetterID)
+	if err != nil {
+		return nil, err
+	}
+	ep, err := d.store.GetEndpoint(dl.EndpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	var event Event
+	if err := json.Unmarshal([]byte(dl.Payload), &event); err != nil {
+		return nil, fmt.Errorf("webhook: decode dead letter payload: %w", err)
+	}
+	now := time.Now().UTC()
+	delivery := &Delivery{
+		ID:         "dlv_" + randomHex(8),
+		EndpointID: ep.ID,
+		EventID:    event.ID,
+		EventType:  dl.EventType,
+		Payload:    dl.Payload,
+		Status:     StatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := d.store.insertDelivery(delivery); err != nil {
+		return nil, err
+	}
+	if err := d.store.markReplayed(dl.ID); err != nil {
+		return nil, err
+	}
+	// A Close that begins meanwhile leaves the delivery pending for the next dispatcher.
+	d.retry(job{endpoint: *ep, delivery: delivery, body: []byte(dl.Payload)}, 0)
+	return delivery, nil
+}
+
+// Close stops accepting events, fans out the events already published, attempts
+// the deliveries already queued and waits for workers to exit. Deliveries
+// waiting to retry stay pending or failed in the store and are resumed by the
+// next dispatcher.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	close(d.events)
+	d.mu.Unlock()
+
+	<-d.fannedOut
+	d.mu.Lock()
+	d.stopping = true
+	d.mu.Unlock()
+	d.cancel()
+	d.retries.Wait()
+
+	close(d.queue)
+	d.wg.Wait()
+}
+
+// enqueue queues j for a worker, or retries it after BaseDelay when the queue
+// is full. It must be called while the delivery queue is open: from fanOut or
+// a retry goroutine.
+func (d *Dispatcher) enqueue(j job) {
+	select {
+	case d.queue <- j:
+	default:
+		d.retry(j, d.cfg.BaseDelay)
+	}
+}
+
+// retry queues j again after delay on its own goroutine. Once Close has begun
+// it does nothing, leaving the delivery in the store.
+func (d *Dispatcher) retry(j job, delay time.Duration) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.stopping {
+		return
+	}
+	d.retries.Add(1)
+	go func() {
+		defer d.retries.Done()
+		if d.sleep(delay) {
+			d.enqueue(j)
+		}
+	}()
+}
+
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+	for j := range d.queue {
+		// Another worker is attempting this endpoint; try again later rather
+		// than letting a slow endpoint hold every worker.
+		if !d.claim(j.endpoint.ID) {
+			d.retry(j, d.cfg.BaseDelay)
+			continue
+		}
+		d.deliver(j)
+		d.release(j.endpoint.ID)
+	}
+}
+
+// claim marks endpointID busy and reports whether it was idle.
+func (d *Dispatcher) claim(endpointID string) bool {
+	d.busyMu.Lock()
+	defer d.busyMu.Unlock()
+	if d.busy[endpointID] {
+		return false
+	}
+	d.busy[endpointID] = true
+	return true
+}
+
+func (d *Dispatcher) release(endpointID string) {
+	d.busyMu.Lock()
+	defer d.busyMu.Unlock()
+	delete(d.busy, endpointID)
+}
+
+// deliver makes one attempt and, on a retryable failure, schedules the next
+// after its backoff.
+func (d *Dispatcher) deliver(j job) {
+	retryable := d.attempt(j)
+	j.delivery.Attempts++
+	j.delivery.UpdatedAt = time.Now().UTC()
+	if j.delivery.LastError == "" {
+		d.finish(j.delivery, StatusSucceeded)
+		return
+	}
+	if !retryable || j.delivery.Attempts >= d.cfg.MaxAttempts {
+		d.finish(j.delivery, StatusDead)
+		return
+	}
+	j.delivery.Status = StatusFailed
+	if err := d.store.updateDelivery(j.delivery); err != nil {
+		slog.Warn("webhook: update delivery failed", "delivery", j.delivery.ID, "error", err)
+	}
+	d.retry(j, d.backoff(j.delivery.Attempts))
+}
+
+// attempt performs one POST and records the outcome on the delivery.
+// It reports whether a failure is worth retrying.
+func (d *Dispatcher) attempt(j job) bool {
+	j.delivery.LastError = ""
+	j.delivery.ResponseCode = 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.endpoint.URL, bytes.NewReader(j.body))
+	if err != nil {
+		j.delivery.LastError = err.Error()
+		return false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mdflow-webhooks/1")
+	req.Header.Set(HeaderEvent, j.delivery.EventType)
+	req.Header.Set(HeaderDelivery, j.delivery.ID)
+	req.Header.Set(HeaderSignature, Sign(j.endpoint.Secret, time.Now(), j.body))
+
+	start := time.Now()
+	resp, err := d.client.Do(req)
+	j.delivery.DurationMS = time.Since(start).Milliseconds()
+	if err != nil {
+		j.delivery.LastError = err.Error()
+		return true
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	j.delivery.ResponseCode = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false
+	}
+	j.delivery.LastError = fmt.Sprintf("endpoint returned status %d", resp.StatusCode)
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout
+}
+
+func (d *Dispatcher) finish(delivery *Delivery, status string) {
+	delivery.Status = status
+	delivery.UpdatedAt = time.Now().UTC()
+	if err := d.store.updateDelivery(delivery); err != nil {
+		slog.Warn("webhook: update delivery failed", "delivery", delivery.ID, "error", err)
+	}
+	if status != StatusDead {
+		return
+	}
+	slog.Warn("webhook: delivery dead-lettered", "delivery", delivery.ID, "endpoint", delivery.EndpointID,
+		"event", delivery.EventType, "attempts", delivery.Attempts, "error", delivery.LastError)
+	if err := d.store.insertDeadLetter(delivery); err != nil {
+		slog.Warn("webhook: dead-letter insert failed", "delivery", delivery.ID, "error", err)
+	}
+}
+
+// backoff returns the wait before retry n (1-based): BaseDelay * 2^(n-1), capped at MaxDelay.
+func (d *Dispatcher) backoff(retry int) time.Duration {
+	delay := d.cfg.BaseDelay
+	for i := 1; i < retry && delay < d.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > d.cfg.MaxDelay {
+		delay = d.cfg.MaxDelay
+	}
+	return delay
+}
+
+// sleep waits for delay and reports false if the dispatcher began closing meanwhile.
+func (d *Dispatcher) sleep(delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-d.ctx.Done():
+		return false
+	}
+}
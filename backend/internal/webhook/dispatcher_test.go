@@ -0,0 +1,334 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// receiver is an httptest endpoint that verifies signatures and answers with
+// the status codes queued in responses (200 once they run out).
+type receiver struct {
+	t      *testing.T
+	secret string
+
+	mu        sync.Mutex
+	responses []int
+	events    []Event
+	calls     atomic.Int32
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.calls.Add(1)
+	body, _ := io.ReadAll(req.Body)
+	if err := Verify(r.secret, req.Header.Get(HeaderSignature), body, time.Minute, time.Now()); err != nil {
+		r.t.Errorf("receiver: signature verification failed: %v", err)
+	}
+	if req.Header.Get(HeaderDelivery) == "" {
+		r.t.Error("receiver: missing delivery header")
+	}
+
+	r.mu.Lock()
+	status := http.StatusOK
+	if len(r.responses) > 0 {
+		status, r.responses = r.responses[0], r.responses[1:]
+	}
+	if status == http.StatusOK {
+		var ev Event
+		if err := json.Unmarshal(body, &ev); err != nil {
+			r.t.Errorf("receiver: decode event: %v", err)
+		}
+		if got := req.Header.Get(HeaderEvent); got != ev.Type {
+			r.t.Errorf("receiver: event header %q, body type %q", got, ev.Type)
+		}
+		r.events = append(r.events, ev)
+	}
+	r.mu.Unlock()
+	w.WriteHeader(status)
+}
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := NewStore("")
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func newTestDispatcher(t *testing.T, s *Store, maxAttempts int) *Dispatcher {
+	t.Helper()
+	d := NewDispatcher(s, nil, Config{MaxAttempts: maxAttempts, BaseDelay: 5 * time.Millisecond, Timeout: time.Second})
+	t.Cleanup(d.Close)
+	return d
+}
+
+func newReceiverEndpoint(t *testing.T, s *Store, events []string, responses ...int) (*receiver, *Endpoint) {
+	t.Helper()
+	recv := &receiver{t: t, responses: responses}
+	server := httptest.NewServer(recv)
+	t.Cleanup(server.Close)
+
+	ep, err := s.CreateEndpoint(CreateEndpointInput{URL: server.URL, Events: events})
+	if err != nil {
+		t.Fatalf("CreateEndpoint: %v", err)
+	}
+	recv.secret = ep.Secret
+	return recv, ep
+}
+
+// waitForDelivery polls until the endpoint has a delivery in a terminal state.
+func waitForDelivery(t *testing.T, s *Store, endpointID string) Delivery {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		deliveries, err := s.ListDeliveries(endpointID, 10)
+		if err != nil {
+			t.Fatalf("ListDeliveries: %v", err)
+		}
+		if len(deliveries) > 0 && (deliveries[0].Status == StatusSucceeded || deliveries[0].Status == StatusDead) {
+			return deliveries[0]
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for delivery")
+	return Delivery{}
+}
+
+func TestDispatcherDeliversSignedEvent(t *testing.T) {
+	s := newTestStore(t)
+	d := newTestDispatcher(t, s, 3)
+	recv, ep := newReceiverEndpoint(t, s, []string{EventShareCreated})
+
+	d.Publish(EventShareCreated, map[string]string{"token": "abc"})
+
+	delivery := waitForDelivery(t, s, ep.ID)
+	if delivery.Status != StatusSucceeded || delivery.Attempts != 1 || delivery.ResponseCode != http.StatusOK {
+		t.Fatalf("unexpected delivery: %+v", delivery)
+	}
+	recv.mu.Lock()
+	defer recv.mu.Unlock()
+	if len(recv.events) != 1 || recv.events[0].Type != EventShareCreated {
+		t.Fatalf("receiver got %+v", recv.events)
+	}
+	data, _ := recv.events[0].Data.(map[string]any)
+	if data["token"] != "abc" {
+		t.Fatalf("unexpected data: %+v", recv.events[0].Data)
+	}
+}
+
+func TestDispatcherRetriesThenSucceeds(t *testing.T) {
+	s := newTestStore(t)
+	d := newTestDispatcher(t, s, 3)
+	recv, ep := newReceiverEndpoint(t, s, []string{EventAll}, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	d.Publish(EventFeedbackSubmitted, map[string]int{"rating": 5})
+
+	delivery := waitForDelivery(t, s, ep.ID)
+	if delivery.Status != StatusSucceeded || delivery.Attempts != 3 {
+		t.Fatalf("expected success on 3rd attempt, got %+v", delivery)
+	}
+	if got := recv.calls.Load(); got != 3 {
+		t.Fatalf("receiver calls = %d, want 3", got)
+	}
+	letters, _ := s.ListDeadLetters(10)
+	if len(letters) != 0 {
+		t.Fatalf("expected no dead letters, got %d", len(letters))
+	}
+}
+
+func TestDispatcherDeadLettersAndReplays(t *testing.T) {
+	s := newTestStore(t)
+	d := newTestDispatcher(t, s, 2)
+	recv, ep := newReceiverEndpoint(t, s, []string{EventCommentAdded},
+		http.StatusInternalServerError, http.StatusBadGateway)
+
+	d.Publish(EventCommentAdded, map[string]string{"comment_id": "c1"})
+
+	delivery := waitForDelivery(t, s, ep.ID)
+	if delivery.Status != StatusDead || delivery.Attempts != 2 || delivery.ResponseCode != http.StatusBadGateway {
+		t.Fatalf("expected dead delivery after 2 attempts, got %+v", delivery)
+	}
+	letters, err := s.ListDeadLetters(10)
+	if err != nil || len(letters) != 1 {
+		t.Fatalf("ListDeadLetters = %v, %v", letters, err)
+	}
+	if letters[0].DeliveryID != delivery.ID || letters[0].EndpointID != ep.ID {
+		t.Fatalf("dead letter does not match delivery: %+v", letters[0])
+	}
+
+	replayed, err := d.Replay(letters[0].ID)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		deliveries, _ := s.ListDeliveries(ep.ID, 10)
+		for _, dl := range deliveries {
+			if dl.ID == replayed.ID && dl.Status == StatusSucceeded {
+				if dl.EventID != delivery.EventID {
+					t.Fatalf("replay changed event id: %s != %s", dl.EventID, delivery.EventID)
+				}
+				if got := recv.calls.Load(); got != 3 {
+					t.Fatalf("receiver calls = %d, want 3", got)
+				}
+				letters, _ = s.ListDeadLetters(10)
+				if letters[0].ReplayedAt == nil {
+					t.Fatal("dead letter not marked replayed")
+				}
+				return
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("replayed delivery did not succeed")
+}
+
+func TestDispatcherDoesNotRetryClientErrors(t *testing.T) {
+	s := newTestStore(t)
+	d := newTestDispatcher(t, s, 5)
+	recv, ep := newReceiverEndpoint(t, s, []string{EventAll}, http.StatusGone)
+
+	d.Publish(EventConversionCompleted, nil)
+
+	delivery := waitForDelivery(t, s, ep.ID)
+	if delivery.Status != StatusDead || delivery.Attempts != 1 {
+		t.Fatalf("expected immediate dead letter, got %+v", delivery)
+	}
+	if got := recv.calls.Load(); got != 1 {
+		t.Fatalf("receiver calls = %d, want 1", got)
+	}
+}
+
+func TestDispatcherSkipsUnsubscribedAndInactive(t *testing.T) {
+	s := newTestStore(t)
+	d := newTestDispatcher(t, s, 1)
+	_, reviewOnly := newReceiverEndpoint(t, s, []string{EventConversionReview})
+	_, paused := newReceiverEndpoint(t, s, []string{EventAll})
+	active, all := newReceiverEndpoint(t, s, []string{EventAll})
+	if err := s.SetEndpointActive(paused.ID, false); err != nil {
+		t.Fatalf("SetEndpointActive: %v", err)
+	}
+
+	d.Publish(EventConversionCompleted, nil)
+	waitForDelivery(t, s, all.ID)
+
+	for _, id := range []string{reviewOnly.ID, paused.ID} {
+		deliveries, _ := s.ListDeliveries(id, 10)
+		if len(deliveries) != 0 {
+			t.Fatalf("endpoint %s should not receive deliveries, got %d", id, len(deliveries))
+		}
+	}
+	if got := active.calls.Load(); got != 1 {
+		t.Fatalf("subscribed endpoint calls = %d, want 1", got)
+	}
+}
+
+func TestDispatcherCloseFansOutPublishedEvents(t *testing.T) {
+	s := newTestStore(t)
+	d := NewDispatcher(s, nil, Config{MaxAttempts: 1, Timeout: time.Second})
+	recv, ep := newReceiverEndpoint(t, s, []string{EventAll})
+
+	d.Publish(EventShareCreated, map[string]string{"token": "abc"})
+	d.Close()
+	d.Publish(EventShareCreated, map[string]string{"token": "late"})
+
+	deliveries, err := s.ListDeliveries(ep.ID, 10)
+	if err != nil {
+		t.Fatalf("ListDeliveries: %v", err)
+	}
+	if len(deliveries) != 1 || recv.calls.Load() > 1 {
+		t.Fatalf("expected the event published before Close only, got %d deliveries and %d calls", len(deliveries), recv.calls.Load())
+	}
+}
+
+func TestDispatcherFailingEndpointDoesNotBlockOthers(t *testing.T) {
+	s := newTestStore(t)
+	d := NewDispatcher(s, nil, Config{MaxAttempts: 5, BaseDelay: time.Second, Timeout: time.Second, Workers: 2})
+	t.Cleanup(d.Close)
+	failing := make([]int, 20)
+	for i := range failing {
+		failing[i] = http.StatusServiceUnavailable
+	}
+	_, down := newReceiverEndpoint(t, s, []string{EventShareCreated}, failing...)
+	healthy, up := newReceiverEndpoint(t, s, []string{EventCommentAdded})
+
+	for i := 0; i < 4; i++ {
+		d.Publish(EventShareCreated, nil)
+	}
+	d.Publish(EventCommentAdded, nil)
+
+	// The healthy endpoint is served long before the failing one's first retry.
+	delivery := waitForDelivery(t, s, up.ID)
+	if delivery.Status != StatusSucceeded || healthy.calls.Load() != 1 {
+		t.Fatalf("healthy endpoint delivery = %+v", delivery)
+	}
+	letters, _ := s.ListDeadLetters(10)
+	if len(letters) != 0 {
+		t.Fatalf("expected no dead letters while retrying, got %+v", letters)
+	}
+	deliveries, _ := s.ListDeliveries(down.ID, 10)
+	for _, dl := range deliveries {
+		if dl.Status == StatusDead || dl.Status == StatusSucceeded {
+			t.Fatalf("failing endpoint delivery finished early: %+v", dl)
+		}
+	}
+}
+
+func TestDispatcherResumesUnfinishedDeliveries(t *testing.T) {
+	s := newTestStore(t)
+	recv, ep := newReceiverEndpoint(t, s, []string{EventAll}, http.StatusServiceUnavailable)
+
+	// The first attempt fails; Close leaves the delivery waiting to retry.
+	first := NewDispatcher(s, nil, Config{MaxAttempts: 3, BaseDelay: time.Hour, Timeout: time.Second})
+	first.Publish(EventShareCreated, map[string]string{"token": "abc"})
+	deadline := time.Now().Add(3 * time.Second)
+	for recv.calls.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	first.Close()
+	deliveries, _ := s.ListDeliveries(ep.ID, 10)
+	if len(deliveries) != 1 || deliveries[0].Status != StatusFailed || deliveries[0].Attempts != 1 {
+		t.Fatalf("deliveries after close = %+v", deliveries)
+	}
+
+	newTestDispatcher(t, s, 3)
+	delivery := waitForDelivery(t, s, ep.ID)
+	if delivery.Status != StatusSucceeded || delivery.Attempts != 2 || recv.calls.Load() != 2 {
+		t.Fatalf("resumed delivery = %+v, calls = %d", delivery, recv.calls.Load())
+	}
+}
+
+func TestCreateEndpointValidation(t *testing.T) {
+	s := newTestStore(t)
+	cases := []CreateEndpointInput{
+		{URL: "ftp://example.com", Events: []string{EventAll}},
+		{URL: "https://example.com/hook"},
+		{URL: "https://example.com/hook", Events: []string{"nope"}},
+	}
+	for _, in := range cases {
+		if _, err := s.CreateEndpoint(in); err != ErrInvalidEndpoint {
+			t.Errorf("CreateEndpoint(%+v) error = %v, want ErrInvalidEndpoint", in, err)
+		}
+	}
+
+	ep, err := s.CreateEndpoint(CreateEndpointInput{URL: "https://example.com/hook", Events: []string{EventShareCreated}})
+	if err != nil {
+		t.Fatalf("CreateEndpoint: %v", err)
+	}
+	if ep.Secret == "" || !ep.Active {
+		t.Fatalf("expected generated secret and active endpoint: %+v", ep)
+	}
+	if err := s.DeleteEndpoint(ep.ID); err != nil {
+		t.Fatalf("DeleteEndpoint: %v", err)
+	}
+	if _, err := s.GetEndpoint(ep.ID); err != ErrEndpointNotFound {
+		t.Fatalf("GetEndpoint after delete = %v", err)
+	}
+}
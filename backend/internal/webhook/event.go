@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event types delivered to webhook endpoints.
+const (
+	EventConversionCompleted = "conversion.completed"
+	EventConversionReview    = "conversion.needs_review"
+	EventShareCreated        = "share.created"
+	EventCommentAdded        = "comment.added"
+	EventFeedbackSubmitted   = "feedback.submitted"
+	EventSheetChanged        = "sheet.changed"
+
+	// EventAll subscribes an endpoint to every event type.
+	EventAll = "*"
+)
+
+// KnownEvents lists the event types an endpoint can subscribe to.
+var KnownEvents = []string{
+	EventConversionCompleted,
+	EventConversionReview,
+	EventShareCreated,
+	EventCommentAdded,
+	EventFeedbackSubmitted,
+	EventSheetChanged,
+}
+
+// IsKnownEvent reports whether eventType is a subscribable type (or the wildcard).
+func IsKnownEvent(eventType string) bool {
+	if eventType == EventAll {
+		return true
+	}
+	for _, known := range KnownEvents {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is the JSON envelope POSTed to endpoints.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// Request headers set on every delivery.
+const (
+	HeaderSignature = "X-MDFlow-Signature"
+	HeaderEvent     = "X-MDFlow-Event"
+	HeaderDelivery  = "X-MDFlow-Delivery"
+)
+
+var (
+	ErrMissingSignature = errors.New("webhook: missing or malformed signature header")
+	ErrSignatureExpired = errors.New("webhook: signature timestamp outside tolerance")
+	ErrSignatureInvalid = errors.New("webhook: signature mismatch")
+)
+
+// Sign returns the signature header value for body sent at timestamp:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">".
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + ts + ",v1=" + computeMAC(secret, ts, body)
+}
+
+// Verify checks a signature header produced by Sign. Receivers should reject
+// requests whose timestamp is older than tolerance to limit replays (0 disables the check).
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts, mac string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			mac = value
+		}
+	}
+	if ts == "" || mac == "" {
+		return ErrMissingSignature
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrMissingSignature
+	}
+	if tolerance > 0 {
+		age := now.Sub(time.Unix(unix, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrSignatureExpired
+		}
+	}
+	if !hmac.Equal([]byte(mac), []byte(computeMAC(secret, ts, body))) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
+
+func computeMAC(secret, ts string, body []byte) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(ts))
+	m.Write([]byte("."))
+	m.Write(body)
+	return hex.EncodeToString(m.Sum(nil))
+}
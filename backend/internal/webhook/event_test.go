@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+	now := time.Unix(1_700_000_000, 0)
+	header := Sign("whsec_test", now, body)
+
+	if err := Verify("whsec_test", header, body, 5*time.Minute, now.Add(time.Minute)); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+	now := time.Unix(1_700_000_000, 0)
+	header := Sign("whsec_test", now, body)
+
+	cases := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		want   error
+	}{
+		{"wrong secret", "other", header, body, now, ErrSignatureInvalid},
+		{"modified body", "whsec_test", header, []byte(`{"id":"evt_2"}`), now, ErrSignatureInvalid},
+		{"expired", "whsec_test", header, body, now.Add(10 * time.Minute), ErrSignatureExpired},
+		{"missing header", "whsec_test", "", body, now, ErrMissingSignature},
+		{"malformed timestamp", "whsec_test", "t=abc,v1=00", body, now, ErrMissingSignature},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Verify(tc.secret, tc.header, tc.body, 5*time.Minute, tc.now)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("Verify() error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsKnownEvent(t *testing.T) {
+	for _, ev := range append([]string{EventAll}, KnownEvents...) {
+		if !IsKnownEvent(ev) {
+			t.Errorf("IsKnownEvent(%q) = false", ev)
+		}
+	}
+	if IsKnownEvent("conversion.unknown") {
+		t.Error("IsKnownEvent accepted an unknown type")
+	}
+}
@@ -0,0 +1,475 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// ErrEndpointNotFound is returned when an endpoint ID does not exist.
+var ErrEndpointNotFound = errors.New("webhook: endpoint not found")
+
+// ErrDeadLetterNotFound is returned when a dead-letter ID does not exist.
+var ErrDeadLetterNotFound = errors.New("webhook: dead letter not found")
+
+// ErrInvalidEndpoint is returned when an endpoint URL or event list is invalid.
+var ErrInvalidEndpoint = errors.New("webhook: endpoint needs an http(s) url and at least one known event")
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed" // attempt failed, retry scheduled
+	StatusDead      = "dead"   // retries exhausted or permanent failure; copied to dead letters
+)
+
+// Endpoint is a registered webhook receiver.
+type Endpoint struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"-"` // HMAC key; only returned once at creation
+	Events      []string  `json:"events"`
+	Description string    `json:"description,omitempty"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Subscribed reports whether the endpoint receives eventType.
+func (e Endpoint) Subscribed(eventType string) bool {
+	for _, ev := range e.Events {
+		if ev == eventType || ev == EventAll {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is the log entry for one event sent to one endpoint.
+type Delivery struct {
+	ID           string    `json:"id"`
+	EndpointID   string    `json:"endpoint_id"`
+	EventID      string    `json:"event_id"`
+	EventType    string    `json:"event_type"`
+	Payload      string    `json:"payload"`
+	Status       string    `json:"status"`
+	Attempts     int       `json:"attempts"`
+	ResponseCode int       `json:"response_code,omitempty"`
+	LastError    string    `json:"last_error,omitempty"`
+	DurationMS   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DeadLetter is a delivery that exhausted its retries.
+type DeadLetter struct {
+	ID         int64      `json:"id"`
+	DeliveryID string     `json:"delivery_id"`
+	EndpointID string     `json:"endpoint_id"`
+	EventType  string     `json:"event_type"`
+	Payload    string     `json:"payload"`
+	LastError  string     `json:"last_error"`
+	Attempts   int        `json:"attempts"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReplayedAt *time.Time `json:"replayed_at,omitempty"`
+}
+
+// CreateEndpointInput describes a new endpoint. An empty Secret is generated.
+type CreateEndpointInput struct {
+	URL         string
+	Secret      string
+	Events      []string
+	Description string
+}
+
+// Store persists endpoints, delivery logs and dead letters in SQLite.
+type Store struct {
+	db *sql.DB
+	mu sync.Mutex // serialises writes
+}
+
+// NewStore opens (or creates) a SQLite webhook database at dbPath.
+// If dbPath is empty, ":memory:" is used (useful for tests).
+func NewStore(dbPath string) (*Store, error) {
+	if dbPath == "" {
+		dbPath = ":memory:"
+	}
+
+	if dbPath != ":memory:" {
+		dir := filepath.Dir(dbPath)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("webhook: create dir %q: %w", dir, err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: open db: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := initWebhookSchema(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func initWebhookSchema(db *sql.DB) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS webhook_endpoints (
+			id          TEXT PRIMARY KEY,
+			url         TEXT NOT NULL,
+			secret      TEXT NOT NULL,
+			events      TEXT NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			active      INTEGER NOT NULL DEFAULT 1,
+			created_at  TIMESTAMP NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id            TEXT PRIMARY KEY,
+			endpoint_id   TEXT NOT NULL,
+			event_id      TEXT NOT NULL,
+			event_type    TEXT NOT NULL,
+			payload       TEXT NOT NULL,
+			status        TEXT NOT NULL,
+			attempts      INTEGER NOT NULL DEFAULT 0,
+			response_code INTEGER NOT NULL DEFAULT 0,
+			last_error    TEXT NOT NULL DEFAULT '',
+			duration_ms   INTEGER NOT NULL DEFAULT 0,
+			created_at    TIMESTAMP NOT NULL,
+			updated_at    TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint ON webhook_deliveries(endpoint_id, created_at)`,
+		`CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			delivery_id TEXT NOT NULL,
+			endpoint_id TEXT NOT NULL,
+			event_type  TEXT NOT NULL,
+			payload     TEXT NOT NULL,
+			last_error  TEXT NOT NULL DEFAULT '',
+			attempts    INTEGER NOT NULL DEFAULT 0,
+			created_at  TIMESTAMP NOT NULL,
+			replayed_at TIMESTAMP
+		)`,
+	}
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("webhook: init schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateEndpoint validates and persists a new endpoint.
+func (s *Store) CreateEndpoint(input CreateEndpointInput) (*Endpoint, error) {
+	url := strings.TrimSpace(input.URL)
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, ErrInvalidEndpoint
+	}
+	events := normalizeEvents(input.Events)
+	if len(events) == 0 {
+		return nil, ErrInvalidEndpoint
+	}
+	secret := strings.TrimSpace(input.Secret)
+	if secret == "" {
+		secret = "whsec_" + randomHex(24)
+	}
+
+	ep := &Endpoint{
+		ID:          "wh_" + randomHex(8),
+		URL:         url,
+		Secret:      secret,
+		Events:      events,
+		Description: strings.TrimSpace(input.Description),
+		Active:      true,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.db.Exec(
+		`INSERT INTO webhook_endpoints (id, url, secret, events, description, active, created_at) VALUES (?, ?, ?, ?, ?, 1, ?)`,
+		ep.ID, ep.URL, ep.Secret, strings.Join(ep.Events, ","), ep.Description, ep.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: create endpoint: %w", err)
+	}
+	return ep, nil
+}
+
+// GetEndpoint returns an endpoint including its secret.
+func (s *Store) GetEndpoint(id string) (*Endpoint, error) {
+	row := s.db.QueryRow(`SELECT id, url, secret, events, description, active, created_at FROM webhook_endpoints WHERE id = ?`, id)
+	ep, err := scanEndpoint(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEndpointNotFound
+	}
+	return ep, err
+}
+
+// ListEndpoints returns all endpoints, oldest first.
+func (s *Store) ListEndpoints() ([]Endpoint, error) {
+	rows, err := s.db.Query(`SELECT id, url, secret, events, description, active, created_at FROM webhook_endpoints ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: list endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	endpoints := make([]Endpoint, 0)
+	for rows.Next() {
+		ep, err := scanEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, *ep)
+	}
+	return endpoints, rows.Err()
+}
+
+// SetEndpointActive pauses or resumes deliveries to an endpoint.
+func (s *Store) SetEndpointActive(id string, active bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res, err := s.db.Exec(`UPDATE webhook_endpoints SET active = ? WHERE id = ?`, boolToInt(active), id)
+	if err != nil {
+		return fmt.Errorf("webhook: update endpoint: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrEndpointNotFound
+	}
+	return nil
+}
+
+// DeleteEndpoint removes an endpoint. Its delivery logs and dead letters are kept.
+func (s *Store) DeleteEndpoint(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res, err := s.db.Exec(`DELETE FROM webhook_endpoints WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("webhook: delete endpoint: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrEndpointNotFound
+	}
+	return nil
+}
+
+func (s *Store) insertDelivery(d *Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.db.Exec(
+		`INSERT INTO webhook_deliveries (id, endpoint_id, event_id, event_type, payload, status, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		d.ID, d.EndpointID, d.EventID, d.EventType, d.Payload, d.Status, d.CreatedAt, d.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("webhook: insert delivery: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) updateDelivery(d *Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.db.Exec(
+		`UPDATE webhook_deliveries
+		 SET status = ?, attempts = ?, response_code = ?, last_error = ?, duration_ms = ?, updated_at = ?
+		 WHERE id = ?`,
+		d.Status, d.Attempts, d.ResponseCode, d.LastError, d.DurationMS, d.UpdatedAt, d.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("webhook: update delivery: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) insertDeadLetter(d *Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.db.Exec(
+		`INSERT INTO webhook_dead_letters (delivery_id, endpoint_id, event_type, payload, last_error, attempts, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		d.ID, d.EndpointID, d.EventType, d.Payload, d.LastError, d.Attempts, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("webhook: insert dead letter: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns the most recent delivery logs for an endpoint, newest first.
+func (s *Store) ListDeliveries(endpointID string, limit int) ([]Delivery, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	rows, err := s.db.Query(
+		`SELECT `+deliveryColumns+`
+		 FROM webhook_deliveries WHERE endpoint_id = ? ORDER BY created_at DESC, rowid DESC LIMIT ?`,
+		endpointID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: list deliveries: %w", err)
+	}
+	defer rows.Close()
+	return scanDeliveries(rows)
+}
+
+// unfinishedDeliveries returns the pending and failed deliveries, oldest first.
+func (s *Store) unfinishedDeliveries() ([]Delivery, error) {
+	rows, err := s.db.Query(
+		`SELECT `+deliveryColumns+`
+		 FROM webhook_deliveries WHERE status IN (?, ?) ORDER BY created_at, rowid`,
+		StatusPending, StatusFailed,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: list unfinished deliveries: %w", err)
+	}
+	defer rows.Close()
+	return scanDeliveries(rows)
+}
+
+// ListDeadLetters returns dead letters, newest first. Replayed entries are included.
+func (s *Store) ListDeadLetters(limit int) ([]DeadLetter, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	rows, err := s.db.Query(
+		`SELECT id, delivery_id, endpoint_id, event_type, payload, last_error, attempts, created_at, replayed_at
+		 FROM webhook_dead_letters ORDER BY id DESC LIMIT ?`, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: list dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	letters := make([]DeadLetter, 0)
+	for rows.Next() {
+		dl, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, *dl)
+	}
+	return letters, rows.Err()
+}
+
+// GetDeadLetter returns a single dead letter.
+func (s *Store) GetDeadLetter(id int64) (*DeadLetter, error) {
+	row := s.db.QueryRow(
+		`SELECT id, delivery_id, endpoint_id, event_type, payload, last_error, attempts, created_at, replayed_at
+		 FROM webhook_dead_letters WHERE id = ?`, id,
+	)
+	dl, err := scanDeadLetter(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDeadLetterNotFound
+	}
+	return dl, err
+}
+
+func (s *Store) markReplayed(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.db.Exec(`UPDATE webhook_dead_letters SET replayed_at = ? WHERE id = ?`, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("webhook: mark replayed: %w", err)
+	}
+	return nil
+}
+
+// Close releases the underlying database handle.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEndpoint(row rowScanner) (*Endpoint, error) {
+	var ep Endpoint
+	var events string
+	var active int
+	if err := row.Scan(&ep.ID, &ep.URL, &ep.Secret, &events, &ep.Description, &active, &ep.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("webhook: scan endpoint: %w", err)
+	}
+	ep.Events = strings.Split(events, ",")
+	ep.Active = active == 1
+	return &ep, nil
+}
+
+const deliveryColumns = `id, endpoint_id, event_id, event_type, payload, status, attempts, response_code, last_error, duration_ms, created_at, updated_at`
+
+func scanDeliveries(rows *sql.Rows) ([]Delivery, error) {
+	deliveries := make([]Delivery, 0)
+	for rows.Next() {
+		var d Delivery
+		if err := rows.Scan(&d.ID, &d.EndpointID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+			&d.ResponseCode, &d.LastError, &d.DurationMS, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("webhook: scan delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func scanDeadLetter(row rowScanner) (*DeadLetter, error) {
+	var dl DeadLetter
+	var replayed sql.NullTime
+	if err := row.Scan(&dl.ID, &dl.DeliveryID, &dl.EndpointID, &dl.EventType, &dl.Payload, &dl.LastError,
+		&dl.Attempts, &dl.CreatedAt, &replayed); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("webhook: scan dead letter: %w", err)
+	}
+	if replayed.Valid {
+		t := replayed.Time
+		dl.ReplayedAt = &t
+	}
+	return &dl, nil
+}
+
+// normalizeEvents trims and dedupes event types. Returns nil if any type is unknown.
+func normalizeEvents(events []string) []string {
+	seen := make(map[string]bool, len(events))
+	result := make([]string, 0, len(events))
+	for _, ev := range events {
+		ev = strings.TrimSpace(ev)
+		if !IsKnownEvent(ev) {
+			return nil
+		}
+		if seen[ev] {
+			continue
+		}
+		seen[ev] = true
+		result = append(result, ev)
+	}
+	return result
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
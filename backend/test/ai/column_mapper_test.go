@@ -183,3 +183,7 @@ func (m *mockAIService) ValidateSemantic(ctx context.Context, req SemanticValida
 		Confidence: 1.0,
 	}, nil
 }
+
+func (m *mockAIService) ExtractRequirements(ctx context.Context, req ExtractRequirementsRequest) (*RequirementsExtraction, error) {
+	return &RequirementsExtraction{}, nil
+}
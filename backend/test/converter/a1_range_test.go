@@ -0,0 +1,65 @@
+package converter_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+func TestParseA1Range(t *testing.T) {
+	cases := []struct {
+		in   string
+		want A1Range
+	}{
+		{"B3:D10", A1Range{StartCol: 2, StartRow: 3, EndCol: 4, EndRow: 10}},
+		{"Sheet1!$A$2:$C", A1Range{StartCol: 1, StartRow: 2, EndCol: 3}},
+		{"AA5", A1Range{StartCol: 27, StartRow: 5}},
+		{"c", A1Range{StartCol: 3, StartRow: 1}},
+	}
+	for _, tc := range cases {
+		got, err := ParseA1Range(tc.in)
+		if err != nil || got != tc.want {
+			t.Errorf("ParseA1Range(%q) = %+v, %v; want %+v", tc.in, got, err, tc.want)
+		}
+	}
+	for _, bad := range []string{"", "3B", "D1:B2", "A5:C2", "ABCD1"} {
+		if _, err := ParseA1Range(bad); err == nil {
+			t.Errorf("ParseA1Range(%q) accepted", bad)
+		}
+	}
+}
+
+func TestA1RangeSlice_UsesSourceRows(t *testing.T) {
+	// Sheet rows 1, 3, 4 and 6 are the non-blank ones parsing kept.
+	matrix := CellMatrix{
+		{"Title row", "", ""},
+		{"ID", "Title", "Notes"},
+		{"REQ-1", "Login", "x"},
+		{"", "", "only notes"},
+	}
+	r, err := ParseA1Range("A3:B6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, rows := r.Slice(matrix, []int{1, 3, 4, 6})
+	want := CellMatrix{{"ID", "Title"}, {"REQ-1", "Login"}}
+	if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(rows, []int{3, 4}) {
+		t.Fatalf("Slice = %v rows %v", got, rows)
+	}
+}
+
+func TestSynonymOverrides(t *testing.T) {
+	matrix := CellMatrix{
+		{"Ticket", "Summary", "Prio"},
+		{"REQ-1", "Login", "High"},
+	}
+	got := SynonymOverrides(matrix, map[string][]string{"id": {"ticket"}, "title": {"Summary"}})
+	want := map[string]string{"Ticket": "id", "Summary": "title"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("SynonymOverrides = %v, want %v", got, want)
+	}
+	if !IsCanonicalField("id") || IsCanonicalField("ticket") {
+		t.Fatal("IsCanonicalField mismatch")
+	}
+}
@@ -40,7 +40,7 @@ func TestGSheetParallelJapaneseEnglishFixture_SelectsEnglishBlock(t *testing.T)
 		headers := block.Matrix.GetRow(headerRow)
 		dataRows := block.Matrix.SliceRows(headerRow+1, block.Matrix.RowCount())
 
-		mapping, unmapped := conv.GetPreviewColumnMappingRuleBased(headers, DefaultTemplateName)
+		mapping, unmapped := conv.GetPreviewColumnMappingRuleBased(headers, HeaderBand{Start: headerRow, End: headerRow}, DefaultTemplateName)
 		quality := BuildPreviewMappingQuality(confidence, headers, dataRows, mapping, unmapped)
 		englishScore := EstimateEnglishScore(headers, dataRows)
 
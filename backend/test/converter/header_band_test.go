@@ -0,0 +1,137 @@
+package converter_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+func TestDetectHeaderBand(t *testing.T) {
+	tests := []struct {
+		name    string
+		matrix  CellMatrix
+		band    HeaderBand
+		headers string
+	}{
+		{
+			name: "group row over blanks",
+			matrix: CellMatrix{
+				{"No", "Feature", "Input", "", "Expected"},
+				{"", "", "Type", "Length", ""},
+				{"1", "Login", "text", "20", "Shown"},
+			},
+			band:    HeaderBand{Start: 0, End: 1},
+			headers: "No|Feature|Input / Type|Input / Length|Expected",
+		},
+		{
+			name: "filled merges",
+			matrix: CellMatrix{
+				{"No", "Feature", "Input", "Input", "Expected"},
+				{"No", "Feature", "Type", "Length", "Expected"},
+				{"1", "Login", "text", "20", "Shown"},
+			},
+			band:    HeaderBand{Start: 0, End: 1},
+			headers: "No|Feature|Input / Type|Input / Length|Expected",
+		},
+		{
+			name: "three rows",
+			matrix: CellMatrix{
+				{"ID", "Request", "", "", ""},
+				{"", "Input", "", "Output", ""},
+				{"", "Field", "Type", "Status", "Body"},
+				{"1", "user", "string", "200", "ok"},
+			},
+			band:    HeaderBand{Start: 0, End: 2},
+			headers: "ID|Request / Input / Field|Request / Input / Type|Request / Output / Status|Request / Output / Body",
+		},
+		{
+			name: "header and short data",
+			matrix: CellMatrix{
+				{"Feature", "Scenario", "Expected"},
+				{"Login", "Valid", "Shown"},
+				{"Logout", "Click", "Hidden"},
+			},
+			band:    HeaderBand{Start: 0, End: 0},
+			headers: "Feature|Scenario|Expected",
+		},
+		{
+			name: "title above header",
+			matrix: CellMatrix{
+				{"Login screen", "", ""},
+				{"Feature", "Scenario", "Expected"},
+				{"Login", "Valid credentials open the dashboard. Always.", "1"},
+			},
+			band:    HeaderBand{Start: 1, End: 1},
+			headers: "Feature|Scenario|Expected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			band, _ := NewHeaderDetector().DetectHeaderBand(tt.matrix, nil)
+			if band != tt.band {
+				t.Fatalf("band = %+v, want %+v", band, tt.band)
+			}
+			if got := strings.Join(band.Headers(tt.matrix), "|"); got != tt.headers {
+				t.Errorf("headers = %q, want %q", got, tt.headers)
+			}
+		})
+	}
+}
+
+func TestColumnMapper_MapsHeaderLevels(t *testing.T) {
+	colMap, unmapped := NewColumnMapper().MapBandColumns([]string{"Feature", "Input / Type", "Input / Length", "Expected / Result", "Expected / Screen"}, HeaderBand{Start: 0, End: 1})
+
+	for field, want := range map[CanonicalField]int{FieldFeature: 0, FieldType: 1, FieldInputs: 2, FieldExpected: 3} {
+		if got, ok := colMap[field]; !ok || got != want {
+			t.Errorf("%s mapped to %d (%v), want %d", field, got, ok, want)
+		}
+	}
+	if len(unmapped) != 1 || unmapped[0] != "Expected / Screen" {
+		t.Errorf("unmapped = %q, want only the second Expected column", unmapped)
+	}
+}
+
+func TestColumnMapper_SingleRowHeaderIsNotSplit(t *testing.T) {
+	headers := []string{"ID", "Feature", "Expected / Actual"}
+	colMap, unmapped := NewColumnMapper().MapBandColumns(headers, HeaderBand{Start: 0, End: 0})
+	if _, ok := colMap[FieldExpected]; ok || len(unmapped) != 1 || unmapped[0] != "Expected / Actual" {
+		t.Errorf("column map = %v, unmapped = %q; a one-row header must not be split into levels", colMap, unmapped)
+	}
+	if colMap, _ = NewColumnMapper().MapColumns(headers); len(colMap) != 2 {
+		t.Errorf("MapColumns column map = %v, want only ID and Feature", colMap)
+	}
+}
+
+func TestConvertXLSX_MultiRowHeader(t *testing.T) {
+	path := writeMergedSpec(t)
+	resp, err := NewConverter().ConvertXLSX(path, "Sheet1", "spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := resp.Meta.HeaderBand; b == nil || *b != (HeaderBand{Start: 1, End: 2}) {
+		t.Fatalf("header band = %+v, want rows 1-2", b)
+	}
+	if resp.Meta.ColumnMap[FieldType] != 2 || resp.Meta.ColumnMap[FieldInputs] != 3 {
+		t.Errorf("column map = %v, want Input / Type as type and Input / Length as inputs", resp.Meta.ColumnMap)
+	}
+
+	matrix, merges, err := NewXLSXParser().ParseSheetWithMerges(path, "Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := AnalyzeQualityWithMerges(matrix, merges)
+	if stats.HeaderRow != 2 || stats.SourceRows != 3 {
+		t.Errorf("stats = %+v, want header row 2 and 3 source rows", stats)
+	}
+	report := BuildQualityReport(stats, resp.Meta, QualityThresholds{})
+	if report.HeaderBand != (HeaderBand{Start: 1, End: 2}) {
+		t.Errorf("quality report band = %+v", report.HeaderBand)
+	}
+
+	overridden := ApplyColumnOverrides(matrix, map[string]string{"Input / Length": "notes"})
+	if overridden[2][3] != "notes" {
+		t.Errorf("override of the composite header left %q", overridden[2][3])
+	}
+}
@@ -0,0 +1,154 @@
+package converter_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+// The fixtures are written by testdata/gen_legacy_sheets.go.
+func legacyFixture(t *testing.T, name string) string {
+	t.Helper()
+	_, fp, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(fp), "testdata", name)
+}
+
+func TestLegacySpreadsheets_ParseFile(t *testing.T) {
+	for _, tc := range []struct {
+		file     string
+		parser   SpreadsheetParser
+		expected string
+	}{
+		{"requirements.xls", NewXLSParser(), "Orders listed, newest first"},
+		{"requirements.ods", NewODSParser(), "Orders listed,\nnewest first"},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			path := legacyFixture(t, tc.file)
+			result, err := tc.parser.ParseFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(result.Sheets, []string{"Requirements", "Notes"}) || result.ActiveSheet != "Requirements" {
+				t.Fatalf("sheets = %v (active %q), want [Requirements Notes]", result.Sheets, result.ActiveSheet)
+			}
+
+			matrix := result.GetMatrix("Requirements")
+			want := [][]string{
+				{"Login requirements"},
+				{"ID", "Feature", "Scenario", "Expected", "Priority", "Due", "Done", "Estimate", "Automated"},
+				{"REQ-1", "Login", "Valid credentials open the dashboard", "Dashboard shown", "P1", "2024-03-15", "50%", "3", "TRUE"},
+				{"REQ-2", "ログイン", "Wrong password is rejected", `Error "Invalid password" shown`, "P2", "2024-04-15", "50%", "5", "FALSE"},
+				{"REQ-3", "Café menu", "Order history lists past orders", tc.expected, "", "", "", "1.25"},
+			}
+			if len(matrix) != len(want) {
+				t.Fatalf("got %d rows, want %d: %q", len(matrix), len(want), matrix)
+			}
+			for i, row := range want {
+				if got := strings.Join(matrix[i], "|"); strings.TrimRight(got, "|") != strings.Join(row, "|") {
+					t.Errorf("row %d = %q, want %q", i+1, matrix[i], row)
+				}
+			}
+			if notes := result.GetMatrix("Notes"); len(notes) != 1 || !slices.Equal(notes[0], []string{"Owner", "QA team"}) {
+				t.Errorf("Notes = %q, want [[Owner QA team]]", notes)
+			}
+
+			if _, err := tc.parser.ParseSheet(path, "Missing"); err == nil {
+				t.Error("ParseSheet of a missing sheet succeeded")
+			}
+		})
+	}
+}
+
+func TestLegacySpreadsheets_Convert(t *testing.T) {
+	for _, file := range []string{"requirements.xls", "requirements.ods"} {
+		t.Run(file, func(t *testing.T) {
+			path := legacyFixture(t, file)
+			conv := NewConverter()
+
+			sheets, err := conv.GetXLSXSheets(path)
+			if err != nil || !slices.Equal(sheets, []string{"Requirements", "Notes"}) {
+				t.Fatalf("GetXLSXSheets = %v, %v", sheets, err)
+			}
+
+			rows, err := XLSXSourceRows(path, "Requirements")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(rows, []int{1, 3, 4, 5, 7}) {
+				t.Fatalf("source rows = %v, want [1 3 4 5 7]", rows)
+			}
+
+			resp, err := conv.ConvertXLSX(path, "Requirements", "spec")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{"REQ-1", "ログイン", "Café menu"} {
+				if !strings.Contains(resp.MDFlow, want) {
+					t.Errorf("output is missing %q:\n%s", want, resp.MDFlow)
+				}
+			}
+		})
+	}
+}
+
+func TestSpreadsheetParserFor(t *testing.T) {
+	if _, ok := SpreadsheetParserFor("book.XLS").(*XLSParser); !ok {
+		t.Error("book.XLS is not read by XLSParser")
+	}
+	if _, ok := SpreadsheetParserFor("book.ods").(*ODSParser); !ok {
+		t.Error("book.ods is not read by ODSParser")
+	}
+	if _, ok := SpreadsheetParserFor("book.xlsx").(*XLSXParser); !ok {
+		t.Error("book.xlsx is not read by XLSXParser")
+	}
+	if IsSpreadsheetFile("notes.csv") || !IsSpreadsheetFile("Book.ODS") {
+		t.Error("IsSpreadsheetFile misclassifies extensions")
+	}
+}
+
+func TestODSParser_BoundsHostileCounts(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"><office:body><office:spreadsheet>
+<table:table table:name="Bomb">
+<table:table-row><table:table-cell><text:p>a<text:s text:c="9223372036854775807"/>b</text:p></table:table-cell></table:table-row>
+<table:table-row table:number-rows-repeated="65536"><table:table-cell table:number-columns-repeated="65536"><text:p>x</text:p></table:table-cell></table:table-row>
+</table:table>
+</office:spreadsheet></office:body></office:document-content>`
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("content.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewODSParser().ParseReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrix := result.GetMatrix("Bomb")
+	if len(matrix) == 0 || len(matrix[0]) == 0 {
+		t.Fatalf("expected the first cell to survive, got %d rows", len(matrix))
+	}
+	if first := matrix[0][0]; len(first) > 2+1024 || !strings.HasPrefix(first, "a ") || !strings.HasSuffix(first, " b") {
+		t.Errorf("space run not clamped: %d bytes", len(first))
+	}
+	cells := 0
+	for _, row := range matrix {
+		cells += len(row)
+	}
+	if cells > 1<<21 {
+		t.Errorf("repeats expanded to %d cells", cells)
+	}
+}
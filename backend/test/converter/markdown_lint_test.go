@@ -0,0 +1,80 @@
+package converter_test
+
+import (
+	"testing"
+
+	. "github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+func lintCodes(warnings []Warning) map[string]int {
+	codes := map[string]int{}
+	for _, w := range warnings {
+		codes[w.Code] = w.Details["line"].(int)
+	}
+	return codes
+}
+
+func TestLintMarkdown_RenderedSpecIsClean(t *testing.T) {
+	result, err := NewConverter().ConvertPaste("ID\tTitle\tPriority\nREQ-1\tLogin\tHigh\nREQ-2\tLogout\tLow\n", "spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range LintMarkdown(result.MDFlow) {
+		if w.Severity != SeverityInfo {
+			t.Errorf("unexpected finding on rendered spec: %+v", w)
+		}
+	}
+}
+
+func TestLintMarkdown_StructuralProblems(t *testing.T) {
+	md := "---\nname: Spec\n---\n" + // lines 1-3
+		"## Summary\n" + // 4
+		"#### Deep\n" + // 5: level jump
+		"## Summary\n" + // 6: duplicate
+		"##\n" + // 7: empty
+		"| a | b |\n" + // 8
+		"| 1 | 2 |\n" + // 9: missing separator
+		"\n" +
+		"| a | b |\n" + // 11
+		"|---|---|\n" + // 12
+		"| 1 | 2 \\| 3 |\n" + // 13: escaped pipe is fine
+		"| 1 | 2 | 3 |\n" + // 14: too many cells
+		"```\n" + // 15: never closed
+		"# Not a heading\n"
+
+	codes := lintCodes(LintMarkdown(md))
+	want := map[string]int{
+		LintHeadingLevelSkipped: 5,
+		LintDuplicateHeading:    6,
+		LintEmptyHeading:        7,
+		LintTableSeparator:      9,
+		LintTableColumns:        14,
+		LintUnclosedFence:       15,
+		LintNoTitle:             0,
+	}
+	for code, line := range want {
+		got, ok := codes[code]
+		if !ok {
+			t.Errorf("missing %s", code)
+		} else if got != line {
+			t.Errorf("%s on line %d, want %d", code, got, line)
+		}
+	}
+	if len(codes) != len(want) {
+		t.Errorf("codes = %v", codes)
+	}
+}
+
+func TestLintMarkdown_FrontMatter(t *testing.T) {
+	cases := map[string]string{
+		"":                          LintEmptyDocument,
+		"# Title\n":                 LintFrontMatterMissing,
+		"---\nname: x\n# Title\n":   LintFrontMatterOpen,
+		"---\nname: [x\n---\n# T\n": LintFrontMatterInvalid,
+	}
+	for md, code := range cases {
+		if _, ok := lintCodes(LintMarkdown(md))[code]; !ok {
+			t.Errorf("LintMarkdown(%q) missing %s", md, code)
+		}
+	}
+}
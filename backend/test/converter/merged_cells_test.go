@@ -0,0 +1,178 @@
+package converter_test
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	. "github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+// writeMergedSpec saves a sheet laid out like our Japanese spec sheets: a
+// title merged across the table, a two-row header whose "Input" group spans
+// two columns, and a feature merged down its three rows, the last of which is
+// a wrapped note under the No column.
+func writeMergedSpec(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "merged.xlsx")
+	book := excelize.NewFile()
+	rows := map[string][]any{
+		"A1": {"Login screen"},
+		"A2": {"No", "Feature", "Input", nil, "Expected"},
+		"C3": {"Type", "Length"},
+		"A4": {"1", "Login", "text", "20", "Shown"},
+		"A5": {"2", nil, "password", "8", "Masked"},
+		"A6": {"※1"},
+	}
+	for cell, values := range rows {
+		if err := book.SetSheetRow("Sheet1", cell, &values); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, r := range [][2]string{{"A1", "E1"}, {"A2", "A3"}, {"B2", "B3"}, {"C2", "D2"}, {"E2", "E3"}, {"B4", "B6"}} {
+		if err := book.MergeCell("Sheet1", r[0], r[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := book.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestXLSXParser_FillsMergedCells(t *testing.T) {
+	path := writeMergedSpec(t)
+
+	matrix, merges, err := NewXLSXParser().ParseSheetWithMerges(path, "Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(matrix[1], "|"); got != "No|Feature|Input|Input|Expected" {
+		t.Errorf("upper header = %q", got)
+	}
+	if got := strings.Join(matrix[2], "|"); got != "No|Feature|Type|Length|Expected" {
+		t.Errorf("lower header = %q", got)
+	}
+	if matrix[4][1] != "Login" || matrix[5][1] != "Login" {
+		t.Errorf("feature column = %q, %q; want Login filled down", matrix[4][1], matrix[5][1])
+	}
+	if len(merges) != 6 {
+		t.Fatalf("got %d merges, want 6: %+v", len(merges), merges)
+	}
+	feature := merges[slices.IndexFunc(merges, func(m MergedRegion) bool { return m.Range == "B4:B6" })]
+	if feature.Value != "Login" || feature.StartRow != 3 || feature.EndRow != 5 || feature.StartCol != 1 || feature.EndCol != 1 {
+		t.Errorf("B4:B6 located at %+v", feature)
+	}
+
+	rows, err := XLSXSourceRows(path, "Sheet1")
+	if err != nil || !slices.Equal(rows, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("source rows = %v, %v", rows, err)
+	}
+}
+
+func TestXLSXParser_MergeFillPerAxis(t *testing.T) {
+	path := writeMergedSpec(t)
+
+	down, err := NewXLSXParser().WithMergeFill(MergeFill{Down: true}).ParseSheet(path, "Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if down[1][3] != "" || down[5][1] != "Login" {
+		t.Errorf("down only: header group %q, feature %q", down[1][3], down[5][1])
+	}
+
+	none, err := NewXLSXParser().WithMergeFill(MergeFill{}).ParseSheet(path, "Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if none[0][1] != "" || none[2][0] != "" || none[5][1] != "" {
+		t.Errorf("none: title %q, header %q, feature %q should stay blank", none[0][1], none[2][0], none[5][1])
+	}
+
+	for _, s := range []string{"", "both", "Down", "across", "none"} {
+		if _, err := ParseMergeFill(s); err != nil {
+			t.Errorf("ParseMergeFill(%q): %v", s, err)
+		}
+	}
+	if _, err := ParseMergeFill("diagonal"); err == nil {
+		t.Error("ParseMergeFill accepted diagonal")
+	}
+}
+
+func TestMergedCells_HeaderAndContinuation(t *testing.T) {
+	path := writeMergedSpec(t)
+	matrix, merges, err := NewXLSXParser().ParseSheetWithMerges(path, "Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if row, _ := NewHeaderDetector().DetectHeaderRowWithMerges(matrix, merges); row != 2 {
+		t.Errorf("header row = %d, want 2 (the lower header row)", row)
+	}
+
+	doc := BuildSpecDocFromMatrixWithMerges(matrix, merges)
+	if len(doc.Rows) != 2 {
+		t.Fatalf("got %d rows, want 2: %+v", len(doc.Rows), doc.Rows)
+	}
+	second := doc.Rows[1]
+	if second.Feature != "Login" {
+		t.Errorf("row 2 feature = %q, want the merged Login", second.Feature)
+	}
+	if !strings.HasSuffix(second.Expected, "\n※1") {
+		t.Errorf("row 2 expected = %q, want the wrapped note appended", second.Expected)
+	}
+	if len(doc.Meta.MergedCells) != 6 {
+		t.Errorf("meta records %d merges, want 6", len(doc.Meta.MergedCells))
+	}
+}
+
+func TestDetectHeaderRowWithMerges(t *testing.T) {
+	matrix := CellMatrix{
+		{"Input", "Input", "Expected"},
+		{"Type", "Length", "Expected"},
+		{"text", "20", "Shown"},
+	}
+	merges := []MergedRegion{
+		{Range: "A1:B1", StartRow: 0, StartCol: 0, EndRow: 0, EndCol: 1},
+		{Range: "C1:C2", StartRow: 0, StartCol: 2, EndRow: 1, EndCol: 2},
+	}
+	if row, _ := NewHeaderDetector().DetectHeaderRow(matrix); row != 0 {
+		t.Fatalf("without merges the group row should win, got row %d", row)
+	}
+	if row, _ := NewHeaderDetector().DetectHeaderRowWithMerges(matrix, merges); row != 1 {
+		t.Errorf("header row = %d, want 1 (the row the group names)", row)
+	}
+
+	banner := CellMatrix{{"Login screen", "Login screen", "Login screen"}, {"Feature", "Scenario", "Expected"}, {"Login", "Valid", "Shown"}}
+	title := []MergedRegion{{Range: "A1:C1", EndCol: 2}}
+	if row, _ := NewHeaderDetector().DetectHeaderRowWithMerges(banner, title); row != 1 {
+		t.Errorf("banner: header row = %d, want 1", row)
+	}
+}
+
+func TestConvertXLSX_ReportsMergedCells(t *testing.T) {
+	resp, err := NewConverter().ConvertXLSX(writeMergedSpec(t), "Sheet1", "spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Meta.HeaderRow != 2 || len(resp.Meta.MergedCells) != 6 {
+		t.Errorf("header row %d, %d merges; want 2 and 6", resp.Meta.HeaderRow, len(resp.Meta.MergedCells))
+	}
+	if strings.Count(resp.MDFlow, "Login") < 2 {
+		t.Errorf("both rows should carry the merged feature:\n%s", resp.MDFlow)
+	}
+}
+
+func TestLocateMerges_FollowsRange(t *testing.T) {
+	merges := []MergedRegion{{Range: "A1:E1"}, {Range: "B4:B6", Value: "Login"}, {Range: "A2:A3"}}
+	located := LocateMerges(merges, []int{4, 5, 6}, 2)
+	if len(located) != 1 {
+		t.Fatalf("located %+v, want only B4:B6", located)
+	}
+	if m := located[0]; m.StartRow != 0 || m.EndRow != 2 || m.StartCol != 0 || m.EndCol != 0 {
+		t.Errorf("B4:B6 in B4:E6 located at %+v", m)
+	}
+}
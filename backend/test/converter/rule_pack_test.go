@@ -0,0 +1,111 @@
+package converter_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	. "github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+func TestConvertPaste_RunsTemplateRulePack(t *testing.T) {
+	conv := NewConverter()
+	input := "ID\tFeature\tScenario\tExpected\n" +
+		"TC-1\tLogin\tValid user\tDashboard\n" +
+		"tc-1\tLogin\t\tError shown\n" +
+		"TC-2\tLogout\tSession ends\tLogin page\n"
+
+	result, err := conv.ConvertPasteWithFormatContext(context.Background(), input, "spec", "spec")
+	if err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+
+	report := result.Meta.RulePack
+	if report == nil || report.Template != "spec" {
+		t.Fatalf("rule pack report = %+v", report)
+	}
+	if report.Warnings != 2 || report.Errors != 0 || !report.Passed() {
+		t.Errorf("expected warnings for the duplicate ID and empty scenario, got %+v", report)
+	}
+
+	var unique, required int
+	for _, w := range result.Warnings {
+		if w.Details["rule_pack"] != "spec" {
+			continue
+		}
+		switch w.Code {
+		case "VALIDATION_UNIQUE":
+			unique++
+			if w.Severity != SeverityWarn || w.Details["row"] != 2 {
+				t.Errorf("unique warning = %+v", w)
+			}
+		case "VALIDATION_REQUIRED":
+			required++
+			if w.Details["field"] != "scenario" {
+				t.Errorf("required warning = %+v", w)
+			}
+		}
+	}
+	if unique != 1 || required != 1 {
+		t.Errorf("unique=%d required=%d, want 1 each; warnings=%+v", unique, required, result.Warnings)
+	}
+}
+
+func TestTemplateRulePack_SkipsUnmappedRequiredFields(t *testing.T) {
+	tmpl := NewTemplateRegistry().LoadTemplateOrDefault("spec")
+
+	pack := tmpl.RulePack(ColumnMap{FieldID: 0})
+	for _, rule := range pack.Rules {
+		if rule.Required {
+			t.Fatalf("required rule %q added for unmapped column", rule.Name)
+		}
+	}
+
+	pack = tmpl.RulePack(ColumnMap{FieldID: 0, FieldScenario: 1})
+	found := false
+	for _, rule := range pack.Rules {
+		found = found || (rule.Required && rule.Field == "scenario")
+	}
+	if !found {
+		t.Fatal("expected required scenario rule when the column is mapped")
+	}
+}
+
+func TestTemplateConfig_ValidateChecksRulePack(t *testing.T) {
+	tmpl := &TemplateConfig{
+		Name:            "custom",
+		HeaderSynonyms:  map[string][]string{"id": {"ID"}},
+		ValidationRules: &ValidationRules{Rules: []FieldRule{{Field: "id", Pattern: "(["}}},
+	}
+	if errs := tmpl.Validate(); len(errs) != 1 || errs[0].Field != "validation_rules" {
+		t.Fatalf("errors = %+v", errs)
+	}
+}
+
+func TestCardTemplates_CarryValidRulePacks(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("..", "..", "templates", "*.yaml"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("templates = %v, %v", paths, err)
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var tmpl TemplateConfig
+		if err := yaml.Unmarshal(data, &tmpl); err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		if tmpl.ValidationRules == nil || len(tmpl.ValidationRules.Rules) == 0 {
+			t.Errorf("%s has no validation_rules pack", path)
+			continue
+		}
+		if err := tmpl.ValidationRules.Check(); err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+}
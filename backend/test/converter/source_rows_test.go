@@ -0,0 +1,38 @@
+package converter_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	. "github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+func TestXLSXSourceRows_SkipsBlankRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.xlsx")
+	book := excelize.NewFile()
+	_ = book.SetSheetRow("Sheet1", "A2", &[]any{"ID", "Feature", "Priority"})
+	_ = book.SetSheetRow("Sheet1", "A3", &[]any{"TC-1", "Login", "P1"})
+	_ = book.SetSheetRow("Sheet1", "A6", &[]any{"TC-2", "Logout", "P2"})
+	if err := book.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := XLSXSourceRows(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0] != 2 || rows[1] != 3 || rows[2] != 6 {
+		t.Fatalf("rows = %v, want [2 3 6]", rows)
+	}
+
+	matrix, err := NewConverter().ParseXLSX(path, "Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := BuildSpecDocFromMatrix(matrix)
+	if len(doc.Rows) != 2 || rows[doc.Rows[1].SourceRow-1] != 6 {
+		t.Fatalf("TC-2 source row %d does not map to sheet row 6", doc.Rows[1].SourceRow)
+	}
+}
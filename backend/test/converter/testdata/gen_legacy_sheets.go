@@ -0,0 +1,418 @@
+//go:build ignore
+
+// gen_legacy_sheets writes requirements.xls and requirements.ods, the
+// fixtures for the .xls and .ods readers. Both hold the same two sheets; the
+// .xls splits its shared strings across CONTINUE records and uses every
+// cell record the reader handles.
+//
+//	go run gen_legacy_sheets.go
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"log"
+	"math"
+	"os"
+	"unicode/utf16"
+)
+
+func main() {
+	if err := os.WriteFile("requirements.xls", compoundFile("Workbook", workbook()), 0644); err != nil {
+		log.Fatal(err)
+	}
+	ods, err := odsPackage()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("requirements.ods", ods, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// --- BIFF8 -----------------------------------------------------------------
+
+type biff struct{ bytes.Buffer }
+
+func (b *biff) record(typ uint16, data []byte) {
+	_ = binary.Write(&b.Buffer, binary.LittleEndian, [2]uint16{typ, uint16(len(data))})
+	b.Write(data)
+}
+
+func le(values ...any) []byte {
+	var buf bytes.Buffer
+	for _, v := range values {
+		_ = binary.Write(&buf, binary.LittleEndian, v)
+	}
+	return buf.Bytes()
+}
+
+func isWide(s string) bool {
+	for _, r := range s {
+		if r > 0xFF {
+			return true
+		}
+	}
+	return false
+}
+
+func chars(s string, wide bool) []byte {
+	if wide {
+		return le(utf16.Encode([]rune(s)))
+	}
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		out = append(out, byte(r))
+	}
+	return out
+}
+
+func unicodeString(s string) []byte {
+	wide := isWide(s)
+	flags := byte(0)
+	if wide {
+		flags = 1
+	}
+	n := len(utf16.Encode([]rune(s)))
+	return append(le(uint16(n), flags), chars(s, wide)...)
+}
+
+func shortString(s string) []byte {
+	b := unicodeString(s)
+	return append(b[:1], b[2:]...) // 8-bit length: drop the high length byte
+}
+
+// sst writes the shared string table in records of at most maxRecord bytes,
+// splitting characters across CONTINUE records the way Excel does.
+func sst(b *biff, strs []string, maxRecord int) {
+	records := [][]byte{le(uint32(len(strs)), uint32(len(strs)))}
+	cur := &records[0]
+	for _, s := range strs {
+		wide := isWide(s)
+		flags := byte(0)
+		width := 1
+		if wide {
+			flags, width = 1, 2
+		}
+		units := utf16.Encode([]rune(s))
+		if len(*cur)+3+width > maxRecord { // string headers are never split
+			records = append(records, nil)
+			cur = &records[len(records)-1]
+		}
+		*cur = append(*cur, le(uint16(len(units)), flags)...)
+		for _, u := range units {
+			if len(*cur)+width > maxRecord {
+				records = append(records, []byte{flags})
+				cur = &records[len(records)-1]
+			}
+			if wide {
+				*cur = append(*cur, le(u)...)
+			} else {
+				*cur = append(*cur, byte(u))
+			}
+		}
+	}
+	b.record(0x00FC, records[0])
+	for _, r := range records[1:] {
+		b.record(0x003C, r)
+	}
+}
+
+func rk(v int32) uint32 { return uint32(v)<<2 | 0x02 }
+
+const (
+	xfGeneral = 15
+	xfDate    = 16
+	xfPercent = 17
+)
+
+func workbook() []byte {
+	strs := []string{
+		"Login requirements", "ID", "Feature", "Scenario", "Expected", "Priority", "Due", "Done", "Estimate", "Automated",
+		"REQ-1", "Login", "Valid credentials open the dashboard", "Dashboard shown", "P1",
+		"REQ-2", "ログイン", "Wrong password is rejected", "Error \"Invalid password\" shown", "P2",
+		"REQ-3", "Café menu", "Order history lists past orders",
+	}
+	idx := map[string]uint32{}
+	for i, s := range strs {
+		idx[s] = uint32(i)
+	}
+
+	sheet1 := func() []byte {
+		var b biff
+		b.record(0x0809, le(uint16(0x0600), uint16(0x0010), uint16(0x0DBB), uint16(0x07CC), uint32(0), uint32(0x06)))
+		b.record(0x0200, le(uint32(0), uint32(7), uint16(0), uint16(9), uint16(0)))
+		labelSST := func(row, col uint16, s string) {
+			b.record(0x00FD, le(row, col, uint16(xfGeneral), idx[s]))
+		}
+		number := func(row, col, xf uint16, v float64) {
+			b.record(0x0203, le(row, col, xf, math.Float64bits(v)))
+		}
+		boolCell := func(row, col uint16, v bool) {
+			val := byte(0)
+			if v {
+				val = 1
+			}
+			b.record(0x0205, le(row, col, uint16(xfGeneral), val, byte(0)))
+		}
+		// Row 0: a title; row 1 is blank; row 2 holds the headers.
+		labelSST(0, 0, "Login requirements")
+		for col, h := range []string{"ID", "Feature", "Scenario", "Expected", "Priority", "Due", "Done", "Estimate", "Automated"} {
+			labelSST(2, uint16(col), h)
+		}
+
+		// REQ-1: plain cells, an RK estimate and a TRUE flag.
+		for col, s := range []string{"REQ-1", "Login", "Valid credentials open the dashboard", "Dashboard shown", "P1"} {
+			labelSST(3, uint16(col), s)
+		}
+		number(3, 5, xfDate, 45366) // 2024-03-15
+		number(3, 6, xfPercent, 0.5)
+		b.record(0x027E, le(uint16(3), uint16(7), uint16(xfGeneral), rk(3)))
+		boolCell(3, 8, true)
+
+		// REQ-2: wide characters, a formula with a string result, and the
+		// done/estimate numbers as one MULRK.
+		for col, s := range []string{"REQ-2", "ログイン", "Wrong password is rejected", "Error \"Invalid password\" shown"} {
+			labelSST(4, uint16(col), s)
+		}
+		formulaString := le(uint16(4), uint16(4), uint16(xfGeneral), byte(0), byte(0), byte(0), byte(0), byte(0), byte(0), uint16(0xFFFF), uint16(0), uint32(0))
+		b.record(0x0006, append(formulaString, le(uint16(5), byte(0x17), byte(2), byte(0), byte('P'), byte('2'))...)) // ="P2"
+		b.record(0x0207, unicodeString("P2"))
+		number(4, 5, xfDate, 45397.5) // 2024-04-15 12:00 shown as a date
+		b.record(0x00BD, le(uint16(4), uint16(6), uint16(xfPercent), uint32(0x3FE00000), uint16(xfGeneral), rk(5), uint16(7)))
+		boolCell(4, 8, false)
+
+		// REQ-3: a numeric formula result and a cell written as LABEL.
+		for col, s := range []string{"REQ-3", "Café menu", "Order history lists past orders"} {
+			labelSST(6, uint16(col), s)
+		}
+		b.record(0x0204, append(le(uint16(6), uint16(3), uint16(xfGeneral)), unicodeString("Orders listed, newest first")...))
+		divide := le(uint16(7), byte(0x1E), uint16(5), byte(0x1E), uint16(4), byte(0x06)) // =5/4
+		b.record(0x0006, append(le(uint16(6), uint16(7), uint16(xfGeneral), math.Float64bits(1.25), uint16(0), uint32(0)), divide...))
+		b.record(0x000A, nil)
+		return b.Bytes()
+	}()
+
+	sheet2 := func() []byte {
+		var b biff
+		b.record(0x0809, le(uint16(0x0600), uint16(0x0010), uint16(0x0DBB), uint16(0x07CC), uint32(0), uint32(0x06)))
+		b.record(0x0204, append(le(uint16(0), uint16(0), uint16(xfGeneral)), unicodeString("Owner")...))
+		b.record(0x0204, append(le(uint16(0), uint16(1), uint16(xfGeneral)), unicodeString("QA team")...))
+		b.record(0x000A, nil)
+		return b.Bytes()
+	}()
+
+	var globals biff
+	globals.record(0x0809, le(uint16(0x0600), uint16(0x0005), uint16(0x0DBB), uint16(0x07CC), uint32(0), uint32(0x06)))
+	globals.record(0x0042, le(uint16(1200))) // CODEPAGE: UTF-16
+	globals.record(0x0022, le(uint16(0)))    // DATEMODE: 1900
+	for i := 0; i < 4; i++ {                 // FONT 0-3 (index 4 is never used)
+		globals.record(0x0031, append(le(uint16(200), uint16(0), uint16(0x7FFF), uint16(400), uint16(0), byte(0), byte(0), byte(0), byte(0)), shortString("Arial")...))
+	}
+	globals.record(0x041E, append(le(uint16(164)), unicodeString(`yyyy\-mm\-dd;@`)...))
+	xf := func(format uint16, style bool) []byte {
+		flags := uint16(0x0001)
+		if style {
+			flags = 0xFFF5
+		}
+		return append(le(uint16(0), format, flags, uint16(0x0020), uint16(0), uint32(0), uint32(0)), le(uint16(0x20C0))...)
+	}
+	for i := 0; i < 15; i++ {
+		globals.record(0x00E0, xf(0, true))
+	}
+	globals.record(0x00E0, xf(0, false))   // 15: General
+	globals.record(0x00E0, xf(164, false)) // 16: yyyy-mm-dd
+	globals.record(0x00E0, xf(9, false))   // 17: 0%
+	globals.record(0x0293, le(uint16(0x8000), byte(0), byte(0xFF)))
+
+	names := []string{"Requirements", "Notes"}
+	boundSheet := func(offset uint32, name string) []byte {
+		return append(le(offset, byte(0), byte(0)), shortString(name)...)
+	}
+	// BOUNDSHEET offsets point past the globals, whose size they are part of:
+	// write the rest of the globals once with placeholders to measure it.
+	tail := func(offsets []uint32) []byte {
+		var b biff
+		for i, name := range names {
+			b.record(0x0085, boundSheet(offsets[i], name))
+		}
+		sst(&b, strs, 48)
+		b.record(0x000A, nil)
+		return b.Bytes()
+	}
+	start := uint32(globals.Len() + len(tail([]uint32{0, 0})))
+	globals.Write(tail([]uint32{start, start + uint32(len(sheet1))}))
+
+	stream := append(globals.Bytes(), sheet1...)
+	stream = append(stream, sheet2...)
+	// Streams under 4096 bytes live in the mini stream; pad past it so the
+	// compound file needs only regular sectors.
+	for len(stream) < 4096 {
+		stream = append(stream, 0)
+	}
+	return stream
+}
+
+// --- OLE2 compound file ----------------------------------------------------
+
+const (
+	sectorSize = 512
+	freeSect   = 0xFFFFFFFF
+	endOfChain = 0xFFFFFFFE
+	fatSect    = 0xFFFFFFFD
+	noStream   = 0xFFFFFFFF
+)
+
+// compoundFile writes a version 3 compound file holding one stream: sector 0
+// is the FAT, then the stream, then the directory.
+func compoundFile(name string, stream []byte) []byte {
+	for len(stream)%sectorSize != 0 {
+		stream = append(stream, 0)
+	}
+	streamSectors := len(stream) / sectorSize
+	dirSector := uint32(1 + streamSectors)
+
+	fat := make([]uint32, sectorSize/4)
+	for i := range fat {
+		fat[i] = freeSect
+	}
+	fat[0] = fatSect
+	for i := 1; i <= streamSectors; i++ {
+		fat[i] = uint32(i + 1)
+	}
+	fat[streamSectors] = endOfChain
+	fat[dirSector] = endOfChain
+
+	header := make([]byte, sectorSize)
+	copy(header, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1})
+	copy(header[24:], le(uint16(0x003E), uint16(0x0003), uint16(0xFFFE), uint16(9), uint16(6)))
+	copy(header[44:], le(uint32(1), dirSector, uint32(0), uint32(4096), uint32(endOfChain), uint32(0), uint32(endOfChain), uint32(0)))
+	copy(header[76:], le(uint32(0)))
+	for i := 1; i < 109; i++ {
+		copy(header[76+4*i:], le(uint32(freeSect)))
+	}
+
+	entry := func(name string, typ byte, child, start uint32, size uint64) []byte {
+		e := make([]byte, 128)
+		units := append(utf16.Encode([]rune(name)), 0)
+		copy(e, le(units))
+		copy(e[64:], le(uint16(2*len(units)), typ, byte(1), uint32(noStream), uint32(noStream), child))
+		copy(e[116:], le(start, size))
+		return e
+	}
+	dir := append(entry("Root Entry", 5, 1, endOfChain, 0), entry(name, 2, noStream, 1, uint64(len(stream)))...)
+	for len(dir) < sectorSize {
+		empty := make([]byte, 128)
+		copy(empty[68:], le(uint32(noStream), uint32(noStream), uint32(noStream)))
+		dir = append(dir, empty...)
+	}
+
+	out := append(header, le(fat)...)
+	out = append(out, stream...)
+	return append(out, dir...)
+}
+
+// --- OpenDocument ----------------------------------------------------------
+
+const odsContent = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" xmlns:dc="http://purl.org/dc/elements/1.1/" office:version="1.3">
+ <office:body>
+  <office:spreadsheet>
+   <table:table table:name="Requirements">
+    <table:table-column table:number-columns-repeated="9"/>
+    <table:table-row><table:table-cell office:value-type="string"><text:p>Login requirements</text:p></table:table-cell><table:table-cell table:number-columns-repeated="16383"/></table:table-row>
+    <table:table-row><table:table-cell table:number-columns-repeated="16384"/></table:table-row>
+    <table:table-header-rows>
+     <table:table-row>
+      <table:table-cell office:value-type="string"><text:p>ID</text:p></table:table-cell>
+      <table:table-cell office:value-type="string"><text:p>Feature</text:p></table:table-cell>
+      <table:table-cell office:value-type="string"><text:p>Scenario</text:p></table:table-cell>
+      <table:table-cell office:value-type="string"><text:p>Expected</text:p></table:table-cell>
+      <table:table-cell office:value-type="string"><text:p>Priority</text:p></table:table-cell>
+      <table:table-cell office:value-type="string"><text:p>Due</text:p></table:table-cell>
+      <table:table-cell office:value-type="string"><text:p>Done</text:p></table:table-cell>
+      <table:table-cell office:value-type="string"><text:p>Estimate</text:p></table:table-cell>
+      <table:table-cell office:value-type="string"><text:p>Automated</text:p></table:table-cell>
+     </table:table-row>
+    </table:table-header-rows>
+    <table:table-row>
+     <table:table-cell office:value-type="string"><text:p>REQ-1</text:p></table:table-cell>
+     <table:table-cell office:value-type="string"><text:p>Login</text:p></table:table-cell>
+     <table:table-cell office:value-type="string"><text:p>Valid credentials<text:s/>open the dashboard</text:p></table:table-cell>
+     <table:table-cell office:value-type="string"><text:p>Dashboard shown</text:p><office:annotation><dc:creator>Reviewer</dc:creator><text:p>Check the landing page</text:p></office:annotation></table:table-cell>
+     <table:table-cell office:value-type="string"><text:p>P1</text:p></table:table-cell>
+     <table:table-cell office:value-type="date" office:date-value="2024-03-15"><text:p>2024-03-15</text:p></table:table-cell>
+     <table:table-cell office:value-type="percentage" office:value="0.5"><text:p>50%</text:p></table:table-cell>
+     <table:table-cell office:value-type="float" office:value="3"><text:p>3</text:p></table:table-cell>
+     <table:table-cell office:value-type="boolean" office:boolean-value="true"><text:p>TRUE</text:p></table:table-cell>
+    </table:table-row>
+    <table:table-row>
+     <table:table-cell office:value-type="string"><text:p>REQ-2</text:p></table:table-cell>
+     <table:table-cell office:value-type="string"><text:p>ログイン</text:p></table:table-cell>
+     <table:table-cell office:value-type="string"><text:p>Wrong password is rejected</text:p></table:table-cell>
+     <table:table-cell office:value-type="string"><text:p>Error "Invalid password" shown</text:p></table:table-cell>
+     <table:table-cell table:formula="of:=&quot;P2&quot;" office:value-type="string" office:string-value="P2"><text:p>P2</text:p></table:table-cell>
+     <table:table-cell office:value-type="date" office:date-value="2024-04-15T12:00:00"><text:p>2024-04-15</text:p></table:table-cell>
+     <table:table-cell office:value-type="percentage" office:value="0.5"><text:p>50%</text:p></table:table-cell>
+     <table:table-cell office:value-type="float" office:value="5"/>
+     <table:table-cell office:value-type="boolean" office:boolean-value="false"><text:p>FALSE</text:p></table:table-cell>
+    </table:table-row>
+    <table:table-row><table:table-cell table:number-columns-repeated="16384"/></table:table-row>
+    <table:table-row>
+     <table:table-cell office:value-type="string"><text:p>REQ-3</text:p></table:table-cell>
+     <table:table-cell office:value-type="string"><text:p>Café menu</text:p></table:table-cell>
+     <table:table-cell office:value-type="string"><text:p>Order history lists past orders</text:p></table:table-cell>
+     <table:table-cell office:value-type="string"><text:p>Orders listed,</text:p><text:p>newest first</text:p></table:table-cell>
+     <table:table-cell table:number-columns-repeated="3"/>
+     <table:table-cell table:formula="of:=5/4" office:value-type="float" office:value="1.25"><text:p>1.25</text:p></table:table-cell>
+     <table:table-cell table:number-columns-repeated="16376"/>
+    </table:table-row>
+    <table:table-row table:number-rows-repeated="1048569"><table:table-cell table:number-columns-repeated="16384"/></table:table-row>
+   </table:table>
+   <table:table table:name="Notes">
+    <table:table-row>
+     <table:table-cell office:value-type="string"><text:p>Owner</text:p></table:table-cell>
+     <table:table-cell office:value-type="string"><text:p>QA team</text:p></table:table-cell>
+    </table:table-row>
+   </table:table>
+  </office:spreadsheet>
+ </office:body>
+</office:document-content>
+`
+
+const odsManifest = `<?xml version="1.0" encoding="UTF-8"?>
+<manifest:manifest xmlns:manifest="urn:oasis:names:tc:opendocument:xmlns:manifest:1.0" manifest:version="1.3">
+ <manifest:file-entry manifest:full-path="/" manifest:media-type="application/vnd.oasis.opendocument.spreadsheet"/>
+ <manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/>
+</manifest:manifest>
+`
+
+func odsPackage() ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	// The mimetype comes first and uncompressed, so it can be sniffed.
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write([]byte("application/vnd.oasis.opendocument.spreadsheet")); err != nil {
+		return nil, err
+	}
+	for _, f := range []struct{ name, body string }{
+		{"META-INF/manifest.xml", odsManifest},
+		{"content.xml", odsContent},
+	} {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(f.body)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
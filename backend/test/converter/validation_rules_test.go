@@ -0,0 +1,130 @@
+package converter_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+const rulesYAML = `
+required_fields: [feature]
+rules:
+  - name: priority-values
+    field: priority
+    enum: [P0, P1, P2, P3]
+    severity: error
+  - field: id
+    unique: true
+    pattern: '^REQ-\d+$'
+  - field: title
+    min_length: 5
+    max_length: 20
+    severity: info
+  - field: metadata.Component
+    enum: [api, web]
+    ignore_case: true
+  - name: p0-needs-expected
+    field: expected
+    required: true
+    message: P0 rows need an expected result
+    when:
+      - field: priority
+        equals: p0
+      - field: metadata.Component
+        matches: '^(?i)api$'
+`
+
+func rulesDoc() *SpecDoc {
+	return &SpecDoc{Rows: []SpecRow{
+		{ID: "REQ-1", Feature: "Auth", Title: "Login works", Priority: "P0", Expected: "200 OK", Metadata: map[string]string{"Component": "API"}},
+		{ID: "REQ-1", Feature: "Auth", Title: "SSO", Priority: "P5", Metadata: map[string]string{"Component": "mobile"}},
+		{ID: "X-3", Feature: "Billing", Title: "Invoice export to PDF and CSV", Priority: "P0", Metadata: map[string]string{"component": "api"}},
+	}}
+}
+
+func warningsByCode(warnings []Warning) map[string][]Warning {
+	out := map[string][]Warning{}
+	for _, w := range warnings {
+		out[w.Code] = append(out[w.Code], w)
+	}
+	return out
+}
+
+func TestValidationRules_DSL(t *testing.T) {
+	rules, err := ParseValidationRules([]byte(rulesYAML))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	result := Validate(rulesDoc(), rules)
+	if result.Valid {
+		t.Fatal("expected invalid result")
+	}
+	byCode := warningsByCode(result.Warnings)
+
+	if enum := byCode["VALIDATION_ENUM"]; len(enum) != 2 {
+		t.Fatalf("enum warnings = %+v", enum)
+	} else if enum[0].Severity != SeverityError || enum[0].Details["rule"] != "priority-values" || enum[0].Details["row"] != 2 {
+		t.Errorf("priority enum warning = %+v", enum[0])
+	}
+	if uniq := byCode["VALIDATION_UNIQUE"]; len(uniq) != 1 || uniq[0].Details["first_row"] != 1 || uniq[0].Details["row"] != 2 {
+		t.Errorf("unique warnings = %+v", uniq)
+	}
+	if pat := byCode["VALIDATION_PATTERN"]; len(pat) != 1 || pat[0].Details["value"] != "X-3" {
+		t.Errorf("pattern warnings = %+v", pat)
+	}
+	if minLen := byCode["VALIDATION_MIN_LENGTH"]; len(minLen) != 1 || minLen[0].Severity != SeverityInfo {
+		t.Errorf("min length warnings = %+v", minLen)
+	}
+	if maxLen := byCode["VALIDATION_MAX_LENGTH"]; len(maxLen) != 1 || maxLen[0].Details["row"] != 3 {
+		t.Errorf("max length warnings = %+v", maxLen)
+	}
+	req := byCode["VALIDATION_REQUIRED"]
+	if len(req) != 1 || req[0].Message != "P0 rows need an expected result" || req[0].Details["row"] != 3 {
+		t.Errorf("conditional required warnings = %+v", req)
+	}
+}
+
+func TestValidationRules_InfoOnlyStaysValid(t *testing.T) {
+	rules, err := ParseValidationRules([]byte(`{"rules": [{"field": "title", "max_length": 3, "severity": "info"}]}`))
+	if err != nil {
+		t.Fatalf("parse JSON: %v", err)
+	}
+	result := Validate(rulesDoc(), rules)
+	if !result.Valid || len(result.Warnings) != 2 {
+		t.Fatalf("valid=%v warnings=%d, want valid with 2 info findings", result.Valid, len(result.Warnings))
+	}
+}
+
+func TestParseValidationRules_Rejects(t *testing.T) {
+	cases := map[string]string{
+		"unknown key":     "rules:\n  - field: id\n    uniqe: true\n",
+		"bad severity":    "rules:\n  - field: id\n    unique: true\n    severity: fatal\n",
+		"bad regex":       "rules:\n  - field: id\n    pattern: '(['\n",
+		"no checks":       "rules:\n  - field: id\n",
+		"inverted length": "rules:\n  - field: id\n    min_length: 5\n    max_length: 2\n",
+		"two predicates":  "rules:\n  - field: id\n    required: true\n    when:\n      - field: type\n        equals: a\n        matches: b\n",
+		"missing field":   "rules:\n  - unique: true\n",
+	}
+	for name, doc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseValidationRules([]byte(doc)); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestLoadValidationRulesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte("rules:\n  - field: id\n    pattern: '(['\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadValidationRulesFile(path)
+	if err == nil || !strings.Contains(err.Error(), "rules.yaml") {
+		t.Fatalf("error %v should name the file", err)
+	}
+}
@@ -0,0 +1,46 @@
+package converter_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	. "github.com/yourorg/md-spec-tool/internal/converter"
+)
+
+func TestMappedTable_RenamesMappedHeaders(t *testing.T) {
+	matrix := CellMatrix{
+		{"Test plan", "", ""},
+		{"Case", "Area", "Reviewer"},
+		{"TC-1", "Login", "Kim"},
+	}
+	got := MappedTable(matrix, 1, ColumnMap{FieldID: 0, FieldFeature: 1})
+	want := CellMatrix{{"id", "feature", "Reviewer"}, {"TC-1", "Login", "Kim"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("table = %q, want %q", got, want)
+	}
+	if got := MappedTable(matrix, 5, nil); len(got) != 0 {
+		t.Fatalf("out-of-range header row = %q", got)
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	table := CellMatrix{{"id", "feature"}, {"TC-1", "Login"}}
+	if err := WriteXLSX(&buf, table, "Q3: [draft]"); err != nil {
+		t.Fatal(err)
+	}
+	book, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sheets := book.GetSheetList(); len(sheets) != 1 || sheets[0] != "Q3_ _draft_" {
+		t.Fatalf("sheets = %q", sheets)
+	}
+	rows, _ := book.GetRows("Q3_ _draft_")
+	if !reflect.DeepEqual(rows, [][]string{{"id", "feature"}, {"TC-1", "Login"}}) {
+		t.Fatalf("rows = %q", rows)
+	}
+}
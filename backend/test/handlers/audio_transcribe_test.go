@@ -0,0 +1,217 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/http/handlers"
+	"github.com/yourorg/md-spec-tool/internal/transcribe"
+)
+
+func newAudioRouter(t *testing.T, cfg *config.Config, tr transcribe.Transcriber) *gin.Engine {
+	t.Helper()
+	h := handlers.NewAudioTranscribeHandler(cfg)
+	if tr != nil {
+		h.SetTranscriber(tr)
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/audio/transcribe", h.Transcribe)
+	return router
+}
+
+func postAudio(t *testing.T, router http.Handler, filename string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	return postAudioURL(t, router, "/audio/transcribe", filename, header)
+}
+
+func postAudioURL(t *testing.T, router http.Handler, url, filename string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = part.Write([]byte("RIFF....WAVEfmt "))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, url, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAudioTranscribe_FakeBackendBuildsSplits(t *testing.T) {
+	fake := &transcribe.Fake{Result: &transcribe.Transcription{
+		Text:     "We need login. It must support SSO.",
+		Language: "en",
+		Duration: 6,
+		Words: []transcribe.Word{
+			{Word: "We", Start: 0.0, End: 0.3},
+			{Word: "need", Start: 0.3, End: 0.6},
+			{Word: "login.", Start: 0.6, End: 1.0},
+			{Word: "It", Start: 3.0, End: 3.2},
+			{Word: "must", Start: 3.2, End: 3.5},
+			{Word: "support", Start: 3.5, End: 3.9},
+			{Word: "SSO.", Start: 3.9, End: 4.4},
+		},
+	}}
+	router := newAudioRouter(t, &config.Config{MaxAudioUploadBytes: 1 << 20}, fake)
+
+	w := postAudio(t, router, "meeting.wav", map[string]string{handlers.BYOKHeader: "sk-user"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+	var resp handlers.AudioTranscribeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Sentences) != 2 || resp.Sentences[1].Text != "It must support SSO." {
+		t.Fatalf("sentences = %+v", resp.Sentences)
+	}
+	if len(resp.Paragraphs) != 2 || resp.Paragraphs[1].Start != 3.0 {
+		t.Fatalf("paragraphs = %+v, want a break at the 2s pause", resp.Paragraphs)
+	}
+
+	reqs := fake.Requests()
+	if len(reqs) != 1 || reqs[0].APIKey != "sk-user" {
+		t.Fatalf("requests = %+v, want one call carrying the BYOK key", reqs)
+	}
+}
+
+func TestAudioTranscribe_BackendErrorIsBadGateway(t *testing.T) {
+	fake := &transcribe.Fake{Err: errors.New("whisper_http rate limited: slow down")}
+	router := newAudioRouter(t, &config.Config{MaxAudioUploadBytes: 1 << 20}, fake)
+
+	w := postAudio(t, router, "meeting.mp3", nil)
+	if w.Code != http.StatusBadGateway {
+		t.Fatalf("status %d, want 502", w.Code)
+	}
+}
+
+func TestAudioTranscribe_OpenAIWithoutKeyIsRejected(t *testing.T) {
+	router := newAudioRouter(t, &config.Config{MaxAudioUploadBytes: 1 << 20}, nil)
+
+	w := postAudio(t, router, "meeting.wav", nil)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status %d, want 503 for missing OpenAI key", w.Code)
+	}
+	if strings.Contains(w.Body.String(), "OpenAI") {
+		t.Errorf("error names a backend: %s", w.Body.String())
+	}
+}
+
+func TestAudioTranscribe_UnconfiguredBackendIsUnavailable(t *testing.T) {
+	cfg := &config.Config{MaxAudioUploadBytes: 1 << 20, TranscribeProvider: transcribe.ProviderWhisperHTTP}
+	router := newAudioRouter(t, cfg, nil)
+
+	if w := postAudio(t, router, "meeting.wav", nil); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status %d, want 503 without a whisper base URL: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestAudioTranscribe_SelfHostedNeedsNoKey(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_ = json.NewEncoder(w).Encode(transcribe.Transcription{
+			Text:     "Local only.",
+			Segments: []transcribe.Segment{{ID: 0, Start: 0, End: 1.2, Text: "Local only."}},
+		})
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{
+		MaxAudioUploadBytes: 1 << 20,
+		OpenAIAPIKey:        "sk-server",
+		TranscribeProvider:  transcribe.ProviderWhisperHTTP,
+		TranscribeBaseURL:   srv.URL + "/v1",
+	}
+	router := newAudioRouter(t, cfg, nil)
+
+	w := postAudio(t, router, "meeting.wav", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+	if gotAuth != "" {
+		t.Fatalf("OpenAI key leaked to self-hosted backend: %q", gotAuth)
+	}
+	var resp handlers.AudioTranscribeResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Sentences) != 1 || resp.Sentences[0].Text != "Local only." {
+		t.Fatalf("segment fallback sentences = %+v", resp.Sentences)
+	}
+}
+
+func subtitleFake() *transcribe.Fake {
+	return &transcribe.Fake{Result: &transcribe.Transcription{
+		Text: "We need login. It must support SSO.",
+		Words: []transcribe.Word{
+			{Word: "We", Start: 0.0, End: 0.3},
+			{Word: "need", Start: 0.3, End: 0.6},
+			{Word: "login.", Start: 0.6, End: 1.0},
+			{Word: "It", Start: 3.0, End: 3.2},
+			{Word: "must", Start: 3.2, End: 3.5},
+			{Word: "support", Start: 3.5, End: 3.9},
+			{Word: "SSO.", Start: 3.9, End: 4.45},
+		},
+	}}
+}
+
+func TestAudioTranscribe_ExportFormats(t *testing.T) {
+	cases := []struct {
+		query, contentType, filename string
+		want                         []string
+	}{
+		{"format=vtt", "text/vtt", "meeting.vtt", []string{"WEBVTT\n", "S2\n00:00:03.000 --> 00:00:04.450\nIt must support SSO."}},
+		{"format=srt", "application/x-subrip", "meeting.srt", []string{"1\n00:00:00,000 --> 00:00:01,000\nWe need login.", "\n2\n00:00:03,000"}},
+		{"format=md", "text/markdown", "meeting.md", []string{"# meeting", "**P1** `[00:00:00 - 00:00:01]`\nWe need login.", "**P2** `[00:00:03 - 00:00:04]`"}},
+		{"format=srt&split=paragraphs", "application/x-subrip", "meeting.srt", []string{"1\n00:00:00,000 --> 00:00:01,000\nWe need login.\n\n2\n"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			router := newAudioRouter(t, &config.Config{MaxAudioUploadBytes: 1 << 20}, subtitleFake())
+			w := postAudioURL(t, router, "/audio/transcribe?"+tc.query, "meeting.wav", nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", w.Code, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tc.contentType) {
+				t.Fatalf("content type = %q", ct)
+			}
+			if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, tc.filename) {
+				t.Fatalf("content disposition = %q", cd)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body missing %q:\n%s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestAudioTranscribe_UnknownFormatRejectedBeforeTranscribing(t *testing.T) {
+	fake := subtitleFake()
+	router := newAudioRouter(t, &config.Config{MaxAudioUploadBytes: 1 << 20}, fake)
+
+	w := postAudioURL(t, router, "/audio/transcribe?format=docx", "meeting.wav", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status %d, want 400", w.Code)
+	}
+	if len(fake.Requests()) != 0 {
+		t.Fatal("backend should not be called for an invalid format")
+	}
+}
@@ -3,10 +3,13 @@ package handlers_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -182,6 +185,66 @@ func TestGetXLSXSheets(t *testing.T) {
 	})
 }
 
+func TestXLSXUploads_LegacyFormats(t *testing.T) {
+	cfg := config.LoadConfig()
+	h := handlers.NewConvertHandler(converter.NewConverter(), cfg, handlers.NewAIServiceProvider(cfg))
+	upload := func(t *testing.T, handle gin.HandlerFunc, filename string, content []byte) *httptest.ResponseRecorder {
+		t.Helper()
+		body, contentType, err := createMultipartForm(filename, content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/mdflow/xlsx", body)
+		c.Request.Header.Set("Content-Type", contentType)
+		handle(c)
+		return w
+	}
+
+	for _, name := range []string{"requirements.xls", "requirements.ods"} {
+		t.Run(name, func(t *testing.T) {
+			content, err := os.ReadFile(filepath.Join("..", "converter", "testdata", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			w := upload(t, h.GetXLSXSheets, name, content)
+			if w.Code != http.StatusOK {
+				t.Fatalf("sheets: status %d: %s", w.Code, w.Body.String())
+			}
+			var sheets handlers.SheetsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &sheets); err != nil {
+				t.Fatal(err)
+			}
+			if len(sheets.Sheets) != 2 || sheets.Sheets[0] != "Requirements" || sheets.Sheets[1] != "Notes" {
+				t.Fatalf("sheets = %v, want [Requirements Notes]", sheets.Sheets)
+			}
+
+			w = upload(t, h.ConvertXLSX, name, content)
+			if w.Code != http.StatusOK {
+				t.Fatalf("convert: status %d: %s", w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), "REQ-2") {
+				t.Errorf("converted output is missing REQ-2: %s", w.Body.String())
+			}
+		})
+	}
+
+	t.Run("signature must match the extension", func(t *testing.T) {
+		w := upload(t, h.ConvertXLSX, "book.xls", []byte("PK\x03\x04not a workbook"))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid xls file") {
+			t.Errorf("status %d: %s", w.Code, w.Body.String())
+		}
+	})
+	t.Run("other extensions are rejected", func(t *testing.T) {
+		w := upload(t, h.ConvertXLSX, "book.numbers", []byte("PK\x03\x04"))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), ".ods") {
+			t.Errorf("status %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
 // createMultipartForm is a helper to create multipart form with file (used by other tests if needed)
 func createMultipartForm(filename string, fileContent []byte) (*bytes.Buffer, string, error) {
 	body := new(bytes.Buffer)
@@ -200,3 +263,131 @@ func createMultipartForm(filename string, fileContent []byte) (*bytes.Buffer, st
 
 	return body, writer.FormDataContentType(), nil
 }
+
+func TestConvertTSV_AppliesValidationRulesFile(t *testing.T) {
+	cfg := config.LoadConfig()
+	h := handlers.NewConvertHandler(converter.NewConverter(), cfg, handlers.NewAIServiceProvider(cfg))
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", "spec.tsv")
+	_, _ = io.WriteString(part, "ID\tFeature\tScenario\tPriority\nTC-1\tLogin\tValid user\tP9\n")
+	rules, _ := writer.CreateFormFile("validation_rules_file", "rules.yaml")
+	_, _ = io.WriteString(rules, "rules:\n  - name: priority-values\n    field: priority\n    enum: [P0, P1, P2, P3]\n    severity: error\n")
+	_ = writer.WriteField("template", "spec")
+	_ = writer.Close()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/api/mdflow/tsv", body)
+	c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+	h.ConvertTSV(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp handlers.MDFlowConvertResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	found := false
+	for _, warning := range resp.Warnings {
+		if warning.Code == "VALIDATION_ENUM" && warning.Details["rule"] == "priority-values" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected VALIDATION_ENUM warning, got %+v", resp.Warnings)
+	}
+	if !strings.Contains(resp.MDFlow, "Login") {
+		t.Fatalf("conversion output missing row:\n%s", resp.MDFlow)
+	}
+}
+
+func TestConvertTSV_ValidatesOverriddenColumns(t *testing.T) {
+	cfg := config.LoadConfig()
+	h := handlers.NewConvertHandler(converter.NewConverter(), cfg, handlers.NewAIServiceProvider(cfg))
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", "spec.tsv")
+	_, _ = io.WriteString(part, "ID\tFeature\tScenario\tUrgency code\nTC-1\tLogin\tValid user\tP9\n")
+	rules, _ := writer.CreateFormFile("validation_rules_file", "rules.yaml")
+	_, _ = io.WriteString(rules, "rules:\n  - name: priority-values\n    field: priority\n    pattern: \"^P[0-3]$\"\n")
+	_ = writer.WriteField("column_overrides", `{"Urgency code": "priority"}`)
+	_ = writer.WriteField("template", "spec")
+	_ = writer.Close()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/api/mdflow/tsv", body)
+	c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+	h.ConvertTSV(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp handlers.MDFlowConvertResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	for _, warning := range resp.Warnings {
+		if warning.Code == "VALIDATION_PATTERN" {
+			if !strings.Contains(warning.Message, "^P[0-3]$") {
+				t.Errorf("pattern warning should name the pattern: %q", warning.Message)
+			}
+			return
+		}
+	}
+	t.Fatalf("rules should see the column mapped by column_overrides, got %+v", resp.Warnings)
+}
+
+func TestConvertPaste_RunsTemplateRulePack(t *testing.T) {
+	cfg := config.LoadConfig()
+	h := handlers.NewConvertHandler(converter.NewConverter(), cfg, handlers.NewAIServiceProvider(cfg))
+	convert := func(paste string) handlers.MDFlowConvertResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		bodyJSON, _ := json.Marshal(handlers.PasteConvertRequest{PasteText: paste, Template: "spec", Format: "spec"})
+		c.Request, _ = http.NewRequest("POST", "/api/mdflow/paste", bytes.NewReader(bodyJSON))
+		c.Request.Header.Set("Content-Type", "application/json")
+		h.ConvertPaste(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status %d: %s", w.Code, w.Body.String())
+		}
+		var resp handlers.MDFlowConvertResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	packFindings := func(resp handlers.MDFlowConvertResponse) map[string]converter.WarningSeverity {
+		found := map[string]converter.WarningSeverity{}
+		for _, w := range resp.Warnings {
+			if w.Details["rule_pack"] == "spec" {
+				found[fmt.Sprint(w.Details["rule"])] = w.Severity
+			}
+		}
+		return found
+	}
+
+	// Duplicate IDs and an empty scenario cell trip the built-in spec pack.
+	resp := convert("ID\tFeature\tScenario\tExpected\nTC-1\tAuth\tLogin\tDashboard\ntc-1\tAuth\t\tError shown")
+	found := packFindings(resp)
+	if found["unique-id"] != converter.SeverityWarn || found["required-scenario"] != converter.SeverityWarn {
+		t.Errorf("rule pack findings = %v", found)
+	}
+	if resp.Meta.RulePack == nil || resp.Meta.RulePack.Template != "spec" || !resp.Meta.RulePack.Passed() {
+		t.Errorf("rule_pack = %+v, needs_review = %v", resp.Meta.RulePack, resp.NeedsReview)
+	}
+
+	// Without a scenario column the pack adds no per-row required findings.
+	resp = convert("ID\tFeature\tExpected\nTC-1\tAuth\tDashboard\nTC-2\tBilling\tPaid")
+	if found := packFindings(resp); len(found) != 0 {
+		t.Errorf("clean sheet rule pack findings = %v", found)
+	}
+	if resp.Meta.RulePack == nil || !resp.Meta.RulePack.Passed() {
+		t.Errorf("clean sheet rule_pack = %+v", resp.Meta.RulePack)
+	}
+}
@@ -0,0 +1,197 @@
+package handlers_test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSheetsAPI is a minimal in-memory stand-in for the Google Sheets v4 REST API.
+// It serves spreadsheet metadata, values.get and batchUpdate for a single spreadsheet,
+// plus the public CSV export used when no credentials are available.
+type fakeSheetsAPI struct {
+	t             *testing.T
+	spreadsheetID string
+	token         string
+
+	mu           sync.Mutex
+	tabs         map[int64]string   // sheetId -> title
+	values       map[string][][]any // title -> values
+	batchUpdates []map[string]any   // decoded batchUpdate bodies
+	valueGets    int
+	exportGets   int
+	failBatch    int // HTTP status returned by batchUpdate when non-zero
+	lostBatches  int // batchUpdates applied but answered with 503, as when a response is lost
+}
+
+func newFakeSheetsAPI(t *testing.T, spreadsheetID, token string) (*fakeSheetsAPI, *httptest.Server) {
+	t.Helper()
+	fake := &fakeSheetsAPI{
+		t:             t,
+		spreadsheetID: spreadsheetID,
+		token:         token,
+		tabs:          map[int64]string{},
+		values:        map[string][][]any{},
+	}
+	server := httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeSheetsAPI) setTab(gid int64, title string, rows [][]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tabs[gid] = title
+	values := make([][]any, 0, len(rows))
+	for _, row := range rows {
+		cells := make([]any, 0, len(row))
+		for _, cell := range row {
+			cells = append(cells, cell)
+		}
+		values = append(values, cells)
+	}
+	f.values[title] = values
+}
+
+func (f *fakeSheetsAPI) batches() []map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]map[string]any(nil), f.batchUpdates...)
+}
+
+// client returns an HTTP client that routes every request (including docs.google.com exports) to server.
+func (f *fakeSheetsAPI) client(server *httptest.Server) *http.Client {
+	target := server.Client().Transport
+	return &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		clone := req.Clone(req.Context())
+		clone.URL.Scheme = "http"
+		clone.URL.Host = strings.TrimPrefix(server.URL, "http://")
+		clone.Host = ""
+		return target.RoundTrip(clone)
+	})}
+}
+
+func (f *fakeSheetsAPI) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/spreadsheets/d/"+f.spreadsheetID+"/export" && r.Method == http.MethodGet {
+		f.serveExport(w, r)
+		return
+	}
+
+	if f.token != "" && r.Header.Get("Authorization") != "Bearer "+f.token {
+		writeFakeError(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	prefix := "/v4/spreadsheets/" + f.spreadsheetID
+	path := r.URL.Path
+	if !strings.HasPrefix(path, prefix) {
+		writeFakeError(w, http.StatusNotFound, "spreadsheet not found")
+		return
+	}
+	rest := strings.TrimPrefix(path, prefix)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		gids := make([]int64, 0, len(f.tabs))
+		for gid := range f.tabs {
+			gids = append(gids, gid)
+		}
+		sort.Slice(gids, func(i, j int) bool { return gids[i] < gids[j] })
+		sheetList := make([]map[string]any, 0, len(gids))
+		for _, gid := range gids {
+			sheetList = append(sheetList, map[string]any{"properties": map[string]any{"sheetId": gid, "title": f.tabs[gid]}})
+		}
+		writeFakeJSON(w, map[string]any{"spreadsheetId": f.spreadsheetID, "sheets": sheetList})
+	case strings.HasPrefix(rest, "/values/") && r.Method == http.MethodGet:
+		f.valueGets++
+		rangeStr := strings.TrimPrefix(rest, "/values/")
+		title := strings.Trim(strings.SplitN(rangeStr, "!", 2)[0], "'")
+		values, ok := f.values[title]
+		if !ok {
+			writeFakeError(w, http.StatusBadRequest, "unable to parse range")
+			return
+		}
+		writeFakeJSON(w, map[string]any{"range": title + "!A1:Z100", "majorDimension": "ROWS", "values": values})
+	case rest == ":batchUpdate" && r.Method == http.MethodPost:
+		if f.failBatch != 0 {
+			writeFakeError(w, f.failBatch, "batch update rejected")
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var decoded map[string]any
+		if err := json.Unmarshal(body, &decoded); err != nil {
+			writeFakeError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		f.batchUpdates = append(f.batchUpdates, decoded)
+		requests, _ := decoded["requests"].([]any)
+		for _, req := range requests {
+			add, _ := req.(map[string]any)["addSheet"].(map[string]any)
+			props, _ := add["properties"].(map[string]any)
+			if id, ok := props["sheetId"].(float64); ok {
+				f.tabs[int64(id)], _ = props["title"].(string)
+			}
+		}
+		if f.lostBatches > 0 {
+			f.lostBatches--
+			writeFakeError(w, http.StatusServiceUnavailable, "backend error")
+			return
+		}
+		writeFakeJSON(w, map[string]any{"spreadsheetId": f.spreadsheetID})
+	default:
+		writeFakeError(w, http.StatusNotFound, "unsupported fake endpoint "+r.Method+" "+path)
+	}
+}
+
+// serveExport mimics the public CSV export; the tab is selected by the gid query parameter.
+func (f *fakeSheetsAPI) serveExport(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.exportGets++
+
+	gid := r.URL.Query().Get("gid")
+	gids := make([]int64, 0, len(f.tabs))
+	for id := range f.tabs {
+		gids = append(gids, id)
+	}
+	sort.Slice(gids, func(i, j int) bool { return gids[i] < gids[j] })
+	for _, id := range gids {
+		if gid != "" && strconv.FormatInt(id, 10) != gid {
+			continue
+		}
+		title := f.tabs[id]
+		w.Header().Set("Content-Type", "text/csv")
+		cw := csv.NewWriter(w)
+		for _, row := range f.values[title] {
+			record := make([]string, len(row))
+			for i, cell := range row {
+				record[i], _ = cell.(string)
+			}
+			_ = cw.Write(record)
+		}
+		cw.Flush()
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func writeFakeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeFakeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": code, "message": message}})
+}
@@ -0,0 +1,276 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	mdhttp "github.com/yourorg/md-spec-tool/internal/http"
+	"github.com/yourorg/md-spec-tool/internal/http/handlers"
+)
+
+func newWriteBackHandler(endpoint string) *handlers.GSheetHandler {
+	cfg := &config.Config{
+		HTTPClientTimeout:       30,
+		MaxUploadBytes:          1 << 20,
+		GSheetMaxRetries:        1,
+		GSheetAPIEndpoint:       endpoint + "/",
+		SpecMinHeaderConfidence: 60,
+		SpecMaxRowLossRatio:     0.4,
+	}
+	return handlers.NewGSheetHandler(converter.NewConverter(), converter.NewMDFlowRenderer(), nil, cfg, nil)
+}
+
+func performWriteBack(t *testing.T, h *handlers.GSheetHandler, token string, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/mdflow/gsheet/writeback", bytes.NewReader([]byte(body)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		c.Request.Header.Set("Authorization", "Bearer "+token)
+	}
+	h.WriteBackGoogleSheet(c)
+	return w
+}
+
+func TestWriteBackGoogleSheet_CreatesTabWithNotes(t *testing.T) {
+	fake, server := newFakeSheetsAPI(t, "SHEET123", "user-token")
+	fake.setTab(0, "Cases", [][]string{
+		{"ID", "Feature", "Scenario", "Expected", "Priority"},
+		{"TC-001", "Login", "Valid credentials", "Dashboard shown", "P1"},
+		{"TC-002", "Login", "Invalid password", "", "High"},
+	})
+
+	h := newWriteBackHandler(server.URL)
+	body := `{
+		"url": "https://docs.google.com/spreadsheets/d/SHEET123/edit#gid=0",
+		"tab_title": "Normalized",
+		"validation_rules": {"required_fields": ["expected"]}
+	}`
+	w := performWriteBack(t, h, "user-token", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp handlers.GoogleSheetWriteBackResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.TabTitle != "Normalized" {
+		t.Errorf("tab title = %q, want Normalized", resp.TabTitle)
+	}
+	if resp.Rows != 2 {
+		t.Errorf("rows = %d, want 2", resp.Rows)
+	}
+	if resp.WarningCount != 1 {
+		t.Errorf("warning count = %d, want 1", resp.WarningCount)
+	}
+	if len(resp.Columns) == 0 || resp.Columns[0] != "row" {
+		t.Fatalf("expected leading row column, got %v", resp.Columns)
+	}
+
+	batches := fake.batches()
+	if len(batches) != 1 {
+		t.Fatalf("expected 1 batchUpdate, got %d", len(batches))
+	}
+	requests := batches[0]["requests"].([]any)
+	if len(requests) != 2 {
+		t.Fatalf("expected addSheet + updateCells, got %d requests", len(requests))
+	}
+	addSheet := requests[0].(map[string]any)["addSheet"].(map[string]any)
+	props := addSheet["properties"].(map[string]any)
+	if props["title"] != "Normalized" {
+		t.Errorf("addSheet title = %v", props["title"])
+	}
+	if props["sheetId"] == nil {
+		t.Error("expected explicit sheetId so updateCells can target the new tab")
+	}
+
+	update := requests[1].(map[string]any)["updateCells"].(map[string]any)
+	rows := update["rows"].([]any)
+	if len(rows) != 3 {
+		t.Fatalf("expected header + 2 data rows, got %d", len(rows))
+	}
+	header := rows[0].(map[string]any)["values"].([]any)
+	foundMappingNote := false
+	for _, cell := range header {
+		note, _ := cell.(map[string]any)["note"].(string)
+		if strings.Contains(note, "Mapped from column") {
+			foundMappingNote = true
+		}
+	}
+	if !foundMappingNote {
+		t.Error("expected mapping decision notes on header cells")
+	}
+
+	expectedCol := -1
+	for i, name := range resp.Columns {
+		if name == "expected" {
+			expectedCol = i
+		}
+	}
+	if expectedCol < 0 {
+		t.Fatalf("expected column missing from %v", resp.Columns)
+	}
+	secondRow := rows[2].(map[string]any)["values"].([]any)
+	note, _ := secondRow[expectedCol].(map[string]any)["note"].(string)
+	if !strings.Contains(note, "Required field") {
+		t.Errorf("expected validation note on expected cell of row 2, got %q", note)
+	}
+}
+
+func TestWriteBackGoogleSheet_RequiresToken(t *testing.T) {
+	h := newWriteBackHandler("http://127.0.0.1:1")
+	w := performWriteBack(t, h, "", `{"url":"https://docs.google.com/spreadsheets/d/SHEET123/edit"}`)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", w.Code)
+	}
+}
+
+func TestWriteBackGoogleSheet_ForbiddenOnReadOnlyAccess(t *testing.T) {
+	fake, server := newFakeSheetsAPI(t, "SHEET123", "user-token")
+	fake.setTab(0, "Cases", [][]string{
+		{"ID", "Feature", "Scenario"},
+		{"TC-001", "Login", "Valid credentials"},
+	})
+	fake.failBatch = http.StatusForbidden
+
+	h := newWriteBackHandler(server.URL)
+	w := performWriteBack(t, h, "user-token", `{"url":"https://docs.google.com/spreadsheets/d/SHEET123/edit"}`)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestWriteBackGoogleSheet_DefaultTitleAvoidsClash(t *testing.T) {
+	fake, server := newFakeSheetsAPI(t, "SHEET123", "user-token")
+	fake.setTab(0, "Cases", [][]string{
+		{"ID", "Feature", "Scenario"},
+		{"TC-001", "Login", "Valid credentials"},
+	})
+	fake.setTab(7, "Normalized", nil)
+
+	h := newWriteBackHandler(server.URL)
+	w := performWriteBack(t, h, "user-token", `{"url":"https://docs.google.com/spreadsheets/d/SHEET123/edit","tab_title":"Normalized"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp handlers.GoogleSheetWriteBackResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.TabTitle != "Normalized (2)" {
+		t.Errorf("tab title = %q, want %q", resp.TabTitle, "Normalized (2)")
+	}
+	if resp.TabGID == "7" || resp.TabGID == "0" {
+		t.Errorf("tab gid %q collides with an existing tab", resp.TabGID)
+	}
+}
+
+func TestWriteBackGoogleSheet_RetryAfterLostResponseKeepsOneTab(t *testing.T) {
+	fake, server := newFakeSheetsAPI(t, "SHEET123", "user-token")
+	fake.setTab(0, "Cases", [][]string{
+		{"ID", "Feature", "Scenario"},
+		{"TC-001", "Login", "Valid credentials"},
+	})
+	fake.lostBatches = 1
+
+	h := newWriteBackHandler(server.URL)
+	w := performWriteBack(t, h, "user-token", `{"url":"https://docs.google.com/spreadsheets/d/SHEET123/edit","tab_title":"Normalized"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 after the landed attempt, got %d: %s", w.Code, w.Body.String())
+	}
+	if n := len(fake.batches()); n != 1 {
+		t.Fatalf("expected the retry to find the tab instead of re-adding it, got %d batchUpdates", n)
+	}
+}
+
+func TestWriteBackGoogleSheet_WarningCountIncludesConversionWarnings(t *testing.T) {
+	fake, server := newFakeSheetsAPI(t, "SHEET123", "user-token")
+	fake.setTab(0, "Cases", [][]string{
+		{"ID", "Feature", "Scenario"},
+		{"TC-001", "Login", "Valid credentials"},
+		{"TC-001", "Login", "Invalid password"},
+	})
+
+	h := newWriteBackHandler(server.URL)
+	w := performWriteBack(t, h, "user-token", `{"url":"https://docs.google.com/spreadsheets/d/SHEET123/edit"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp handlers.GoogleSheetWriteBackResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !resp.NeedsReview || resp.WarningCount == 0 {
+		t.Errorf("needs_review = %v with warning_count = %d; duplicate IDs should count", resp.NeedsReview, resp.WarningCount)
+	}
+}
+
+func TestRouter_WriteBackOnBothAPIPrefixes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := mdhttp.SetupRouter(readinessConfig(t))
+	for _, path := range []string{"/api/mdflow/gsheet/writeback", "/api/v1/mdflow/gsheet/writeback"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"url":"https://docs.google.com/spreadsheets/d/SHEET123/edit"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status %d, want 401 without a Google token", path, w.Code)
+		}
+	}
+}
+
+func TestWriteBackGoogleSheet_NotesRulePackFindings(t *testing.T) {
+	fake, server := newFakeSheetsAPI(t, "SHEET123", "user-token")
+	fake.setTab(0, "Cases", [][]string{
+		{"ID", "Feature", "Case", "", "Expected"},
+		{"", "", "Scenario", "Priority", ""},
+		{"TC-001", "Login", "Valid credentials", "P1", "Dashboard shown"},
+		{"tc-001", "Login", "Invalid password", "P2", "Error shown"},
+	})
+
+	h := newWriteBackHandler(server.URL)
+	w := performWriteBack(t, h, "user-token", `{"url": "https://docs.google.com/spreadsheets/d/SHEET123/edit#gid=0", "template": "spec"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp handlers.GoogleSheetWriteBackResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	idCol, scenarioCol := -1, -1
+	for i, name := range resp.Columns {
+		switch name {
+		case "id":
+			idCol = i
+		case "scenario":
+			scenarioCol = i
+		}
+	}
+	if idCol < 0 || scenarioCol < 0 {
+		t.Fatalf("id or scenario column missing from %v", resp.Columns)
+	}
+	if resp.Meta.HeaderBand == nil {
+		t.Fatal("expected a two-row header band")
+	}
+
+	requests := fake.batches()[0]["requests"].([]any)
+	rows := requests[1].(map[string]any)["updateCells"].(map[string]any)["rows"].([]any)
+	header := rows[0].(map[string]any)["values"].([]any)
+	headerNote, _ := header[scenarioCol].(map[string]any)["note"].(string)
+	if !strings.Contains(headerNote, "Case"+converter.HeaderLevelSeparator+"Scenario") {
+		t.Errorf("expected composite header name in note, got %q", headerNote)
+	}
+	duplicate := rows[len(rows)-1].(map[string]any)["values"].([]any)
+	note, _ := duplicate[idCol].(map[string]any)["note"].(string)
+	if !strings.Contains(note, "[warn]") {
+		t.Errorf("expected rule pack note on duplicate id cell, got %q", note)
+	}
+}
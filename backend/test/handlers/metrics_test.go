@@ -0,0 +1,97 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mdhttp "github.com/yourorg/md-spec-tool/internal/http"
+	"github.com/yourorg/md-spec-tool/internal/http/handlers"
+	"github.com/yourorg/md-spec-tool/internal/http/middleware"
+)
+
+func scrapeMetrics(t *testing.T, router http.Handler) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics/prometheus", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("/metrics/prometheus status %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("/metrics/prometheus Content-Type = %q, want Prometheus text format", ct)
+	}
+	return w.Body.String()
+}
+
+func TestMetrics_PrometheusExposition(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := mdhttp.SetupRouter(readinessConfig(t))
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/no/such/route/42", nil))
+
+	body := scrapeMetrics(t, router)
+	for _, want := range []string{
+		"# TYPE mdflow_http_request_duration_seconds histogram",
+		`mdflow_http_requests_total{code="200",method="GET",route="/health"}`,
+		`mdflow_http_request_duration_seconds_count{method="GET",route="/health"}`,
+		`mdflow_http_requests_total{code="404",method="GET",route="unmatched"}`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("exposition missing %q", want)
+		}
+	}
+	if strings.Contains(body, "/no/such/route/42") {
+		t.Error("raw unmatched path leaked into route label")
+	}
+}
+
+func TestMetrics_JSONSummary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := mdhttp.SetupRouter(readinessConfig(t))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	var summary map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("decode json summary: %v", err)
+	}
+	if _, ok := summary["total_requests"]; !ok {
+		t.Fatalf("summary missing total_requests: %v", summary)
+	}
+}
+
+func TestMetrics_CountsRateLimitRejections(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.MetricsMiddleware())
+	router.GET("/metrics/prometheus", handlers.PrometheusHandler)
+	router.POST("/limited/metrics-test", middleware.RateLimit(1, time.Minute), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/limited/metrics-test", nil))
+	}
+
+	body := scrapeMetrics(t, router)
+	for _, want := range []string{
+		`mdflow_rate_limit_rejections_total{route="/limited/metrics-test"} 2`,
+		`mdflow_http_requests_total{code="429",method="POST",route="/limited/metrics-test"} 2`,
+		`mdflow_http_requests_total{code="204",method="POST",route="/limited/metrics-test"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("exposition missing %q", want)
+		}
+	}
+}
@@ -94,3 +94,36 @@ func TestPreviewHandler_emptyTablePreview(t *testing.T) {
 		t.Errorf("expected empty rows, got %d", len(resp.Rows))
 	}
 }
+
+func TestPreviewPaste_MultiRowHeader(t *testing.T) {
+	cfg := config.LoadConfig()
+	h := handlers.NewPreviewHandler(converter.NewConverter(), cfg, handlers.NewAIServiceProvider(cfg))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	bodyJSON, _ := json.Marshal(handlers.PasteConvertRequest{
+		PasteText: "No\tFeature\tInput\t\tExpected\n\t\tType\tLength\t\n1\tLogin\ttext\t20\tShown\n2\tLogout\tbutton\t0\tHidden",
+		Template:  "spec",
+	})
+	c.Request, _ = http.NewRequest("POST", "/api/mdflow/preview", bytes.NewReader(bodyJSON))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	h.PreviewPaste(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp handlers.PreviewResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.HeaderRow != 1 || resp.HeaderBand != (converter.HeaderBand{Start: 0, End: 1}) {
+		t.Errorf("header row %d, band %+v; want row 1 in band 0-1", resp.HeaderRow, resp.HeaderBand)
+	}
+	if len(resp.Headers) != 5 || resp.Headers[2] != "Input / Type" || resp.Headers[3] != "Input / Length" {
+		t.Errorf("headers = %q, want composite Input headers", resp.Headers)
+	}
+	if resp.TotalRows != 2 {
+		t.Errorf("total rows = %d, want 2", resp.TotalRows)
+	}
+}
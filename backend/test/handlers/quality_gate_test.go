@@ -211,3 +211,27 @@ func TestRequiresReview_NilQualityReportDoesNotPanic(t *testing.T) {
 		t.Errorf("expected needs_review=false when quality report is nil and no warning thresholds are violated, got true")
 	}
 }
+
+func TestRequiresReview_RulePackSeverity(t *testing.T) {
+	meta := converter.SpecDocMeta{
+		AIUsed:          true,
+		AIAvgConfidence: 0.92,
+		UnmappedColumns: []string{},
+		QualityReport: &converter.QualityReport{
+			ValidationPassed: true,
+			HeaderConfidence: 90,
+			HeaderCount:      3,
+			MappedColumns:    3,
+			MappedRatio:      1.0,
+		},
+		RulePack: &converter.RulePackResult{Template: "spec", Rules: 2, Warnings: 3, Infos: 1},
+	}
+	if handlers.RequiresReview(meta, nil) {
+		t.Errorf("expected needs_review=false when rule pack has only warn/info findings")
+	}
+
+	meta.RulePack.Errors = 1
+	if !handlers.RequiresReview(meta, nil) {
+		t.Errorf("expected needs_review=true when an error-severity rule failed")
+	}
+}
@@ -0,0 +1,157 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/config"
+	mdhttp "github.com/yourorg/md-spec-tool/internal/http"
+	"github.com/yourorg/md-spec-tool/internal/http/handlers"
+)
+
+func readinessConfig(t *testing.T) *config.Config {
+	t.Helper()
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	cfg := config.LoadConfig()
+	dir := t.TempDir()
+	cfg.FeedbackDBPath = filepath.Join(dir, "feedback.db")
+	cfg.ShareStorePath = filepath.Join(dir, "shares.json")
+	cfg.WebhookDBPath = filepath.Join(dir, "webhooks.db")
+	cfg.TelemetryDBPath = filepath.Join(dir, "telemetry.db")
+	return cfg
+}
+
+func getReadiness(t *testing.T, router http.Handler) (int, handlers.ReadinessResponse) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	var resp handlers.ReadinessResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode readiness: %v (%s)", err, w.Body.String())
+	}
+	return w.Code, resp
+}
+
+func TestHealthLive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := mdhttp.SetupRouter(readinessConfig(t))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("/health/live status %d", w.Code)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body["status"] != "alive" {
+		t.Fatalf("status = %v, want alive", body["status"])
+	}
+}
+
+func TestHealthReady_OKWithoutOptionalDependencies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := mdhttp.SetupRouter(readinessConfig(t))
+
+	code, resp := getReadiness(t, router)
+	if code != http.StatusOK {
+		t.Fatalf("status %d, want 200", code)
+	}
+	if resp.Status != "ok" {
+		t.Fatalf("overall status = %q, want ok", resp.Status)
+	}
+	want := map[string]string{
+		"feedback_db":        handlers.ComponentOK,
+		"share_store":        handlers.ComponentOK,
+		"ai_service":         handlers.ComponentDisabled,
+		"ai_budget":          handlers.ComponentDisabled,
+		"ai_cache_db":        handlers.ComponentDisabled,
+		"google_credentials": handlers.ComponentOK,
+	}
+	for name, status := range want {
+		got, ok := resp.Components[name]
+		if !ok {
+			t.Errorf("component %s missing", name)
+			continue
+		}
+		if got.Status != status {
+			t.Errorf("%s status = %q, want %q (%s)", name, got.Status, status, got.Message)
+		}
+	}
+	if len(resp.Degraded) != 0 {
+		t.Fatalf("degraded = %v, want none", resp.Degraded)
+	}
+	if configured, ok := resp.Components["google_credentials"].Details["configured"]; !ok || configured != false {
+		t.Fatalf("google_credentials details = %v, want configured false", resp.Components["google_credentials"].Details)
+	}
+	// Write-back uses the caller's OAuth token, so the service account is not needed for it.
+	if msg := resp.Components["google_credentials"].Message; msg != "GOOGLE_APPLICATION_CREDENTIALS not set; private sheets need user OAuth" {
+		t.Fatalf("google_credentials message = %q", msg)
+	}
+}
+
+func TestHealthReady_FeedbackDBFailureIsUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := readinessConfig(t)
+	blocker := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(blocker, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.FeedbackDBPath = filepath.Join(blocker, "feedback.db")
+	router := mdhttp.SetupRouter(cfg)
+
+	code, resp := getReadiness(t, router)
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("status %d, want 503", code)
+	}
+	if resp.Status != "unavailable" {
+		t.Fatalf("overall status = %q, want unavailable", resp.Status)
+	}
+	fb := resp.Components["feedback_db"]
+	if fb.Status != handlers.ComponentDown || !fb.Required || fb.Message != "failed to open" {
+		t.Fatalf("feedback_db = %+v, want required and down with a short reason", fb)
+	}
+
+	// Liveness must not depend on the failed store.
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("/health/live status %d", w.Code)
+	}
+}
+
+func TestHealthReady_OptionalDownOnlyDegrades(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewReadinessHandler()
+	h.AddProbe(handlers.ReadinessProbe{
+		Name:     "db",
+		Required: true,
+		Check: func(context.Context) handlers.ComponentStatus {
+			return handlers.ComponentStatus{Status: handlers.ComponentOK}
+		},
+	})
+	h.AddProbe(handlers.ReadinessProbe{
+		Name: "extra",
+		Check: func(context.Context) handlers.ComponentStatus {
+			return handlers.ComponentStatus{Status: handlers.ComponentDown, Message: "unreachable"}
+		},
+	})
+	router := gin.New()
+	router.GET("/health/ready", h.Ready)
+
+	code, resp := getReadiness(t, router)
+	if code != http.StatusOK || resp.Status != "degraded" {
+		t.Fatalf("got %d %q, want 200 degraded", code, resp.Status)
+	}
+	if resp.Components["extra"].Required {
+		t.Fatal("optional probe reported as required")
+	}
+}
@@ -0,0 +1,69 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/config"
+	mdhttp "github.com/yourorg/md-spec-tool/internal/http"
+	"github.com/yourorg/md-spec-tool/internal/share"
+)
+
+func TestShareRouter_ServesDirectoryReadOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "specs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "specs", "login.mdflow.md"), []byte("# Login\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := share.NewDirStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := mdhttp.SetupShareRouter(config.LoadConfig(), store)
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := do(http.MethodGet, "/api/share/public")
+	var list struct {
+		Items []share.ShareSummary `json:"items"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("public: %d %s", w.Code, w.Body.String())
+	}
+	if len(list.Items) != 1 || list.Items[0].Slug != "specs-login" {
+		t.Fatalf("public items = %+v", list.Items)
+	}
+
+	w = do(http.MethodGet, "/api/share/specs-login")
+	var got shareResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("get: %d %s", w.Code, w.Body.String())
+	}
+	if got.MDFlow != "# Login\n" || got.Permission != string(share.PermissionView) || got.AllowComments {
+		t.Errorf("share = %+v", got)
+	}
+
+	if w := do(http.MethodGet, "/api/share/missing"); w.Code != http.StatusNotFound {
+		t.Errorf("missing share status = %d", w.Code)
+	}
+	for _, path := range []string{"/api/share", "/api/share/specs-login/comments", "/api/mdflow/paste"} {
+		if w := do(http.MethodPost, path); w.Code != http.StatusNotFound {
+			t.Errorf("POST %s status = %d, want 404", path, w.Code)
+		}
+	}
+	if w := do(http.MethodGet, "/health/ready"); w.Code != http.StatusOK {
+		t.Errorf("ready status = %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -23,7 +23,10 @@ func setupRouterWithSprint2Limits(t *testing.T) http.Handler {
 	cfg.ConvertRateLimit = 1
 	cfg.AISuggestRateLimit = 1
 	cfg.RateLimitWindow = time.Minute
-	cfg.ShareStorePath = filepath.Join(t.TempDir(), "share-store.json")
+	dir := t.TempDir()
+	cfg.ShareStorePath = filepath.Join(dir, "share-store.json")
+	cfg.FeedbackDBPath = filepath.Join(dir, "feedback.db")
+	cfg.TelemetryDBPath = filepath.Join(dir, "telemetry.db")
 
 	router, cleanup := mdhttp.SetupRouterWithCleanup(cfg)
 	t.Cleanup(cleanup)
@@ -0,0 +1,189 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/http/handlers"
+	"github.com/yourorg/md-spec-tool/internal/http/middleware"
+	"github.com/yourorg/md-spec-tool/internal/telemetry"
+)
+
+type dashboardBucket struct {
+	Start         string `json:"start"`
+	Events        int    `json:"events"`
+	Previews      int    `json:"previews"`
+	Converts      int    `json:"converts"`
+	Shares        int    `json:"shares"`
+	P50DurationMS int64  `json:"p50_duration_ms"`
+	P95DurationMS int64  `json:"p95_duration_ms"`
+}
+
+type dashboardResponse struct {
+	Bucket  string            `json:"bucket"`
+	Series  []dashboardBucket `json:"series"`
+	Funnel  map[string]any    `json:"conversion_funnel"`
+	Filters map[string]string `json:"filters"`
+	Totals  struct {
+		EventsTotal int `json:"events_total"`
+	} `json:"totals"`
+}
+
+// setupTelemetryDashboard installs a SQLite backend seeded with events and restores
+// memory-only telemetry when the test ends.
+func setupTelemetryDashboard(t *testing.T, events []telemetry.Event) *gin.Engine {
+	t.Helper()
+	store, err := telemetry.NewStore("", 0, 0)
+	if err != nil {
+		t.Fatalf("telemetry.NewStore: %v", err)
+	}
+	middleware.SetTelemetryBackend(store)
+	t.Cleanup(func() {
+		middleware.SetTelemetryBackend(nil)
+		_ = store.Close()
+	})
+	for _, e := range events {
+		middleware.RecordTelemetryEvent(e)
+	}
+	middleware.FlushTelemetryEvents()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/telemetry/dashboard", handlers.NewTelemetryHandler().Dashboard)
+	return router
+}
+
+func getDashboard(t *testing.T, router *gin.Engine, query string) dashboardResponse {
+	t.Helper()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/telemetry/dashboard?"+query, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("dashboard: status %d body %s", w.Code, w.Body.String())
+	}
+	var resp dashboardResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode dashboard: %v", err)
+	}
+	return resp
+}
+
+func apiEvent(name, session, template string, at time.Time, durationMS int64) telemetry.Event {
+	return telemetry.Event{
+		EventName:    name,
+		EventTime:    at,
+		SessionID:    session,
+		Status:       "success",
+		TemplateType: template,
+		InputSource:  "paste",
+		DurationMS:   durationMS,
+		Source:       "backend",
+	}
+}
+
+func TestTelemetryDashboard_BucketsAndFunnel(t *testing.T) {
+	hour := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+	router := setupTelemetryDashboard(t, []telemetry.Event{
+		apiEvent("api_preview_completed", "s1", "spec", hour.Add(time.Minute), 100),
+		apiEvent("api_convert_completed", "s1", "spec", hour.Add(2*time.Minute), 300),
+		apiEvent("api_share_created", "s1", "", hour.Add(3*time.Minute), 20),
+		apiEvent("api_preview_completed", "s2", "table", hour.Add(4*time.Minute), 200),
+		apiEvent("api_preview_completed", "s3", "spec", hour.Add(time.Hour+time.Minute), 400),
+		apiEvent("api_convert_completed", "s3", "spec", hour.Add(time.Hour+2*time.Minute), 500),
+	})
+
+	resp := getDashboard(t, router, "hours=6&bucket=hour")
+	if resp.Bucket != "hour" || len(resp.Series) != 2 {
+		t.Fatalf("expected 2 hourly buckets, got %s %+v", resp.Bucket, resp.Series)
+	}
+	first := resp.Series[0]
+	if first.Start != hour.Format(time.RFC3339) || first.Events != 4 || first.Previews != 2 || first.Converts != 1 || first.Shares != 1 {
+		t.Fatalf("unexpected first bucket: %+v", first)
+	}
+	if first.P50DurationMS != 100 || first.P95DurationMS != 200 {
+		t.Fatalf("unexpected first bucket percentiles: p50=%d p95=%d", first.P50DurationMS, first.P95DurationMS)
+	}
+
+	funnel := resp.Funnel
+	if funnel["sessions_previewed"] != float64(3) || funnel["sessions_converted"] != float64(2) || funnel["sessions_shared"] != float64(1) {
+		t.Fatalf("unexpected funnel: %+v", funnel)
+	}
+	if funnel["convert_to_share_rate"] != 0.5 {
+		t.Fatalf("convert_to_share_rate = %v, want 0.5", funnel["convert_to_share_rate"])
+	}
+
+	daily := getDashboard(t, router, "hours=72")
+	if daily.Bucket != "day" || daily.Totals.EventsTotal != 6 {
+		t.Fatalf("expected day buckets over 72h with 6 events, got %s %d", daily.Bucket, daily.Totals.EventsTotal)
+	}
+}
+
+func TestTelemetryDashboard_FiltersByTemplate(t *testing.T) {
+	at := time.Now().UTC().Add(-time.Hour)
+	router := setupTelemetryDashboard(t, []telemetry.Event{
+		apiEvent("api_preview_completed", "s1", "spec", at, 100),
+		apiEvent("api_convert_completed", "s1", "spec", at.Add(time.Second), 100),
+		apiEvent("api_share_created", "s1", "", at.Add(2*time.Second), 10),
+		apiEvent("api_preview_completed", "s2", "table", at, 100),
+		apiEvent("api_convert_completed", "s2", "table", at.Add(time.Second), 100),
+	})
+
+	resp := getDashboard(t, router, "template=spec")
+	if resp.Filters["template"] != "spec" || resp.Totals.EventsTotal != 2 {
+		t.Fatalf("expected 2 spec events, got %d (filters %+v)", resp.Totals.EventsTotal, resp.Filters)
+	}
+	// The share has no template but belongs to the spec session, so the funnel keeps it.
+	if resp.Funnel["shares"] != float64(1) || resp.Funnel["sessions_shared"] != float64(1) {
+		t.Fatalf("share not attributed to filtered session: %+v", resp.Funnel)
+	}
+
+	resp = getDashboard(t, router, "template=table")
+	if resp.Funnel["shares"] != float64(0) || resp.Funnel["sessions_converted"] != float64(1) {
+		t.Fatalf("unexpected table funnel: %+v", resp.Funnel)
+	}
+}
+
+func TestTelemetryDashboard_RejectsUnknownBucket(t *testing.T) {
+	router := setupTelemetryDashboard(t, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/telemetry/dashboard?bucket=week", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestTelemetryIngest_ClampsFutureEventTimes(t *testing.T) {
+	router := setupTelemetryDashboard(t, nil)
+	router.POST("/api/telemetry/events", handlers.NewTelemetryHandler().IngestEvents)
+
+	now := time.Now().UTC()
+	body := fmt.Sprintf(`{"events":[
+		{"event_name":"studio_opened","event_time":%q},
+		{"event_name":"input_provided","event_time":%q},
+		{"event_name":"preview_succeeded","event_time":%q}
+	]}`,
+		now.Add(-time.Hour).Format(time.RFC3339),
+		now.Add(time.Minute).Format(time.RFC3339),
+		now.Add(365*24*time.Hour).Format(time.RFC3339))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/telemetry/events", strings.NewReader(body)))
+	if w.Code != http.StatusAccepted || !strings.Contains(w.Body.String(), `"recorded":2`) {
+		t.Fatalf("ingest: status %d body %s", w.Code, w.Body.String())
+	}
+	middleware.FlushTelemetryEvents()
+
+	events := middleware.QueryTelemetryEvents(telemetry.Query{Since: now.Add(-2 * time.Hour)})
+	if len(events) != 2 {
+		t.Fatalf("stored %d events, want 2: %+v", len(events), events)
+	}
+	for _, e := range events {
+		if e.EventTime.After(time.Now().UTC()) {
+			t.Errorf("event %s stored in the future at %s", e.EventName, e.EventTime)
+		}
+	}
+}
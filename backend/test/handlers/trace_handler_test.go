@@ -0,0 +1,139 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/http/handlers"
+)
+
+func TestTracePaste_Matrix(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewTraceHandler(nil, config.LoadConfig())
+
+	body, _ := json.Marshal(handlers.TraceRequest{
+		Requirements: "ID\tTitle\nREQ-1\tLogin with email\nREQ-2\tExport invoices to PDF\n",
+		Tests:        "ID\tScenario\tRequirement\nTC-1\tValid login\tREQ-1\nTC-2\tDark mode\t\n",
+	})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/mdflow/trace", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.TracePaste(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp handlers.TraceResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Uncovered) != 1 || resp.Uncovered[0] != "REQ-2" {
+		t.Errorf("uncovered = %v", resp.Uncovered)
+	}
+	if len(resp.OrphanTests) != 1 || resp.OrphanTests[0].ID != "TC-2" {
+		t.Errorf("orphans = %+v", resp.OrphanTests)
+	}
+	if !strings.Contains(resp.Markdown, "| REQ-1 | Login with email | TC-1 | covered |") {
+		t.Errorf("markdown:\n%s", resp.Markdown)
+	}
+}
+
+func TestTracePaste_RejectsInvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewTraceHandler(nil, config.LoadConfig())
+
+	for name, body := range map[string]string{
+		"missing tests":   `{"requirements":"ID\tTitle\nREQ-1\tLogin\n"}`,
+		"bad threshold":   `{"requirements":"ID\tTitle\nREQ-1\tLogin\n","tests":"ID\tTitle\nTC-1\tLogin\n","options":{"fuzzy_threshold":2}}`,
+		"no requirements": `{"requirements":"ID\tTitle\n","tests":"ID\tTitle\nTC-1\tLogin\n"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/mdflow/trace", strings.NewReader(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			h.TracePaste(c)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTraceXLSX_TwoSheets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewTraceHandler(nil, config.LoadConfig())
+
+	book := excelize.NewFile()
+	_ = book.SetSheetName("Sheet1", "Requirements")
+	_ = book.SetSheetRow("Requirements", "A1", &[]any{"ID", "Title"})
+	_ = book.SetSheetRow("Requirements", "A2", &[]any{"REQ-1", "Reset forgotten password"})
+	_ = book.SetSheetRow("Requirements", "A3", &[]any{"REQ-2", "Export invoices to PDF"})
+	_, _ = book.NewSheet("Tests")
+	_ = book.SetSheetRow("Tests", "A1", &[]any{"ID", "Scenario"})
+	_ = book.SetSheetRow("Tests", "A2", &[]any{"TC-1", "Reset forgotten password by email"})
+	var xlsx bytes.Buffer
+	if err := book.Write(&xlsx); err != nil {
+		t.Fatal(err)
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", "book.xlsx")
+	_, _ = part.Write(xlsx.Bytes())
+	_ = writer.WriteField("requirements_sheet", "Requirements")
+	_ = writer.WriteField("tests_sheet", "Tests")
+	_ = writer.Close()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/mdflow/xlsx/trace", body)
+	c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+	h.TraceXLSX(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp handlers.TraceResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !resp.Requirements[0].Covered || resp.Requirements[0].Tests[0].Method != "fuzzy" {
+		t.Errorf("REQ-1 coverage = %+v", resp.Requirements[0])
+	}
+	if resp.Summary.Uncovered != 1 || resp.Summary.OrphanTests != 0 {
+		t.Errorf("summary = %+v", resp.Summary)
+	}
+}
+
+func TestTracePaste_EmptyReferenceFallsBackToFuzzy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := handlers.NewTraceHandler(nil, config.LoadConfig())
+
+	body, _ := json.Marshal(handlers.TraceRequest{
+		Requirements: "ID\tTitle\nREQ-1\tReset forgotten password\n",
+		Tests:        "ID\tTitle\tRequirement\nTC-1\tReset forgotten password by email\t\n",
+	})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/mdflow/trace", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.TracePaste(c)
+
+	var resp handlers.TraceResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Requirements) != 1 || !resp.Requirements[0].Covered || resp.Requirements[0].Tests[0].Method != "fuzzy" {
+		t.Fatalf("requirements = %+v", resp.Requirements)
+	}
+}
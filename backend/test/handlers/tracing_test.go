@@ -0,0 +1,137 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	"github.com/yourorg/md-spec-tool/internal/http/handlers"
+	"github.com/yourorg/md-spec-tool/internal/http/middleware"
+	"github.com/yourorg/md-spec-tool/internal/tracing"
+)
+
+// installInMemoryTracing swaps the global provider for one that records spans synchronously.
+func installInMemoryTracing(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	prev := otel.GetTracerProvider()
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(tracing.NewProvider(tracing.Config{}, sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return exporter
+}
+
+func TestTracing_ConvertPasteSpans(t *testing.T) {
+	exporter := installInMemoryTracing(t)
+
+	cfg := config.LoadConfig()
+	cfg.AIEnabled = false
+	h := handlers.NewConvertHandler(converter.NewConverter(), cfg, handlers.NewAIServiceProvider(cfg))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.Tracing())
+	router.POST("/api/mdflow/paste", h.ConvertPaste)
+
+	body, _ := json.Marshal(handlers.PasteConvertRequest{
+		PasteText:       "ID\tFeature\tExpected\n1\tLogin\tDashboard shown\n2\tLogout\t",
+		Template:        "spec",
+		Format:          "spec",
+		ValidationRules: &converter.ValidationRules{RequiredFields: []string{"expected"}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/mdflow/paste", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d body %s", w.Code, w.Body.String())
+	}
+
+	byName := map[string]tracetest.SpanStub{}
+	for _, s := range exporter.GetSpans() {
+		byName[s.Name] = s
+	}
+	server, ok := byName["POST /api/mdflow/paste"]
+	if !ok {
+		t.Fatalf("missing server span; got %v", spanNames(exporter))
+	}
+	if server.SpanKind != trace.SpanKindServer || server.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("server span did not continue incoming trace: %+v", server.SpanContext)
+	}
+
+	for _, name := range []string{
+		"converter.parse_input",
+		"converter.detect_header",
+		"converter.map_columns",
+		"converter.render",
+		"converter.validate",
+	} {
+		s, ok := byName[name]
+		if !ok {
+			t.Fatalf("missing span %q; got %v", name, spanNames(exporter))
+		}
+		if s.SpanContext.TraceID() != server.SpanContext.TraceID() {
+			t.Errorf("span %q not in request trace", name)
+		}
+	}
+
+	mapping := byName["converter.map_columns"]
+	if got := attrValue(mapping, "mapping.strategy"); got != "rule_based" {
+		t.Errorf("mapping.strategy = %q, want rule_based", got)
+	}
+	if got := attrValue(byName["converter.validate"], "validation.valid"); got != "false" {
+		t.Errorf("validation.valid = %q, want false", got)
+	}
+}
+
+func TestTracing_UnmatchedPathsShareOneSpanName(t *testing.T) {
+	exporter := installInMemoryTracing(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.Tracing())
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/missing/1", "/missing/2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got spans %v, want 2", spanNames(exporter))
+	}
+	for _, s := range spans {
+		if s.Name != "GET unmatched" {
+			t.Errorf("span name %q, want GET unmatched", s.Name)
+		}
+		if got := attrValue(s, "http.route"); got != "unmatched" {
+			t.Errorf("http.route = %q, want unmatched", got)
+		}
+	}
+}
+
+func spanNames(exporter *tracetest.InMemoryExporter) []string {
+	var names []string
+	for _, s := range exporter.GetSpans() {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func attrValue(s tracetest.SpanStub, key string) string {
+	for _, kv := range s.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/ai"
+	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/http/handlers"
+)
+
+func newTranscriptSpecRouter(t *testing.T, service ai.Service) *gin.Engine {
+	t.Helper()
+	cfg := &config.Config{MaxPasteBytes: 1 << 20}
+	provider := handlers.NewAIServiceProvider(cfg)
+	if service != nil {
+		provider.SetDefaultAIService(service)
+	}
+	h := handlers.NewTranscriptSpecHandler(provider, cfg)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/audio/spec", h.DraftSpec)
+	return router
+}
+
+func postTranscriptSpec(t *testing.T, router http.Handler, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	payload, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/audio/spec", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTranscriptSpec_DraftsCitedRows(t *testing.T) {
+	mock := ai.NewMockAIService()
+	var got ai.ExtractRequirementsRequest
+	mock.ExtractRequirementsFunc = func(_ context.Context, req ai.ExtractRequirementsRequest) (*ai.RequirementsExtraction, error) {
+		got = req
+		return &ai.RequirementsExtraction{
+			Confidence: 0.75,
+			Requirements: []ai.ExtractedRequirement{
+				{Kind: "requirement", Title: "Password reset", Description: "Users reset passwords by email.", SourceParagraphs: []string{"P2"}},
+			},
+		}, nil
+	}
+	router := newTranscriptSpecRouter(t, mock)
+
+	w := postTranscriptSpec(t, router, map[string]any{
+		"text":   "[00:00:00] Hi all.\n\n[00:00:40] Users must be able to reset passwords by email.",
+		"format": "table",
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+	var resp handlers.TranscriptSpecResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Paragraphs) != 2 || got.Paragraphs[1].Start != 40 {
+		t.Fatalf("AI received paragraphs %+v", got.Paragraphs)
+	}
+	if len(resp.Rows) != 1 || resp.Rows[0].Metadata["Source"] != "P2" {
+		t.Fatalf("rows = %+v", resp.Rows)
+	}
+	if resp.Format != "table" || !strings.Contains(resp.MDFlow, "00:00:40-00:00:40") {
+		t.Fatalf("mdflow missing timestamp citation:\n%s", resp.MDFlow)
+	}
+	if resp.AIPromptVersion != ai.PromptVersionRequirementsExtraction || resp.Confidence != 0.75 {
+		t.Fatalf("unexpected AI metadata: %+v", resp)
+	}
+}
+
+func TestTranscriptSpec_RequiresInput(t *testing.T) {
+	router := newTranscriptSpecRouter(t, ai.NewMockAIService())
+
+	w := postTranscriptSpec(t, router, map[string]any{"text": "   "})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status %d, want 400", w.Code)
+	}
+}
+
+func TestTranscriptSpec_NoAIServiceIsUnavailable(t *testing.T) {
+	router := newTranscriptSpecRouter(t, nil)
+
+	w := postTranscriptSpec(t, router, map[string]any{"text": "We need audit logs."})
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status %d, want 503", w.Code)
+	}
+	var resp handlers.ErrorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Code != "AI_UNAVAILABLE" {
+		t.Fatalf("code = %q", resp.Code)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -96,3 +97,152 @@ func TestValidateWithRules(t *testing.T) {
 	// Just verify the response has expected structure
 	_ = resp
 }
+
+func postValidate(t *testing.T, req handlers.ValidateRequest) *httptest.ResponseRecorder {
+	t.Helper()
+	return postValidateAccept(t, req, "")
+}
+
+func postValidateAccept(t *testing.T, req handlers.ValidateRequest, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	cfg := config.LoadConfig()
+	h := handlers.NewValidationHandler(cfg, handlers.NewAIServiceProvider(cfg))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	bodyJSON, _ := json.Marshal(req)
+	c.Request, _ = http.NewRequest("POST", "/api/mdflow/validate", bytes.NewReader(bodyJSON))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if accept != "" {
+		c.Request.Header.Set("Accept", accept)
+	}
+	h.Validate(c)
+	return w
+}
+
+func TestValidateWithRulesText(t *testing.T) {
+	w := postValidate(t, handlers.ValidateRequest{
+		PasteText: "ID\tFeature\tPriority\nTC-1\tLogin\tP1\nTC-1\tLogout\tUrgent",
+		ValidationRulesText: `
+rules:
+  - field: priority
+    enum: [P0, P1, P2, P3]
+    severity: error
+  - field: id
+    unique: true
+`,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp handlers.ValidateResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	codes := map[string]converter.WarningSeverity{}
+	for _, warning := range resp.Warnings {
+		codes[warning.Code] = warning.Severity
+	}
+	if resp.Valid || codes["VALIDATION_ENUM"] != converter.SeverityError || codes["VALIDATION_UNIQUE"] != converter.SeverityWarn {
+		t.Fatalf("unexpected result: valid=%v warnings=%+v", resp.Valid, resp.Warnings)
+	}
+}
+
+func TestValidateRejectsMalformedRules(t *testing.T) {
+	w := postValidate(t, handlers.ValidateRequest{
+		PasteText:           "ID\tFeature\nTC-1\tLogin",
+		ValidationRulesText: "rules:\n  - field: id\n    pattern: '(['\n",
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	var resp handlers.ErrorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Code != "INVALID_VALIDATION_RULES" {
+		t.Fatalf("code = %q", resp.Code)
+	}
+}
+
+func TestValidate_ReportFormats(t *testing.T) {
+	req := handlers.ValidateRequest{
+		PasteText:           "ID\tFeature\tPriority\nTC-1\tLogin\tP1\n\nTC-2\tLogout\tUrgent\n",
+		ValidationRulesText: "rules:\n  - field: priority\n    enum: [P0, P1]\n    severity: error\n",
+		SourceName:          "specs/login.tsv",
+	}
+
+	w := postValidateAccept(t, req, "application/sarif+json")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/sarif+json") {
+		t.Fatalf("status %d, content type %q: %s", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+	}
+	var sarif struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &sarif); err != nil {
+		t.Fatalf("invalid SARIF: %v", err)
+	}
+	if sarif.Version != "2.1.0" || len(sarif.Runs) != 1 || len(sarif.Runs[0].Results) != 1 {
+		t.Fatalf("sarif = %+v", sarif)
+	}
+	result := sarif.Runs[0].Results[0]
+	loc := result.Locations[0].PhysicalLocation
+	if result.Level != "error" || loc.ArtifactLocation.URI != "specs/login.tsv" || loc.Region.StartLine != 4 || loc.Region.StartColumn != 3 {
+		t.Errorf("result = %+v", result)
+	}
+
+	w = postValidateAccept(t, req, "application/junit+xml")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/xml") {
+		t.Fatalf("status %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `<testsuite name="mdflow.validation" tests="1" failures="1">`) || !strings.Contains(body, "specs/login.tsv:4") {
+		t.Errorf("junit:\n%s", body)
+	}
+}
+
+func TestValidate_FormatQueryOverridesAccept(t *testing.T) {
+	cfg := config.LoadConfig()
+	h := handlers.NewValidationHandler(cfg, handlers.NewAIServiceProvider(cfg))
+	post := func(target, accept string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		bodyJSON, _ := json.Marshal(handlers.ValidateRequest{PasteText: "ID\tFeature\nTC-1\tLogin"})
+		c.Request, _ = http.NewRequest("POST", target, bytes.NewReader(bodyJSON))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set("Accept", accept)
+		h.Validate(c)
+		return w
+	}
+
+	w := post("/api/mdflow/validate", "application/xml, text/xml")
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		t.Errorf("generic XML Accept: content type %q, want JSON", w.Header().Get("Content-Type"))
+	}
+
+	w = post("/api/mdflow/validate?format=junit", "application/json")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "<testsuites") {
+		t.Errorf("?format=junit: status %d, body %s", w.Code, w.Body.String())
+	}
+
+	w = post("/api/mdflow/validate?format=html", "")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("?format=html: status %d, want 400", w.Code)
+	}
+}
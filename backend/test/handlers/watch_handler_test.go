@@ -0,0 +1,391 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	mdhttp "github.com/yourorg/md-spec-tool/internal/http"
+	"github.com/yourorg/md-spec-tool/internal/http/handlers"
+	"github.com/yourorg/md-spec-tool/internal/share"
+	"github.com/yourorg/md-spec-tool/internal/sheetwatch"
+	"github.com/yourorg/md-spec-tool/internal/webhook"
+)
+
+type watchTestEnv struct {
+	fake   *fakeSheetsAPI
+	shares *share.Store
+	events *watchEvents
+	router *gin.Engine
+}
+
+// watchEvents records what the syncer publishes to the webhook dispatcher.
+type watchEvents struct {
+	mu     sync.Mutex
+	types  []string
+	events []sheetwatch.SheetChangedEvent
+}
+
+func (e *watchEvents) Publish(eventType string, data any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.types = append(e.types, eventType)
+	e.events = append(e.events, data.(sheetwatch.SheetChangedEvent))
+}
+
+func newWatchTestEnv(t *testing.T) *watchTestEnv {
+	t.Helper()
+	fake, server := newFakeSheetsAPI(t, "SHEET123", "")
+	fake.setTab(0, "Cases", [][]string{
+		{"ID", "Feature", "Scenario", "Expected"},
+		{"TC-001", "Login", "Valid credentials", "Dashboard shown"},
+	})
+
+	cfg := &config.Config{
+		HTTPClientTimeout: 30,
+		MaxUploadBytes:    1 << 20,
+		GSheetMaxRetries:  1,
+		WatchMinInterval:  time.Minute,
+	}
+	gsheetHandler := handlers.NewGSheetHandler(converter.NewConverter(), converter.NewMDFlowRenderer(), nil, cfg, nil)
+	handlers.SetGSheetHTTPClientForTest(gsheetHandler, fake.client(server))
+
+	shares := share.NewStore("")
+	store, err := sheetwatch.NewStore("", cfg.WatchMinInterval, 0)
+	if err != nil {
+		t.Fatalf("sheetwatch.NewStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	syncer := sheetwatch.NewSyncer(store, gsheetHandler, converter.NewConverter(), shares)
+	events := &watchEvents{}
+	syncer.SetEventPublisher(events)
+	h := handlers.NewWatchHandler(store, syncer, shares, cfg)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/watches", h.CreateWatch)
+	router.GET("/watches", h.ListWatches)
+	router.GET("/watches/:id", h.GetWatch)
+	router.PATCH("/watches/:id", h.UpdateWatch)
+	router.DELETE("/watches/:id", h.DeleteWatch)
+	router.GET("/watches/:id/versions", h.ListWatchVersions)
+	router.POST("/watches/:id/check", h.CheckWatch)
+
+	return &watchTestEnv{fake: fake, shares: shares, events: events, router: router}
+}
+
+func (e *watchTestEnv) do(method, path, body string) *httptest.ResponseRecorder {
+	return e.doAs("", method, path, body)
+}
+
+// doAs sends the request with ownerKey as the watch owner key.
+func (e *watchTestEnv) doAs(ownerKey, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	var reader io.Reader
+	if body != "" {
+		reader = bytes.NewBufferString(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if ownerKey != "" {
+		req.Header.Set(handlers.WatchOwnerKeyHeader, ownerKey)
+	}
+	e.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestWatchHandler_CRUDAndChangeDetection(t *testing.T) {
+	env := newWatchTestEnv(t)
+
+	sh, err := env.shares.CreateShare(share.CreateShareInput{Title: "Login", MDFlow: "# draft"})
+	if err != nil {
+		t.Fatalf("create share: %v", err)
+	}
+
+	w := env.do(http.MethodPost, "/watches", `{
+		"url": "https://docs.google.com/spreadsheets/d/SHEET123/edit#gid=0",
+		"interval_seconds": 300,
+		"share_key": "`+sh.Token+`",
+		"share_owner_key": "`+sh.OwnerKey+`"
+	}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created handlers.WatchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if created.LastHash == "" || created.VersionCount != 1 || created.LastError != "" {
+		t.Fatalf("expected baseline recorded on create, got %+v", created)
+	}
+	if created.OwnerKey == "" {
+		t.Fatal("expected an owner key in the create response")
+	}
+	if created.IntervalSeconds != 300 || created.Template != "spec" || !created.Shared {
+		t.Errorf("unexpected watch settings %+v", created)
+	}
+
+	// Unchanged sheet: no new version
+	w = env.doAs(created.OwnerKey, http.MethodPost, "/watches/"+created.ID+"/check", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var check handlers.WatchCheckResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &check)
+	if check.Changed || check.Watch.VersionCount != 1 {
+		t.Fatalf("expected unchanged, got %+v", check)
+	}
+
+	// Edit the sheet: new version with diff, share revision and sheet.changed event
+	env.fake.setTab(0, "Cases", [][]string{
+		{"ID", "Feature", "Scenario", "Expected"},
+		{"TC-001", "Login", "Valid credentials", "Dashboard shown"},
+		{"TC-002", "Login", "Locked account", "Lockout message shown"},
+	})
+	w = env.doAs(created.OwnerKey, http.MethodPost, "/watches/"+created.ID+"/check", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	check = handlers.WatchCheckResponse{}
+	_ = json.Unmarshal(w.Body.Bytes(), &check)
+	if !check.Changed || check.Version == nil || check.Version.Number != 2 {
+		t.Fatalf("expected version 2, got %+v", check)
+	}
+	if check.Version.Diff == nil || check.Version.Diff.Added == 0 {
+		t.Errorf("expected added lines in diff, got %+v", check.Version.Diff)
+	}
+	if check.ShareRevision != 1 {
+		t.Errorf("expected share revision 1, got %+v", check)
+	}
+	updatedShare, _ := env.shares.GetShare(sh.Token)
+	if updatedShare.MDFlow != check.Version.MDFlow {
+		t.Error("expected linked share to carry the new spec")
+	}
+	env.events.mu.Lock()
+	if len(env.events.events) != 1 || env.events.types[0] != webhook.EventSheetChanged || env.events.events[0].Version != 2 {
+		t.Errorf("expected one sheet.changed event for version 2, got %v %+v", env.events.types, env.events.events)
+	}
+	env.events.mu.Unlock()
+
+	if env.fake.exportGets != 3 {
+		t.Errorf("expected 3 public export fetches, got %d", env.fake.exportGets)
+	}
+
+	w = env.doAs(created.OwnerKey, http.MethodGet, "/watches/"+created.ID+"/versions", "")
+	var versions struct {
+		Items []sheetwatch.Version `json:"items"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &versions)
+	if len(versions.Items) != 2 || versions.Items[0].Number != 2 {
+		t.Fatalf("expected versions newest first, got %+v", versions.Items)
+	}
+
+	w = env.doAs(created.OwnerKey, http.MethodPatch, "/watches/"+created.ID, `{"interval_seconds": 600}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var patched handlers.WatchResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &patched)
+	if patched.IntervalSeconds != 600 {
+		t.Errorf("unexpected patched watch %+v", patched)
+	}
+
+	w = env.doAs(created.OwnerKey, http.MethodGet, "/watches", "")
+	var list struct {
+		Items []handlers.WatchResponse `json:"items"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &list)
+	if len(list.Items) != 1 {
+		t.Fatalf("expected 1 watch, got %d", len(list.Items))
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte(sh.Token)) {
+		t.Error("watch list exposes the linked share's token")
+	}
+
+	if w = env.doAs(created.OwnerKey, http.MethodDelete, "/watches/"+created.ID, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	if w = env.doAs(created.OwnerKey, http.MethodGet, "/watches/"+created.ID, ""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d", w.Code)
+	}
+}
+
+func TestWatchHandler_CreateValidation(t *testing.T) {
+	env := newWatchTestEnv(t)
+
+	cases := []struct {
+		name string
+		body string
+	}{
+		{"invalid url", `{"url":"https://example.com/sheet"}`},
+		{"short interval", `{"url":"https://docs.google.com/spreadsheets/d/SHEET123/edit","interval_seconds":5}`},
+		{"unknown share", `{"url":"https://docs.google.com/spreadsheets/d/SHEET123/edit","share_key":"nope"}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := env.do(http.MethodPost, "/watches", tc.body)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWatchHandler_ShareRequiresOwner(t *testing.T) {
+	env := newWatchTestEnv(t)
+	sh, err := env.shares.CreateShare(share.CreateShareInput{Title: "Public spec", MDFlow: "# spec", IsPublic: true})
+	if err != nil {
+		t.Fatalf("create share: %v", err)
+	}
+	const sheet = `"url":"https://docs.google.com/spreadsheets/d/SHEET123/edit"`
+
+	cases := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"public slug", `{` + sheet + `,"share_key":"` + sh.Slug + `","share_owner_key":"` + sh.OwnerKey + `"}`, http.StatusBadRequest},
+		{"token without owner key", `{` + sheet + `,"share_key":"` + sh.Token + `"}`, http.StatusForbidden},
+		{"wrong owner key", `{` + sheet + `,"share_key":"` + sh.Token + `","share_owner_key":"guess"}`, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if w := env.do(http.MethodPost, "/watches", tc.body); w.Code != tc.code {
+				t.Fatalf("expected %d, got %d: %s", tc.code, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	w := env.do(http.MethodPost, "/watches", `{`+sheet+`}`)
+	var created handlers.WatchResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	if w = env.doAs(created.OwnerKey, http.MethodPatch, "/watches/"+created.ID, `{"share_key":"`+sh.Token+`"}`); w.Code != http.StatusForbidden {
+		t.Fatalf("linking a share by PATCH without its owner key: expected 403, got %d", w.Code)
+	}
+	if got, _ := env.shares.GetShare(sh.Token); got.Revision != 0 {
+		t.Errorf("share revision %d, want untouched", got.Revision)
+	}
+}
+
+func TestWatchHandler_CheckRecordsFetchFailure(t *testing.T) {
+	env := newWatchTestEnv(t)
+
+	w := env.do(http.MethodPost, "/watches", `{"url":"https://docs.google.com/spreadsheets/d/SHEET123/edit#gid=0"}`)
+	var created handlers.WatchResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+
+	env.fake.mu.Lock()
+	env.fake.tabs = map[int64]string{}
+	env.fake.mu.Unlock()
+
+	w = env.doAs(created.OwnerKey, http.MethodPost, "/watches/"+created.ID+"/check", "")
+	if w.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d: %s", w.Code, w.Body.String())
+	}
+	w = env.doAs(created.OwnerKey, http.MethodGet, "/watches/"+created.ID, "")
+	var got handlers.WatchResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &got)
+	if got.LastError == "" || got.VersionCount != 1 {
+		t.Errorf("expected failure recorded without dropping versions, got %+v", got)
+	}
+}
+
+func TestWatchHandler_RequiresOwnerKey(t *testing.T) {
+	env := newWatchTestEnv(t)
+	sh, err := env.shares.CreateShare(share.CreateShareInput{Title: "Login", MDFlow: "# draft"})
+	if err != nil {
+		t.Fatalf("create share: %v", err)
+	}
+
+	w := env.do(http.MethodPost, "/watches", `{
+		"url": "https://docs.google.com/spreadsheets/d/SHEET123/edit#gid=0",
+		"share_key": "`+sh.Token+`",
+		"share_owner_key": "`+sh.OwnerKey+`"
+	}`)
+	var created handlers.WatchResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	w = env.do(http.MethodPost, "/watches", `{"url":"https://docs.google.com/spreadsheets/d/SHEET123/edit#gid=0"}`)
+	var other handlers.WatchResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &other)
+	if created.OwnerKey == "" || other.OwnerKey == "" || created.OwnerKey == other.OwnerKey {
+		t.Fatalf("expected distinct owner keys, got %q and %q", created.OwnerKey, other.OwnerKey)
+	}
+
+	for _, r := range []struct{ method, path, body string }{
+		{http.MethodGet, "/watches", ""},
+		{http.MethodGet, "/watches/" + created.ID, ""},
+		{http.MethodGet, "/watches/" + created.ID + "/versions", ""},
+		{http.MethodPatch, "/watches/" + created.ID, `{"share_key": ""}`},
+		{http.MethodDelete, "/watches/" + created.ID, ""},
+		{http.MethodPost, "/watches/" + created.ID + "/check", ""},
+	} {
+		if w := env.do(r.method, r.path, r.body); w.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s without owner key: expected 401, got %d", r.method, r.path, w.Code)
+		}
+		if r.path == "/watches" {
+			continue
+		}
+		if w := env.doAs(other.OwnerKey, r.method, r.path, r.body); w.Code != http.StatusNotFound {
+			t.Errorf("%s %s with another owner's key: expected 404, got %d", r.method, r.path, w.Code)
+		}
+	}
+
+	w = env.doAs(other.OwnerKey, http.MethodGet, "/watches", "")
+	var list struct {
+		Items []handlers.WatchResponse `json:"items"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &list)
+	if len(list.Items) != 1 || list.Items[0].ID != other.ID || list.Items[0].OwnerKey != "" {
+		t.Errorf("expected only the caller's watch without its owner key, got %+v", list.Items)
+	}
+
+	got := env.doAs(created.OwnerKey, http.MethodGet, "/watches/"+created.ID, "")
+	var watch handlers.WatchResponse
+	_ = json.Unmarshal(got.Body.Bytes(), &watch)
+	if !watch.Shared {
+		t.Error("another owner's PATCH unlinked the share")
+	}
+
+	// A caller reusing its owner key sees both watches in one list.
+	w = env.doAs(other.OwnerKey, http.MethodPost, "/watches", `{"url":"https://docs.google.com/spreadsheets/d/SHEET123/edit#gid=0"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	w = env.doAs(other.OwnerKey, http.MethodGet, "/watches", "")
+	list.Items = nil
+	_ = json.Unmarshal(w.Body.Bytes(), &list)
+	if len(list.Items) != 2 {
+		t.Errorf("expected 2 watches for the reused owner key, got %d", len(list.Items))
+	}
+	if w = env.doAs("short", http.MethodPost, "/watches", `{"url":"https://docs.google.com/spreadsheets/d/SHEET123/edit#gid=0"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("short owner key: expected 400, got %d", w.Code)
+	}
+}
+
+func TestRouter_WatchesOnBothAPIPrefixes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, cleanup := mdhttp.SetupRouterWithCleanup(readinessConfig(t))
+	t.Cleanup(cleanup)
+	// Without cleanup there is no watch store, so the routes are unregistered.
+	noCleanup := mdhttp.SetupRouter(readinessConfig(t))
+	for _, path := range []string{"/api/mdflow/watches", "/api/v1/mdflow/watches"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status %d, want 401 without an owner key", path, w.Code)
+		}
+		w = httptest.NewRecorder()
+		noCleanup.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s without cleanup: status %d, want 404", path, w.Code)
+		}
+	}
+}
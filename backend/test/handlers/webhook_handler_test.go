@@ -0,0 +1,319 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourorg/md-spec-tool/internal/config"
+	"github.com/yourorg/md-spec-tool/internal/converter"
+	mdhttp "github.com/yourorg/md-spec-tool/internal/http"
+	"github.com/yourorg/md-spec-tool/internal/http/handlers"
+	"github.com/yourorg/md-spec-tool/internal/share"
+	"github.com/yourorg/md-spec-tool/internal/webhook"
+)
+
+// webhookReceiver collects verified events posted by the dispatcher.
+type webhookReceiver struct {
+	mu     sync.Mutex
+	secret string
+	events []webhook.Event
+	errs   []error
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := webhook.Verify(r.secret, req.Header.Get(webhook.HeaderSignature), body, time.Minute, time.Now()); err != nil {
+		r.errs = append(r.errs, err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var ev webhook.Event
+	if err := json.Unmarshal(body, &ev); err != nil {
+		r.errs = append(r.errs, err)
+	}
+	r.events = append(r.events, ev)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *webhookReceiver) waitFor(t *testing.T, n int) []webhook.Event {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		r.mu.Lock()
+		if len(r.errs) > 0 {
+			r.mu.Unlock()
+			t.Fatalf("receiver errors: %v", r.errs)
+		}
+		if len(r.events) >= n {
+			events := append([]webhook.Event(nil), r.events...)
+			r.mu.Unlock()
+			return events
+		}
+		r.mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d webhook events", n)
+	return nil
+}
+
+type webhookTestEnv struct {
+	router     *gin.Engine
+	dispatcher *webhook.Dispatcher
+}
+
+func newWebhookTestEnv(t *testing.T) *webhookTestEnv {
+	t.Helper()
+	store, err := webhook.NewStore("")
+	if err != nil {
+		t.Fatalf("webhook.NewStore: %v", err)
+	}
+	dispatcher := webhook.NewDispatcher(store, nil, webhook.Config{MaxAttempts: 2, BaseDelay: 5 * time.Millisecond})
+	t.Cleanup(func() {
+		dispatcher.Close()
+		_ = store.Close()
+	})
+	h := handlers.NewWebhookHandler(store, dispatcher)
+
+	cfg := config.LoadConfig()
+	convertHandler := handlers.NewConvertHandler(converter.NewConverter(), cfg, handlers.NewAIServiceProvider(cfg))
+	convertHandler.SetEventPublisher(dispatcher)
+	shareHandler := handlers.NewShareHandler(share.NewStore(""))
+	shareHandler.SetEventPublisher(dispatcher)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	webhooks := router.Group("/api/webhooks")
+	{
+		webhooks.POST("", h.CreateWebhook)
+		webhooks.GET("", h.ListWebhooks)
+		webhooks.GET("/dead-letters", h.ListDeadLetters)
+		webhooks.POST("/dead-letters/:id/replay", h.ReplayDeadLetter)
+		webhooks.PATCH("/:id", h.UpdateWebhook)
+		webhooks.DELETE("/:id", h.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", h.ListDeliveries)
+	}
+	router.POST("/api/mdflow/paste", convertHandler.ConvertPaste)
+	router.POST("/api/share", shareHandler.CreateShare)
+	router.POST("/api/share/:key/comments", shareHandler.CreateComment)
+
+	return &webhookTestEnv{router: router, dispatcher: dispatcher}
+}
+
+func (e *webhookTestEnv) do(method, path string, body any) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		reader = bytes.NewReader(payload)
+	}
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	e.router.ServeHTTP(w, req)
+	return w
+}
+
+func (e *webhookTestEnv) register(t *testing.T, url string, events ...string) handlers.WebhookEndpointResponse {
+	t.Helper()
+	w := e.do(http.MethodPost, "/api/webhooks", handlers.CreateWebhookRequest{URL: url, Events: events})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create webhook: status %d body %s", w.Code, w.Body.String())
+	}
+	var resp handlers.WebhookEndpointResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode webhook: %v", err)
+	}
+	return resp
+}
+
+func TestWebhookHandler_CRUD(t *testing.T) {
+	env := newWebhookTestEnv(t)
+
+	w := env.do(http.MethodPost, "/api/webhooks", handlers.CreateWebhookRequest{URL: "https://example.com/hook", Events: []string{"unknown.event"}})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown event, got %d", w.Code)
+	}
+
+	created := env.register(t, "https://example.com/hook", webhook.EventShareCreated)
+	if created.Secret == "" || !created.Active {
+		t.Fatalf("expected secret and active endpoint on create: %+v", created)
+	}
+
+	w = env.do(http.MethodGet, "/api/webhooks", nil)
+	var list struct {
+		Items []handlers.WebhookEndpointResponse `json:"items"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Items) != 1 {
+		t.Fatalf("list webhooks: %v %s", err, w.Body.String())
+	}
+	if list.Items[0].Secret != "" {
+		t.Fatal("secret must not be returned by list")
+	}
+
+	w = env.do(http.MethodPatch, "/api/webhooks/"+created.ID, map[string]bool{"active": false})
+	if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(`"active":false`)) {
+		t.Fatalf("pause webhook: status %d body %s", w.Code, w.Body.String())
+	}
+
+	if w = env.do(http.MethodDelete, "/api/webhooks/"+created.ID, nil); w.Code != http.StatusNoContent {
+		t.Fatalf("delete webhook: status %d", w.Code)
+	}
+	if w = env.do(http.MethodGet, "/api/webhooks/"+created.ID+"/deliveries", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("deliveries of deleted webhook: status %d", w.Code)
+	}
+}
+
+func TestWebhookHandler_DeliversConversionAndShareEvents(t *testing.T) {
+	env := newWebhookTestEnv(t)
+	recv := &webhookReceiver{}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	ep := env.register(t, server.URL, webhook.EventConversionCompleted, webhook.EventShareCreated, webhook.EventCommentAdded)
+	recv.secret = ep.Secret
+
+	w := env.do(http.MethodPost, "/api/mdflow/paste", handlers.PasteConvertRequest{
+		PasteText: "Feature\tScenario\tExpected\nAuth\tLogin works\tDashboard shown",
+		Template:  "spec",
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("paste: status %d body %s", w.Code, w.Body.String())
+	}
+	events := recv.waitFor(t, 1)
+	if events[0].Type != webhook.EventConversionCompleted {
+		t.Fatalf("first event = %s", events[0].Type)
+	}
+	data, _ := events[0].Data.(map[string]any)
+	if data["source"] != "paste" || data["template"] != "spec" {
+		t.Fatalf("unexpected conversion payload: %+v", data)
+	}
+
+	w = env.do(http.MethodPost, "/api/share", map[string]any{"mdflow": "# Spec", "title": "Spec", "allow_comments": true})
+	if w.Code != http.StatusOK {
+		t.Fatalf("create share: status %d body %s", w.Code, w.Body.String())
+	}
+	var created shareResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	if events = recv.waitFor(t, 2); events[1].Type != webhook.EventShareCreated {
+		t.Fatalf("second event = %s", events[1].Type)
+	}
+
+	w = env.do(http.MethodPost, "/api/share/"+created.Token+"/comments", map[string]string{"author": "qa", "message": "looks good"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("create comment: status %d body %s", w.Code, w.Body.String())
+	}
+
+	events = recv.waitFor(t, 3)
+	if events[2].Type != webhook.EventCommentAdded {
+		t.Fatalf("third event = %s", events[2].Type)
+	}
+	if data, _ := events[1].Data.(map[string]any); data["token"] != nil || data["title"] != "Spec" {
+		t.Fatalf("share payload must not carry the token: %+v", events[1].Data)
+	}
+	if data, _ := events[2].Data.(map[string]any); data["author"] != "qa" || data["message"] != nil || data["share_key"] != nil {
+		t.Fatalf("comment payload must carry neither the text nor the share key: %+v", events[2].Data)
+	}
+
+	w = env.do(http.MethodGet, "/api/webhooks/"+ep.ID+"/deliveries", nil)
+	var deliveries struct {
+		Items []webhook.Delivery `json:"items"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &deliveries); err != nil || len(deliveries.Items) != 3 {
+		t.Fatalf("deliveries: %v %s", err, w.Body.String())
+	}
+}
+
+func TestWebhookHandler_DeadLetterReplay(t *testing.T) {
+	env := newWebhookTestEnv(t)
+	var failing sync.Mutex
+	fail := true
+	recv := &webhookReceiver{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failing.Lock()
+		shouldFail := fail
+		failing.Unlock()
+		if shouldFail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		recv.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	ep := env.register(t, server.URL, webhook.EventAll)
+	recv.secret = ep.Secret
+	env.dispatcher.Publish(webhook.EventFeedbackSubmitted, map[string]int{"rating": 4})
+
+	var letters struct {
+		Items []webhook.DeadLetter `json:"items"`
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for len(letters.Items) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		w := env.do(http.MethodGet, "/api/webhooks/dead-letters", nil)
+		_ = json.Unmarshal(w.Body.Bytes(), &letters)
+	}
+	if len(letters.Items) != 1 || letters.Items[0].Attempts != 2 {
+		t.Fatalf("expected one dead letter after 2 attempts, got %+v", letters.Items)
+	}
+
+	failing.Lock()
+	fail = false
+	failing.Unlock()
+
+	if w := env.do(http.MethodPost, "/api/webhooks/dead-letters/999/replay", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("replay unknown dead letter: status %d", w.Code)
+	}
+	w := env.do(http.MethodPost, "/api/webhooks/dead-letters/"+strconv.FormatInt(letters.Items[0].ID, 10)+"/replay", nil)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("replay: status %d body %s", w.Code, w.Body.String())
+	}
+	events := recv.waitFor(t, 1)
+	if events[0].Type != webhook.EventFeedbackSubmitted {
+		t.Fatalf("replayed event = %s", events[0].Type)
+	}
+}
+
+func TestRouter_WebhookRoutesRequireAdminToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	list := func(router http.Handler, auth string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/webhooks", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	cfg := readinessConfig(t)
+	router, cleanup := mdhttp.SetupRouterWithCleanup(cfg)
+	t.Cleanup(cleanup)
+	if code := list(router, ""); code != http.StatusNotFound {
+		t.Fatalf("without an admin token the routes are unregistered: got %d", code)
+	}
+
+	cfg = readinessConfig(t)
+	cfg.WebhookAdminToken = "s3cret"
+	router, cleanup = mdhttp.SetupRouterWithCleanup(cfg)
+	t.Cleanup(cleanup)
+	for auth, want := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"s3cret":        http.StatusUnauthorized,
+		"Bearer s3cret": http.StatusOK,
+	} {
+		if code := list(router, auth); code != want {
+			t.Errorf("Authorization %q: got %d, want %d", auth, code, want)
+		}
+	}
+}
@@ -61,6 +61,10 @@ func (m *mockAIService) ValidateSemantic(ctx context.Context, req ai.SemanticVal
 	}, nil
 }
 
+func (m *mockAIService) ExtractRequirements(ctx context.Context, req ai.ExtractRequirementsRequest) (*ai.RequirementsExtraction, error) {
+	return &ai.RequirementsExtraction{}, nil
+}
+
 func TestNewSuggester(t *testing.T) {
 	mockService := &mockAIService{}
 	suggester := NewSuggester(mockService)
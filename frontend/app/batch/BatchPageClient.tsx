@@ -150,7 +150,7 @@ const HeroSection = memo(function HeroSection() {
         Batch Processing
       </h1>
       <p className="text-sm text-white/60 max-w-lg mx-auto">
-        Convert multiple files at once. Upload Excel (.xlsx) or TSV and
+        Convert multiple files at once. Upload Excel (.xlsx, .xls), OpenDocument (.ods) or TSV and
         download all outputs as a ZIP.
       </p>
     </div>
@@ -69,7 +69,7 @@ export function BatchProcessor({ format }: BatchProcessorProps) {
         };
 
         // Get sheets for XLSX files
-        if (/\.xlsx$/i.test(file.name)) {
+        if (/\.(xlsx|xls|ods)$/i.test(file.name)) {
           try {
             const sheetsResult = await getSheetsMutation.mutateAsync(file);
             batchFile.sheets = sheetsResult.sheets;
@@ -115,7 +115,7 @@ export function BatchProcessor({ format }: BatchProcessorProps) {
 
       try {
         let result;
-        const isExcel = /\.xlsx$/i.test(batchFile.file.name);
+        const isExcel = /\.(xlsx|xls|ods)$/i.test(batchFile.file.name);
 
         if (
           isExcel &&
@@ -278,7 +278,7 @@ export function BatchProcessor({ format }: BatchProcessorProps) {
       >
         <input
           type="file"
-          accept=".xlsx,.tsv"
+          accept=".xlsx,.xls,.ods,.tsv"
           multiple
           onChange={onFileChange}
           className="absolute inset-0 w-full h-full opacity-0 cursor-pointer"
@@ -303,7 +303,7 @@ export function BatchProcessor({ format }: BatchProcessorProps) {
               {dragOver ? "Drop files here" : "Drop files or click to upload"}
             </p>
             <p className="text-[10px] text-muted mt-1.5 uppercase font-medium">
-              .xlsx, .tsv • Multiple files supported
+              .xlsx, .xls, .ods, .tsv • Multiple files supported
             </p>
           </div>
         </div>
@@ -453,7 +453,7 @@ const BatchFileItem = memo(function BatchFileItem({
   onSheetChange: (sheet: string) => void;
 }) {
   const [expanded, setExpanded] = useState(false);
-  const isExcel = /\.xlsx$/i.test(batchFile.file.name);
+  const isExcel = /\.(xlsx|xls|ods)$/i.test(batchFile.file.name);
   const hasMultipleSheets = batchFile.sheets && batchFile.sheets.length > 1;
   const warningCount = batchFile.result?.warnings?.length || 0;
 
@@ -325,7 +325,7 @@ const docContent: Record<string, { title: string; content: React.ReactNode }> =
               </h4>
               <p className="text-sm text-muted mb-4">
                 Designed for production specifications. Accepts
-                `multipart/form-data` binary streams (10MB limit, .xlsx only).
+                `multipart/form-data` binary streams (10MB limit; .xlsx, .xls or .ods).
               </p>
               <ul className="list-disc list-inside text-sm text-white/60 space-y-2 mb-4">
                 <li>
@@ -976,7 +976,7 @@ const docContent: Record<string, { title: string; content: React.ReactNode }> =
             outputs can be downloaded as a ZIP archive.
           </p>
           <ul className="space-y-2 text-sm text-white/60">
-            <li>• Supports .xlsx and .tsv uploads</li>
+            <li>• Supports .xlsx, .xls, .ods and .tsv uploads</li>
             <li>• Optional “process all sheets” for multi-sheet workbooks</li>
             <li>
               • ZIP export names files as <code>*.mdflow.md</code>
@@ -92,7 +92,7 @@ export const FileUploadInput = memo(function FileUploadInput({
       >
         <input
           type="file"
-          accept={mode === "tsv" ? ".tsv" : ".xlsx"}
+          accept={mode === "tsv" ? ".tsv" : ".xlsx,.xls,.ods"}
           onChange={onFileChange}
           className="absolute inset-0 w-full h-full opacity-0 cursor-pointer"
           aria-label={mode === "tsv" ? "Upload TSV file" : "Upload Excel file"}
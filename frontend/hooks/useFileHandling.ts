@@ -88,7 +88,7 @@ export function useFileHandling({
         return;
       }
 
-      if (mode === "xlsx" && /\.xlsx$/i.test(f.name)) {
+      if (mode === "xlsx" && /\.(xlsx|xls|ods)$/i.test(f.name)) {
         setFile(f);
         setLoading(true);
         setError(null);
@@ -42,9 +42,17 @@ export interface MDFlowWarning {
   details?: Record<string, unknown>;
 }
 
+// Rows (0-based, inclusive) of a header spanning several rows; `end` is the
+// row naming the columns and equals `header_row`.
+export interface HeaderBand {
+  start: number;
+  end: number;
+}
+
 export interface MDFlowMeta {
   sheet_name?: string;
   header_row: number;
+  header_band?: HeaderBand;
   column_map: Record<string, number>;
   unmapped_columns?: string[];
   total_rows: number;
@@ -67,6 +75,7 @@ export interface MDFlowMeta {
     validation_passed: boolean;
     validation_reason?: string;
     header_confidence: number;
+    header_band: HeaderBand;
     min_header_confidence: number;
     source_rows: number;
     converted_rows: number;
@@ -112,6 +121,7 @@ export interface PreviewResponse {
   total_rows: number;
   preview_rows: number;
   header_row: number;
+  header_band: HeaderBand;
   confidence: number;
   column_mapping: Record<string, string>;
   unmapped_columns: string[];